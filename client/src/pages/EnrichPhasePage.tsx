@@ -86,8 +86,18 @@ const ENRICH_GROUPS = [
     { key: 'orphan_warning', label: 'Orphan Warnings', color: '#00838f' },
     { key: 'cardinality_violation', label: 'Cardinality Violations', color: '#4527a0' },
     { key: 'missing_required', label: 'Missing Required Relationships', color: '#bf360c' },
+    { key: 'session_memory', label: 'Session Memories', color: '#37474f' },
 ] as const;
 
+/**
+ * Detection types whose suggestion the GM can edit before accepting,
+ * mapped to the suggested content field the edit overrides.
+ */
+const EDITABLE_FIELDS: Record<string, string> = {
+    description_update: 'suggestedDescription',
+    session_memory: 'content',
+};
+
 /** Detection types that belong to the graph health summary section. */
 const GRAPH_TYPES = [
     'graph_warning',
@@ -293,25 +303,21 @@ export default function EnrichPhasePage() {
     };
 
     /**
-     * Accept handler for description_update items that may have been
-     * edited by the GM. When an edited description exists in
-     * editingContent for the given item, we include it as a
-     * suggestedContentOverride. Otherwise we fall through to the
-     * standard handleResolve path.
+     * Accept handler for items that may have been edited by the GM
+     * (see EDITABLE_FIELDS). When edited text exists in editingContent
+     * for the given item, we include it as a suggestedContentOverride.
+     * Otherwise we fall through to the standard handleResolve path.
      */
     const handleAcceptDescription = (item: ContentAnalysisItem) => {
-        if (
-            item.detectionType === 'description_update' &&
-            editingContent[item.id] !== undefined
-        ) {
+        const field = EDITABLE_FIELDS[item.detectionType];
+        if (field && editingContent[item.id] !== undefined) {
             resolveItem.mutate(
                 {
                     itemId: item.id,
                     req: {
                         resolution: 'accepted',
                         suggestedContentOverride: {
-                            suggestedDescription:
-                                editingContent[item.id],
+                            [field]: editingContent[item.id],
                         },
                     },
                 },
@@ -970,8 +976,7 @@ function DetailPanel({
                             disabled={isResolving}
                             startIcon={<Check />}
                             onClick={() =>
-                                item.detectionType ===
-                                'description_update'
+                                item.detectionType in EDITABLE_FIELDS
                                     ? onAcceptDescription(item)
                                     : onResolve(
                                           item.id,
@@ -979,8 +984,7 @@ function DetailPanel({
                                       )
                             }
                         >
-                            {item.detectionType ===
-                                'description_update' &&
+                            {item.detectionType in EDITABLE_FIELDS &&
                             editingContent[item.id] !== undefined
                                 ? 'Accept Edited'
                                 : 'Accept'}
@@ -1321,6 +1325,138 @@ function renderSuggestedContent(
                 </Box>
             );
 
+        case 'session_memory': {
+            const isEditing = editingContent[itemId] !== undefined;
+            const entityNames = Array.isArray(suggested.entityNames)
+                ? (suggested.entityNames as string[])
+                : [];
+            return (
+                <Box>
+                    <Typography variant="subtitle2" gutterBottom>
+                        Session Memory
+                    </Typography>
+                    <Stack
+                        direction="row"
+                        spacing={1}
+                        sx={{ mb: 1, flexWrap: 'wrap' }}
+                    >
+                        <Chip
+                            size="small"
+                            label={String(suggested.memoryType)}
+                        />
+                        <Chip
+                            size="small"
+                            variant="outlined"
+                            label={`Importance ${String(suggested.importance)}`}
+                        />
+                        <Chip
+                            size="small"
+                            variant="outlined"
+                            color={
+                                suggested.isPlayerVisible
+                                    ? 'success'
+                                    : 'warning'
+                            }
+                            label={
+                                suggested.isPlayerVisible
+                                    ? 'Player visible'
+                                    : 'GM only'
+                            }
+                        />
+                    </Stack>
+                    <Paper
+                        variant="outlined"
+                        sx={{
+                            p: 2,
+                            bgcolor: isEditing
+                                ? 'background.paper'
+                                : 'action.hover',
+                        }}
+                    >
+                        <Stack
+                            direction="row"
+                            alignItems="center"
+                            justifyContent="space-between"
+                            sx={{ mb: 1 }}
+                        >
+                            <Typography variant="body2">
+                                <strong>
+                                    {suggested.title
+                                        ? String(suggested.title)
+                                        : 'Untitled'}
+                                </strong>
+                            </Typography>
+                            {!isEditing ? (
+                                <Button
+                                    size="small"
+                                    startIcon={<Edit />}
+                                    onClick={() =>
+                                        setEditingContent((prev) => ({
+                                            ...prev,
+                                            [itemId]: String(
+                                                suggested.content,
+                                            ),
+                                        }))
+                                    }
+                                >
+                                    Edit
+                                </Button>
+                            ) : (
+                                <Button
+                                    size="small"
+                                    color="inherit"
+                                    startIcon={<Close />}
+                                    onClick={() =>
+                                        setEditingContent((prev) => {
+                                            const next = { ...prev };
+                                            delete next[itemId];
+                                            return next;
+                                        })
+                                    }
+                                >
+                                    Cancel Edit
+                                </Button>
+                            )}
+                        </Stack>
+                        {isEditing ? (
+                            <TextField
+                                fullWidth
+                                multiline
+                                minRows={3}
+                                value={editingContent[itemId]}
+                                onChange={(e) =>
+                                    setEditingContent((prev) => ({
+                                        ...prev,
+                                        [itemId]: e.target.value,
+                                    }))
+                                }
+                                inputProps={{
+                                    'aria-label': 'Edit session memory',
+                                }}
+                            />
+                        ) : (
+                            <Typography
+                                variant="body2"
+                                sx={{ whiteSpace: 'pre-wrap' }}
+                            >
+                                {String(suggested.content)}
+                            </Typography>
+                        )}
+                        {entityNames.length > 0 && (
+                            <Typography
+                                variant="caption"
+                                color="text.secondary"
+                                component="div"
+                                sx={{ mt: 1 }}
+                            >
+                                Mentions: {entityNames.join(', ')}
+                            </Typography>
+                        )}
+                    </Paper>
+                </Box>
+            );
+        }
+
         // Graph items: graph_warning, redundant_edge,
         // invalid_type_pair, orphan_warning
         default:
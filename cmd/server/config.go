@@ -21,5 +21,6 @@ const (
 	DefaultPort        = "3001"
 	DefaultConfigPath  = "config/db/db.json"
 	DefaultOntologyDir = "schemas/ontology"
+	DefaultSchemasDir  = "schemas"
 	ShutdownTimeout    = 30 * time.Second
 )
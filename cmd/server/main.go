@@ -26,13 +26,16 @@ import (
 	"github.com/antonypegg/imagineer/internal/auth"
 	"github.com/antonypegg/imagineer/internal/crypto"
 	"github.com/antonypegg/imagineer/internal/database"
+	"github.com/antonypegg/imagineer/internal/events"
+	"github.com/antonypegg/imagineer/internal/gamesystem"
+	"github.com/antonypegg/imagineer/internal/jobs"
 	"github.com/antonypegg/imagineer/internal/ontology"
 	"github.com/joho/godotenv"
 )
 
 // main initializes application configuration and services, starts the HTTP server, and performs a graceful shutdown on interrupt signals.
 //
-// It prints the application name and version, reads configuration from environment variables (PORT, DB_CONFIG, JWT_SECRET, JWT_EXPIRY_HOURS),
+// It prints the application name and version, reads configuration from environment variables (PORT, DB_CONFIG, JWT_SECRET, JWT_EXPIRY_HOURS, JOB_WORKERS),
 // loads and connects to the database, and conditionally initializes OAuth/JWT authentication if configuration is present.
 // It constructs the API router, starts the HTTP server with sensible timeouts, and blocks until an OS interrupt (SIGINT or SIGTERM) is received,
 // at which point it attempts a graceful shutdown within a configured timeout.
@@ -89,6 +92,19 @@ func main() {
 			len(ont.RelationshipTypes.Types))
 	}
 
+	// Load game system schemas used to validate entity attributes
+	schemasDir := os.Getenv("SCHEMAS_DIR")
+	if schemasDir == "" {
+		schemasDir = DefaultSchemasDir
+	}
+	gameSystems, err := gamesystem.LoadRegistry(schemasDir)
+	if err != nil {
+		log.Printf("Game system schemas not loaded: %v (entity attributes will not be validated)", err)
+	} else {
+		db.GameSystems = gameSystems
+		log.Printf("Game system schemas loaded: %d systems", gameSystems.Len())
+	}
+
 	// Configure API key encryption if ENCRYPTION_KEY is set
 	if encKeyHex := os.Getenv("ENCRYPTION_KEY"); encKeyHex != "" {
 		encKey, err := hex.DecodeString(encKeyHex)
@@ -126,11 +142,27 @@ func main() {
 		}
 	}
 
+	// Create the background job queue for enrichment and revision work
+	queue := jobs.NewQueue(db)
+	if workersStr := os.Getenv("JOB_WORKERS"); workersStr != "" {
+		if parsed, err := strconv.Atoi(workersStr); err == nil && parsed > 0 {
+			queue.WithWorkers(parsed)
+		}
+	}
+	api.RegisterJobKinds(queue, db)
+
+	// Create the broker that pushes enrichment events to SSE clients
+	broker := events.NewBroker(db)
+
 	// Create router (requires JWT secret for authentication)
-	router, err := api.NewRouter(db, authHandler, jwtSecret)
+	router, err := api.NewRouter(db, authHandler, jwtSecret, queue, broker)
 	if err != nil {
 		log.Fatalf("Failed to create router: %v", err)
 	}
+
+	// Start job workers; this also recovers jobs orphaned by a restart
+	queue.Start(ctx)
+	broker.Start(ctx)
 	log.Println("JWT authentication middleware enabled for protected routes")
 
 	// Create HTTP server
@@ -166,5 +198,9 @@ func main() {
 		log.Printf("Server forced to shutdown: %v", err)
 	}
 
+	// Stop job workers; interrupted jobs are requeued for the next start
+	queue.Stop()
+	broker.Stop()
+
 	log.Println("Server stopped")
 }
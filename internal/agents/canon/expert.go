@@ -74,12 +74,14 @@ func (e *Expert) Run(
 		UserPrompt:   userPrompt,
 		MaxTokens:    4096,
 		Temperature:  0.2,
+		Tools:        []llm.Tool{reportTool},
+		ToolChoice:   reportToolName,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	parsed, err := parseCanonResponse(resp.Content)
+	parsed, err := parseCanonResponse(llm.StructuredContent(resp, reportToolName))
 	if err != nil {
 		log.Printf(
 			"canon-expert: parse error for job %d, returning empty results: %v",
@@ -25,9 +25,11 @@ import (
 
 // mockProvider implements llm.Provider for testing.
 type mockProvider struct {
-	response string
-	err      error
-	called   bool
+	response  string
+	toolCalls []llm.ToolCall
+	err       error
+	called    bool
+	lastReq   llm.CompletionRequest
 }
 
 func (m *mockProvider) Complete(
@@ -35,10 +37,14 @@ func (m *mockProvider) Complete(
 	req llm.CompletionRequest,
 ) (llm.CompletionResponse, error) {
 	m.called = true
+	m.lastReq = req
 	if m.err != nil {
 		return llm.CompletionResponse{}, m.err
 	}
-	return llm.CompletionResponse{Content: m.response}, nil
+	return llm.CompletionResponse{
+		Content:   m.response,
+		ToolCalls: m.toolCalls,
+	}, nil
 }
 
 // ---------------------------------------------------------------------------
@@ -192,6 +198,47 @@ func TestExpert_Run_ValidResponse(t *testing.T) {
 	assert.Equal(t, "analysis", items[1].Phase)
 }
 
+func TestExpert_Run_ToolCallResponse(t *testing.T) {
+	provider := &mockProvider{
+		response: "Here are the contradictions I found.",
+		toolCalls: []llm.ToolCall{
+			{
+				ID:   "call_1",
+				Name: reportToolName,
+				Arguments: []byte(`{"contradictions":[{
+					"contradictionType": "temporal",
+					"severity": "warning",
+					"conflictingText": "Viktor arrived in London in 1923",
+					"establishedFact": "Viktor arrived in London in 1921",
+					"description": "The arrival year contradicts established canon."
+				}]}`),
+			},
+		},
+	}
+	expert := NewExpert()
+
+	input := enrichment.PipelineInput{
+		CampaignID: 1,
+		JobID:      42,
+		Content:    "Viktor arrived in London in 1923.",
+		Context: &enrichment.RAGContext{
+			CampaignResults: []models.SearchResult{
+				{ChunkContent: "Viktor arrived in London in 1921."},
+			},
+		},
+	}
+
+	items, err := expert.Run(context.Background(), provider, input)
+
+	require.NoError(t, err)
+	require.Len(t, provider.lastReq.Tools, 1)
+	assert.Equal(t, reportToolName, provider.lastReq.Tools[0].Name)
+	assert.Equal(t, reportToolName, provider.lastReq.ToolChoice)
+	require.Len(t, items, 1)
+	assert.Equal(t, "temporal_inconsistency", items[0].DetectionType)
+	assert.Equal(t, "Viktor arrived in London in 1923", items[0].MatchedText)
+}
+
 func TestExpert_Run_LLMError(t *testing.T) {
 	provider := &mockProvider{
 		err: errors.New("API rate limit exceeded"),
@@ -20,8 +20,42 @@ import (
 	"strings"
 
 	"github.com/antonypegg/imagineer/internal/agents"
+	"github.com/antonypegg/imagineer/internal/llm"
 )
 
+// reportToolName is the tool the LLM is asked to call with its
+// detected contradictions.
+const reportToolName = "report_contradictions"
+
+// reportTool describes canonResponse as a JSON Schema so that providers
+// with tool support return structured arguments rather than prose.
+var reportTool = llm.Tool{
+	Name:        reportToolName,
+	Description: "Report contradictions between the new content and established campaign facts.",
+	InputSchema: json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"contradictions": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"properties": {
+						"contradictionType": {"type": "string", "enum": ["factual", "temporal", "character"]},
+						"severity": {"type": "string", "enum": ["info", "warning", "error"]},
+						"conflictingText": {"type": "string"},
+						"establishedFact": {"type": "string"},
+						"source": {"type": "string"},
+						"description": {"type": "string"},
+						"suggestion": {"type": "string"}
+					},
+					"required": ["contradictionType", "severity", "conflictingText", "establishedFact", "description"]
+				}
+			}
+		},
+		"required": ["contradictions"]
+	}`),
+}
+
 // canonResponse represents the expected JSON output from the LLM.
 type canonResponse struct {
 	Contradictions []contradiction `json:"contradictions"`
@@ -167,25 +167,19 @@ func buildUserPrompt(input enrichment.PipelineInput) string {
 		b.WriteString("\n")
 	}
 
-	// Include established facts from RAG context. These are the
-	// authoritative references against which the new content is
-	// compared.
-	if input.Context != nil && len(input.Context.CampaignResults) > 0 {
+	// Include shared campaign context. Memory, recent sessions and
+	// previously established content are the authoritative references
+	// against which the new content is compared.
+	if block := input.Context.Block(enrichment.DefaultContextBudget); block != "" {
 		b.WriteString("\n## Established Facts\n\n")
-		b.WriteString("The following content has been previously ")
-		b.WriteString("established in this campaign. Treat these as ")
-		b.WriteString("authoritative references. Only flag a ")
-		b.WriteString("contradiction if the new content DIRECTLY ")
-		b.WriteString("conflicts with information stated here.\n\n")
-		for _, result := range input.Context.CampaignResults {
-			b.WriteString("- **")
-			b.WriteString(result.SourceName)
-			b.WriteString("** (")
-			b.WriteString(result.SourceTable)
-			b.WriteString("): ")
-			b.WriteString(agents.TruncateString(result.ChunkContent, 500))
-			b.WriteString("\n")
-		}
+		b.WriteString("The following context has been previously ")
+		b.WriteString("established in this campaign. Treat it as ")
+		b.WriteString("authoritative. Only flag a contradiction if the ")
+		b.WriteString("new content DIRECTLY conflicts with information ")
+		b.WriteString("stated here. Use the game system schema, if ")
+		b.WriteString("present, to flag stats, skills, or rules that ")
+		b.WriteString("conflict with it.\n\n")
+		b.WriteString(block)
 	}
 
 	// Include entity list with current descriptions. Entity
@@ -229,18 +223,6 @@ func buildUserPrompt(input enrichment.PipelineInput) string {
 		}
 	}
 
-	// Include game system schema if available, for mechanics
-	// contradiction detection.
-	if input.Context != nil && input.Context.GameSystemYAML != "" {
-		b.WriteString("\n## Game System Schema\n\n")
-		b.WriteString("Use this schema to validate any game mechanics ")
-		b.WriteString("referenced in the content. Flag contradictions ")
-		b.WriteString("where the content uses stats, skills, or rules ")
-		b.WriteString("that conflict with the schema:\n\n```yaml\n")
-		b.WriteString(input.Context.GameSystemYAML)
-		b.WriteString("\n```\n")
-	}
-
 	// Add metadata.
 	b.WriteString("\n## Metadata\n\n")
 	b.WriteString(fmt.Sprintf(
@@ -320,6 +320,8 @@ func (e *Expert) runSemanticChecks(
 		UserPrompt:   userPrompt,
 		MaxTokens:    2048,
 		Temperature:  0.2,
+		Tools:        []llm.Tool{reportTool},
+		ToolChoice:   reportToolName,
 	})
 	if err != nil {
 		log.Printf(
@@ -329,7 +331,7 @@ func (e *Expert) runSemanticChecks(
 		return nil
 	}
 
-	parsed, err := parseGraphResponse(resp.Content)
+	parsed, err := parseGraphResponse(llm.StructuredContent(resp, reportToolName))
 	if err != nil {
 		log.Printf(
 			"graph-expert: parse error for job %d, returning structural findings only: %v",
@@ -17,8 +17,39 @@ import (
 	"strings"
 
 	"github.com/antonypegg/imagineer/internal/agents"
+	"github.com/antonypegg/imagineer/internal/llm"
 )
 
+// reportToolName is the tool the LLM is asked to call with its graph
+// hygiene findings.
+const reportToolName = "report_graph_findings"
+
+// reportTool describes graphResponse as a JSON Schema so that providers
+// with tool support return structured arguments rather than prose.
+var reportTool = llm.Tool{
+	Name:        reportToolName,
+	Description: "Report redundant or implied relationships in the campaign graph.",
+	InputSchema: json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"findings": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"properties": {
+						"findingType": {"type": "string", "enum": ["redundant_edge", "implied_edge"]},
+						"description": {"type": "string"},
+						"involvedEntities": {"type": "array", "items": {"type": "string"}},
+						"suggestion": {"type": "string"}
+					},
+					"required": ["findingType", "description", "involvedEntities"]
+				}
+			}
+		},
+		"required": ["findings"]
+	}`),
+}
+
 // graphResponse represents the expected JSON output from the LLM.
 type graphResponse struct {
 	Findings []graphFinding `json:"findings"`
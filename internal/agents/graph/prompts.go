@@ -21,6 +21,12 @@ import (
 	"github.com/antonypegg/imagineer/internal/models"
 )
 
+// graphContextBudget is the token budget for campaign context in the
+// graph prompt. The graph review needs the campaign's factions and
+// threads rather than content or rules, so it takes a smaller block
+// than the other agents, leaving the lower-priority tiers out.
+const graphContextBudget = 4000
+
 // buildSystemPrompt returns the system prompt instructing the LLM to
 // act as a knowledge graph analyst for TTRPG campaigns. The LLM
 // identifies redundant and implied relationships.
@@ -76,6 +82,13 @@ func buildUserPrompt(
 ) string {
 	var b strings.Builder
 
+	// Include shared campaign context, such as faction summaries and
+	// the entities mentioned alongside those being linked.
+	if block := input.Context.Block(graphContextBudget); block != "" {
+		b.WriteString(block)
+		b.WriteString("\n")
+	}
+
 	// Include existing relationships.
 	if len(input.Relationships) > 0 {
 		b.WriteString("## Existing Relationships\n\n")
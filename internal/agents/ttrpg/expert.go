@@ -67,12 +67,14 @@ func (e *Expert) Run(
 		UserPrompt:   userPrompt,
 		MaxTokens:    4096,
 		Temperature:  0.3,
+		Tools:        []llm.Tool{reportTool},
+		ToolChoice:   reportToolName,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	parsed, err := parseExpertResponse(resp.Content)
+	parsed, err := parseExpertResponse(llm.StructuredContent(resp, reportToolName))
 	if err != nil {
 		log.Printf(
 			"ttrpg-expert: parse error for job %d, returning empty results: %v",
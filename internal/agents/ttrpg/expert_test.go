@@ -444,6 +444,29 @@ func TestBuildUserPrompt_WithRAGContext(t *testing.T) {
 	assert.Contains(t, prompt, "dockworkers")
 }
 
+func TestBuildUserPrompt_WithMemories(t *testing.T) {
+	input := enrichment.PipelineInput{
+		CampaignID:  1,
+		JobID:       10,
+		SourceTable: "chapters",
+		SourceID:    5,
+		Content:     "Viktor enters the Silver Fox Inn.",
+		Context: &enrichment.RAGContext{
+			Memories: []models.CampaignMemory{
+				{
+					MemoryType: models.MemoryTypePremise,
+					Content:    "Émigrés in 1920s London are hunted by a cult.",
+				},
+			},
+		},
+	}
+
+	prompt := buildUserPrompt(input)
+
+	assert.Contains(t, prompt, "## Campaign Memory")
+	assert.Contains(t, prompt, "**Premise**: Émigrés in 1920s London are hunted by a cult.")
+}
+
 func TestBuildUserPrompt_MinimalInput(t *testing.T) {
 	input := enrichment.PipelineInput{
 		CampaignID:  1,
@@ -19,8 +19,46 @@ import (
 	"strings"
 
 	"github.com/antonypegg/imagineer/internal/agents"
+	"github.com/antonypegg/imagineer/internal/llm"
 )
 
+// reportToolName is the tool the LLM is asked to call with its report
+// and findings.
+const reportToolName = "report_analysis"
+
+// reportTool describes expertResponse as a JSON Schema so that
+// providers with tool support return structured arguments rather than
+// prose.
+var reportTool = llm.Tool{
+	Name:        reportToolName,
+	Description: "Report the quality analysis as a markdown report plus individual findings.",
+	InputSchema: json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"report": {"type": "string", "description": "Full analysis report in markdown."},
+			"findings": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"properties": {
+						"category": {
+							"type": "string",
+							"enum": ["pacing", "investigation", "spotlight", "npc_development", "mechanics",
+								"pc_agency", "continuity", "setting", "scenario_writing"]
+						},
+						"severity": {"type": "string", "enum": ["info", "warning", "error"]},
+						"description": {"type": "string"},
+						"suggestion": {"type": "string"},
+						"lineReference": {"type": "string"}
+					},
+					"required": ["category", "severity", "description"]
+				}
+			}
+		},
+		"required": ["report", "findings"]
+	}`),
+}
+
 // expertResponse represents the expected JSON output from the LLM.
 type expertResponse struct {
 	Report   string    `json:"report"`
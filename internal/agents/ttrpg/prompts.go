@@ -187,29 +187,12 @@ func buildUserPrompt(input enrichment.PipelineInput) string {
 		b.WriteString("\n")
 	}
 
-	// Include game system schema if available.
-	if input.Context != nil && input.Context.GameSystemYAML != "" {
-		b.WriteString("\n## Game System Schema\n\n")
-		b.WriteString("Use this schema to validate any game mechanics ")
-		b.WriteString("referenced in the content:\n\n```yaml\n")
-		b.WriteString(input.Context.GameSystemYAML)
-		b.WriteString("\n```\n")
-	}
-
-	// Include campaign context from RAG results if available.
-	if input.Context != nil && len(input.Context.CampaignResults) > 0 {
-		b.WriteString("\n## Campaign Context\n\n")
-		b.WriteString("Related campaign content for reference ")
-		b.WriteString("(use for continuity checks):\n\n")
-		for _, result := range input.Context.CampaignResults {
-			b.WriteString("- **")
-			b.WriteString(result.SourceName)
-			b.WriteString("** (")
-			b.WriteString(result.SourceTable)
-			b.WriteString("): ")
-			b.WriteString(agents.TruncateString(result.ChunkContent, 300))
-			b.WriteString("\n")
-		}
+	// Include shared campaign context: memory, recent play, related
+	// content for continuity checks and the game system schema used
+	// to validate any game mechanics referenced in the content.
+	if block := input.Context.Block(enrichment.DefaultContextBudget); block != "" {
+		b.WriteString("\n")
+		b.WriteString(block)
 	}
 
 	// Include entity list for reference.
@@ -77,11 +77,13 @@ func (a *Analyzer) AnalyzeContent(
 	phases []string,
 ) (*models.ContentAnalysisJob, []models.ContentAnalysisItem, error) {
 	var items []models.ContentAnalysisItem
+	var linkedIDs []int64
 
 	if content != "" {
 		// Scan 1: extract wiki links and resolve them against
 		// known entities.
-		wikiItems, resolvedNames := a.scanWikiLinks(ctx, campaignID, content)
+		wikiItems, resolvedNames, resolvedIDs := a.scanWikiLinks(ctx, campaignID, content)
+		linkedIDs = resolvedIDs
 		items = append(items, wikiItems...)
 
 		// Compute original-coordinate ranges of existing wiki
@@ -150,19 +152,54 @@ func (a *Analyzer) AnalyzeContent(
 		}
 	}
 
+	// Entities named in session notes are recorded as mentioned in
+	// the session.
+	if sourceTable == "sessions" {
+		mentioned := mentionedEntityIDs(linkedIDs, items)
+		if _, err := a.db.AddSessionEntityMentions(ctx, sourceID, mentioned); err != nil {
+			log.Printf("analysis: failed to record entity mentions for session %d: %v", sourceID, err)
+		}
+	}
+
 	return createdJob, items, nil
 }
 
+// mentionedEntityIDs returns the IDs of the entities content mentions:
+// those of its resolved wiki links and of its untagged mentions, which
+// match an entity name exactly. Unresolved wiki links and misspellings
+// are only suggestions and are left out.
+func mentionedEntityIDs(linkedIDs []int64, items []models.ContentAnalysisItem) []int64 {
+	seen := make(map[int64]bool)
+	var ids []int64
+	add := func(id int64) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	for _, id := range linkedIDs {
+		add(id)
+	}
+	for _, item := range items {
+		if item.DetectionType == "untagged_mention" && item.EntityID != nil {
+			add(*item.EntityID)
+		}
+	}
+	return ids
+}
+
 // scanWikiLinks extracts [[Entity]] and [[Entity|Display]] references,
 // resolves each against the campaign entity list using fuzzy matching,
 // and returns analysis items plus the set of entity names that were
-// resolved (similarity >= threshold).
+// resolved (similarity >= threshold) and the IDs of their entities.
 func (a *Analyzer) scanWikiLinks(
 	ctx context.Context,
 	campaignID int64,
 	content string,
-) ([]models.ContentAnalysisItem, map[string]bool) {
+) ([]models.ContentAnalysisItem, map[string]bool, []int64) {
 	resolvedNames := make(map[string]bool)
+	var resolvedIDs []int64
 	var items []models.ContentAnalysisItem
 
 	matches := wikiLinkRe.FindAllStringSubmatchIndex(content, -1)
@@ -201,6 +238,7 @@ func (a *Analyzer) scanWikiLinks(
 			if r.Similarity >= similarityThresholdResolved {
 				item.DetectionType = "wiki_link_resolved"
 				resolvedNames[strings.ToLower(entityName)] = true
+				resolvedIDs = append(resolvedIDs, r.ID)
 			} else if r.Similarity >= similarityThresholdMinimum {
 				item.DetectionType = "wiki_link_unresolved"
 			} else {
@@ -223,13 +261,12 @@ func (a *Analyzer) scanWikiLinks(
 		}
 	}
 
-	return items, resolvedNames
+	return items, resolvedNames, resolvedIDs
 }
 
-// scanUntaggedMentions searches the content for entity names that
-// appear without wiki link markup. It receives the original content
-// (with [[...]] brackets intact) and wiki link ranges in the same
-// coordinate space, so matches inside existing links are skipped.
+// scanUntaggedMentions searches the content for the names and aliases
+// of the campaign's entities that appear without wiki link markup. See
+// findUntaggedMentions.
 func (a *Analyzer) scanUntaggedMentions(
 	ctx context.Context,
 	campaignID int64,
@@ -242,49 +279,81 @@ func (a *Analyzer) scanUntaggedMentions(
 		return nil
 	}
 
-	var items []models.ContentAnalysisItem
+	aliases, err := a.db.ListCampaignEntityAliases(ctx, campaignID)
+	if err != nil {
+		// Aliases only add matches; scan entity names alone.
+		log.Printf("analysis: failed to list entity aliases for campaign %d: %v", campaignID, err)
+	}
+	aliasesByEntity := make(map[int64][]string)
+	for _, alias := range aliases {
+		aliasesByEntity[alias.EntityID] = append(aliasesByEntity[alias.EntityID], alias.Alias)
+	}
 
-	for _, entity := range entities {
-		if len(entity.Name) < 3 {
-			continue
-		}
+	return findUntaggedMentions(content, entities, aliasesByEntity, wikiRanges)
+}
 
-		// Use case-insensitive regex matching against the
-		// original content to avoid byte-offset misalignment
-		// that occurs when strings.ToLower changes byte lengths
-		// for certain Unicode characters.
-		pattern := "(?i)" + regexp.QuoteMeta(entity.Name)
-		re, err := regexp.Compile(pattern)
-		if err != nil {
-			continue
-		}
+// findUntaggedMentions searches the content for entity names and
+// aliases, keyed by entity ID, that appear without wiki link markup.
+// A mention of an alias is reported against its entity. It receives
+// the original content (with [[...]] brackets intact) and wiki link
+// ranges in the same coordinate space, so matches inside existing
+// links are skipped. An alias occurrence overlapping a mention of the
+// same entity already found is skipped too.
+func findUntaggedMentions(
+	content string,
+	entities []models.Entity,
+	aliasesByEntity map[int64][]string,
+	wikiRanges []wikiLinkRange,
+) []models.ContentAnalysisItem {
+	var items []models.ContentAnalysisItem
 
-		matches := re.FindAllStringIndex(content, -1)
-		for _, match := range matches {
-			idx := match[0]
-			end := match[1]
+	for _, entity := range entities {
+		var found []wikiLinkRange
+		names := append([]string{entity.Name}, aliasesByEntity[entity.ID]...)
+		for _, name := range names {
+			if len(name) < 3 {
+				continue
+			}
 
-			// Skip if this occurrence overlaps with any wiki
-			// link range in original-content coordinates.
-			if overlapsWikiRange(idx, end, wikiRanges) {
+			// Use case-insensitive regex matching against the
+			// original content to avoid byte-offset misalignment
+			// that occurs when strings.ToLower changes byte lengths
+			// for certain Unicode characters.
+			pattern := "(?i)" + regexp.QuoteMeta(name)
+			re, err := regexp.Compile(pattern)
+			if err != nil {
 				continue
 			}
 
-			sim := 1.0
-			entityID := entity.ID
-			snippet := extractContextSnippet(content, idx, end)
-
-			items = append(items, models.ContentAnalysisItem{
-				DetectionType:  "untagged_mention",
-				MatchedText:    content[idx:end],
-				EntityID:       &entityID,
-				Similarity:     &sim,
-				ContextSnippet: &snippet,
-				PositionStart:  &idx,
-				PositionEnd:    &end,
-				Resolution:     "pending",
-				Phase:          "identification",
-			})
+			matches := re.FindAllStringIndex(content, -1)
+			for _, match := range matches {
+				idx := match[0]
+				end := match[1]
+
+				// Skip if this occurrence overlaps with any wiki
+				// link range in original-content coordinates, or
+				// with a mention of this entity already found.
+				if overlapsWikiRange(idx, end, wikiRanges) || overlapsWikiRange(idx, end, found) {
+					continue
+				}
+				found = append(found, wikiLinkRange{start: idx, end: end})
+
+				sim := 1.0
+				entityID := entity.ID
+				snippet := extractContextSnippet(content, idx, end)
+
+				items = append(items, models.ContentAnalysisItem{
+					DetectionType:  "untagged_mention",
+					MatchedText:    content[idx:end],
+					EntityID:       &entityID,
+					Similarity:     &sim,
+					ContextSnippet: &snippet,
+					PositionStart:  &idx,
+					PositionEnd:    &end,
+					Resolution:     "pending",
+					Phase:          "identification",
+				})
+			}
 		}
 	}
 
@@ -351,30 +420,14 @@ func (a *Analyzer) scanMisspellings(
 		}
 
 		r := results[0]
-
-		// Skip fragments that cover less than half the entity name.
-		// These are typically just the opening words of a longer name
-		// (e.g., "Canticle of" matching "Canticle of Æternity").
-		if float64(len(phrase)) < float64(len(r.Name))*0.5 {
-			continue
-		}
-
-		if r.Similarity < similarityThresholdMinimum || r.Similarity >= similarityThresholdResolved {
+		detectionType, ok := classifyFuzzyMatch(phrase, r)
+		if !ok {
 			continue
 		}
 
 		snippet := extractContextSnippet(content, loc[0], loc[1])
 		entityID := r.ID
 
-		// Classify as alias or misspelling based on substring
-		// relationship.
-		detectionType := "misspelling"
-		lowerPhrase := strings.ToLower(phrase)
-		lowerEntityName := strings.ToLower(r.Name)
-		if strings.Contains(lowerEntityName, lowerPhrase) || strings.Contains(lowerPhrase, lowerEntityName) {
-			detectionType = "potential_alias"
-		}
-
 		items = append(items, models.ContentAnalysisItem{
 			DetectionType:  detectionType,
 			MatchedText:    phrase,
@@ -391,6 +444,37 @@ func (a *Analyzer) scanMisspellings(
 	return items
 }
 
+// classifyFuzzyMatch decides whether phrase, fuzzily matched to the
+// entity in r, is reported, and as what. The phrase is compared with
+// the alias it matched, if it matched one, rather than the entity
+// name, so that an exact alias is not taken for a misspelling. It is
+// reported as a "potential_alias" when it is a substring of the name
+// or vice versa, and as a "misspelling" otherwise.
+func classifyFuzzyMatch(phrase string, r models.EntityResolveResult) (string, bool) {
+	matchedName := r.Name
+	if r.MatchedAlias != nil {
+		matchedName = *r.MatchedAlias
+	}
+
+	// Skip fragments that cover less than half the entity name.
+	// These are typically just the opening words of a longer name
+	// (e.g., "Canticle of" matching "Canticle of Æternity").
+	if float64(len(phrase)) < float64(len(matchedName))*0.5 {
+		return "", false
+	}
+
+	if r.Similarity < similarityThresholdMinimum || r.Similarity >= similarityThresholdResolved {
+		return "", false
+	}
+
+	lowerPhrase := strings.ToLower(phrase)
+	lowerName := strings.ToLower(matchedName)
+	if strings.Contains(lowerName, lowerPhrase) || strings.Contains(lowerPhrase, lowerName) {
+		return "potential_alias", true
+	}
+	return "misspelling", true
+}
+
 // extractContextSnippet returns a substring of content surrounding the
 // range [start, end), padded by up to contextRadius characters on each
 // side. The returned string is clamped to content boundaries.
@@ -188,6 +188,125 @@ func TestBuildMatchedNames(t *testing.T) {
 	}
 }
 
+// TestMentionedEntityIDs verifies that resolved wiki links and
+// untagged mentions are merged into de-duplicated entity IDs, and that
+// suggestions are left out.
+func TestMentionedEntityIDs(t *testing.T) {
+	armitage, dagon, innsmouth := int64(1), int64(2), int64(3)
+	items := []models.ContentAnalysisItem{
+		{DetectionType: "untagged_mention", EntityID: &armitage},
+		{DetectionType: "untagged_mention", EntityID: &dagon},
+		{DetectionType: "untagged_mention"},
+		{DetectionType: "wiki_link_unresolved", EntityID: &innsmouth},
+		{DetectionType: "misspelling", EntityID: &innsmouth},
+	}
+
+	assert.Equal(t, []int64{2, 1}, mentionedEntityIDs([]int64{2, 2}, items))
+	assert.Nil(t, mentionedEntityIDs(nil, nil))
+}
+
+// TestFindUntaggedMentions_Aliases verifies that a mention of an alias
+// is reported as the canonical entity, that mentions inside wiki links
+// are skipped, and that an alias within a name mention of the same
+// entity is not reported twice.
+func TestFindUntaggedMentions_Aliases(t *testing.T) {
+	entities := []models.Entity{
+		{ID: 1, Name: "Henry Armitage"},
+		{ID: 2, Name: "Arkham"},
+	}
+	aliases := map[int64][]string{
+		1: {"the Librarian", "Armitage"},
+	}
+	content := "The Librarian met Henry Armitage in [[Arkham]]."
+	wikiRanges := wikiLinkOriginalRanges(content)
+
+	items := findUntaggedMentions(content, entities, aliases, wikiRanges)
+
+	require.Len(t, items, 2)
+	for _, item := range items {
+		assert.Equal(t, "untagged_mention", item.DetectionType)
+		require.NotNil(t, item.EntityID)
+		assert.Equal(t, int64(1), *item.EntityID)
+	}
+	assert.Equal(t, "Henry Armitage", items[0].MatchedText)
+	assert.Equal(t, "The Librarian", items[1].MatchedText)
+	assert.Equal(t, 0, *items[1].PositionStart)
+	assert.Equal(t, len("The Librarian"), *items[1].PositionEnd)
+}
+
+// TestClassifyFuzzyMatch verifies how fuzzy matches are reported,
+// comparing phrases that matched an alias with the alias rather than
+// the entity name.
+func TestClassifyFuzzyMatch(t *testing.T) {
+	alias := func(s string) *string { return &s }
+
+	tests := []struct {
+		name     string
+		phrase   string
+		result   models.EntityResolveResult
+		wantType string
+		wantOK   bool
+	}{
+		{
+			name:     "misspelled name",
+			phrase:   "Henry Armitige",
+			result:   models.EntityResolveResult{ID: 1, Name: "Henry Armitage", Similarity: 0.7},
+			wantType: "misspelling",
+			wantOK:   true,
+		},
+		{
+			name:     "part of name",
+			phrase:   "Henry Armit",
+			result:   models.EntityResolveResult{ID: 1, Name: "Henry Armitage", Similarity: 0.65},
+			wantType: "potential_alias",
+			wantOK:   true,
+		},
+		{
+			name:   "exact alias",
+			phrase: "Old Tom",
+			result: models.EntityResolveResult{
+				ID: 1, Name: "Thomas Blackwood", Similarity: 1.0, MatchedAlias: alias("Old Tom"),
+			},
+		},
+		{
+			name:   "misspelled short alias of a long name",
+			phrase: "Old Tomm",
+			result: models.EntityResolveResult{
+				ID: 1, Name: "Thomas Ezekiel Blackwood III", Similarity: 0.7, MatchedAlias: alias("Old Tom"),
+			},
+			wantType: "potential_alias",
+			wantOK:   true,
+		},
+		{
+			name:   "misspelled alias",
+			phrase: "Old Tam",
+			result: models.EntityResolveResult{
+				ID: 1, Name: "Thomas Blackwood", Similarity: 0.62, MatchedAlias: alias("Old Tom"),
+			},
+			wantType: "misspelling",
+			wantOK:   true,
+		},
+		{
+			name:   "fragment of a long name",
+			phrase: "Canticle",
+			result: models.EntityResolveResult{ID: 2, Name: "Canticle of Æternity", Similarity: 0.7},
+		},
+		{
+			name:   "below minimum similarity",
+			phrase: "Hannah Armstrong",
+			result: models.EntityResolveResult{ID: 1, Name: "Henry Armitage", Similarity: 0.4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotType, gotOK := classifyFuzzyMatch(tt.phrase, tt.result)
+			assert.Equal(t, tt.wantOK, gotOK)
+			assert.Equal(t, tt.wantType, gotType)
+		})
+	}
+}
+
 // TestWikiLinkRegex verifies the compiled wikiLinkRe pattern against
 // various wiki link formats and edge cases.
 func TestWikiLinkRegex(t *testing.T) {
@@ -0,0 +1,886 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antonypegg/imagineer/internal/agents"
+	"github.com/antonypegg/imagineer/internal/assistant"
+	"github.com/antonypegg/imagineer/internal/database"
+	"github.com/antonypegg/imagineer/internal/embedding"
+	"github.com/antonypegg/imagineer/internal/enrichment"
+	"github.com/antonypegg/imagineer/internal/jobs"
+	"github.com/antonypegg/imagineer/internal/llm"
+	"github.com/antonypegg/imagineer/internal/memory"
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// Background job kinds run by the job queue. Phase 1 content analysis
+// is not among them: it makes no LLM calls, creates its analysis job
+// only once its scans are done, and the triage UI shows its items as
+// soon as the request returns, so it runs in the request.
+const (
+	jobKindEnrichment       = "enrichment"
+	jobKindRevision         = "revision"
+	jobKindChapterMemory    = "chapter_memory"
+	jobKindSessionMemory    = "session_memory"
+	jobKindEntityExtraction = "entity_extraction"
+	jobKindChatCompression  = "chat_compression"
+)
+
+// memoryContextLimit is the number of campaign memories given to the
+// chapter summarizer and session extractor for context.
+const memoryContextLimit = 10
+
+// sessionMemorySourceField is the source field of the content analysis
+// job that holds a session's extracted memories for review. It is not
+// a column: the memories are drawn from the whole session.
+const sessionMemorySourceField = "session_memories"
+
+// revisionTimeout bounds revision generation. A client waits for the
+// revision synchronously, so it is not retried through the queue.
+const revisionTimeout = 3 * time.Minute
+
+var (
+	errLLMNotConfigured = errors.New("no LLM configured")
+	errBudgetExceeded   = errors.New("campaign exceeded its monthly token budget")
+)
+
+// enrichmentJobPayload is the payload of an enrichment background job.
+// The content analysis job it enriches is the background job's
+// AnalysisJobID.
+type enrichmentJobPayload struct {
+	UserID int64 `json:"userId"`
+
+	// Content is the text to enrich. When empty it is read from the
+	// analysis job's source field when the job runs.
+	Content string `json:"content,omitempty"`
+
+	// EntityIDs lists the accepted Phase 1 entities to enrich, if any.
+	EntityIDs []int64 `json:"entityIds,omitempty"`
+}
+
+// revisionJobPayload is the payload of a revision background job.
+type revisionJobPayload struct {
+	UserID          int64  `json:"userId"`
+	OriginalContent string `json:"originalContent"`
+}
+
+// chapterMemoryJobPayload is the payload of a chapter memory background
+// job. The chapter's campaign is the background job's CampaignID.
+type chapterMemoryJobPayload struct {
+	UserID    int64 `json:"userId"`
+	ChapterID int64 `json:"chapterId"`
+}
+
+// sessionMemoryJobPayload is the payload of a session memory background
+// job. The content analysis job that receives the extracted memories
+// for review is the background job's AnalysisJobID.
+type sessionMemoryJobPayload struct {
+	UserID    int64 `json:"userId"`
+	SessionID int64 `json:"sessionId"`
+}
+
+// entityExtractionJobPayload is the payload of an entity extraction
+// background job. The session's campaign is the background job's
+// CampaignID.
+type entityExtractionJobPayload struct {
+	UserID    int64                         `json:"userId"`
+	SessionID int64                         `json:"sessionId"`
+	Source    models.EntityExtractionSource `json:"source"`
+	Text      string                        `json:"text"`
+}
+
+// chatCompressionJobPayload is the payload of a chat compression
+// background job.
+type chatCompressionJobPayload struct {
+	UserID    int64 `json:"userId"`
+	SessionID int64 `json:"sessionId"`
+}
+
+// RegisterJobKinds registers the handlers for the background job kinds
+// used by the API with queue.
+func RegisterJobKinds(queue *jobs.Queue, db *database.DB) {
+	queue.Register(jobKindEnrichment, jobs.Kind{
+		Handler: func(ctx context.Context, job *models.BackgroundJob) (any, error) {
+			return runEnrichmentJob(ctx, db, job)
+		},
+		Timeout: 10 * time.Minute,
+		OnFailure: func(ctx context.Context, job *models.BackgroundJob, err error) {
+			failEnrichmentJob(ctx, db, job, err)
+		},
+	})
+	queue.Register(jobKindRevision, jobs.Kind{
+		Handler: func(ctx context.Context, job *models.BackgroundJob) (any, error) {
+			return runRevisionJob(ctx, db, job)
+		},
+		Timeout:     revisionTimeout,
+		MaxAttempts: 1,
+	})
+	queue.Register(jobKindChapterMemory, jobs.Kind{
+		Handler: func(ctx context.Context, job *models.BackgroundJob) (any, error) {
+			return runChapterMemoryJob(ctx, db, job)
+		},
+		Timeout: 5 * time.Minute,
+	})
+	queue.Register(jobKindSessionMemory, jobs.Kind{
+		Handler: func(ctx context.Context, job *models.BackgroundJob) (any, error) {
+			return runSessionMemoryJob(ctx, db, job)
+		},
+		Timeout: 5 * time.Minute,
+		OnFailure: func(ctx context.Context, job *models.BackgroundJob, err error) {
+			failEnrichmentJob(ctx, db, job, err)
+		},
+	})
+	queue.Register(jobKindEntityExtraction, jobs.Kind{
+		Handler: func(ctx context.Context, job *models.BackgroundJob) (any, error) {
+			return runEntityExtractionJob(ctx, db, job)
+		},
+		Timeout:     2 * time.Minute,
+		MaxAttempts: 2,
+	})
+	queue.Register(jobKindChatCompression, jobs.Kind{
+		Handler: func(ctx context.Context, job *models.BackgroundJob) (any, error) {
+			return runChatCompressionJob(ctx, db, job)
+		},
+		Timeout: 2 * time.Minute,
+	})
+}
+
+// enqueueEnrichment queues enrichment for a content analysis job that
+// has already been set to the enriching status. If the job cannot be
+// queued the analysis job is marked failed so it does not stay
+// enriching forever.
+func enqueueEnrichment(
+	ctx context.Context,
+	db *database.DB,
+	queue *jobs.Queue,
+	campaignID int64,
+	jobID int64,
+	payload enrichmentJobPayload,
+) error {
+	if queue == nil {
+		return fmt.Errorf("job queue is not running")
+	}
+	if _, err := queue.Enqueue(ctx, jobKindEnrichment, &campaignID, &jobID, payload); err != nil {
+		if dbErr := db.SetJobFailureReason(context.WithoutCancel(ctx), jobID,
+			"Failed to queue enrichment"); dbErr != nil {
+			log.Printf("Failed to mark job %d failed: %v", jobID, dbErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// runEnrichmentJob runs the enrichment pipeline for the content
+// analysis job attached to an enrichment background job, saving each
+// agent's items and publishing progress events as the pipeline runs.
+// Everything the pipeline needs is loaded when the job runs, so a job
+// that is retried or resumed after a restart sees the current settings
+// and data.
+func runEnrichmentJob(ctx context.Context, db *database.DB, bg *models.BackgroundJob) (any, error) {
+	var payload enrichmentJobPayload
+	if err := json.Unmarshal(bg.Payload, &payload); err != nil {
+		return nil, jobs.Permanent(fmt.Errorf("invalid enrichment payload: %w", err))
+	}
+	if bg.AnalysisJobID == nil {
+		return nil, jobs.Permanent(errors.New("enrichment job has no analysis job"))
+	}
+	jobID := *bg.AnalysisJobID
+
+	// Items are tagged with the background job ID as their pipeline
+	// run, so that a retry can discard what an earlier attempt saved.
+	if bg.Attempts > 1 {
+		if err := db.DiscardEnrichmentRun(ctx, jobID, bg.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	job, err := db.GetAnalysisJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := db.GetUserSettings(ctx, payload.UserID)
+	if err != nil {
+		return nil, err
+	}
+	apiKey, configured := contentGenAPIKey(settings)
+	if !configured {
+		return nil, jobs.Permanent(errLLMNotConfigured)
+	}
+	if campaignBudgetExceeded(ctx, db, job.CampaignID) {
+		return nil, jobs.Permanent(errBudgetExceeded)
+	}
+
+	provider, err := newContentGenProvider(db, settings, apiKey, job.CampaignID, &jobID)
+	if err != nil {
+		return nil, jobs.Permanent(fmt.Errorf("failed to create LLM provider: %w", err))
+	}
+
+	// Optional embedder for semantic search without pgedge_vectorizer.
+	embedder := embedding.EmbedderForSettings(settings)
+
+	content := payload.Content
+	if content == "" {
+		content, err = fetchSourceContent(
+			ctx, db, job.CampaignID, job.SourceTable, job.SourceID, job.SourceField,
+		)
+		if err != nil {
+			return nil, jobs.Permanent(fmt.Errorf("failed to fetch source content: %w", err))
+		}
+	}
+
+	entities := make([]models.Entity, 0, len(payload.EntityIDs))
+	for _, eid := range payload.EntityIDs {
+		entity, err := db.GetEntity(ctx, eid)
+		if err != nil {
+			log.Printf("Enrichment: failed to get entity %d: %v", eid, err)
+			continue
+		}
+		// Strip GM-only content before passing to the pipeline.
+		entity.GMNotes = nil
+		entities = append(entities, *entity)
+	}
+
+	// Look up the campaign to get its game system for RAG context.
+	campaign, campaignErr := db.GetCampaign(ctx, job.CampaignID)
+	var gameSystemCode string
+	var gameSystemID *int64
+	if campaignErr != nil {
+		log.Printf("Enrichment: failed to get campaign %d: %v",
+			job.CampaignID, campaignErr)
+	} else {
+		gameSystemID = campaign.SystemID
+		if campaign.System != nil {
+			gameSystemCode = campaign.System.Code
+		}
+	}
+
+	log.Printf("Enrichment: starting enrichment for job %d with %d entities",
+		jobID, len(entities))
+
+	ctxBuilder := enrichment.NewContextBuilder(db, "").
+		WithEmbedder(embedder)
+	ragCtx, ragErr := ctxBuilder.BuildContext(ctx, job.CampaignID, content, gameSystemCode, entities)
+	if ragErr != nil {
+		log.Printf("Enrichment: failed to build RAG context for job %d: %v",
+			jobID, ragErr)
+	}
+
+	// Load relationships so the graph expert receives complete data.
+	relationships, relErr := db.ListRelationshipsByCampaign(ctx, job.CampaignID)
+	if relErr != nil {
+		log.Printf("Enrichment: failed to load relationships for job %d: %v",
+			jobID, relErr)
+		// Continue without relationships rather than blocking
+		// enrichment.
+	}
+
+	input := enrichment.PipelineInput{
+		CampaignID:    job.CampaignID,
+		JobID:         jobID,
+		SourceTable:   job.SourceTable,
+		SourceID:      job.SourceID,
+		SourceScope:   enrichment.ScopeFromSourceTable(job.SourceTable),
+		Content:       content,
+		Entities:      entities,
+		Relationships: relationships,
+		GameSystemID:  gameSystemID,
+		Context:       ragCtx,
+		Ontology:      db.Ontology,
+		Embedder:      embedder,
+	}
+
+	progress := &enrichmentProgress{
+		db:    db,
+		jobID: jobID,
+		runID: bg.ID,
+		total: job.EnrichmentTotal,
+	}
+	enrichItems, err := runEnrichmentPipeline(ctx, buildDefaultPipeline(db).WithObserver(progress), provider, input)
+	if err != nil {
+		return nil, err
+	}
+	if err := progress.Err(); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Enrichment: completed enrichment for job %d", jobID)
+	if err := db.CompleteAnalysisJob(ctx, jobID, false); err != nil {
+		log.Printf("Enrichment: failed to set job %d status to completed: %v",
+			jobID, err)
+	}
+
+	return map[string]int{"itemCount": len(enrichItems)}, nil
+}
+
+// runEnrichmentPipeline runs the pipeline of an enrichment job. A
+// failing agent fails the job, so that the queue retries it, unless
+// the provider's quota is exhausted, which a retry cannot fix.
+func runEnrichmentPipeline(
+	ctx context.Context,
+	pipeline *enrichment.Pipeline,
+	provider llm.Provider,
+	input enrichment.PipelineInput,
+) ([]models.ContentAnalysisItem, error) {
+	items, err := pipeline.Run(ctx, provider, input)
+	if err != nil {
+		var qe *llm.QuotaExceededError
+		if errors.As(err, &qe) {
+			return nil, jobs.Permanent(err)
+		}
+		return nil, fmt.Errorf("enrichment pipeline failed: %w", err)
+	}
+	return items, nil
+}
+
+// enrichmentProgress is the pipeline Observer of an enrichment job. It
+// saves each agent's items as soon as the agent finishes and publishes
+// phase changes, so that clients streaming the job see results while
+// the rest of the pipeline is still running.
+type enrichmentProgress struct {
+	db    *database.DB
+	jobID int64
+	runID int64
+
+	mu    sync.Mutex
+	total int
+	err   error
+}
+
+// StageStarted publishes a phase_changed event for the stage.
+func (p *enrichmentProgress) StageStarted(ctx context.Context, stage enrichment.Stage) {
+	p.mu.Lock()
+	event := models.PhaseChangedEvent{
+		Stage:           stage.Name,
+		Phase:           stage.Phase,
+		EnrichmentTotal: p.total,
+	}
+	p.mu.Unlock()
+
+	if err := p.db.PublishAnalysisJobEvent(ctx, p.jobID, models.AnalysisEventPhaseChanged, event); err != nil {
+		log.Printf("Enrichment: failed to publish phase change for job %d: %v", p.jobID, err)
+	}
+}
+
+// AgentFinished saves the items an agent produced, which also
+// publishes an item_created event for each of them.
+func (p *enrichmentProgress) AgentFinished(
+	ctx context.Context,
+	_ enrichment.Stage,
+	agentName string,
+	items []models.ContentAnalysisItem,
+) {
+	if len(items) == 0 {
+		return
+	}
+
+	runID := p.runID
+	tagged := make([]models.ContentAnalysisItem, len(items))
+	for i, item := range items {
+		item.PipelineRunID = &runID
+		tagged[i] = item
+	}
+
+	saved, err := p.db.AddEnrichmentItems(ctx, p.jobID, tagged)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err != nil {
+		log.Printf("Enrichment: failed to save items from %s for job %d: %v",
+			agentName, p.jobID, err)
+		if p.err == nil {
+			p.err = fmt.Errorf("failed to save enrichment items: %w", err)
+		}
+		return
+	}
+	p.total += len(saved)
+}
+
+// Err returns the first error encountered while saving items.
+func (p *enrichmentProgress) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+// failEnrichmentJob marks the content analysis job of an enrichment
+// background job that has failed for good as failed, with a reason the
+// frontend can show.
+func failEnrichmentJob(ctx context.Context, db *database.DB, bg *models.BackgroundJob, err error) {
+	if bg.AnalysisJobID == nil {
+		return
+	}
+	if dbErr := db.SetJobFailureReason(ctx, *bg.AnalysisJobID, enrichmentFailureReason(err)); dbErr != nil {
+		log.Printf("Enrichment: failed to mark job %d failed: %v", *bg.AnalysisJobID, dbErr)
+	}
+}
+
+// enrichmentFailureReason converts an enrichment error into a
+// human-readable failure reason.
+func enrichmentFailureReason(err error) string {
+	var qe *llm.QuotaExceededError
+	switch {
+	case errors.As(err, &qe):
+		return "API quota exceeded"
+	case errors.Is(err, errBudgetExceeded):
+		return "Monthly token budget exceeded"
+	case errors.Is(err, errLLMNotConfigured):
+		return "LLM service not configured"
+	case strings.Contains(err.Error(), "rate limit"):
+		return "Rate limited after retries"
+	default:
+		return "Enrichment encountered an error"
+	}
+}
+
+// runRevisionJob generates a revision of the source content of the
+// content analysis job attached to a revision background job,
+// incorporating its acknowledged analysis findings.
+func runRevisionJob(ctx context.Context, db *database.DB, bg *models.BackgroundJob) (any, error) {
+	var payload revisionJobPayload
+	if err := json.Unmarshal(bg.Payload, &payload); err != nil {
+		return nil, jobs.Permanent(fmt.Errorf("invalid revision payload: %w", err))
+	}
+	if bg.AnalysisJobID == nil {
+		return nil, jobs.Permanent(errors.New("revision job has no analysis job"))
+	}
+	jobID := *bg.AnalysisJobID
+
+	job, err := db.GetAnalysisJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	acceptedItems, err := db.ListAnalysisItemsByJob(ctx, jobID, "acknowledged", "analysis")
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := db.GetUserSettings(ctx, payload.UserID)
+	if err != nil {
+		return nil, err
+	}
+	apiKey, configured := contentGenAPIKey(settings)
+	if !configured {
+		return nil, jobs.Permanent(errLLMNotConfigured)
+	}
+	if campaignBudgetExceeded(ctx, db, job.CampaignID) {
+		return nil, jobs.Permanent(errBudgetExceeded)
+	}
+
+	provider, err := newContentGenProvider(db, settings, apiKey, job.CampaignID, &jobID)
+	if err != nil {
+		return nil, jobs.Permanent(fmt.Errorf("failed to create LLM provider: %w", err))
+	}
+	embedder := embedding.EmbedderForSettings(settings)
+
+	// Build RAG context for the revision agent.
+	campaign, campaignErr := db.GetCampaign(ctx, job.CampaignID)
+	var gameSystemCode string
+	if campaignErr != nil {
+		log.Printf("Revision: failed to get campaign %d: %v",
+			job.CampaignID, campaignErr)
+	} else if campaign.System != nil {
+		gameSystemCode = campaign.System.Code
+	}
+
+	ctxBuilder := enrichment.NewContextBuilder(db, "").
+		WithEmbedder(embedder)
+	ragCtx, ragErr := ctxBuilder.BuildContext(
+		ctx, job.CampaignID, payload.OriginalContent,
+		gameSystemCode, nil)
+	if ragErr != nil {
+		log.Printf("Revision: failed to build RAG context for job %d: %v",
+			jobID, ragErr)
+	}
+
+	revisionInput := enrichment.RevisionInput{
+		OriginalContent: payload.OriginalContent,
+		AcceptedItems:   acceptedItems,
+		SourceTable:     job.SourceTable,
+		SourceID:        job.SourceID,
+		Context:         ragCtx,
+	}
+
+	revision, err := enrichment.NewRevisionAgent().GenerateRevision(ctx, provider, revisionInput)
+	if err != nil {
+		var qe *llm.QuotaExceededError
+		if errors.As(err, &qe) {
+			return nil, jobs.Permanent(err)
+		}
+		return nil, err
+	}
+	return revision, nil
+}
+
+// runChapterMemoryJob regenerates the summary and active threads of a
+// chapter memory from the wrap-up notes of the chapter's sessions. The
+// chapter's goals and current flag are left untouched.
+func runChapterMemoryJob(ctx context.Context, db *database.DB, bg *models.BackgroundJob) (any, error) {
+	var payload chapterMemoryJobPayload
+	if err := json.Unmarshal(bg.Payload, &payload); err != nil {
+		return nil, jobs.Permanent(fmt.Errorf("invalid chapter memory payload: %w", err))
+	}
+	if bg.CampaignID == nil {
+		return nil, jobs.Permanent(errors.New("chapter memory job has no campaign"))
+	}
+	campaignID := *bg.CampaignID
+
+	chapter, err := db.GetChapter(ctx, payload.ChapterID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, jobs.Permanent(err)
+		}
+		return nil, err
+	}
+	if chapter.CampaignID != campaignID {
+		return nil, jobs.Permanent(fmt.Errorf("chapter %d is not in campaign %d",
+			chapter.ID, campaignID))
+	}
+
+	input := memory.ChapterInput{ChapterTitle: chapter.Title}
+	if chapter.Overview != nil {
+		input.Overview = *chapter.Overview
+	}
+
+	existing, err := db.GetChapterMemory(ctx, chapter.ID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	if existing != nil {
+		input.Goals = existing.Goals
+		input.ActiveThreads = existing.ActiveThreads
+	}
+
+	sessions, err := db.ListSessionsByChapter(ctx, chapter.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		cs := memory.ChapterSession{Number: session.SessionNumber}
+		if session.Title != nil {
+			cs.Title = *session.Title
+		}
+		if session.ActualNotes != nil {
+			cs.Notes = *session.ActualNotes
+		}
+		input.Sessions = append(input.Sessions, cs)
+	}
+
+	input.Memories, err = db.ListTopCampaignMemories(ctx, campaignID, memoryContextLimit)
+	if err != nil {
+		log.Printf("Chapter memory: failed to load campaign memories for campaign %d: %v",
+			campaignID, err)
+	}
+
+	settings, err := db.GetUserSettings(ctx, payload.UserID)
+	if err != nil {
+		return nil, err
+	}
+	apiKey, configured := contentGenAPIKey(settings)
+	if !configured {
+		return nil, jobs.Permanent(errLLMNotConfigured)
+	}
+	if campaignBudgetExceeded(ctx, db, campaignID) {
+		return nil, jobs.Permanent(errBudgetExceeded)
+	}
+
+	provider, err := newContentGenProvider(db, settings, apiKey, campaignID, nil)
+	if err != nil {
+		return nil, jobs.Permanent(fmt.Errorf("failed to create LLM provider: %w", err))
+	}
+
+	result, err := memory.NewChapterSummarizer().Summarize(ctx, provider, input)
+	if err != nil {
+		if errors.Is(err, memory.ErrNoSessionNotes) {
+			return nil, jobs.Permanent(err)
+		}
+		return nil, err
+	}
+
+	return db.SaveChapterMemory(ctx, campaignID, chapter.ID, models.SaveChapterMemoryRequest{
+		ActiveThreads: result.ActiveThreads,
+		Summary:       &result.Summary,
+	})
+}
+
+// runSessionMemoryJob extracts the memories of a session from its
+// notes, scenes and chat messages and saves them as pending
+// session_memory items on the attached content analysis job, where the
+// GM reviews them. Accepted items become session memories.
+func runSessionMemoryJob(ctx context.Context, db *database.DB, bg *models.BackgroundJob) (any, error) {
+	var payload sessionMemoryJobPayload
+	if err := json.Unmarshal(bg.Payload, &payload); err != nil {
+		return nil, jobs.Permanent(fmt.Errorf("invalid session memory payload: %w", err))
+	}
+	if bg.AnalysisJobID == nil {
+		return nil, jobs.Permanent(errors.New("session memory job has no analysis job"))
+	}
+	jobID := *bg.AnalysisJobID
+
+	if bg.Attempts > 1 {
+		if err := db.DiscardEnrichmentRun(ctx, jobID, bg.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	// The analysis job is deleted if the session is extracted again
+	// while this job is waiting.
+	job, err := db.GetAnalysisJob(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, jobs.Permanent(err)
+		}
+		return nil, err
+	}
+
+	session, err := db.GetSession(ctx, payload.SessionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, jobs.Permanent(err)
+		}
+		return nil, err
+	}
+	if session.CampaignID != job.CampaignID {
+		return nil, jobs.Permanent(fmt.Errorf("session %d is not in campaign %d",
+			session.ID, job.CampaignID))
+	}
+
+	input := memory.SessionInput{Number: session.SessionNumber}
+	if session.Title != nil {
+		input.Title = *session.Title
+	}
+	if session.PrepNotes != nil {
+		input.PrepNotes = *session.PrepNotes
+	}
+	if session.PlayNotes != nil {
+		input.PlayNotes = *session.PlayNotes
+	}
+	if session.ActualNotes != nil {
+		input.ActualNotes = *session.ActualNotes
+	}
+
+	if input.Scenes, err = db.ListScenesBySession(ctx, session.ID); err != nil {
+		return nil, err
+	}
+	if input.ChatMessages, err = db.ListSessionChatMessages(ctx, session.ID); err != nil {
+		return nil, err
+	}
+	if input.Entities, err = db.ListEntitiesByCampaign(ctx, job.CampaignID); err != nil {
+		return nil, err
+	}
+	input.Memories, err = db.ListTopCampaignMemories(ctx, job.CampaignID, memoryContextLimit)
+	if err != nil {
+		log.Printf("Session memory: failed to load campaign memories for campaign %d: %v",
+			job.CampaignID, err)
+	}
+
+	settings, err := db.GetUserSettings(ctx, payload.UserID)
+	if err != nil {
+		return nil, err
+	}
+	apiKey, configured := contentGenAPIKey(settings)
+	if !configured {
+		return nil, jobs.Permanent(errLLMNotConfigured)
+	}
+	if campaignBudgetExceeded(ctx, db, job.CampaignID) {
+		return nil, jobs.Permanent(errBudgetExceeded)
+	}
+
+	provider, err := newContentGenProvider(db, settings, apiKey, job.CampaignID, &jobID)
+	if err != nil {
+		return nil, jobs.Permanent(fmt.Errorf("failed to create LLM provider: %w", err))
+	}
+
+	suggestions, err := memory.NewSessionExtractor().Extract(ctx, provider, input)
+	if err != nil && !errors.Is(err, memory.ErrNoSessionContent) {
+		var qe *llm.QuotaExceededError
+		if errors.As(err, &qe) {
+			return nil, jobs.Permanent(err)
+		}
+		return nil, err
+	}
+
+	runID := bg.ID
+	items := make([]models.ContentAnalysisItem, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		content, err := json.Marshal(suggestion)
+		if err != nil {
+			return nil, jobs.Permanent(fmt.Errorf("failed to marshal session memory: %w", err))
+		}
+		matched := suggestion.Title
+		if matched == "" {
+			matched = agents.TruncateString(suggestion.Content, 80)
+		}
+		items = append(items, models.ContentAnalysisItem{
+			JobID:            jobID,
+			DetectionType:    models.ItemTypeSessionMemory,
+			MatchedText:      matched,
+			Resolution:       "pending",
+			SuggestedContent: content,
+			Phase:            "enrichment",
+			AgentName:        memory.AgentName,
+			PipelineRunID:    &runID,
+		})
+	}
+	if _, err := db.AddEnrichmentItems(ctx, jobID, items); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Session memory: extracted %d memories from session %d for job %d",
+		len(items), session.ID, jobID)
+	if err := db.CompleteAnalysisJob(ctx, jobID, false); err != nil {
+		log.Printf("Session memory: failed to set job %d status to completed: %v",
+			jobID, err)
+	}
+
+	return map[string]int{"itemCount": len(items)}, nil
+}
+
+// runEntityExtractionJob detects the entities in a fragment of text
+// saved during play and queues them in the session's entity
+// extractions for the GM to review.
+func runEntityExtractionJob(ctx context.Context, db *database.DB, bg *models.BackgroundJob) (any, error) {
+	var payload entityExtractionJobPayload
+	if err := json.Unmarshal(bg.Payload, &payload); err != nil {
+		return nil, jobs.Permanent(fmt.Errorf("invalid entity extraction payload: %w", err))
+	}
+
+	session, err := db.GetSession(ctx, payload.SessionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, jobs.Permanent(err)
+		}
+		return nil, err
+	}
+
+	entities, err := db.ListEntitiesByCampaign(ctx, session.CampaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := db.GetUserSettings(ctx, payload.UserID)
+	if err != nil {
+		return nil, err
+	}
+	apiKey, configured := contentGenAPIKey(settings)
+	if !configured {
+		return nil, jobs.Permanent(errLLMNotConfigured)
+	}
+	if campaignBudgetExceeded(ctx, db, session.CampaignID) {
+		return nil, jobs.Permanent(errBudgetExceeded)
+	}
+
+	provider, err := newContentGenProvider(db, settings, apiKey, session.CampaignID, nil)
+	if err != nil {
+		return nil, jobs.Permanent(fmt.Errorf("failed to create LLM provider: %w", err))
+	}
+
+	proposals, err := memory.NewEntityExtractor().Extract(ctx, provider, memory.EntityInput{
+		Source:   payload.Source,
+		Text:     payload.Text,
+		Entities: entities,
+	})
+	if err != nil {
+		var qe *llm.QuotaExceededError
+		if errors.Is(err, memory.ErrNoEntityText) || errors.As(err, &qe) {
+			return nil, jobs.Permanent(err)
+		}
+		return nil, err
+	}
+
+	extractions, err := db.CreateMemoryEntityExtractions(ctx, session.ID, payload.Source, proposals)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Entity extraction: queued %d of %d entities from session %d %s",
+		len(extractions), len(proposals), session.ID, payload.Source)
+	return map[string]int{"extractionCount": len(extractions)}, nil
+}
+
+// runChatCompressionJob folds the older turns of a session's play
+// assistant chat into a new rolling summary. The chat is read when the
+// job runs, so a job queued after another has already folded the same
+// turns does nothing.
+func runChatCompressionJob(ctx context.Context, db *database.DB, bg *models.BackgroundJob) (any, error) {
+	var payload chatCompressionJobPayload
+	if err := json.Unmarshal(bg.Payload, &payload); err != nil {
+		return nil, jobs.Permanent(fmt.Errorf("invalid chat compression payload: %w", err))
+	}
+
+	session, err := db.GetSession(ctx, payload.SessionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, jobs.Permanent(err)
+		}
+		return nil, err
+	}
+
+	summary, err := db.GetLatestSessionChatSummary(ctx, session.ID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	messages, err := db.ListSessionChatMessages(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(assistant.MessagesToCompress(summary, messages)) == 0 {
+		return map[string]int{"messageCount": 0}, nil
+	}
+
+	settings, err := db.GetUserSettings(ctx, payload.UserID)
+	if err != nil {
+		return nil, err
+	}
+	apiKey, configured := contentGenAPIKey(settings)
+	if !configured {
+		return nil, jobs.Permanent(errLLMNotConfigured)
+	}
+	if campaignBudgetExceeded(ctx, db, session.CampaignID) {
+		return nil, jobs.Permanent(errBudgetExceeded)
+	}
+
+	provider, err := newContentGenProvider(db, settings, apiKey, session.CampaignID, nil)
+	if err != nil {
+		return nil, jobs.Permanent(fmt.Errorf("failed to create LLM provider: %w", err))
+	}
+
+	next, err := assistant.NewCompressor().Compress(ctx, provider, summary, messages)
+	if err != nil {
+		var qe *llm.QuotaExceededError
+		if errors.As(err, &qe) {
+			return nil, jobs.Permanent(err)
+		}
+		return nil, err
+	}
+
+	saved, err := db.CreateSessionChatSummary(ctx, session.ID,
+		next.Summary, next.ThroughSortOrder, next.MessageCount)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Chat compression: summarized %d messages of session %d",
+		saved.MessageCount, session.ID)
+	return map[string]int{"messageCount": saved.MessageCount}, nil
+}
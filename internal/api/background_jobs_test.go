@@ -0,0 +1,87 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/enrichment"
+	"github.com/antonypegg/imagineer/internal/jobs"
+	"github.com/antonypegg/imagineer/internal/llm"
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubAgent is a pipeline agent that returns fixed items or an error.
+type stubAgent struct {
+	name  string
+	items []models.ContentAnalysisItem
+	err   error
+}
+
+func (a *stubAgent) Name() string        { return a.name }
+func (a *stubAgent) DependsOn() []string { return nil }
+
+func (a *stubAgent) Run(
+	_ context.Context,
+	_ llm.Provider,
+	_ enrichment.PipelineInput,
+) ([]models.ContentAnalysisItem, error) {
+	return a.items, a.err
+}
+
+func newStubPipeline(agents ...enrichment.PipelineAgent) *enrichment.Pipeline {
+	return enrichment.NewPipeline(nil, []enrichment.Stage{
+		{Name: "analysis", Phase: "analysis", Agents: agents},
+	})
+}
+
+func TestRunEnrichmentPipeline_FailingAgentIsRetried(t *testing.T) {
+	pipeline := newStubPipeline(
+		&stubAgent{name: "ttrpg-expert", items: []models.ContentAnalysisItem{{MatchedText: "a"}}},
+		&stubAgent{name: "canon-expert", err: &llm.ServerError{StatusCode: 503, Err: errors.New("overloaded")}},
+	)
+
+	items, err := runEnrichmentPipeline(context.Background(), pipeline, nil, enrichment.PipelineInput{})
+
+	require.Error(t, err)
+	assert.Nil(t, items)
+	assert.False(t, jobs.IsPermanent(err), "a transient agent failure should be retried")
+	var se *llm.ServerError
+	assert.ErrorAs(t, err, &se)
+}
+
+func TestRunEnrichmentPipeline_QuotaErrorIsPermanent(t *testing.T) {
+	pipeline := newStubPipeline(
+		&stubAgent{name: "ttrpg-expert", err: &llm.QuotaExceededError{Provider: "openai", Message: "quota"}},
+	)
+
+	_, err := runEnrichmentPipeline(context.Background(), pipeline, nil, enrichment.PipelineInput{})
+
+	require.Error(t, err)
+	assert.True(t, jobs.IsPermanent(err), "an exhausted quota should not be retried")
+	assert.Equal(t, "API quota exceeded", enrichmentFailureReason(err))
+}
+
+func TestRunEnrichmentPipeline_Success(t *testing.T) {
+	pipeline := newStubPipeline(
+		&stubAgent{name: "ttrpg-expert", items: []models.ContentAnalysisItem{{MatchedText: "a"}}},
+	)
+
+	items, err := runEnrichmentPipeline(context.Background(), pipeline, nil, enrichment.PipelineInput{})
+
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "ttrpg-expert", items[0].AgentName)
+}
@@ -0,0 +1,354 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/antonypegg/imagineer/internal/auth"
+	"github.com/antonypegg/imagineer/internal/database"
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// Bounds of the importance of a campaign memory, as enforced by the
+// campaign_memories check constraint.
+const (
+	minMemoryImportance = 1
+	maxMemoryImportance = 10
+)
+
+// validMemoryTypes lists the accepted campaign memory types.
+var validMemoryTypes = map[models.MemoryType]bool{
+	models.MemoryTypePremise:        true,
+	models.MemoryTypeTheme:          true,
+	models.MemoryTypeFactionSummary: true,
+	models.MemoryTypePlotThread:     true,
+	models.MemoryTypeGMNote:         true,
+}
+
+// CampaignMemoryHandler handles campaign memory CRUD API requests.
+type CampaignMemoryHandler struct {
+	db *database.DB
+}
+
+// NewCampaignMemoryHandler creates a new CampaignMemoryHandler.
+func NewCampaignMemoryHandler(db *database.DB) *CampaignMemoryHandler {
+	return &CampaignMemoryHandler{db: db}
+}
+
+// parseCampaignMemoryFilter reads the optional type, minImportance and
+// spoiler query parameters of a memory listing.
+func parseCampaignMemoryFilter(r *http.Request) (models.CampaignMemoryFilter, error) {
+	var filter models.CampaignMemoryFilter
+	query := r.URL.Query()
+
+	if memoryType := query.Get("type"); memoryType != "" {
+		if !validMemoryTypes[models.MemoryType(memoryType)] {
+			return filter, fmt.Errorf("invalid memory type %q", memoryType)
+		}
+		filter.MemoryType = models.MemoryType(memoryType)
+	}
+
+	if value := query.Get("minImportance"); value != "" {
+		importance, err := strconv.Atoi(value)
+		if err != nil || !validMemoryImportance(importance) {
+			return filter, fmt.Errorf("minImportance must be between %d and %d",
+				minMemoryImportance, maxMemoryImportance)
+		}
+		filter.MinImportance = importance
+	}
+
+	if value := query.Get("spoiler"); value != "" {
+		spoiler, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, fmt.Errorf("spoiler must be true or false")
+		}
+		filter.IsSpoiler = &spoiler
+	}
+
+	return filter, nil
+}
+
+// validMemoryImportance reports whether importance is within the
+// accepted range.
+func validMemoryImportance(importance int) bool {
+	return importance >= minMemoryImportance && importance <= maxMemoryImportance
+}
+
+// validateUpdateCampaignMemoryRequest checks the fields of an update
+// request that are set.
+func validateUpdateCampaignMemoryRequest(req models.UpdateCampaignMemoryRequest) error {
+	if req.MemoryType != nil && !validMemoryTypes[*req.MemoryType] {
+		return fmt.Errorf("invalid memory type %q", *req.MemoryType)
+	}
+	if req.Content != nil && *req.Content == "" {
+		return fmt.Errorf("content must not be empty")
+	}
+	if req.Importance != nil && !validMemoryImportance(*req.Importance) {
+		return fmt.Errorf("importance must be between %d and %d",
+			minMemoryImportance, maxMemoryImportance)
+	}
+	return nil
+}
+
+// validateCreateCampaignMemoryRequest checks the fields of a create
+// request.
+func validateCreateCampaignMemoryRequest(req models.CreateCampaignMemoryRequest) error {
+	if req.MemoryType == "" {
+		return fmt.Errorf("memoryType is required")
+	}
+	if req.Content == "" {
+		return fmt.Errorf("content is required")
+	}
+	return validateUpdateCampaignMemoryRequest(models.UpdateCampaignMemoryRequest{
+		MemoryType: &req.MemoryType,
+		Importance: req.Importance,
+	})
+}
+
+// authorizeCampaign parses the campaign ID and checks that the
+// authenticated user owns the campaign. Returns false and writes an
+// error response if the check fails.
+func (h *CampaignMemoryHandler) authorizeCampaign(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	campaignID, err := parseInt64(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid campaign ID")
+		return 0, false
+	}
+
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Authentication required")
+		return 0, false
+	}
+
+	if err := h.db.VerifyCampaignOwnership(r.Context(), campaignID, userID); err != nil {
+		respondError(w, http.StatusNotFound, "Campaign not found")
+		return 0, false
+	}
+
+	return campaignID, true
+}
+
+// getCampaignMemory loads the memory named by the memoryId URL
+// parameter and checks that it belongs to the campaign. Returns false
+// and writes an error response if the check fails.
+func (h *CampaignMemoryHandler) getCampaignMemory(
+	w http.ResponseWriter,
+	r *http.Request,
+	campaignID int64,
+) (*models.CampaignMemory, bool) {
+	memoryID, err := parseInt64(r, "memoryId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid memory ID")
+		return nil, false
+	}
+
+	memory, err := h.db.GetCampaignMemory(r.Context(), memoryID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "Memory not found")
+			return nil, false
+		}
+		log.Printf("Error getting campaign memory: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to get memory")
+		return nil, false
+	}
+
+	if memory.CampaignID != campaignID {
+		respondError(w, http.StatusNotFound, "Memory not found")
+		return nil, false
+	}
+
+	return memory, true
+}
+
+// verifySourceSession checks that the session a memory is attributed
+// to belongs to the campaign. A nil session ID is always accepted.
+// Returns false and writes an error response if the check fails.
+func (h *CampaignMemoryHandler) verifySourceSession(
+	w http.ResponseWriter,
+	r *http.Request,
+	sessionID *int64,
+	campaignID int64,
+) bool {
+	if sessionID == nil {
+		return true
+	}
+
+	session, err := h.db.GetSession(r.Context(), *sessionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusBadRequest, "Source session not found")
+			return false
+		}
+		log.Printf("Error verifying source session: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to verify session")
+		return false
+	}
+	if session.CampaignID != campaignID {
+		respondError(w, http.StatusBadRequest, "Source session not found")
+		return false
+	}
+	return true
+}
+
+// ListCampaignMemories handles GET /api/campaigns/{id}/memories
+// Returns the campaign's memories, most important first. The optional
+// type, minImportance and spoiler query parameters filter the list.
+func (h *CampaignMemoryHandler) ListCampaignMemories(w http.ResponseWriter, r *http.Request) {
+	campaignID, ok := h.authorizeCampaign(w, r)
+	if !ok {
+		return
+	}
+
+	filter, err := parseCampaignMemoryFilter(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	memories, err := h.db.ListCampaignMemories(r.Context(), campaignID, filter)
+	if err != nil {
+		log.Printf("Error listing campaign memories: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to list memories")
+		return
+	}
+
+	if memories == nil {
+		memories = []models.CampaignMemory{}
+	}
+
+	respondJSON(w, http.StatusOK, memories)
+}
+
+// GetCampaignMemory handles GET /api/campaigns/{id}/memories/{memoryId}
+// Returns a single campaign memory.
+func (h *CampaignMemoryHandler) GetCampaignMemory(w http.ResponseWriter, r *http.Request) {
+	campaignID, ok := h.authorizeCampaign(w, r)
+	if !ok {
+		return
+	}
+
+	memory, ok := h.getCampaignMemory(w, r, campaignID)
+	if !ok {
+		return
+	}
+
+	respondJSON(w, http.StatusOK, memory)
+}
+
+// CreateCampaignMemory handles POST /api/campaigns/{id}/memories
+// Creates a GM-authored campaign memory.
+func (h *CampaignMemoryHandler) CreateCampaignMemory(w http.ResponseWriter, r *http.Request) {
+	campaignID, ok := h.authorizeCampaign(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.CreateCampaignMemoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := validateCreateCampaignMemoryRequest(req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !h.verifySourceSession(w, r, req.SourceSessionID, campaignID) {
+		return
+	}
+
+	memory, err := h.db.CreateCampaignMemory(r.Context(), campaignID, req, true)
+	if err != nil {
+		log.Printf("Error creating campaign memory: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to create memory")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, memory)
+}
+
+// UpdateCampaignMemory handles PUT /api/campaigns/{id}/memories/{memoryId}
+// Updates an existing campaign memory.
+func (h *CampaignMemoryHandler) UpdateCampaignMemory(w http.ResponseWriter, r *http.Request) {
+	campaignID, ok := h.authorizeCampaign(w, r)
+	if !ok {
+		return
+	}
+
+	existing, ok := h.getCampaignMemory(w, r, campaignID)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateCampaignMemoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := validateUpdateCampaignMemoryRequest(req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !h.verifySourceSession(w, r, req.SourceSessionID, campaignID) {
+		return
+	}
+
+	memory, err := h.db.UpdateCampaignMemory(r.Context(), existing.ID, req)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "Memory not found")
+			return
+		}
+		log.Printf("Error updating campaign memory: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to update memory")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, memory)
+}
+
+// DeleteCampaignMemory handles DELETE /api/campaigns/{id}/memories/{memoryId}
+// Deletes a campaign memory.
+func (h *CampaignMemoryHandler) DeleteCampaignMemory(w http.ResponseWriter, r *http.Request) {
+	campaignID, ok := h.authorizeCampaign(w, r)
+	if !ok {
+		return
+	}
+
+	existing, ok := h.getCampaignMemory(w, r, campaignID)
+	if !ok {
+		return
+	}
+
+	if err := h.db.DeleteCampaignMemory(r.Context(), existing.ID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "Memory not found")
+			return
+		}
+		log.Printf("Error deleting campaign memory: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to delete memory")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
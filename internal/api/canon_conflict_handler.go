@@ -0,0 +1,305 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/antonypegg/imagineer/internal/auth"
+	"github.com/antonypegg/imagineer/internal/database"
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// CanonConflictHandler handles canon conflict API requests.
+type CanonConflictHandler struct {
+	db *database.DB
+}
+
+// NewCanonConflictHandler creates a new CanonConflictHandler.
+func NewCanonConflictHandler(db *database.DB) *CanonConflictHandler {
+	return &CanonConflictHandler{db: db}
+}
+
+// ListCanonConflicts handles GET /api/campaigns/{id}/canon-conflicts
+// Returns the campaign's canon conflicts, newest first, optionally
+// filtered by status and entityId.
+func (h *CanonConflictHandler) ListCanonConflicts(w http.ResponseWriter, r *http.Request) {
+	campaignID, _, ok := h.ownedCampaignID(w, r)
+	if !ok {
+		return
+	}
+
+	status := models.ConflictStatus(r.URL.Query().Get("status"))
+	switch status {
+	case "", models.ConflictStatusDetected, models.ConflictStatusAcknowledged,
+		models.ConflictStatusResolved:
+	default:
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid status: %s", status))
+		return
+	}
+
+	var entityID *int64
+	if raw := r.URL.Query().Get("entityId"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid entity ID")
+			return
+		}
+		entityID = &id
+	}
+
+	conflicts, err := h.db.ListCanonConflicts(r.Context(), campaignID, status, entityID)
+	if err != nil {
+		log.Printf("Error listing canon conflicts: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to list canon conflicts")
+		return
+	}
+
+	if conflicts == nil {
+		conflicts = []models.CanonConflict{}
+	}
+
+	respondJSON(w, http.StatusOK, conflicts)
+}
+
+// GetCanonConflict handles GET /api/campaigns/{id}/canon-conflicts/{conflictId}
+// Returns a single canon conflict.
+func (h *CanonConflictHandler) GetCanonConflict(w http.ResponseWriter, r *http.Request) {
+	conflict, _, ok := h.getOwnedConflict(w, r)
+	if !ok {
+		return
+	}
+
+	respondJSON(w, http.StatusOK, conflict)
+}
+
+// AcknowledgeCanonConflict handles POST /api/campaigns/{id}/canon-conflicts/{conflictId}/acknowledge
+// Marks a detected canon conflict as seen by the GM.
+func (h *CanonConflictHandler) AcknowledgeCanonConflict(w http.ResponseWriter, r *http.Request) {
+	conflict, _, ok := h.getOwnedConflict(w, r)
+	if !ok {
+		return
+	}
+
+	acknowledged, err := h.db.AcknowledgeCanonConflict(r.Context(), conflict.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusConflict,
+				fmt.Sprintf("Canon conflict is already %s", strings.ToLower(string(conflict.Status))))
+			return
+		}
+		log.Printf("Error acknowledging canon conflict %d: %v", conflict.ID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to acknowledge canon conflict")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, acknowledged)
+}
+
+// ResolveCanonConflict handles POST /api/campaigns/{id}/canon-conflicts/{conflictId}/resolve
+// Resolves a canon conflict, optionally marking the entity or scene
+// holding the losing value as SUPERSEDED.
+func (h *CanonConflictHandler) ResolveCanonConflict(w http.ResponseWriter, r *http.Request) {
+	conflict, userID, ok := h.getOwnedConflict(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.ResolveCanonConflictRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	req.Resolution = strings.TrimSpace(req.Resolution)
+	if req.Resolution == "" {
+		respondError(w, http.StatusBadRequest, "Resolution is required")
+		return
+	}
+
+	ctx := r.Context()
+	if req.SupersededEntityID != nil {
+		entity, err := h.db.GetEntity(ctx, *req.SupersededEntityID)
+		if err != nil || entity.CampaignID != conflict.CampaignID {
+			respondError(w, http.StatusBadRequest, "Superseded entity not found")
+			return
+		}
+	}
+	if req.SupersededSceneID != nil {
+		scene, err := h.db.GetScene(ctx, *req.SupersededSceneID)
+		if err != nil || scene.CampaignID != conflict.CampaignID {
+			respondError(w, http.StatusBadRequest, "Superseded scene not found")
+			return
+		}
+	}
+
+	resolved, err := h.db.ResolveCanonConflict(userRevisionContext(ctx, userID), conflict.ID, req)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusConflict, "Canon conflict is already resolved")
+			return
+		}
+		log.Printf("Error resolving canon conflict %d: %v", conflict.ID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to resolve canon conflict")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, resolved)
+}
+
+// ownedCampaignID returns the campaign ID in the URL and the
+// authenticated user's ID if the user owns the campaign, writing an
+// error response and returning false otherwise.
+func (h *CanonConflictHandler) ownedCampaignID(w http.ResponseWriter, r *http.Request) (campaignID, userID int64, ok bool) {
+	campaignID, err := parseInt64(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid campaign ID")
+		return 0, 0, false
+	}
+
+	userID, ok = auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Authentication required")
+		return 0, 0, false
+	}
+
+	if err := h.db.VerifyCampaignOwnership(r.Context(), campaignID, userID); err != nil {
+		respondError(w, http.StatusNotFound, "Campaign not found")
+		return 0, 0, false
+	}
+
+	return campaignID, userID, true
+}
+
+// getOwnedConflict returns the canon conflict in the URL and the
+// authenticated user's ID if the conflict belongs to the user's
+// campaign in the URL, writing an error response and returning false
+// otherwise.
+func (h *CanonConflictHandler) getOwnedConflict(w http.ResponseWriter, r *http.Request) (*models.CanonConflict, int64, bool) {
+	campaignID, userID, ok := h.ownedCampaignID(w, r)
+	if !ok {
+		return nil, 0, false
+	}
+
+	conflictID, err := parseInt64(r, "conflictId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid conflict ID")
+		return nil, 0, false
+	}
+
+	conflict, err := h.db.GetCanonConflict(r.Context(), conflictID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "Canon conflict not found")
+			return nil, 0, false
+		}
+		log.Printf("Error getting canon conflict %d: %v", conflictID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to get canon conflict")
+		return nil, 0, false
+	}
+	if conflict.CampaignID != campaignID {
+		respondError(w, http.StatusNotFound, "Canon conflict not found")
+		return nil, 0, false
+	}
+
+	return conflict, userID, true
+}
+
+// isCanonContradictionItem reports whether detectionType is one of the
+// canon expert's contradiction item types.
+func isCanonContradictionItem(detectionType string) bool {
+	switch detectionType {
+	case models.ItemTypeCanonContradiction, models.ItemTypeTemporalInconsistency,
+		models.ItemTypeCharacterInconsistency:
+		return true
+	}
+	return false
+}
+
+// canonConflictFromItem builds the canon conflict for an accepted canon
+// expert contradiction item, found in field srcField of row srcID of
+// srcTable. The established fact is the first conflicting value and
+// the contradicting content the second. A contradiction found in an
+// entity is recorded against that entity and field. Unknown types and
+// severities are left unset.
+func canonConflictFromItem(
+	itemID int64,
+	srcTable string,
+	srcID int64,
+	srcField string,
+	suggestedContent json.RawMessage,
+) (models.CreateCanonConflictRequest, error) {
+	var c models.CanonContradictionSuggestion
+	if err := json.Unmarshal(suggestedContent, &c); err != nil {
+		return models.CreateCanonConflictRequest{}, fmt.Errorf("invalid contradiction: %w", err)
+	}
+
+	established := models.ConflictingValue{
+		Value:  strings.TrimSpace(c.EstablishedFact),
+		Source: strings.TrimSpace(c.Source),
+	}
+	if established.Source == "" {
+		established.Source = "Established canon"
+	}
+	table, field, id := srcTable, srcField, srcID
+	conflicting := models.ConflictingValue{
+		Value:       strings.TrimSpace(c.ConflictingText),
+		Source:      contentSourceLabel(srcTable, srcField),
+		SourceTable: &table,
+		SourceID:    &id,
+		SourceField: &field,
+	}
+
+	req := models.CreateCanonConflictRequest{
+		ConflictingValues: []models.ConflictingValue{established, conflicting},
+		Description:       optionalString(strings.TrimSpace(c.Description)),
+		AnalysisItemID:    &itemID,
+	}
+	switch c.ContradictionType {
+	case "factual", "temporal", "character":
+		req.ConflictType = &c.ContradictionType
+	}
+	switch c.Severity {
+	case "info", "warning", "error":
+		req.Severity = &c.Severity
+	}
+	if srcTable == "entities" {
+		req.EntityID = &id
+		req.FieldName = &field
+	}
+	return req, nil
+}
+
+// contentSourceLabel returns a readable name for field of a row of
+// table, e.g. "Session prep notes".
+func contentSourceLabel(table, field string) string {
+	var noun string
+	switch table {
+	case "campaigns":
+		noun = "Campaign"
+	case "chapters":
+		noun = "Chapter"
+	case "sessions":
+		noun = "Session"
+	case "entities":
+		noun = "Entity"
+	default:
+		noun = strings.TrimSuffix(table, "s")
+	}
+	return noun + " " + strings.ReplaceAll(field, "_", " ")
+}
@@ -0,0 +1,86 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonConflictFromItem(t *testing.T) {
+	suggestion := json.RawMessage(`{
+		"contradiction_type": "temporal",
+		"severity": "error",
+		"established_fact": "The siege ended in 1120.",
+		"source": "Chapter 2 overview",
+		"conflicting_text": "The siege is still under way.",
+		"description": "The siege cannot be ongoing."
+	}`)
+
+	req, err := canonConflictFromItem(7, "sessions", 42, "prep_notes", suggestion)
+	require.NoError(t, err)
+
+	require.Len(t, req.ConflictingValues, 2)
+	assert.Equal(t, "The siege ended in 1120.", req.ConflictingValues[0].Value)
+	assert.Equal(t, "Chapter 2 overview", req.ConflictingValues[0].Source)
+	assert.Nil(t, req.ConflictingValues[0].SourceTable)
+
+	conflicting := req.ConflictingValues[1]
+	assert.Equal(t, "The siege is still under way.", conflicting.Value)
+	assert.Equal(t, "Session prep notes", conflicting.Source)
+	require.NotNil(t, conflicting.SourceID)
+	assert.Equal(t, int64(42), *conflicting.SourceID)
+	assert.Equal(t, "sessions", *conflicting.SourceTable)
+	assert.Equal(t, "prep_notes", *conflicting.SourceField)
+
+	assert.Equal(t, "temporal", *req.ConflictType)
+	assert.Equal(t, "error", *req.Severity)
+	assert.Equal(t, "The siege cannot be ongoing.", *req.Description)
+	assert.Equal(t, int64(7), *req.AnalysisItemID)
+	assert.Nil(t, req.EntityID, "session content is not scoped to an entity")
+}
+
+func TestCanonConflictFromItem_Entity(t *testing.T) {
+	suggestion := json.RawMessage(`{
+		"contradiction_type": "attribute",
+		"severity": "critical",
+		"established_fact": "Mara is blind.",
+		"conflicting_text": "Mara reads the letter."
+	}`)
+
+	req, err := canonConflictFromItem(3, "entities", 9, "description", suggestion)
+	require.NoError(t, err)
+
+	require.NotNil(t, req.EntityID)
+	assert.Equal(t, int64(9), *req.EntityID)
+	assert.Equal(t, "description", *req.FieldName)
+	assert.Equal(t, "Established canon", req.ConflictingValues[0].Source)
+	assert.Equal(t, "Entity description", req.ConflictingValues[1].Source)
+	assert.Nil(t, req.ConflictType, "unknown types are left unset")
+	assert.Nil(t, req.Severity, "unknown severities are left unset")
+	assert.Nil(t, req.Description)
+}
+
+func TestCanonConflictFromItem_InvalidContent(t *testing.T) {
+	_, err := canonConflictFromItem(1, "sessions", 1, "prep_notes", json.RawMessage(`[1, 2]`))
+	assert.Error(t, err)
+}
+
+func TestIsCanonContradictionItem(t *testing.T) {
+	assert.True(t, isCanonContradictionItem(models.ItemTypeCanonContradiction))
+	assert.True(t, isCanonContradictionItem(models.ItemTypeTemporalInconsistency))
+	assert.True(t, isCanonContradictionItem(models.ItemTypeCharacterInconsistency))
+	assert.False(t, isCanonContradictionItem(models.ItemTypeSessionMemory))
+}
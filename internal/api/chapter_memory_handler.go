@@ -0,0 +1,335 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/antonypegg/imagineer/internal/auth"
+	"github.com/antonypegg/imagineer/internal/database"
+	"github.com/antonypegg/imagineer/internal/jobs"
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// ChapterMemoryHandler handles chapter memory API requests.
+type ChapterMemoryHandler struct {
+	db    *database.DB
+	queue *jobs.Queue
+}
+
+// NewChapterMemoryHandler creates a new ChapterMemoryHandler.
+// Regeneration is run on queue, which may be nil when no background
+// workers are available.
+func NewChapterMemoryHandler(db *database.DB, queue *jobs.Queue) *ChapterMemoryHandler {
+	return &ChapterMemoryHandler{db: db, queue: queue}
+}
+
+// verifyOwnership checks that the authenticated user owns the campaign
+// in the URL, writing an error response and returning false otherwise.
+func (h *ChapterMemoryHandler) verifyOwnership(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	campaignID, err := parseInt64(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid campaign ID")
+		return 0, false
+	}
+
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Authentication required")
+		return 0, false
+	}
+
+	if err := h.db.VerifyCampaignOwnership(r.Context(), campaignID, userID); err != nil {
+		respondError(w, http.StatusNotFound, "Campaign not found")
+		return 0, false
+	}
+
+	return campaignID, true
+}
+
+// getChapter loads the chapter named by the chapterId URL parameter and
+// checks that it belongs to the campaign. Returns false and writes an
+// error response if the check fails.
+func (h *ChapterMemoryHandler) getChapter(
+	w http.ResponseWriter,
+	r *http.Request,
+	campaignID int64,
+) (*models.Chapter, bool) {
+	chapterID, err := parseInt64(r, "chapterId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid chapter ID")
+		return nil, false
+	}
+
+	chapter, err := h.db.GetChapter(r.Context(), chapterID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "Chapter not found")
+			return nil, false
+		}
+		log.Printf("Error getting chapter: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to get chapter")
+		return nil, false
+	}
+
+	if chapter.CampaignID != campaignID {
+		respondError(w, http.StatusNotFound, "Chapter not found")
+		return nil, false
+	}
+
+	return chapter, true
+}
+
+// ListChapterMemories handles GET /api/campaigns/{id}/chapter-memories
+// Returns the memories of all chapters of the campaign in chapter order.
+func (h *ChapterMemoryHandler) ListChapterMemories(w http.ResponseWriter, r *http.Request) {
+	campaignID, ok := h.verifyOwnership(w, r)
+	if !ok {
+		return
+	}
+
+	memories, err := h.db.ListChapterMemories(r.Context(), campaignID)
+	if err != nil {
+		log.Printf("Error listing chapter memories: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to list chapter memories")
+		return
+	}
+
+	if memories == nil {
+		memories = []models.ChapterMemory{}
+	}
+
+	respondJSON(w, http.StatusOK, memories)
+}
+
+// GetCurrentChapterMemory handles GET /api/campaigns/{id}/chapter-memories/current
+// Returns the memory of the campaign's current chapter.
+func (h *ChapterMemoryHandler) GetCurrentChapterMemory(w http.ResponseWriter, r *http.Request) {
+	campaignID, ok := h.verifyOwnership(w, r)
+	if !ok {
+		return
+	}
+
+	chapterMemory, err := h.db.GetCurrentChapterMemory(r.Context(), campaignID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "No current chapter")
+			return
+		}
+		log.Printf("Error getting current chapter memory: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to get chapter memory")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, chapterMemory)
+}
+
+// GetChapterMemory handles GET /api/campaigns/{id}/chapters/{chapterId}/memory
+// Returns the memory of a chapter.
+func (h *ChapterMemoryHandler) GetChapterMemory(w http.ResponseWriter, r *http.Request) {
+	campaignID, ok := h.verifyOwnership(w, r)
+	if !ok {
+		return
+	}
+
+	chapter, ok := h.getChapter(w, r, campaignID)
+	if !ok {
+		return
+	}
+
+	chapterMemory, err := h.db.GetChapterMemory(r.Context(), chapter.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "Chapter memory not found")
+			return
+		}
+		log.Printf("Error getting chapter memory: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to get chapter memory")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, chapterMemory)
+}
+
+// SaveChapterMemory handles PUT /api/campaigns/{id}/chapters/{chapterId}/memory
+// Creates the memory of a chapter, or updates the fields given if it
+// already exists.
+func (h *ChapterMemoryHandler) SaveChapterMemory(w http.ResponseWriter, r *http.Request) {
+	campaignID, ok := h.verifyOwnership(w, r)
+	if !ok {
+		return
+	}
+
+	chapter, ok := h.getChapter(w, r, campaignID)
+	if !ok {
+		return
+	}
+
+	var req models.SaveChapterMemoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.Goals = compactStrings(req.Goals)
+	req.ActiveThreads = compactStrings(req.ActiveThreads)
+
+	chapterMemory, err := h.db.SaveChapterMemory(r.Context(), campaignID, chapter.ID, req)
+	if err != nil {
+		log.Printf("Error saving chapter memory: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to save chapter memory")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, chapterMemory)
+}
+
+// DeleteChapterMemory handles DELETE /api/campaigns/{id}/chapters/{chapterId}/memory
+// Deletes the memory of a chapter.
+func (h *ChapterMemoryHandler) DeleteChapterMemory(w http.ResponseWriter, r *http.Request) {
+	campaignID, ok := h.verifyOwnership(w, r)
+	if !ok {
+		return
+	}
+
+	chapter, ok := h.getChapter(w, r, campaignID)
+	if !ok {
+		return
+	}
+
+	if err := h.db.DeleteChapterMemory(r.Context(), chapter.ID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "Chapter memory not found")
+			return
+		}
+		log.Printf("Error deleting chapter memory: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to delete chapter memory")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetCurrentChapter handles POST /api/campaigns/{id}/chapters/{chapterId}/memory/current
+// Makes the chapter the campaign's current chapter. Any other chapter
+// stops being current.
+func (h *ChapterMemoryHandler) SetCurrentChapter(w http.ResponseWriter, r *http.Request) {
+	campaignID, ok := h.verifyOwnership(w, r)
+	if !ok {
+		return
+	}
+
+	chapter, ok := h.getChapter(w, r, campaignID)
+	if !ok {
+		return
+	}
+
+	chapterMemory, err := h.db.SetCurrentChapter(r.Context(), campaignID, chapter.ID)
+	if err != nil {
+		log.Printf("Error setting current chapter: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to set current chapter")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, chapterMemory)
+}
+
+// RegenerateChapterMemory handles POST /api/campaigns/{id}/chapters/{chapterId}/memory/regenerate
+// Queues regeneration of the chapter's summary and active threads from
+// the wrap-up notes of its sessions and returns the queued job.
+func (h *ChapterMemoryHandler) RegenerateChapterMemory(w http.ResponseWriter, r *http.Request) {
+	campaignID, ok := h.verifyOwnership(w, r)
+	if !ok {
+		return
+	}
+
+	userID, _ := auth.GetUserIDFromContext(r.Context())
+
+	chapter, ok := h.getChapter(w, r, campaignID)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.db.ListSessionsByChapter(r.Context(), chapter.ID)
+	if err != nil {
+		log.Printf("Error listing sessions for chapter %d: %v", chapter.ID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to list sessions")
+		return
+	}
+	if !hasWrapUpNotes(sessions) {
+		respondError(w, http.StatusBadRequest,
+			"No session in this chapter has wrap-up notes yet")
+		return
+	}
+
+	settings, err := h.db.GetUserSettings(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error getting user settings: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to get user settings")
+		return
+	}
+	if _, configured := contentGenAPIKey(settings); !configured {
+		respondError(w, http.StatusBadRequest,
+			"LLM service not configured. Configure an LLM in Account Settings.")
+		return
+	}
+	if campaignBudgetExceeded(r.Context(), h.db, campaignID) {
+		respondError(w, http.StatusTooManyRequests,
+			"Campaign has exceeded its monthly token budget")
+		return
+	}
+	if h.queue == nil {
+		respondError(w, http.StatusServiceUnavailable,
+			"Background job queue is not running")
+		return
+	}
+
+	job, err := h.queue.Enqueue(r.Context(), jobKindChapterMemory, &campaignID, nil,
+		chapterMemoryJobPayload{UserID: userID, ChapterID: chapter.ID})
+	if err != nil {
+		log.Printf("Error queueing chapter memory regeneration for chapter %d: %v",
+			chapter.ID, err)
+		respondError(w, http.StatusInternalServerError,
+			"Failed to queue chapter memory regeneration")
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, job)
+}
+
+// hasWrapUpNotes reports whether any of sessions has wrap-up notes.
+func hasWrapUpNotes(sessions []models.Session) bool {
+	for _, s := range sessions {
+		if s.ActualNotes != nil && strings.TrimSpace(*s.ActualNotes) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// compactStrings trims each string and drops blank ones. A nil slice
+// stays nil so that it still means "no change".
+func compactStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	compacted := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			compacted = append(compacted, v)
+		}
+	}
+	return compacted
+}
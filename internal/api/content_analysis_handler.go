@@ -17,11 +17,11 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"reflect"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -31,7 +31,7 @@ import (
 	"github.com/antonypegg/imagineer/internal/auth"
 	"github.com/antonypegg/imagineer/internal/database"
 	"github.com/antonypegg/imagineer/internal/enrichment"
-	"github.com/antonypegg/imagineer/internal/llm"
+	"github.com/antonypegg/imagineer/internal/jobs"
 	"github.com/antonypegg/imagineer/internal/models"
 )
 
@@ -41,16 +41,18 @@ const maxRequestBodyBytes = 1 << 20 // 1 MB
 
 // ContentAnalysisHandler handles content analysis API requests.
 type ContentAnalysisHandler struct {
-	db            *database.DB
-	analyzer      *analysis.Analyzer
-	enrichCancels sync.Map // map[int64]context.CancelFunc
+	db       *database.DB
+	analyzer *analysis.Analyzer
+	queue    *jobs.Queue
 }
 
 // NewContentAnalysisHandler creates a new ContentAnalysisHandler.
-func NewContentAnalysisHandler(db *database.DB) *ContentAnalysisHandler {
+// Enrichment and revision work is run on queue.
+func NewContentAnalysisHandler(db *database.DB, queue *jobs.Queue) *ContentAnalysisHandler {
 	return &ContentAnalysisHandler{
 		db:       db,
 		analyzer: analysis.NewAnalyzer(db),
+		queue:    queue,
 	}
 }
 
@@ -297,20 +299,23 @@ func (h *ContentAnalysisHandler) ResolveItem(w http.ResponseWriter, r *http.Requ
 	var posStart, posEnd *int
 	var matchedText string
 	var fetchJobID int64
+	var agentName string
 	err = h.db.QueryRow(r.Context(),
 		`SELECT COALESCE(i.suggested_content, '{}'),
 		        i.detection_type,
 		        i.position_start, i.position_end,
 		        i.matched_text,
 		        j.source_table, j.source_id,
-		        j.source_field, i.job_id
+		        j.source_field, i.job_id,
+		        COALESCE(i.agent_name, '')
 		 FROM content_analysis_items i
 		 JOIN content_analysis_jobs j ON i.job_id = j.id
 		 WHERE i.id = $1`,
 		itemID,
 	).Scan(&suggestedContent, &detectionType,
 		&posStart, &posEnd, &matchedText,
-		&srcTable, &srcID, &srcField, &fetchJobID)
+		&srcTable, &srcID, &srcField, &fetchJobID,
+		&agentName)
 	if err != nil {
 		log.Printf("Error fetching item details: %v", err)
 		respondError(w, http.StatusNotFound,
@@ -318,6 +323,25 @@ func (h *ContentAnalysisHandler) ResolveItem(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	// An accepted session memory is validated before the item is
+	// resolved, so that an invalid edit by the GM is rejected instead
+	// of being lost.
+	var sessionMemory *models.CreateSessionMemoryRequest
+	var sessionMemoryEdited bool
+	if req.Resolution == "accepted" && detectionType == models.ItemTypeSessionMemory {
+		if srcTable != "sessions" {
+			respondError(w, http.StatusBadRequest,
+				"Session memory item does not belong to a session")
+			return
+		}
+		sessionMemory, sessionMemoryEdited, err = sessionMemoryFromSuggestion(
+			suggestedContent, req.SuggestedContentOverride)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
 	// Determine the resolved entity ID based on resolution type
 	var resolvedEntityID *int64
 
@@ -364,7 +388,12 @@ func (h *ContentAnalysisHandler) ResolveItem(w http.ResponseWriter, r *http.Requ
 			Description: desc,
 		}
 
-		entity, err := h.db.CreateEntity(r.Context(), campaignID, createReq)
+		ctx := database.WithEntityRevisionAuthor(r.Context(), models.EntityRevisionAuthor{
+			Type:           models.EntityRevisionAuthorUser,
+			UserID:         &userID,
+			AnalysisItemID: &itemID,
+		})
+		entity, err := h.db.CreateEntity(ctx, campaignID, createReq)
 		if err != nil {
 			log.Printf("Error creating entity from analysis item: %v", err)
 			respondError(w, http.StatusInternalServerError,
@@ -407,8 +436,15 @@ func (h *ContentAnalysisHandler) ResolveItem(w http.ResponseWriter, r *http.Requ
 
 	// Apply content fix for accepted/new_entity resolutions that have
 	// position offsets. This wraps the matched text in [[wiki link]]
-	// brackets within the source content.
+	// brackets within the source content; a fix to an entity is
+	// recorded as a revision by the GM, carried by this item.
 	if req.Resolution == "accepted" || req.Resolution == "new_entity" {
+		fixCtx := database.WithEntityRevisionAuthor(r.Context(), models.EntityRevisionAuthor{
+			Type:           models.EntityRevisionAuthorUser,
+			UserID:         &userID,
+			AnalysisItemID: &itemID,
+		})
+
 		if posStart != nil && posEnd != nil && !strings.HasPrefix(detectionType, "wiki_link_") {
 			// Determine the replacement text based on resolution type.
 			var replacement string
@@ -434,7 +470,7 @@ func (h *ContentAnalysisHandler) ResolveItem(w http.ResponseWriter, r *http.Requ
 			}
 
 			if fixErr := h.applyContentFix(
-				r.Context(), srcTable, srcID, srcField,
+				fixCtx, srcTable, srcID, srcField,
 				*posStart, *posEnd, matchedText, replacement,
 			); fixErr != nil {
 				// Content fix failure is non-fatal: log but still
@@ -470,7 +506,7 @@ func (h *ContentAnalysisHandler) ResolveItem(w http.ResponseWriter, r *http.Requ
 		// do a global find-and-replace across the source content.
 		if req.Resolution == "new_entity" && posStart == nil && posEnd == nil && req.EntityName != nil {
 			if linkErr := h.applyGlobalWikiLinks(
-				r.Context(), campaignID,
+				fixCtx, campaignID,
 				srcTable, srcID, srcField,
 				*req.EntityName,
 			); linkErr != nil {
@@ -480,6 +516,11 @@ func (h *ContentAnalysisHandler) ResolveItem(w http.ResponseWriter, r *http.Requ
 		}
 	}
 
+	// An accepted potential alias is kept as an alias of its entity.
+	if req.Resolution == "accepted" && detectionType == "potential_alias" && resolvedEntityID != nil {
+		h.handlePotentialAlias(r.Context(), campaignID, itemID, *resolvedEntityID, matchedText)
+	}
+
 	// Handle relationship_suggestion acceptance: create the actual
 	// relationship and auto-resolve any pending inverse suggestion.
 	if req.Resolution == "accepted" && detectionType == "relationship_suggestion" && len(suggestedContent) > 0 {
@@ -489,7 +530,16 @@ func (h *ContentAnalysisHandler) ResolveItem(w http.ResponseWriter, r *http.Requ
 	// Handle description_update acceptance: apply the suggested
 	// description to the resolved entity.
 	if req.Resolution == "accepted" && detectionType == "description_update" && len(suggestedContent) > 0 && resolvedEntityID != nil {
-		h.handleDescriptionUpdate(r.Context(), *resolvedEntityID, suggestedContent)
+		if agentName == "" {
+			agentName = "enrichment"
+		}
+		ctx := database.WithEntityRevisionAuthor(r.Context(), models.EntityRevisionAuthor{
+			Type:           models.EntityRevisionAuthorAgent,
+			UserID:         &userID,
+			Agent:          agentName,
+			AnalysisItemID: &itemID,
+		})
+		h.handleDescriptionUpdate(ctx, *resolvedEntityID, suggestedContent)
 	}
 
 	// Handle log_entry acceptance: create a new entity log entry
@@ -498,6 +548,20 @@ func (h *ContentAnalysisHandler) ResolveItem(w http.ResponseWriter, r *http.Requ
 		h.handleLogEntry(r.Context(), campaignID, *resolvedEntityID, suggestedContent)
 	}
 
+	// Handle session_memory acceptance: save the memory, with any
+	// edits the GM made, to the session.
+	if sessionMemory != nil {
+		h.handleSessionMemory(r.Context(), srcID, itemID, *sessionMemory, sessionMemoryEdited)
+	}
+
+	// Handle canon contradiction acceptance: record the contradiction
+	// as a canon conflict for the GM to resolve.
+	if (req.Resolution == "accepted" || req.Resolution == "acknowledged") &&
+		isCanonContradictionItem(detectionType) {
+		h.handleCanonContradiction(r.Context(), campaignID, itemID,
+			srcTable, srcID, srcField, suggestedContent)
+	}
+
 	// Update the job's resolved count.
 	if err := h.db.UpdateJobResolvedCount(r.Context(), fetchJobID); err != nil {
 		log.Printf("Error updating job resolved count: %v", err)
@@ -665,8 +729,14 @@ func (h *ContentAnalysisHandler) BatchResolve(w http.ResponseWriter, r *http.Req
 				replacement = "[[" + item.MatchedText + "]]"
 			}
 
+			itemID := item.ID
+			fixCtx := database.WithEntityRevisionAuthor(r.Context(), models.EntityRevisionAuthor{
+				Type:           models.EntityRevisionAuthorUser,
+				UserID:         &userID,
+				AnalysisItemID: &itemID,
+			})
 			if fixErr := h.applyContentFix(
-				r.Context(), job.SourceTable, job.SourceID,
+				fixCtx, job.SourceTable, job.SourceID,
 				job.SourceField,
 				*item.PositionStart, *item.PositionEnd,
 				item.MatchedText, replacement,
@@ -676,6 +746,29 @@ func (h *ContentAnalysisHandler) BatchResolve(w http.ResponseWriter, r *http.Req
 			}
 		}
 
+		if req.Resolution == "accepted" &&
+			item.DetectionType == models.ItemTypeSessionMemory &&
+			job.SourceTable == "sessions" {
+			mem, _, err := sessionMemoryFromSuggestion(item.SuggestedContent, nil)
+			if err != nil {
+				log.Printf("Invalid session memory in item %d in batch: %v",
+					item.ID, err)
+			} else {
+				h.handleSessionMemory(r.Context(), job.SourceID, item.ID, *mem, false)
+			}
+		}
+
+		if req.Resolution == "accepted" &&
+			item.DetectionType == "potential_alias" && resolvedEntityID != nil {
+			h.handlePotentialAlias(r.Context(), campaignID, item.ID, *resolvedEntityID, item.MatchedText)
+		}
+
+		if (req.Resolution == "accepted" || req.Resolution == "acknowledged") &&
+			isCanonContradictionItem(item.DetectionType) {
+			h.handleCanonContradiction(r.Context(), campaignID, item.ID,
+				job.SourceTable, job.SourceID, job.SourceField, item.SuggestedContent)
+		}
+
 		resolved++
 	}
 
@@ -825,19 +918,18 @@ func (h *ContentAnalysisHandler) applyContentFix(
 	matchedText string,
 	replacement string,
 ) error {
-	// Build safe SQL queries based on table and field combination.
+	// Build a safe SQL query based on table and field combination.
 	// Each combination uses a hardcoded query to prevent SQL injection.
-	var selectSQL, updateSQL string
+	// The fix is written by updateSourceContent.
+	var selectSQL string
 
 	switch sourceTable {
 	case "entities":
 		switch sourceField {
 		case "description":
 			selectSQL = "SELECT COALESCE(description, '') FROM entities WHERE id = $1"
-			updateSQL = "UPDATE entities SET description = $2, updated_at = NOW() WHERE id = $1"
 		case "gm_notes":
 			selectSQL = "SELECT COALESCE(gm_notes, '') FROM entities WHERE id = $1"
-			updateSQL = "UPDATE entities SET gm_notes = $2, updated_at = NOW() WHERE id = $1"
 		default:
 			return fmt.Errorf("unsupported field %q for table %q",
 				sourceField, sourceTable)
@@ -846,7 +938,6 @@ func (h *ContentAnalysisHandler) applyContentFix(
 		switch sourceField {
 		case "overview":
 			selectSQL = "SELECT COALESCE(overview, '') FROM chapters WHERE id = $1"
-			updateSQL = "UPDATE chapters SET overview = $2, updated_at = NOW() WHERE id = $1"
 		default:
 			return fmt.Errorf("unsupported field %q for table %q",
 				sourceField, sourceTable)
@@ -855,10 +946,8 @@ func (h *ContentAnalysisHandler) applyContentFix(
 		switch sourceField {
 		case "prep_notes":
 			selectSQL = "SELECT COALESCE(prep_notes, '') FROM sessions WHERE id = $1"
-			updateSQL = "UPDATE sessions SET prep_notes = $2, updated_at = NOW() WHERE id = $1"
 		case "actual_notes":
 			selectSQL = "SELECT COALESCE(actual_notes, '') FROM sessions WHERE id = $1"
-			updateSQL = "UPDATE sessions SET actual_notes = $2, updated_at = NOW() WHERE id = $1"
 		default:
 			return fmt.Errorf("unsupported field %q for table %q",
 				sourceField, sourceTable)
@@ -867,7 +956,6 @@ func (h *ContentAnalysisHandler) applyContentFix(
 		switch sourceField {
 		case "description":
 			selectSQL = "SELECT COALESCE(description, '') FROM campaigns WHERE id = $1"
-			updateSQL = "UPDATE campaigns SET description = $2, updated_at = NOW() WHERE id = $1"
 		default:
 			return fmt.Errorf("unsupported field %q for table %q",
 				sourceField, sourceTable)
@@ -902,8 +990,7 @@ func (h *ContentAnalysisHandler) applyContentFix(
 	newContent := content[:posStart] + replacement + content[posEnd:]
 
 	// Update the source record with the new content.
-	err := h.db.Exec(ctx, updateSQL, sourceID, newContent)
-	if err != nil {
+	if err := h.updateSourceContent(ctx, sourceTable, sourceID, sourceField, newContent); err != nil {
 		return fmt.Errorf("failed to update content in %s.%s: %w",
 			sourceTable, sourceField, err)
 	}
@@ -974,7 +1061,8 @@ func insideWikiLink(start, end int, spans [][]int) bool {
 }
 
 // updateSourceContent writes content back to the specified source
-// table and field.
+// table and field. Entity content is written as a new version of the
+// entity, recorded as a revision with the author in ctx.
 func (h *ContentAnalysisHandler) updateSourceContent(
 	ctx context.Context,
 	sourceTable string,
@@ -986,10 +1074,9 @@ func (h *ContentAnalysisHandler) updateSourceContent(
 	switch sourceTable {
 	case "entities":
 		switch sourceField {
-		case "description":
-			updateSQL = "UPDATE entities SET description = $2, updated_at = NOW() WHERE id = $1"
-		case "gm_notes":
-			updateSQL = "UPDATE entities SET gm_notes = $2, updated_at = NOW() WHERE id = $1"
+		case "description", "gm_notes":
+			_, err := h.db.UpdateEntityContentField(ctx, sourceID, sourceField, content)
+			return err
 		default:
 			return fmt.Errorf("unsupported field %q for table %q", sourceField, sourceTable)
 		}
@@ -1117,8 +1204,13 @@ func (h *ContentAnalysisHandler) RevertItem(w http.ResponseWriter, r *http.Reque
 	if (resolution == "accepted" || resolution == "new_entity") &&
 		posStart != nil && posEnd != nil {
 
+		fixCtx := database.WithEntityRevisionAuthor(r.Context(), models.EntityRevisionAuthor{
+			Type:           models.EntityRevisionAuthorUser,
+			UserID:         &userID,
+			AnalysisItemID: &itemID,
+		})
 		delta, fixErr := h.revertContentFix(
-			r.Context(), campaignID, srcTable, srcID, srcField,
+			fixCtx, campaignID, srcTable, srcID, srcField,
 			*posStart, matchedText,
 		)
 		if fixErr != nil {
@@ -1252,51 +1344,7 @@ func (h *ContentAnalysisHandler) revertContentFix(
 	newContent := content[:foundOffset] + matchedText +
 		content[foundOffset+foundLen:]
 
-	// Build the update SQL using the same safe switch pattern as
-	// applyContentFix.
-	var updateSQL string
-	switch sourceTable {
-	case "entities":
-		switch sourceField {
-		case "description":
-			updateSQL = "UPDATE entities SET description = $2, updated_at = NOW() WHERE id = $1"
-		case "gm_notes":
-			updateSQL = "UPDATE entities SET gm_notes = $2, updated_at = NOW() WHERE id = $1"
-		default:
-			return 0, fmt.Errorf("unsupported field %q for table %q",
-				sourceField, sourceTable)
-		}
-	case "chapters":
-		switch sourceField {
-		case "overview":
-			updateSQL = "UPDATE chapters SET overview = $2, updated_at = NOW() WHERE id = $1"
-		default:
-			return 0, fmt.Errorf("unsupported field %q for table %q",
-				sourceField, sourceTable)
-		}
-	case "sessions":
-		switch sourceField {
-		case "prep_notes":
-			updateSQL = "UPDATE sessions SET prep_notes = $2, updated_at = NOW() WHERE id = $1"
-		case "actual_notes":
-			updateSQL = "UPDATE sessions SET actual_notes = $2, updated_at = NOW() WHERE id = $1"
-		default:
-			return 0, fmt.Errorf("unsupported field %q for table %q",
-				sourceField, sourceTable)
-		}
-	case "campaigns":
-		switch sourceField {
-		case "description":
-			updateSQL = "UPDATE campaigns SET description = $2, updated_at = NOW() WHERE id = $1"
-		default:
-			return 0, fmt.Errorf("unsupported field %q for table %q",
-				sourceField, sourceTable)
-		}
-	default:
-		return 0, fmt.Errorf("unsupported source table: %s", sourceTable)
-	}
-
-	if err := h.db.Exec(ctx, updateSQL, sourceID, newContent); err != nil {
+	if err := h.updateSourceContent(ctx, sourceTable, sourceID, sourceField, newContent); err != nil {
 		return 0, fmt.Errorf("failed to update content in %s.%s: %w",
 			sourceTable, sourceField, err)
 	}
@@ -1382,7 +1430,7 @@ func (h *ContentAnalysisHandler) handleRelationshipSuggestion(
 }
 
 // handleDescriptionUpdate applies an accepted description update to
-// the entity.
+// the entity. The revision is attributed to the author in ctx.
 func (h *ContentAnalysisHandler) handleDescriptionUpdate(
 	ctx context.Context,
 	entityID int64,
@@ -1429,6 +1477,152 @@ func (h *ContentAnalysisHandler) handleLogEntry(
 	}
 }
 
+// sessionMemoryFromSuggestion builds the session memory to save for an
+// accepted session_memory item. Fields present in override replace
+// those of the suggestion; edited reports whether they changed
+// anything. The result is validated against the session_memories
+// constraints.
+func sessionMemoryFromSuggestion(
+	suggestedContent json.RawMessage,
+	override map[string]interface{},
+) (mem *models.CreateSessionMemoryRequest, edited bool, err error) {
+	var suggestion models.SessionMemorySuggestion
+	if err := json.Unmarshal(suggestedContent, &suggestion); err != nil {
+		return nil, false, fmt.Errorf("invalid session memory suggestion: %w", err)
+	}
+
+	if len(override) > 0 {
+		original := suggestion
+		data, err := json.Marshal(override)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid session memory override: %w", err)
+		}
+		if err := json.Unmarshal(data, &suggestion); err != nil {
+			return nil, false, fmt.Errorf("invalid session memory override: %w", err)
+		}
+		edited = !reflect.DeepEqual(original, suggestion)
+	}
+
+	switch suggestion.MemoryType {
+	case models.SessionMemoryTypeSummary, models.SessionMemoryTypeScene,
+		models.SessionMemoryTypeDecision, models.SessionMemoryTypeDiscovery,
+		models.SessionMemoryTypeMoment:
+	default:
+		return nil, false, fmt.Errorf("invalid session memory type: %s", suggestion.MemoryType)
+	}
+	content := strings.TrimSpace(suggestion.Content)
+	if content == "" {
+		return nil, false, errors.New("session memory content is required")
+	}
+	if suggestion.Importance < 1 || suggestion.Importance > 10 {
+		return nil, false, errors.New("session memory importance must be between 1 and 10")
+	}
+	if suggestion.SceneIndex != nil && *suggestion.SceneIndex < 1 {
+		return nil, false, errors.New("session memory scene index must be positive")
+	}
+
+	mem = &models.CreateSessionMemoryRequest{
+		MemoryType:        suggestion.MemoryType,
+		Content:           content,
+		SceneIndex:        suggestion.SceneIndex,
+		EntitiesMentioned: suggestion.EntityIDs,
+		Importance:        &suggestion.Importance,
+		IsPlayerVisible:   suggestion.IsPlayerVisible,
+	}
+	if title := strings.TrimSpace(suggestion.Title); title != "" {
+		mem.Title = &title
+	}
+	return mem, edited, nil
+}
+
+// handleSessionMemory saves an accepted session memory to the session
+// its analysis job was extracted from.
+func (h *ContentAnalysisHandler) handleSessionMemory(
+	ctx context.Context,
+	sessionID int64,
+	itemID int64,
+	mem models.CreateSessionMemoryRequest,
+	gmEdited bool,
+) {
+	created, err := h.db.CreateSessionMemory(ctx, sessionID, mem, gmEdited)
+	if err != nil {
+		log.Printf("handleSessionMemory: failed to create session memory from item %d: %v",
+			itemID, err)
+		return
+	}
+
+	log.Printf("Created session memory %d for session %d from analysis item %d",
+		created.ID, sessionID, itemID)
+}
+
+// handleCanonContradiction records an accepted canon contradiction
+// item as a canon conflict. Recording the same item twice keeps the
+// first conflict.
+func (h *ContentAnalysisHandler) handleCanonContradiction(
+	ctx context.Context,
+	campaignID int64,
+	itemID int64,
+	srcTable string,
+	srcID int64,
+	srcField string,
+	suggestedContent json.RawMessage,
+) {
+	req, err := canonConflictFromItem(itemID, srcTable, srcID, srcField, suggestedContent)
+	if err != nil {
+		log.Printf("handleCanonContradiction: item %d: %v", itemID, err)
+		return
+	}
+
+	conflict, err := h.db.CreateCanonConflict(ctx, campaignID, req)
+	if err != nil {
+		log.Printf("handleCanonContradiction: failed to create canon conflict from item %d: %v",
+			itemID, err)
+		return
+	}
+
+	log.Printf("Recorded canon conflict %d in campaign %d from analysis item %d",
+		conflict.ID, campaignID, itemID)
+}
+
+// handlePotentialAlias records the text of an accepted potential
+// alias item as an alias of the entity, so that later analyses find
+// it as a mention instead of flagging it again. Text that is already
+// an alias or an entity name is left alone.
+func (h *ContentAnalysisHandler) handlePotentialAlias(
+	ctx context.Context,
+	campaignID int64,
+	itemID int64,
+	entityID int64,
+	matchedText string,
+) {
+	alias := strings.TrimSpace(matchedText)
+	if alias == "" {
+		return
+	}
+
+	isName, err := h.db.EntityNameExists(ctx, campaignID, alias)
+	if err != nil {
+		log.Printf("handlePotentialAlias: item %d: %v", itemID, err)
+		return
+	}
+	if isName {
+		return
+	}
+
+	created, err := h.db.CreateEntityAlias(ctx, entityID, alias, models.EntityAliasSourceAnalysis)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return
+		}
+		log.Printf("handlePotentialAlias: failed to record alias from item %d: %v",
+			itemID, err)
+		return
+	}
+
+	log.Printf("Recorded alias %d (%q) of entity %d from analysis item %d",
+		created.ID, alias, entityID, itemID)
+}
+
 // CancelEnrichment handles POST /api/campaigns/{id}/analysis/jobs/{jobId}/cancel-enrichment
 // Cancels a running LLM enrichment for the specified job.
 func (h *ContentAnalysisHandler) CancelEnrichment(w http.ResponseWriter, r *http.Request) {
@@ -1471,16 +1665,17 @@ func (h *ContentAnalysisHandler) CancelEnrichment(w http.ResponseWriter, r *http
 		return
 	}
 
-	// Cancel the enrichment goroutine if running.
-	if cancelVal, ok := h.enrichCancels.LoadAndDelete(jobID); ok {
-		cancelVal.(context.CancelFunc)()
+	// Cancel queued or running enrichment for the job.
+	if h.queue != nil {
+		if _, err := h.queue.CancelAnalysisJob(r.Context(), jobID); err != nil {
+			log.Printf("CancelEnrichment: failed to cancel background jobs for job %d: %v",
+				jobID, err)
+		}
 	}
 
-	// Set job status to completed.
-	if err := h.db.Exec(r.Context(),
-		"UPDATE content_analysis_jobs SET status = 'completed' WHERE id = $1",
-		jobID,
-	); err != nil {
+	// Set job status to completed, telling streaming clients that
+	// enrichment was cancelled.
+	if err := h.db.CompleteAnalysisJob(r.Context(), jobID, true); err != nil {
 		log.Printf("CancelEnrichment: failed to update job %d status: %v", jobID, err)
 		respondError(w, http.StatusInternalServerError, "Failed to update job status")
 		return
@@ -1492,8 +1687,9 @@ func (h *ContentAnalysisHandler) CancelEnrichment(w http.ResponseWriter, r *http
 // RunContentEnrichment runs LLM enrichment directly on content without
 // depending on Phase 1 analysis results. It delegates entity discovery
 // and enrichment to the Pipeline and EnrichmentAgent. Results are saved
-// as enrichment-phase analysis items on the given job. The method runs
-// enrichment in a background goroutine and returns immediately.
+// as enrichment-phase analysis items on the given job. The method
+// queues enrichment on the background job queue and returns
+// immediately.
 func (h *ContentAnalysisHandler) RunContentEnrichment(
 	ctx context.Context,
 	jobID int64,
@@ -1508,30 +1704,18 @@ func (h *ContentAnalysisHandler) RunContentEnrichment(
 			jobID, userID)
 		return
 	}
-	if settings.ContentGenService == nil || settings.ContentGenAPIKey == nil {
+	if _, configured := contentGenAPIKey(settings); !configured {
 		log.Printf("Content-enrich: skipping job %d — no LLM configured (service=%v, key=%v)",
 			jobID, settings.ContentGenService != nil, settings.ContentGenAPIKey != nil)
 		return
 	}
-
-	// 2. Create LLM provider from user settings.
-	provider, err := llm.NewProvider(
-		*settings.ContentGenService, *settings.ContentGenAPIKey,
-	)
-	if err != nil {
-		log.Printf("Content-enrich: failed to create LLM provider for job %d: %v",
-			jobID, err)
-		return
-	}
-
-	// 3. Get the job for source info.
-	job, err := h.db.GetAnalysisJob(ctx, jobID)
-	if err != nil {
-		log.Printf("Content-enrich: failed to get job %d: %v", jobID, err)
+	if campaignBudgetExceeded(ctx, h.db, campaignID) {
+		log.Printf("Content-enrich: skipping job %d — campaign %d exceeded its monthly token budget",
+			jobID, campaignID)
 		return
 	}
 
-	// 4. Set job status to "enriching".
+	// 2. Set job status to "enriching".
 	if err := h.db.Exec(ctx,
 		"UPDATE content_analysis_jobs SET status = 'enriching' WHERE id = $1",
 		jobID,
@@ -1541,111 +1725,174 @@ func (h *ContentAnalysisHandler) RunContentEnrichment(
 		return
 	}
 
-	// 5. Look up the campaign to get its game system code for RAG context.
-	campaign, campaignErr := h.db.GetCampaign(ctx, campaignID)
-	var gameSystemCode string
-	if campaignErr != nil {
-		log.Printf("Content-enrich: failed to get campaign %d: %v",
-			campaignID, campaignErr)
-	} else if campaign.System != nil {
-		gameSystemCode = campaign.System.Code
+	// 3. Queue the enrichment.
+	if err := enqueueEnrichment(ctx, h.db, h.queue, campaignID, jobID, enrichmentJobPayload{
+		UserID:  userID,
+		Content: content,
+	}); err != nil {
+		log.Printf("Content-enrich: failed to queue enrichment for job %d: %v",
+			jobID, err)
+		return
 	}
+	log.Printf("Content-enrich: queued enrichment for job %d", jobID)
+}
 
-	// 6. Build pipeline and spawn background goroutine for enrichment.
-	pipeline := buildDefaultPipeline(h.db)
-
-	bgCtx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
-	h.enrichCancels.Store(jobID, cancel)
-	go func() {
-		defer h.enrichCancels.Delete(jobID)
-		defer cancel()
-		log.Printf("Content-enrich: starting enrichment for job %d", jobID)
-
-		// Build RAG context for the enrichment pipeline.
-		ctxBuilder := enrichment.NewContextBuilder(h.db, "")
-		ragCtx, ragErr := ctxBuilder.BuildContext(bgCtx, campaignID, content, gameSystemCode, nil)
-		if ragErr != nil {
-			log.Printf("Content-enrich: failed to build RAG context for job %d: %v",
-				jobID, ragErr)
-		}
+// RunSessionMemoryExtraction queues the extraction of a session's
+// memories when the session moves to wrap-up. A content analysis job
+// for the session's memories is created, replacing any earlier one,
+// and the extracted memories are added to it as enrichment-phase items
+// for the GM to review. Nothing is queued if the user has not
+// configured an LLM or the campaign is over its token budget.
+func (h *ContentAnalysisHandler) RunSessionMemoryExtraction(
+	ctx context.Context,
+	session *models.Session,
+	userID int64,
+) {
+	settings, err := h.db.GetUserSettings(ctx, userID)
+	if err != nil || settings == nil {
+		log.Printf("Session memory: skipping session %d — no user settings found for user %d",
+			session.ID, userID)
+		return
+	}
+	if _, configured := contentGenAPIKey(settings); !configured {
+		log.Printf("Session memory: skipping session %d — no LLM configured", session.ID)
+		return
+	}
+	if campaignBudgetExceeded(ctx, h.db, session.CampaignID) {
+		log.Printf("Session memory: skipping session %d — campaign %d exceeded its monthly token budget",
+			session.ID, session.CampaignID)
+		return
+	}
+	if h.queue == nil {
+		log.Printf("Session memory: skipping session %d — job queue is not running", session.ID)
+		return
+	}
 
-		// Load relationships for the enrichment pipeline so
-		// the graph expert receives complete data.
-		relationships, relErr := h.db.ListRelationshipsByCampaign(
-			bgCtx, campaignID)
-		if relErr != nil {
-			log.Printf(
-				"Content-enrich: failed to load relationships for job %d: %v",
-				jobID, relErr)
-			// Continue without relationships rather than
-			// blocking enrichment.
-		}
+	// Keep a single memory review job per session.
+	if err := h.db.DeleteAnalysisJobsForSource(
+		ctx, session.CampaignID, "sessions", session.ID, sessionMemorySourceField,
+	); err != nil {
+		log.Printf("Session memory: failed to delete old jobs for session %d: %v",
+			session.ID, err)
+		return
+	}
 
-		var gameSystemID *int64
-		if campaign != nil {
-			gameSystemID = campaign.SystemID
-		}
+	job, err := h.db.CreateAnalysisJob(ctx, &models.ContentAnalysisJob{
+		CampaignID:  session.CampaignID,
+		SourceTable: "sessions",
+		SourceID:    session.ID,
+		SourceField: sessionMemorySourceField,
+		Status:      "enriching",
+		Phases:      []string{"enrich"},
+	})
+	if err != nil {
+		log.Printf("Session memory: failed to create job for session %d: %v",
+			session.ID, err)
+		return
+	}
 
-		input := enrichment.PipelineInput{
-			CampaignID:    campaignID,
-			JobID:         jobID,
-			SourceTable:   job.SourceTable,
-			SourceID:      job.SourceID,
-			SourceScope:   enrichment.ScopeFromSourceTable(job.SourceTable),
-			Content:       content,
-			Relationships: relationships,
-			GameSystemID:  gameSystemID,
-			Context:       ragCtx,
-			Ontology:      h.db.Ontology,
+	if _, err := h.queue.Enqueue(ctx, jobKindSessionMemory, &session.CampaignID, &job.ID,
+		sessionMemoryJobPayload{UserID: userID, SessionID: session.ID},
+	); err != nil {
+		log.Printf("Session memory: failed to queue extraction for job %d: %v", job.ID, err)
+		if dbErr := h.db.SetJobFailureReason(context.WithoutCancel(ctx), job.ID,
+			"Failed to queue session memory extraction"); dbErr != nil {
+			log.Printf("Session memory: failed to mark job %d failed: %v", job.ID, dbErr)
 		}
+		return
+	}
+	log.Printf("Session memory: queued extraction for session %d (job %d)", session.ID, job.ID)
+}
 
-		enrichItems, err := pipeline.Run(bgCtx, provider, input)
-		if err != nil {
-			log.Printf(
-				"Content-enrich: pipeline run failed for job %d: %v",
-				jobID, err)
+// minEntityExtractionChars is the shortest text worth scanning for
+// entities. Shorter fragments rarely name anything and would cost an
+// LLM call each.
+const minEntityExtractionChars = 20
+
+// RunEntityExtraction queues the detection of entities in text the GM
+// wrote during play, from source. The entities found are added to the
+// session's entity extractions for the GM to accept, defer or reject.
+// Nothing is queued for text too short to name anything, if the user
+// has not configured an LLM or if the campaign is over its token
+// budget.
+func (h *ContentAnalysisHandler) RunEntityExtraction(
+	ctx context.Context,
+	session *models.Session,
+	userID int64,
+	source models.EntityExtractionSource,
+	text string,
+) {
+	text = strings.TrimSpace(text)
+	if len([]rune(text)) < minEntityExtractionChars {
+		return
+	}
+	if !h.canQueueSessionJob(ctx, "Entity extraction", session, userID) {
+		return
+	}
 
-			reason := "Enrichment encountered an error"
-			var qe *llm.QuotaExceededError
-			if errors.As(err, &qe) {
-				reason = "API quota exceeded"
-			} else if strings.Contains(err.Error(), "rate limit") {
-				reason = "Rate limited after retries"
-			}
+	if _, err := h.queue.Enqueue(ctx, jobKindEntityExtraction, &session.CampaignID, nil,
+		entityExtractionJobPayload{
+			UserID:    userID,
+			SessionID: session.ID,
+			Source:    source,
+			Text:      text,
+		},
+	); err != nil {
+		log.Printf("Entity extraction: failed to queue extraction for session %d: %v",
+			session.ID, err)
+	}
+}
 
-			_ = h.db.SetJobFailureReason(
-				context.Background(), jobID, reason)
-			return
-		}
+// RunChatCompression queues the folding of the older turns of a
+// session's play assistant chat into a new rolling summary. Nothing is
+// queued if the user has not configured an LLM or the campaign is over
+// its token budget.
+func (h *ContentAnalysisHandler) RunChatCompression(
+	ctx context.Context,
+	session *models.Session,
+	userID int64,
+) {
+	if !h.canQueueSessionJob(ctx, "Chat compression", session, userID) {
+		return
+	}
 
-		if len(enrichItems) > 0 {
-			if err := h.db.CreateAnalysisItems(bgCtx, enrichItems); err != nil {
-				log.Printf(
-					"Content-enrich: failed to save items for job %d: %v",
-					jobID, err)
-			} else {
-				if err := h.db.Exec(bgCtx,
-					"UPDATE content_analysis_jobs SET enrichment_total = enrichment_total + $1 WHERE id = $2",
-					len(enrichItems), jobID,
-				); err != nil {
-					log.Printf(
-						"Content-enrich: failed to update enrichment count for job %d: %v",
-						jobID, err)
-				}
-			}
-		}
+	if _, err := h.queue.Enqueue(ctx, jobKindChatCompression, &session.CampaignID, nil,
+		chatCompressionJobPayload{UserID: userID, SessionID: session.ID},
+	); err != nil {
+		log.Printf("Chat compression: failed to queue compression for session %d: %v",
+			session.ID, err)
+	}
+}
 
-		// Mark enrichment as completed.
-		log.Printf("Content-enrich: completed enrichment for job %d", jobID)
-		if err := h.db.Exec(context.Background(),
-			"UPDATE content_analysis_jobs SET status = 'completed' WHERE id = $1",
-			jobID,
-		); err != nil {
-			log.Printf(
-				"Content-enrich: failed to set job %d status to completed: %v",
-				jobID, err)
-		}
-	}()
+// canQueueSessionJob reports whether an LLM background job for session
+// can be queued: the user has configured an LLM, the campaign is within
+// its token budget and the job queue is running. Reasons other than a
+// missing LLM configuration are logged with prefix.
+func (h *ContentAnalysisHandler) canQueueSessionJob(
+	ctx context.Context,
+	prefix string,
+	session *models.Session,
+	userID int64,
+) bool {
+	settings, err := h.db.GetUserSettings(ctx, userID)
+	if err != nil || settings == nil {
+		log.Printf("%s: skipping session %d — no user settings found for user %d",
+			prefix, session.ID, userID)
+		return false
+	}
+	if _, configured := contentGenAPIKey(settings); !configured {
+		return false
+	}
+	if campaignBudgetExceeded(ctx, h.db, session.CampaignID) {
+		log.Printf("%s: skipping session %d — campaign %d exceeded its monthly token budget",
+			prefix, session.ID, session.CampaignID)
+		return false
+	}
+	if h.queue == nil {
+		log.Printf("%s: skipping session %d — job queue is not running", prefix, session.ID)
+		return false
+	}
+	return true
 }
 
 // TryAutoEnrich automatically triggers LLM enrichment when all Phase 1
@@ -1661,30 +1908,25 @@ func (h *ContentAnalysisHandler) TryAutoEnrich(
 		log.Printf("Auto-enrich: skipping job %d — no user settings found for user %d", jobID, userID)
 		return
 	}
-	if settings.ContentGenService == nil || settings.ContentGenAPIKey == nil {
+	if _, configured := contentGenAPIKey(settings); !configured {
 		log.Printf("Auto-enrich: skipping job %d — no LLM configured (service=%v, key=%v)",
 			jobID, settings.ContentGenService != nil, settings.ContentGenAPIKey != nil)
 		return
 	}
 
-	// 2. Create LLM provider from user settings.
-	provider, err := llm.NewProvider(
-		*settings.ContentGenService, *settings.ContentGenAPIKey,
-	)
-	if err != nil {
-		log.Printf("Auto-enrich: failed to create LLM provider for job %d: %v",
-			jobID, err)
-		return
-	}
-
-	// 3. Get the job for source info.
+	// 2. Get the job for source info.
 	job, err := h.db.GetAnalysisJob(ctx, jobID)
 	if err != nil {
 		log.Printf("Auto-enrich: failed to get job %d: %v", jobID, err)
 		return
 	}
+	if campaignBudgetExceeded(ctx, h.db, job.CampaignID) {
+		log.Printf("Auto-enrich: skipping job %d — campaign %d exceeded its monthly token budget",
+			jobID, job.CampaignID)
+		return
+	}
 
-	// 4. Collect accepted items with resolved entity IDs.
+	// 3. Collect accepted items with resolved entity IDs.
 	items, err := h.db.ListAnalysisItemsByJob(ctx, jobID, "", "identification")
 	if err != nil {
 		log.Printf("Auto-enrich: failed to list items for job %d: %v",
@@ -1710,7 +1952,7 @@ func (h *ContentAnalysisHandler) TryAutoEnrich(
 		entityIDs = append(entityIDs, id)
 	}
 
-	// 5. Set job status to "enriching".
+	// 4. Set job status to "enriching".
 	if err := h.db.Exec(ctx,
 		"UPDATE content_analysis_jobs SET status = 'enriching' WHERE id = $1",
 		jobID,
@@ -1720,143 +1962,17 @@ func (h *ContentAnalysisHandler) TryAutoEnrich(
 		return
 	}
 
-	// 6. Fetch source content.
-	content, err := fetchSourceContent(
-		ctx, h.db, job.CampaignID, job.SourceTable, job.SourceID, job.SourceField,
-	)
-	if err != nil {
-		log.Printf("Auto-enrich: failed to fetch content for job %d: %v",
+	// 5. Queue the enrichment. Source content is read when the job runs.
+	if err := enqueueEnrichment(ctx, h.db, h.queue, job.CampaignID, jobID, enrichmentJobPayload{
+		UserID:    userID,
+		EntityIDs: entityIDs,
+	}); err != nil {
+		log.Printf("Auto-enrich: failed to queue enrichment for job %d: %v",
 			jobID, err)
-		_ = h.db.Exec(ctx,
-			"UPDATE content_analysis_jobs SET status = 'completed' WHERE id = $1",
-			jobID,
-		)
 		return
 	}
-
-	// 7. Pre-load entity objects from accepted entity IDs.
-	entities := make([]models.Entity, 0, len(entityIDs))
-	for _, eid := range entityIDs {
-		entity, err := h.db.GetEntity(ctx, eid)
-		if err != nil {
-			log.Printf("Auto-enrich: failed to get entity %d: %v", eid, err)
-			continue
-		}
-		entities = append(entities, *entity)
-	}
-
-	// Strip GM-only content from entities before passing to pipeline.
-	for i := range entities {
-		entities[i].GMNotes = nil
-	}
-
-	// 8. Look up the campaign to get its game system code for RAG context.
-	campaign, campaignErr := h.db.GetCampaign(ctx, job.CampaignID)
-	var gameSystemCode string
-	if campaignErr != nil {
-		log.Printf("Auto-enrich: failed to get campaign %d: %v",
-			job.CampaignID, campaignErr)
-	} else if campaign.System != nil {
-		gameSystemCode = campaign.System.Code
-	}
-
-	// 9. Build pipeline and spawn background goroutine for enrichment.
-	pipeline := buildDefaultPipeline(h.db)
-
-	bgCtx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
-	h.enrichCancels.Store(jobID, cancel)
-	go func() {
-		defer h.enrichCancels.Delete(jobID)
-		defer cancel()
-		log.Printf("Auto-enrich: starting enrichment for job %d with %d entities",
-			jobID, len(entities))
-
-		// Build RAG context for the enrichment pipeline.
-		ctxBuilder := enrichment.NewContextBuilder(h.db, "")
-		ragCtx, ragErr := ctxBuilder.BuildContext(bgCtx, job.CampaignID, content, gameSystemCode, entities)
-		if ragErr != nil {
-			log.Printf("Auto-enrich: failed to build RAG context for job %d: %v",
-				jobID, ragErr)
-		}
-
-		// Load relationships for the enrichment pipeline so
-		// the graph expert receives complete data.
-		relationships, relErr := h.db.ListRelationshipsByCampaign(
-			bgCtx, job.CampaignID)
-		if relErr != nil {
-			log.Printf(
-				"Auto-enrich: failed to load relationships for job %d: %v",
-				jobID, relErr)
-			// Continue without relationships rather than
-			// blocking enrichment.
-		}
-
-		var gameSystemID *int64
-		if campaign != nil {
-			gameSystemID = campaign.SystemID
-		}
-
-		input := enrichment.PipelineInput{
-			CampaignID:    job.CampaignID,
-			JobID:         jobID,
-			SourceTable:   job.SourceTable,
-			SourceID:      job.SourceID,
-			SourceScope:   enrichment.ScopeFromSourceTable(job.SourceTable),
-			Content:       content,
-			Entities:      entities,
-			Relationships: relationships,
-			GameSystemID:  gameSystemID,
-			Context:       ragCtx,
-			Ontology:      h.db.Ontology,
-		}
-
-		enrichItems, err := pipeline.Run(bgCtx, provider, input)
-		if err != nil {
-			log.Printf(
-				"Auto-enrich: pipeline run failed for job %d: %v",
-				jobID, err)
-
-			reason := "Enrichment encountered an error"
-			var qe *llm.QuotaExceededError
-			if errors.As(err, &qe) {
-				reason = "API quota exceeded"
-			} else if strings.Contains(err.Error(), "rate limit") {
-				reason = "Rate limited after retries"
-			}
-
-			_ = h.db.SetJobFailureReason(
-				context.Background(), jobID, reason)
-			return
-		}
-
-		if len(enrichItems) > 0 {
-			if err := h.db.CreateAnalysisItems(bgCtx, enrichItems); err != nil {
-				log.Printf(
-					"Auto-enrich: failed to save items for job %d: %v",
-					jobID, err)
-			} else {
-				if err := h.db.Exec(bgCtx,
-					"UPDATE content_analysis_jobs SET enrichment_total = enrichment_total + $1 WHERE id = $2",
-					len(enrichItems), jobID,
-				); err != nil {
-					log.Printf(
-						"Auto-enrich: failed to update enrichment count for job %d: %v",
-						jobID, err)
-				}
-			}
-		}
-
-		// Mark enrichment as completed.
-		log.Printf("Auto-enrich: completed enrichment for job %d", jobID)
-		if err := h.db.Exec(context.Background(),
-			"UPDATE content_analysis_jobs SET status = 'completed' WHERE id = $1",
-			jobID,
-		); err != nil {
-			log.Printf(
-				"Auto-enrich: failed to set job %d status to completed: %v",
-				jobID, err)
-		}
-	}()
+	log.Printf("Auto-enrich: queued enrichment for job %d with %d entities",
+		jobID, len(entityIDs))
 }
 
 // GenerateRevisionResponse is the response body for the generate
@@ -1991,7 +2107,7 @@ func (h *ContentAnalysisHandler) GenerateRevision(w http.ResponseWriter, r *http
 		return
 	}
 
-	// Get the LLM provider from user settings.
+	// Check the LLM configuration before queueing.
 	settings, err := h.db.GetUserSettings(r.Context(), userID)
 	if err != nil {
 		log.Printf("GenerateRevision: error getting user settings: %v", err)
@@ -1999,60 +2115,48 @@ func (h *ContentAnalysisHandler) GenerateRevision(w http.ResponseWriter, r *http
 			"Failed to get user settings")
 		return
 	}
-	if settings == nil || settings.ContentGenService == nil || settings.ContentGenAPIKey == nil {
+	if _, configured := contentGenAPIKey(settings); !configured {
 		respondError(w, http.StatusBadRequest,
 			"LLM service not configured. Configure an LLM in Account Settings.")
 		return
 	}
+	if h.queue == nil {
+		respondError(w, http.StatusServiceUnavailable,
+			"Background job queue is not running")
+		return
+	}
 
-	provider, err := llm.NewProvider(
-		*settings.ContentGenService, *settings.ContentGenAPIKey,
-	)
+	// Run the RevisionAgent on the job queue and wait for its result.
+	// The wait outlives the request context like the LLM call did
+	// before it was queued.
+	bgJob, err := h.queue.Enqueue(r.Context(), jobKindRevision, &campaignID, &jobID,
+		revisionJobPayload{UserID: userID, OriginalContent: originalContent})
 	if err != nil {
-		log.Printf("GenerateRevision: error creating LLM provider: %v", err)
+		log.Printf("GenerateRevision: failed to queue revision for job %d: %v",
+			jobID, err)
 		respondError(w, http.StatusInternalServerError,
-			"Failed to create LLM provider")
+			"Failed to generate revision")
 		return
 	}
 
-	// Build RAG context for the revision agent.
-	campaign, campaignErr := h.db.GetCampaign(r.Context(), campaignID)
-	var gameSystemCode string
-	if campaignErr != nil {
-		log.Printf("GenerateRevision: failed to get campaign %d: %v",
-			campaignID, campaignErr)
-	} else if campaign.System != nil {
-		gameSystemCode = campaign.System.Code
-	}
-
-	ctxBuilder := enrichment.NewContextBuilder(h.db, "")
-	ragCtx, ragErr := ctxBuilder.BuildContext(
-		r.Context(), campaignID, originalContent,
-		gameSystemCode, nil)
-	if ragErr != nil {
-		log.Printf("GenerateRevision: failed to build RAG context for job %d: %v",
-			jobID, ragErr)
-	}
-
-	// Call the RevisionAgent to generate revised content.
-	revisionInput := enrichment.RevisionInput{
-		OriginalContent: originalContent,
-		AcceptedItems:   acceptedItems,
-		SourceTable:     job.SourceTable,
-		SourceID:        job.SourceID,
-	}
-	if ragCtx != nil {
-		revisionInput.GameSystemYAML = ragCtx.GameSystemYAML
-		revisionInput.CampaignResults = ragCtx.CampaignResults
+	waitCtx, waitCancel := context.WithTimeout(
+		context.WithoutCancel(r.Context()), revisionTimeout+30*time.Second)
+	defer waitCancel()
+	bgJob, err = h.queue.Wait(waitCtx, bgJob.ID)
+	if err != nil || bgJob.Status != models.BackgroundJobCompleted {
+		if err == nil && bgJob.LastError != nil {
+			err = errors.New(*bgJob.LastError)
+		}
+		log.Printf("GenerateRevision: revision agent failed for job %d: %v",
+			jobID, err)
+		respondError(w, http.StatusInternalServerError,
+			"Failed to generate revision")
+		return
 	}
 
-	llmCtx, llmCancel := context.WithTimeout(context.Background(), 3*time.Minute)
-	defer llmCancel()
-	result, err := enrichment.NewRevisionAgent().GenerateRevision(
-		llmCtx, provider, revisionInput,
-	)
-	if err != nil {
-		log.Printf("GenerateRevision: revision agent failed for job %d: %v",
+	var result enrichment.RevisionResult
+	if err := json.Unmarshal(bgJob.Result, &result); err != nil {
+		log.Printf("GenerateRevision: invalid revision result for job %d: %v",
 			jobID, err)
 		respondError(w, http.StatusInternalServerError,
 			"Failed to generate revision")
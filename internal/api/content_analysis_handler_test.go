@@ -24,12 +24,12 @@ import (
 )
 
 func TestNewContentAnalysisHandler(t *testing.T) {
-	handler := NewContentAnalysisHandler(nil)
+	handler := NewContentAnalysisHandler(nil, nil)
 	assert.NotNil(t, handler)
 }
 
 func TestContentAnalysisHandler_AuthEnforcement(t *testing.T) {
-	handler := NewContentAnalysisHandler(nil)
+	handler := NewContentAnalysisHandler(nil, nil)
 
 	tests := []struct {
 		name   string
@@ -359,7 +359,7 @@ func TestAnalysisSummary_JSONSerialization(t *testing.T) {
 }
 
 func TestContentAnalysis_RoutesRegistered(t *testing.T) {
-	router, err := NewRouter(nil, nil, testJWTSecret)
+	router, err := NewRouter(nil, nil, testJWTSecret, nil, nil)
 	require.NoError(t, err)
 
 	tests := []struct {
@@ -586,3 +586,70 @@ func TestWikiLinkReplacement(t *testing.T) {
 		})
 	}
 }
+
+func TestSessionMemoryFromSuggestion(t *testing.T) {
+	suggested := json.RawMessage(`{
+		"memoryType": "scene",
+		"title": " Ambush ",
+		"content": "Cultists ambushed the party.",
+		"sceneIndex": 2,
+		"entityIds": [7],
+		"entityNames": ["Mira Vance"],
+		"importance": 7,
+		"isPlayerVisible": true
+	}`)
+
+	t.Run("as suggested", func(t *testing.T) {
+		mem, edited, err := sessionMemoryFromSuggestion(suggested, nil)
+		require.NoError(t, err)
+		assert.False(t, edited)
+		assert.Equal(t, models.SessionMemoryTypeScene, mem.MemoryType)
+		require.NotNil(t, mem.Title)
+		assert.Equal(t, "Ambush", *mem.Title)
+		assert.Equal(t, []int64{7}, mem.EntitiesMentioned)
+		require.NotNil(t, mem.Importance)
+		assert.Equal(t, 7, *mem.Importance)
+		assert.True(t, mem.IsPlayerVisible)
+	})
+
+	t.Run("edited by the GM", func(t *testing.T) {
+		mem, edited, err := sessionMemoryFromSuggestion(suggested, map[string]interface{}{
+			"content":         "Cultists led by Mira ambushed the party.",
+			"isPlayerVisible": false,
+		})
+		require.NoError(t, err)
+		assert.True(t, edited)
+		assert.Equal(t, "Cultists led by Mira ambushed the party.", mem.Content)
+		assert.False(t, mem.IsPlayerVisible)
+		assert.Equal(t, []int64{7}, mem.EntitiesMentioned, "fields not overridden are kept")
+	})
+
+	t.Run("unchanged override", func(t *testing.T) {
+		_, edited, err := sessionMemoryFromSuggestion(suggested, map[string]interface{}{
+			"importance": 7,
+		})
+		require.NoError(t, err)
+		assert.False(t, edited)
+	})
+
+	invalid := []struct {
+		name     string
+		override map[string]interface{}
+	}{
+		{"unknown type", map[string]interface{}{"memoryType": "rumour"}},
+		{"blank content", map[string]interface{}{"content": "  "}},
+		{"importance too high", map[string]interface{}{"importance": 11}},
+		{"importance too low", map[string]interface{}{"importance": 0}},
+		{"non-positive scene index", map[string]interface{}{"sceneIndex": 0}},
+		{"wrong field type", map[string]interface{}{"importance": "high"}},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := sessionMemoryFromSuggestion(suggested, tc.override)
+			assert.Error(t, err)
+		})
+	}
+
+	_, _, err := sessionMemoryFromSuggestion(json.RawMessage(`not json`), nil)
+	assert.Error(t, err)
+}
@@ -16,23 +16,28 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/antonypegg/imagineer/internal/auth"
 	"github.com/antonypegg/imagineer/internal/database"
-	"github.com/antonypegg/imagineer/internal/enrichment"
-	"github.com/antonypegg/imagineer/internal/llm"
+	"github.com/antonypegg/imagineer/internal/events"
+	"github.com/antonypegg/imagineer/internal/jobs"
 	"github.com/antonypegg/imagineer/internal/models"
 )
 
 // EnrichmentHandler handles enrichment trigger and streaming API requests.
 type EnrichmentHandler struct {
-	db *database.DB
+	db     *database.DB
+	queue  *jobs.Queue
+	broker *events.Broker
 }
 
-// NewEnrichmentHandler creates a new EnrichmentHandler.
-func NewEnrichmentHandler(db *database.DB) *EnrichmentHandler {
-	return &EnrichmentHandler{db: db}
+// NewEnrichmentHandler creates a new EnrichmentHandler that runs
+// enrichment on queue and pushes enrichment events from broker to
+// streaming clients. When broker is nil, streams poll the event log.
+func NewEnrichmentHandler(db *database.DB, queue *jobs.Queue, broker *events.Broker) *EnrichmentHandler {
+	return &EnrichmentHandler{db: db, queue: queue, broker: broker}
 }
 
 // triggerEnrichmentResponse is the response body for the trigger
@@ -90,20 +95,12 @@ func (h *EnrichmentHandler) TriggerEnrichment(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	if settings == nil || settings.ContentGenService == nil || settings.ContentGenAPIKey == nil {
+	if _, configured := contentGenAPIKey(settings); !configured {
 		respondError(w, http.StatusBadRequest,
 			"LLM service not configured. Configure an LLM in Account Settings.")
 		return
 	}
 
-	// Create LLM provider
-	provider, err := llm.NewProvider(*settings.ContentGenService, *settings.ContentGenAPIKey)
-	if err != nil {
-		log.Printf("Error creating LLM provider: %v", err)
-		respondError(w, http.StatusInternalServerError, "Failed to create LLM provider")
-		return
-	}
-
 	// Collect accepted Phase 1 items with resolved entity IDs
 	items, err := h.db.ListAnalysisItemsByJob(r.Context(), jobID, "", "identification")
 	if err != nil {
@@ -157,109 +154,16 @@ func (h *EnrichmentHandler) TriggerEnrichment(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	// Build the pipeline with analysis and enrichment stages.
-	pipeline := buildDefaultPipeline(h.db)
-
-	// Pre-load entity objects from the accepted entity IDs.
-	entities := make([]models.Entity, 0, len(entityIDs))
-	for _, eid := range entityIDs {
-		entity, err := h.db.GetEntity(r.Context(), eid)
-		if err != nil {
-			log.Printf("Enrichment: error fetching entity %d: %v", eid, err)
-			continue
-		}
-		entities = append(entities, *entity)
-	}
-
-	// Look up the campaign to get its game system code for RAG context.
-	campaign, campaignErr := h.db.GetCampaign(r.Context(), campaignID)
-	var gameSystemCode string
-	if campaignErr != nil {
-		log.Printf("Enrichment: failed to get campaign %d: %v", campaignID, campaignErr)
-	} else if campaign.System != nil {
-		gameSystemCode = campaign.System.Code
-	}
-
-	// Spawn background goroutine for enrichment processing
-	go func() {
-		bgCtx, bgCancel := context.WithTimeout(context.Background(), 10*time.Minute)
-		defer bgCancel()
-
-		defer func() {
-			if r := recover(); r != nil {
-				log.Printf("Enrichment: panic in background goroutine for job %d: %v",
-					jobID, r)
-				if err := h.db.Exec(bgCtx,
-					"UPDATE content_analysis_jobs SET status = 'failed' WHERE id = $1",
-					jobID,
-				); err != nil {
-					log.Printf("Enrichment: error setting job %d to failed after panic: %v",
-						jobID, err)
-				}
-			}
-		}()
-
-		// Build RAG context for the enrichment pipeline.
-		ctxBuilder := enrichment.NewContextBuilder(h.db, "")
-		ragCtx, ragErr := ctxBuilder.BuildContext(bgCtx, campaignID, content, gameSystemCode, entities)
-		if ragErr != nil {
-			log.Printf("Enrichment: failed to build RAG context for job %d: %v",
-				jobID, ragErr)
-		}
-
-		var gameSystemID *int64
-		if campaign != nil {
-			gameSystemID = campaign.SystemID
-		}
-
-		// Strip GM-only content from entities before passing to pipeline.
-		for i := range entities {
-			entities[i].GMNotes = nil
-		}
-
-		input := enrichment.PipelineInput{
-			CampaignID:   campaignID,
-			JobID:        jobID,
-			SourceTable:  job.SourceTable,
-			SourceID:     job.SourceID,
-			SourceScope:  enrichment.ScopeFromSourceTable(job.SourceTable),
-			Content:      content,
-			Entities:     entities,
-			GameSystemID: gameSystemID,
-			Context:      ragCtx,
-		}
-
-		enrichItems, err := pipeline.Run(bgCtx, provider, input)
-		if err != nil {
-			log.Printf("Enrichment: pipeline run failed for job %d: %v", jobID, err)
-			if dbErr := h.db.Exec(bgCtx,
-				"UPDATE content_analysis_jobs SET status = 'failed' WHERE id = $1",
-				jobID,
-			); dbErr != nil {
-				log.Printf("Enrichment: error setting job %d to failed: %v", jobID, dbErr)
-			}
-			return
-		}
-
-		if len(enrichItems) > 0 {
-			if err := h.db.CreateAnalysisItems(bgCtx, enrichItems); err != nil {
-				log.Printf("Enrichment: error inserting items for job %d: %v",
-					jobID, err)
-			} else {
-				_ = h.db.Exec(bgCtx,
-					"UPDATE content_analysis_jobs SET enrichment_total = enrichment_total + $1 WHERE id = $2",
-					len(enrichItems), jobID)
-			}
-		}
-
-		// Mark enrichment as completed
-		if err := h.db.Exec(bgCtx,
-			"UPDATE content_analysis_jobs SET status = 'completed' WHERE id = $1",
-			jobID,
-		); err != nil {
-			log.Printf("Enrichment: error updating job status to completed: %v", err)
-		}
-	}()
+	// Queue the enrichment; the pipeline runs on a background worker.
+	if err := enqueueEnrichment(r.Context(), h.db, h.queue, campaignID, jobID, enrichmentJobPayload{
+		UserID:    userID,
+		Content:   content,
+		EntityIDs: entityIDs,
+	}); err != nil {
+		log.Printf("Error queueing enrichment for job %d: %v", jobID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to queue enrichment")
+		return
+	}
 
 	respondJSON(w, http.StatusOK, triggerEnrichmentResponse{
 		Status:      "enriching",
@@ -268,7 +172,10 @@ func (h *EnrichmentHandler) TriggerEnrichment(w http.ResponseWriter, r *http.Req
 }
 
 // EnrichmentStream handles GET /api/campaigns/{id}/analysis/jobs/{jobId}/enrichment-stream
-// Streams enrichment progress and new items via Server-Sent Events.
+// Streams enrichment events via Server-Sent Events. Events stored since
+// the Last-Event-ID header (or lastEventId query parameter) are
+// replayed first, then new events are pushed as they are published.
+// The stream ends once the job stops enriching.
 func (h *EnrichmentHandler) EnrichmentStream(w http.ResponseWriter, r *http.Request) {
 	campaignID, err := parseInt64(r, "id")
 	if err != nil {
@@ -306,10 +213,11 @@ func (h *EnrichmentHandler) EnrichmentStream(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// Set SSE headers
-	w.Header().Set("Content-Type", "text/event-stream")
-	w.Header().Set("Cache-Control", "no-cache")
-	w.Header().Set("Connection", "keep-alive")
+	lastEventID, err := parseLastEventID(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid Last-Event-ID")
+		return
+	}
 
 	flusher, ok := w.(http.Flusher)
 	if !ok {
@@ -317,64 +225,200 @@ func (h *EnrichmentHandler) EnrichmentStream(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// Track the last item ID to only send new items
-	var lastItemID int64
-	timeout := time.After(5 * time.Minute)
-	ticker := time.NewTicker(2 * time.Second)
-	defer ticker.Stop()
+	// The stream stays open for as long as the job runs, so lift the
+	// server's write timeout for this response.
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		log.Printf("SSE: could not clear write deadline for job %d: %v", jobID, err)
+	}
+
+	// Set SSE headers
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ctx := r.Context()
+	keepAlive := time.NewTicker(sseKeepAliveInterval)
+	defer keepAlive.Stop()
 
 	for {
+		// Subscribe before catching up so that no event published
+		// in between is missed; duplicates are skipped by ID.
+		var sub *events.Subscription
+		if h.broker != nil {
+			sub = h.broker.Subscribe(jobID)
+		}
+
+		done, err := h.catchUpEnrichmentEvents(ctx, w, jobID, &lastEventID)
+		flusher.Flush()
+		if err != nil || done {
+			if err != nil && ctx.Err() == nil {
+				log.Printf("SSE: error reading events for job %d: %v", jobID, err)
+			}
+			if sub != nil {
+				sub.Close()
+			}
+			return
+		}
+
+		if sub == nil {
+			// Without a broker, poll the event log instead.
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(sseFallbackPollInterval):
+			}
+			continue
+		}
+
+		finished := streamEnrichmentEvents(ctx, w, flusher, sub, keepAlive.C, &lastEventID)
+		sub.Close()
+		if finished {
+			return
+		}
+
+		// The broker dropped the subscription and may have missed
+		// events; resubscribe and catch up from the event log.
 		select {
-		case <-r.Context().Done():
+		case <-ctx.Done():
 			return
-		case <-timeout:
-			fmt.Fprintf(w, "event: timeout\ndata: {\"message\":\"Stream timeout\"}\n\n")
+		case <-time.After(sseResubscribeDelay):
+		}
+	}
+}
+
+// Timing of enrichment event streams.
+const (
+	// sseKeepAliveInterval is how often a comment is sent on an idle
+	// stream so that proxies do not close it.
+	sseKeepAliveInterval = 15 * time.Second
+
+	// sseResubscribeDelay is the pause before resubscribing after the
+	// broker drops a subscription, e.g. while it reconnects.
+	sseResubscribeDelay = time.Second
+
+	// sseFallbackPollInterval is how often the event log is read when
+	// no broker is running.
+	sseFallbackPollInterval = 2 * time.Second
+)
+
+// parseLastEventID returns the ID of the last event a reconnecting
+// client received, from the Last-Event-ID header that EventSource
+// sends or the lastEventId query parameter. It returns 0 when neither
+// is present.
+func parseLastEventID(r *http.Request) (int64, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("lastEventId")
+	}
+	if value == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 0 {
+		return 0, fmt.Errorf("invalid event ID %q", value)
+	}
+	return id, nil
+}
+
+// catchUpEnrichmentEvents writes the stored events of a job after
+// *lastEventID and advances it. It reports whether the stream is done
+// because the job is no longer enriching, in which case the final event
+// written is job_complete.
+func (h *EnrichmentHandler) catchUpEnrichmentEvents(
+	ctx context.Context,
+	w http.ResponseWriter,
+	jobID int64,
+	lastEventID *int64,
+) (bool, error) {
+	// Read the status before the events: the status change that ends
+	// enrichment is written together with its job_complete event, so
+	// a finished job's events always include it.
+	job, err := h.db.GetAnalysisJob(ctx, jobID)
+	if err != nil {
+		return false, err
+	}
+	active := job.Status == "enriching"
+
+	stored, err := h.db.ListAnalysisJobEvents(ctx, jobID, *lastEventID)
+	if err != nil {
+		return false, err
+	}
+
+	for i, ev := range stored {
+		final := !active && i == len(stored)-1
+		if ev.EventType == models.AnalysisEventJobComplete && !final {
+			// Completion of an earlier enrichment run of this job.
+			*lastEventID = ev.ID
+			continue
+		}
+		writeSSEEvent(w, ev.ID, ev.EventType, ev.Payload)
+		*lastEventID = ev.ID
+	}
+
+	if active {
+		return false, nil
+	}
+
+	// Jobs that finished before events were recorded, or whose
+	// completion the client has already seen, still get a final
+	// job_complete so that the client knows to stop.
+	if len(stored) == 0 || stored[len(stored)-1].EventType != models.AnalysisEventJobComplete {
+		payload, err := json.Marshal(models.JobCompleteEvent{
+			Status:        job.Status,
+			FailureReason: job.FailureReason,
+		})
+		if err != nil {
+			return true, err
+		}
+		writeSSEEvent(w, 0, models.AnalysisEventJobComplete, payload)
+	}
+	return true, nil
+}
+
+// streamEnrichmentEvents writes events from sub as they arrive, with a
+// keep-alive comment whenever keepAlive fires. It reports whether the
+// stream is finished, either because the job completed or the client
+// went away; false means the subscription was dropped.
+func streamEnrichmentEvents(
+	ctx context.Context,
+	w http.ResponseWriter,
+	flusher http.Flusher,
+	sub *events.Subscription,
+	keepAlive <-chan time.Time,
+	lastEventID *int64,
+) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return true
+		case <-keepAlive:
+			fmt.Fprint(w, ": keep-alive\n\n")
 			flusher.Flush()
-			return
-		case <-ticker.C:
-			// Fetch current job status
-			currentJob, err := h.db.GetAnalysisJob(r.Context(), jobID)
-			if err != nil {
-				log.Printf("SSE: error fetching job %d: %v", jobID, err)
-				continue
+		case ev, ok := <-sub.Events():
+			if !ok {
+				return false
 			}
-
-			// Fetch new enrichment items
-			enrichItems, err := h.db.ListAnalysisItemsByJob(r.Context(), jobID, "", "enrichment")
-			if err != nil {
-				log.Printf("SSE: error listing enrichment items for job %d: %v", jobID, err)
+			if ev.ID <= *lastEventID {
 				continue
 			}
-
-			// Send new items that we have not sent yet
-			for _, item := range enrichItems {
-				if item.ID > lastItemID {
-					itemJSON, err := json.Marshal(item)
-					if err != nil {
-						log.Printf("SSE: error marshalling item %d: %v", item.ID, err)
-						continue
-					}
-					fmt.Fprintf(w, "event: enrichment_item\ndata: %s\n\n", itemJSON)
-					lastItemID = item.ID
-				}
-			}
-
-			// Send progress heartbeat
-			progressJSON, _ := json.Marshal(map[string]interface{}{
-				"total":    currentJob.EnrichmentTotal,
-				"resolved": currentJob.EnrichmentResolved,
-				"status":   currentJob.Status,
-			})
-			fmt.Fprintf(w, "event: enrichment_progress\ndata: %s\n\n", progressJSON)
+			writeSSEEvent(w, ev.ID, ev.EventType, ev.Payload)
 			flusher.Flush()
-
-			// Close stream when job is completed (all items were already
-			// sent above, so no additional check is needed)
-			if currentJob.Status == "completed" {
-				fmt.Fprintf(w, "event: enrichment_complete\ndata: {\"status\":\"completed\"}\n\n")
-				flusher.Flush()
-				return
+			*lastEventID = ev.ID
+			if ev.EventType == models.AnalysisEventJobComplete {
+				return true
 			}
 		}
 	}
 }
+
+// writeSSEEvent writes a single Server-Sent Event. An id of 0 omits
+// the id field, leaving the client's last event ID unchanged.
+func writeSSEEvent(w http.ResponseWriter, id int64, event string, data []byte) {
+	if id > 0 {
+		fmt.Fprintf(w, "id: %d\n", id)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+}
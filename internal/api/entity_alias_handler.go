@@ -0,0 +1,223 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/antonypegg/imagineer/internal/database"
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// EntityAliasHandler handles entity alias CRUD API requests.
+type EntityAliasHandler struct {
+	db *database.DB
+}
+
+// NewEntityAliasHandler creates a new EntityAliasHandler.
+func NewEntityAliasHandler(db *database.DB) *EntityAliasHandler {
+	return &EntityAliasHandler{db: db}
+}
+
+// ListEntityAliases handles GET /api/campaigns/{id}/entities/{entityId}/aliases
+// Returns the aliases of an entity.
+func (h *EntityAliasHandler) ListEntityAliases(w http.ResponseWriter, r *http.Request) {
+	entity, _, ok := getOwnedEntity(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	aliases, err := h.db.ListEntityAliases(r.Context(), entity.ID)
+	if err != nil {
+		log.Printf("Error listing aliases of entity %d: %v", entity.ID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to list entity aliases")
+		return
+	}
+
+	if aliases == nil {
+		aliases = []models.EntityAlias{}
+	}
+
+	respondJSON(w, http.StatusOK, aliases)
+}
+
+// CreateEntityAlias handles POST /api/campaigns/{id}/entities/{entityId}/aliases
+// Adds an alias to an entity.
+func (h *EntityAliasHandler) CreateEntityAlias(w http.ResponseWriter, r *http.Request) {
+	entity, _, ok := getOwnedEntity(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	var req models.CreateEntityAliasRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	alias := strings.TrimSpace(req.Alias)
+	if alias == "" {
+		respondError(w, http.StatusBadRequest, "Alias is required")
+		return
+	}
+	if !h.checkAliasFree(w, r, entity.CampaignID, alias) {
+		return
+	}
+
+	created, err := h.db.CreateEntityAlias(r.Context(), entity.ID, alias, models.EntityAliasSourceManual)
+	if err != nil {
+		if isUniqueViolation(err) {
+			respondError(w, http.StatusConflict, fmt.Sprintf("Alias %q is already in use", alias))
+			return
+		}
+		log.Printf("Error creating alias of entity %d: %v", entity.ID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to create entity alias")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, created)
+}
+
+// UpdateEntityAlias handles PUT /api/campaigns/{id}/entities/{entityId}/aliases/{aliasId}
+// Renames an alias or reassigns it to another entity of the campaign.
+func (h *EntityAliasHandler) UpdateEntityAlias(w http.ResponseWriter, r *http.Request) {
+	entity, _, ok := getOwnedEntity(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	existing, ok := h.getAlias(w, r, entity.ID)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateEntityAliasRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Alias != nil {
+		alias := strings.TrimSpace(*req.Alias)
+		if alias == "" {
+			respondError(w, http.StatusBadRequest, "Alias cannot be empty")
+			return
+		}
+		req.Alias = &alias
+		if !strings.EqualFold(alias, existing.Alias) && !h.checkAliasFree(w, r, entity.CampaignID, alias) {
+			return
+		}
+	}
+
+	if req.EntityID != nil && *req.EntityID != entity.ID {
+		target, err := h.db.GetEntity(r.Context(), *req.EntityID)
+		if err != nil || target.CampaignID != entity.CampaignID {
+			respondError(w, http.StatusBadRequest, "Target entity not found")
+			return
+		}
+	}
+
+	updated, err := h.db.UpdateEntityAlias(r.Context(), existing.ID, req)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "Entity alias not found")
+			return
+		}
+		if isUniqueViolation(err) {
+			respondError(w, http.StatusConflict, fmt.Sprintf("Alias %q is already in use", *req.Alias))
+			return
+		}
+		log.Printf("Error updating entity alias %d: %v", existing.ID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to update entity alias")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, updated)
+}
+
+// DeleteEntityAlias handles DELETE /api/campaigns/{id}/entities/{entityId}/aliases/{aliasId}
+// Deletes an entity alias.
+func (h *EntityAliasHandler) DeleteEntityAlias(w http.ResponseWriter, r *http.Request) {
+	entity, _, ok := getOwnedEntity(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	existing, ok := h.getAlias(w, r, entity.ID)
+	if !ok {
+		return
+	}
+
+	if err := h.db.DeleteEntityAlias(r.Context(), existing.ID); err != nil {
+		log.Printf("Error deleting entity alias %d: %v", existing.ID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to delete entity alias")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getAlias returns the alias in the URL if it belongs to the entity,
+// writing an error response and returning false otherwise.
+func (h *EntityAliasHandler) getAlias(w http.ResponseWriter, r *http.Request, entityID int64) (*models.EntityAlias, bool) {
+	aliasID, err := parseInt64(r, "aliasId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid alias ID")
+		return nil, false
+	}
+
+	alias, err := h.db.GetEntityAlias(r.Context(), aliasID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "Entity alias not found")
+			return nil, false
+		}
+		log.Printf("Error getting entity alias %d: %v", aliasID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to get entity alias")
+		return nil, false
+	}
+	if alias.EntityID != entityID {
+		respondError(w, http.StatusNotFound, "Entity alias not found")
+		return nil, false
+	}
+
+	return alias, true
+}
+
+// checkAliasFree reports whether alias can be used in the campaign. An
+// alias may not be the name of an entity, or it would resolve to two
+// entities. Writes an error response and returns false if it cannot.
+func (h *EntityAliasHandler) checkAliasFree(w http.ResponseWriter, r *http.Request, campaignID int64, alias string) bool {
+	exists, err := h.db.EntityNameExists(r.Context(), campaignID, alias)
+	if err != nil {
+		log.Printf("Error checking entity names for alias %q: %v", alias, err)
+		respondError(w, http.StatusInternalServerError, "Failed to check entity names")
+		return false
+	}
+	if exists {
+		respondError(w, http.StatusConflict, fmt.Sprintf("An entity is already named %q", alias))
+		return false
+	}
+	return true
+}
+
+// isUniqueViolation reports whether err is a PostgreSQL unique_violation.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,86 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/antonypegg/imagineer/internal/database"
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+// validateEntityAttributes checks an entity's attributes against the
+// schema of the campaign's game system. In strict mode problems are
+// rejected with a 422 listing them, writing the response and returning
+// false; otherwise they are returned as warnings. Campaigns without a
+// game system, or whose system has no schema, are not checked.
+func validateEntityAttributes(
+	w http.ResponseWriter,
+	r *http.Request,
+	db *database.DB,
+	campaignID int64,
+	entityType models.EntityType,
+	attributes json.RawMessage,
+) ([]models.AttributeFieldError, bool) {
+	if db.GameSystems == nil {
+		return nil, true
+	}
+
+	campaign, err := db.GetCampaign(r.Context(), campaignID)
+	if err != nil {
+		// The campaign was checked by the caller; do not block the
+		// write on a failed lookup.
+		log.Printf("Error getting campaign %d to validate entity attributes: %v", campaignID, err)
+		return nil, true
+	}
+	if campaign.System == nil {
+		return nil, true
+	}
+	schema := db.GameSystems.Schema(campaign.System.Code)
+	if schema == nil {
+		return nil, true
+	}
+
+	fieldErrors := schema.ValidateAttributes(string(entityType), attributes)
+	if len(fieldErrors) == 0 {
+		return nil, true
+	}
+
+	if attributeValidationMode(campaign.Settings) == models.AttributeValidationStrict {
+		respondJSON(w, http.StatusUnprocessableEntity, models.AttributeValidationError{
+			Code:        http.StatusUnprocessableEntity,
+			Message:     "Entity attributes do not match the " + campaign.System.Name + " schema",
+			FieldErrors: fieldErrors,
+		})
+		return nil, false
+	}
+
+	return fieldErrors, true
+}
+
+// attributeValidationMode returns the attributeValidation setting of a
+// campaign, defaulting to warn-only.
+func attributeValidationMode(settings json.RawMessage) models.AttributeValidationMode {
+	var s struct {
+		AttributeValidation models.AttributeValidationMode `json:"attributeValidation"`
+	}
+	if len(settings) > 0 {
+		if err := json.Unmarshal(settings, &s); err != nil {
+			return models.AttributeValidationWarn
+		}
+	}
+	if s.AttributeValidation == models.AttributeValidationStrict {
+		return models.AttributeValidationStrict
+	}
+	return models.AttributeValidationWarn
+}
@@ -0,0 +1,37 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttributeValidationMode(t *testing.T) {
+	tests := []struct {
+		settings string
+		want     models.AttributeValidationMode
+	}{
+		{``, models.AttributeValidationWarn},
+		{`{}`, models.AttributeValidationWarn},
+		{`{"attributeValidation": "strict"}`, models.AttributeValidationStrict},
+		{`{"attributeValidation": "warn"}`, models.AttributeValidationWarn},
+		{`{"attributeValidation": "sometimes"}`, models.AttributeValidationWarn},
+		{`not json`, models.AttributeValidationWarn},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, attributeValidationMode(json.RawMessage(tt.settings)), tt.settings)
+	}
+}
@@ -20,6 +20,8 @@ import (
 
 	"github.com/antonypegg/imagineer/internal/auth"
 	"github.com/antonypegg/imagineer/internal/database"
+	"github.com/antonypegg/imagineer/internal/embedding"
+	"github.com/antonypegg/imagineer/internal/llm"
 	"github.com/antonypegg/imagineer/internal/models"
 	"github.com/go-chi/chi/v5"
 )
@@ -101,8 +103,9 @@ func (h *EntityDetectionHandler) DetectEntities(w http.ResponseWriter, r *http.R
 		svc := *settings.EmbeddingService
 		if svc == models.LLMServiceOllama {
 			// Ollama needs no API key; consider it configured if
-			// the vectorizer extension is available.
-			embeddingConfigured = h.db.IsVectorizationAvailable(r.Context())
+			// either semantic search backend is available.
+			embeddingConfigured = h.db.IsVectorizationAvailable(r.Context()) ||
+				h.db.IsEmbeddingIndexAvailable(r.Context())
 		} else {
 			// Cloud services require an API key.
 			embeddingConfigured = settings.EmbeddingAPIKey != nil &&
@@ -121,9 +124,10 @@ func (h *EntityDetectionHandler) DetectEntities(w http.ResponseWriter, r *http.R
 	var suggestions []EntitySuggestion
 
 	// Try vector-based detection first
-	if h.db.IsVectorizationAvailable(r.Context()) {
+	embedder := embedding.EmbedderForSettings(settings)
+	if embedding.Available(r.Context(), h.db, embedder) {
 		suggestions, err = h.detectEntitiesFromVectors(
-			r.Context(), campaignID, req.TextSegments, excludeSet,
+			r.Context(), embedder, campaignID, req.TextSegments, excludeSet,
 		)
 		if err != nil {
 			log.Printf("Vector search failed, falling back to text: %v", err)
@@ -151,10 +155,12 @@ func (h *EntityDetectionHandler) DetectEntities(w http.ResponseWriter, r *http.R
 	respondJSON(w, http.StatusOK, response)
 }
 
-// detectEntitiesFromVectors uses the pgedge_vectorizer to find entities
-// semantically similar to the provided text segments.
+// detectEntitiesFromVectors uses semantic search to find entities
+// similar to the provided text segments. The embedder is only used when
+// the pgedge_vectorizer extension is not installed.
 func (h *EntityDetectionHandler) detectEntitiesFromVectors(
 	ctx context.Context,
+	embedder llm.Embedder,
 	campaignID int64,
 	textSegments []string,
 	excludeSet map[int64]bool,
@@ -168,9 +174,9 @@ func (h *EntityDetectionHandler) detectEntitiesFromVectors(
 		return []EntitySuggestion{}, nil
 	}
 
-	// Search entity chunks via the SQL search function.
-	// We request more results than needed to allow for filtering.
-	results, err := h.db.SearchCampaignContent(ctx, campaignID, combined, 50)
+	// Search entity chunks. We request more results than needed to
+	// allow for filtering.
+	results, err := embedding.Search(ctx, h.db, embedder, campaignID, combined, 50)
 	if err != nil {
 		return nil, err
 	}
@@ -213,7 +219,8 @@ func (h *EntityDetectionHandler) detectEntitiesFromVectors(
 }
 
 // detectEntitiesFromText performs text-based entity detection using ILIKE pattern matching.
-// It extracts potential entity names from text segments and searches for matches.
+// It extracts potential entity names from text segments and searches for matches
+// of entity names and aliases.
 func (h *EntityDetectionHandler) detectEntitiesFromText(
 	ctx context.Context,
 	campaignID int64,
@@ -228,9 +235,31 @@ func (h *EntityDetectionHandler) detectEntitiesFromText(
 
 	// Build entity name lookup map (lowercase for case-insensitive matching)
 	entityByName := make(map[string]*models.Entity)
+	textByName := make(map[string]string)
 	for i := range entities {
 		normalizedName := strings.ToLower(strings.TrimSpace(entities[i].Name))
 		entityByName[normalizedName] = &entities[i]
+		textByName[normalizedName] = entities[i].Name
+	}
+
+	// Entities are found by their aliases too. An alias never
+	// replaces an entity name in the lookup.
+	aliases, err := h.db.ListCampaignEntityAliases(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	entityByID := make(map[int64]*models.Entity, len(entities))
+	for i := range entities {
+		entityByID[entities[i].ID] = &entities[i]
+	}
+	for _, alias := range aliases {
+		normalizedAlias := strings.ToLower(strings.TrimSpace(alias.Alias))
+		entity, ok := entityByID[alias.EntityID]
+		if !ok || entityByName[normalizedAlias] != nil {
+			continue
+		}
+		entityByName[normalizedAlias] = entity
+		textByName[normalizedAlias] = alias.Alias
 	}
 
 	// Track unique suggestions to avoid duplicates
@@ -260,7 +289,7 @@ func (h *EntityDetectionHandler) detectEntitiesFromText(
 				// Make a copy of the entity to avoid pointer issues
 				entityCopy := *entity
 				suggestions = append(suggestions, EntitySuggestion{
-					Text:       entity.Name,
+					Text:       textByName[name],
 					Entity:     &entityCopy,
 					Similarity: similarity,
 				})
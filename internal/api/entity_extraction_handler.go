@@ -0,0 +1,313 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/antonypegg/imagineer/internal/database"
+	"github.com/antonypegg/imagineer/internal/memory"
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// EntityExtractionHandler handles the review of entities detected in a
+// session's chat and play notes.
+type EntityExtractionHandler struct {
+	db *database.DB
+}
+
+// NewEntityExtractionHandler creates a new EntityExtractionHandler.
+func NewEntityExtractionHandler(db *database.DB) *EntityExtractionHandler {
+	return &EntityExtractionHandler{db: db}
+}
+
+// ListEntityExtractions handles GET /api/campaigns/{id}/sessions/{sessionId}/entity-extractions
+// Returns the session's entity extractions in the order they were
+// detected. The optional status query parameter filters them, e.g.
+// status=deferred for the wrap-up review.
+func (h *EntityExtractionHandler) ListEntityExtractions(w http.ResponseWriter, r *http.Request) {
+	session, _, ok := getOwnedSession(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	status := models.EntityExtractionStatus(r.URL.Query().Get("status"))
+	switch status {
+	case "", models.EntityExtractionStatusPending, models.EntityExtractionStatusAccepted,
+		models.EntityExtractionStatusDeferred, models.EntityExtractionStatusRejected:
+	default:
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid status: %s", status))
+		return
+	}
+
+	extractions, err := h.db.ListMemoryEntityExtractions(r.Context(), session.ID, status)
+	if err != nil {
+		log.Printf("Error listing entity extractions for session %d: %v", session.ID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to list entity extractions")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, extractions)
+}
+
+// ResolveEntityExtraction handles PUT /api/campaigns/{id}/sessions/{sessionId}/entity-extractions/{extractionId}
+// Accepts, defers or rejects an entity extraction. Accepting creates
+// the entity, or applies the update to the matched entity. Deferring
+// creates the entity as a DRAFT to review at wrap-up, where it can be
+// accepted, which makes it authoritative, or rejected, which deletes
+// it.
+func (h *EntityExtractionHandler) ResolveEntityExtraction(w http.ResponseWriter, r *http.Request) {
+	session, userID, ok := getOwnedSession(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	extractionID, err := parseInt64(r, "extractionId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid entity extraction ID")
+		return
+	}
+
+	// Entity changes apply the memory agent's extraction as accepted
+	// by the GM.
+	ctx := database.WithEntityRevisionAuthor(r.Context(), models.EntityRevisionAuthor{
+		Type:   models.EntityRevisionAuthorAgent,
+		UserID: &userID,
+		Agent:  memory.AgentName,
+	})
+	extraction, err := h.db.GetMemoryEntityExtraction(ctx, extractionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "Entity extraction not found")
+			return
+		}
+		log.Printf("Error getting entity extraction %d: %v", extractionID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to get entity extraction")
+		return
+	}
+	if extraction.SessionID != session.ID {
+		respondError(w, http.StatusNotFound, "Entity extraction not found")
+		return
+	}
+
+	var req models.ResolveEntityExtractionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	switch req.Status {
+	case models.EntityExtractionStatusAccepted, models.EntityExtractionStatusDeferred,
+		models.EntityExtractionStatusRejected:
+	default:
+		respondError(w, http.StatusBadRequest, "Status must be accepted, deferred or rejected")
+		return
+	}
+	if req.Name != nil && strings.TrimSpace(*req.Name) == "" {
+		respondError(w, http.StatusBadRequest, "Name cannot be empty")
+		return
+	}
+	if req.EntityType != nil && !isExtractableEntityType(*req.EntityType) {
+		respondError(w, http.StatusBadRequest,
+			fmt.Sprintf("Invalid entity type: %s", *req.EntityType))
+		return
+	}
+
+	switch {
+	case extraction.Status == models.EntityExtractionStatusAccepted,
+		extraction.Status == models.EntityExtractionStatusRejected,
+		extraction.Status == req.Status:
+		respondError(w, http.StatusConflict,
+			fmt.Sprintf("Entity extraction is already %s", extraction.Status))
+		return
+	}
+
+	entityID := extraction.EntityID
+	var created *models.Entity
+	switch req.Status {
+	case models.EntityExtractionStatusAccepted:
+		entity, err := h.acceptExtraction(ctx, session, extraction, req)
+		if err != nil {
+			log.Printf("Error accepting entity extraction %d: %v", extraction.ID, err)
+			respondError(w, http.StatusInternalServerError, "Failed to accept entity extraction")
+			return
+		}
+		entityID = &entity.ID
+		if extraction.EntityID == nil && extraction.MatchedEntityID == nil {
+			created = entity
+		}
+
+	case models.EntityExtractionStatusDeferred:
+		// An update of an existing entity waits for wrap-up without a
+		// draft.
+		if extraction.MatchedEntityID == nil {
+			entity, err := h.createEntity(ctx, session, extraction, req,
+				models.SourceConfidenceDraft)
+			if err != nil {
+				log.Printf("Error creating draft entity for extraction %d: %v", extraction.ID, err)
+				respondError(w, http.StatusInternalServerError, "Failed to defer entity extraction")
+				return
+			}
+			entityID = &entity.ID
+			created = entity
+		}
+
+	case models.EntityExtractionStatusRejected:
+		if extraction.EntityID != nil {
+			if err := h.discardDraft(ctx, *extraction.EntityID); err != nil {
+				log.Printf("Error deleting draft entity %d: %v", *extraction.EntityID, err)
+				respondError(w, http.StatusInternalServerError, "Failed to reject entity extraction")
+				return
+			}
+		}
+		entityID = nil
+	}
+
+	resolved, err := h.db.ResolveMemoryEntityExtraction(ctx, extraction.ID,
+		extraction.Status, req.Status, entityID)
+	if err != nil {
+		if created != nil {
+			if delErr := h.db.DeleteEntity(context.WithoutCancel(ctx), created.ID); delErr != nil {
+				log.Printf("Error deleting entity %d of resolved extraction %d: %v",
+					created.ID, extraction.ID, delErr)
+			}
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusConflict, "Entity extraction was resolved by another request")
+			return
+		}
+		log.Printf("Error resolving entity extraction %d: %v", extraction.ID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to resolve entity extraction")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, resolved)
+}
+
+// acceptExtraction makes the entity of an accepted extraction
+// authoritative. A deferred extraction's draft entity is promoted, an
+// update is appended to the matched entity's description and anything
+// else is created as a new entity.
+func (h *EntityExtractionHandler) acceptExtraction(
+	ctx context.Context,
+	session *models.Session,
+	extraction *models.MemoryEntityExtraction,
+	req models.ResolveEntityExtractionRequest,
+) (*models.Entity, error) {
+	authoritative := models.SourceConfidenceAuthoritative
+
+	if extraction.EntityID != nil {
+		entity, err := h.db.UpdateEntity(ctx, *extraction.EntityID, models.UpdateEntityRequest{
+			Name:             req.Name,
+			EntityType:       req.EntityType,
+			Description:      req.Description,
+			SourceConfidence: &authoritative,
+		})
+		// The GM may have deleted the draft since deferring it.
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return entity, err
+		}
+	}
+
+	if extraction.MatchedEntityID != nil {
+		existing, err := h.db.GetEntity(ctx, *extraction.MatchedEntityID)
+		if err != nil {
+			return nil, err
+		}
+		description := req.Description
+		if description == nil {
+			description = appendDescription(existing.Description, extraction.Description)
+		}
+		return h.db.UpdateEntity(ctx, existing.ID, models.UpdateEntityRequest{
+			Description: description,
+		})
+	}
+
+	return h.createEntity(ctx, session, extraction, req, authoritative)
+}
+
+// createEntity creates the entity proposed by an extraction, with the
+// GM's overrides from req, as discovered in session.
+func (h *EntityExtractionHandler) createEntity(
+	ctx context.Context,
+	session *models.Session,
+	extraction *models.MemoryEntityExtraction,
+	req models.ResolveEntityExtractionRequest,
+	confidence models.SourceConfidence,
+) (*models.Entity, error) {
+	create := models.CreateEntityRequest{
+		EntityType:        extraction.EntityType,
+		Name:              extraction.Name,
+		Description:       extraction.Description,
+		DiscoveredSession: &session.ID,
+		SourceConfidence:  &confidence,
+	}
+	if req.Name != nil {
+		create.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.EntityType != nil {
+		create.EntityType = *req.EntityType
+	}
+	if req.Description != nil {
+		create.Description = req.Description
+	}
+	return h.db.CreateEntity(ctx, session.CampaignID, create)
+}
+
+// discardDraft deletes the draft entity of a rejected extraction. An
+// entity the GM has since made authoritative, or already deleted, is
+// left alone.
+func (h *EntityExtractionHandler) discardDraft(ctx context.Context, entityID int64) error {
+	entity, err := h.db.GetEntity(ctx, entityID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	if entity.SourceConfidence != models.SourceConfidenceDraft {
+		return nil
+	}
+	return h.db.DeleteEntity(ctx, entityID)
+}
+
+// appendDescription returns description with addition appended as a
+// new paragraph.
+func appendDescription(description, addition *string) *string {
+	if addition == nil || strings.TrimSpace(*addition) == "" {
+		return description
+	}
+	if description == nil || strings.TrimSpace(*description) == "" {
+		return addition
+	}
+	combined := strings.TrimRight(*description, "\n") + "\n\n" + strings.TrimSpace(*addition)
+	return &combined
+}
+
+// isExtractableEntityType reports whether entityType is one of the
+// types an entity extraction can have.
+func isExtractableEntityType(entityType models.EntityType) bool {
+	switch entityType {
+	case models.EntityTypeNPC, models.EntityTypeLocation,
+		models.EntityTypeItem, models.EntityTypeFaction,
+		models.EntityTypeClue, models.EntityTypeCreature,
+		models.EntityTypeOrganization, models.EntityTypeEvent,
+		models.EntityTypeDocument, models.EntityTypeOther:
+		return true
+	}
+	return false
+}
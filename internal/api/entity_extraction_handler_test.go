@@ -0,0 +1,37 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package api
+
+import (
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendDescription(t *testing.T) {
+	existing := "A harbourmaster.\n"
+	addition := " Owes the cult money. "
+	blank := " "
+
+	assert.Equal(t, "A harbourmaster.\n\nOwes the cult money.",
+		*appendDescription(&existing, &addition))
+	assert.Equal(t, &addition, appendDescription(nil, &addition))
+	assert.Equal(t, &existing, appendDescription(&existing, &blank))
+	assert.Nil(t, appendDescription(nil, nil))
+}
+
+func TestIsExtractableEntityType(t *testing.T) {
+	assert.True(t, isExtractableEntityType(models.EntityTypeNPC))
+	assert.True(t, isExtractableEntityType(models.EntityTypeOther))
+	assert.False(t, isExtractableEntityType("player_character"))
+	assert.False(t, isExtractableEntityType(""))
+}
@@ -0,0 +1,80 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/antonypegg/imagineer/internal/database"
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// EntityMergeHandler handles merging duplicate entities.
+type EntityMergeHandler struct {
+	db *database.DB
+}
+
+// NewEntityMergeHandler creates a new EntityMergeHandler.
+func NewEntityMergeHandler(db *database.DB) *EntityMergeHandler {
+	return &EntityMergeHandler{db: db}
+}
+
+// MergeEntity handles POST /api/campaigns/{id}/entities/{entityId}/merge
+// Folds the entity in the request body into the entity in the URL,
+// moving its relationships, links and references, and deletes it.
+func (h *EntityMergeHandler) MergeEntity(w http.ResponseWriter, r *http.Request) {
+	target, userID, ok := getOwnedEntity(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	var req models.MergeEntityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.SourceEntityID == 0 {
+		respondError(w, http.StatusBadRequest, "Source entity ID is required")
+		return
+	}
+	if req.SourceEntityID == target.ID {
+		respondError(w, http.StatusBadRequest, "Cannot merge an entity into itself")
+		return
+	}
+
+	source, err := h.db.GetEntity(r.Context(), req.SourceEntityID)
+	if err != nil || source.CampaignID != target.CampaignID {
+		respondError(w, http.StatusBadRequest, "Source entity not found")
+		return
+	}
+
+	result, err := h.db.MergeEntities(userRevisionContext(r.Context(), userID), source.ID, target.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "Entity not found")
+			return
+		}
+		log.Printf("Error merging entity %d into %d: %v", source.ID, target.ID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to merge entities")
+		return
+	}
+
+	log.Printf("Merged entity %d (%q) into %d (%q): %d relationships moved, %d dropped",
+		source.ID, source.Name, target.ID, target.Name,
+		result.RelationshipsMoved, result.RelationshipsDropped)
+
+	respondJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,331 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/antonypegg/imagineer/internal/auth"
+	"github.com/antonypegg/imagineer/internal/database"
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// EntityRevisionHandler handles entity revision history API requests.
+type EntityRevisionHandler struct {
+	db *database.DB
+}
+
+// NewEntityRevisionHandler creates a new EntityRevisionHandler.
+func NewEntityRevisionHandler(db *database.DB) *EntityRevisionHandler {
+	return &EntityRevisionHandler{db: db}
+}
+
+// ListEntityRevisions handles GET /api/campaigns/{id}/entities/{entityId}/revisions
+// Returns the revisions of an entity, newest first.
+func (h *EntityRevisionHandler) ListEntityRevisions(w http.ResponseWriter, r *http.Request) {
+	entity, _, ok := getOwnedEntity(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.db.ListEntityRevisions(r.Context(), entity.ID)
+	if err != nil {
+		log.Printf("Error listing revisions of entity %d: %v", entity.ID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to list entity revisions")
+		return
+	}
+
+	if revisions == nil {
+		revisions = []models.EntityRevision{}
+	}
+
+	respondJSON(w, http.StatusOK, revisions)
+}
+
+// GetEntityRevision handles GET /api/campaigns/{id}/entities/{entityId}/revisions/{version}
+// Returns the entity as it was at a version.
+func (h *EntityRevisionHandler) GetEntityRevision(w http.ResponseWriter, r *http.Request) {
+	entity, _, ok := getOwnedEntity(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid version")
+		return
+	}
+
+	revision, ok := h.getRevision(w, r, entity.ID, version)
+	if !ok {
+		return
+	}
+
+	respondJSON(w, http.StatusOK, revision)
+}
+
+// DiffEntityRevisions handles GET /api/campaigns/{id}/entities/{entityId}/revisions/diff
+// Returns the fields changed between the from and to versions. to
+// defaults to the current version and from to the one before to.
+func (h *EntityRevisionHandler) DiffEntityRevisions(w http.ResponseWriter, r *http.Request) {
+	entity, _, ok := getOwnedEntity(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	toVersion := entity.Version
+	if raw := r.URL.Query().Get("to"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid to version")
+			return
+		}
+		toVersion = v
+	}
+	fromVersion := toVersion - 1
+	if raw := r.URL.Query().Get("from"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid from version")
+			return
+		}
+		fromVersion = v
+	}
+
+	from, ok := h.getRevision(w, r, entity.ID, fromVersion)
+	if !ok {
+		return
+	}
+	to, ok := h.getRevision(w, r, entity.ID, toVersion)
+	if !ok {
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.EntityRevisionDiff{
+		EntityID:    entity.ID,
+		FromVersion: fromVersion,
+		ToVersion:   toVersion,
+		Changes:     diffEntityRevisions(from, to),
+	})
+}
+
+// RestoreEntityRevision handles POST /api/campaigns/{id}/entities/{entityId}/revisions/{version}/restore
+// Restores the entity as it was at a version, as its next version. The
+// restored attributes are validated like an edit, since the game
+// system's schema or the campaign's validation mode may have changed
+// since the version was saved.
+func (h *EntityRevisionHandler) RestoreEntityRevision(w http.ResponseWriter, r *http.Request) {
+	entity, userID, ok := getOwnedEntity(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid version")
+		return
+	}
+	if version == entity.Version {
+		respondError(w, http.StatusBadRequest, "Version is the current version")
+		return
+	}
+
+	revision, ok := h.getRevision(w, r, entity.ID, version)
+	if !ok {
+		return
+	}
+	warnings, ok := validateEntityAttributes(w, r, h.db, entity.CampaignID, revision.EntityType, revision.Attributes)
+	if !ok {
+		return
+	}
+
+	ctx := userRevisionContext(r.Context(), userID)
+	restored, err := h.db.RestoreEntityRevision(ctx, entity.ID, version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "Entity revision not found")
+			return
+		}
+		log.Printf("Error restoring entity %d to version %d: %v", entity.ID, version, err)
+		respondError(w, http.StatusInternalServerError, "Failed to restore entity revision")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, EntityWithAnalysis{Entity: restored, AttributeWarnings: warnings})
+}
+
+// getOwnedEntity returns the entity in the URL and the authenticated
+// user's ID if the user owns the campaign in the URL and the entity
+// belongs to it, writing an error response and returning false
+// otherwise.
+func getOwnedEntity(w http.ResponseWriter, r *http.Request, db *database.DB) (*models.Entity, int64, bool) {
+	campaignID, err := parseInt64(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid campaign ID")
+		return nil, 0, false
+	}
+
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Authentication required")
+		return nil, 0, false
+	}
+
+	if err := db.VerifyCampaignOwnership(r.Context(), campaignID, userID); err != nil {
+		respondError(w, http.StatusNotFound, "Campaign not found")
+		return nil, 0, false
+	}
+
+	entityID, err := parseInt64(r, "entityId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid entity ID")
+		return nil, 0, false
+	}
+
+	entity, err := db.GetEntity(r.Context(), entityID)
+	if err != nil || entity.CampaignID != campaignID {
+		respondError(w, http.StatusNotFound, "Entity not found")
+		return nil, 0, false
+	}
+
+	return entity, userID, true
+}
+
+// getRevision returns the revision of the entity at version, writing
+// an error response and returning false if there is none.
+func (h *EntityRevisionHandler) getRevision(
+	w http.ResponseWriter,
+	r *http.Request,
+	entityID int64,
+	version int,
+) (*models.EntityRevision, bool) {
+	revision, err := h.db.GetEntityRevision(r.Context(), entityID, version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "Entity revision not found")
+			return nil, false
+		}
+		log.Printf("Error getting version %d of entity %d: %v", version, entityID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to get entity revision")
+		return nil, false
+	}
+	return revision, true
+}
+
+// userRevisionContext returns a context that attributes the entity
+// revisions recorded with it to the user.
+func userRevisionContext(ctx context.Context, userID int64) context.Context {
+	return database.WithEntityRevisionAuthor(ctx, models.EntityRevisionAuthor{
+		Type:   models.EntityRevisionAuthorUser,
+		UserID: &userID,
+	})
+}
+
+// diffEntityRevisions lists the fields that differ from one revision to
+// another, in a fixed field order. Attributes that are JSON objects
+// are compared key by key.
+func diffEntityRevisions(from, to *models.EntityRevision) []models.EntityFieldChange {
+	changes := []models.EntityFieldChange{}
+	add := func(field string, a, b interface{}) {
+		if !reflect.DeepEqual(a, b) {
+			changes = append(changes, models.EntityFieldChange{Field: field, From: a, To: b})
+		}
+	}
+
+	add("entityType", from.EntityType, to.EntityType)
+	add("name", from.Name, to.Name)
+	add("description", derefString(from.Description), derefString(to.Description))
+
+	fromAttrs, fromOK := attributeMap(from.Attributes)
+	toAttrs, toOK := attributeMap(to.Attributes)
+	if fromOK && toOK {
+		keys := make([]string, 0, len(fromAttrs)+len(toAttrs))
+		for k := range fromAttrs {
+			keys = append(keys, k)
+		}
+		for k := range toAttrs {
+			if _, ok := fromAttrs[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			add("attributes."+k, fromAttrs[k], toAttrs[k])
+		}
+	} else {
+		add("attributes", rawJSONValue(from.Attributes), rawJSONValue(to.Attributes))
+	}
+
+	add("tags", nonNilTags(from.Tags), nonNilTags(to.Tags))
+	add("gmNotes", derefString(from.GMNotes), derefString(to.GMNotes))
+	add("discoveredSession", derefInt64(from.DiscoveredSession), derefInt64(to.DiscoveredSession))
+	add("sourceDocument", derefString(from.SourceDocument), derefString(to.SourceDocument))
+	add("sourceConfidence", from.SourceConfidence, to.SourceConfidence)
+
+	return changes
+}
+
+// attributeMap decodes entity attributes that are a JSON object. Empty
+// attributes are an empty object.
+func attributeMap(raw json.RawMessage) (map[string]interface{}, bool) {
+	if len(raw) == 0 {
+		return map[string]interface{}{}, true
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(raw, &m); err != nil || m == nil {
+		return nil, false
+	}
+	return m, true
+}
+
+// rawJSONValue decodes raw for comparison, or returns it as a string if
+// it is not valid JSON.
+func rawJSONValue(raw json.RawMessage) interface{} {
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return string(raw)
+	}
+	return v
+}
+
+// nonNilTags returns tags, or an empty slice if it is nil, so that no
+// tags and an empty tag list compare equal.
+func nonNilTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+// derefString returns the value of s, or nil if s is nil.
+func derefString(s *string) interface{} {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+// derefInt64 returns the value of n, or nil if n is nil.
+func derefInt64(n *int64) interface{} {
+	if n == nil {
+		return nil
+	}
+	return *n
+}
@@ -0,0 +1,69 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffEntityRevisions(t *testing.T) {
+	oldDesc := "A retired sea captain."
+	newDesc := "A retired sea captain, secretly a smuggler."
+	from := &models.EntityRevision{
+		Version:          1,
+		EntityType:       models.EntityTypeNPC,
+		Name:             "Captain Hale",
+		Description:      &oldDesc,
+		Attributes:       json.RawMessage(`{"STR": 60, "occupation": "Sailor"}`),
+		SourceConfidence: models.SourceConfidenceDraft,
+	}
+	to := &models.EntityRevision{
+		Version:          2,
+		EntityType:       models.EntityTypeNPC,
+		Name:             "Captain Hale",
+		Description:      &newDesc,
+		Attributes:       json.RawMessage(`{"STR": 65, "occupation": "Sailor", "POW": 50}`),
+		Tags:             []string{},
+		SourceConfidence: models.SourceConfidenceDraft,
+	}
+
+	changes := diffEntityRevisions(from, to)
+
+	assert.Equal(t, []models.EntityFieldChange{
+		{Field: "description", From: oldDesc, To: newDesc},
+		{Field: "attributes.POW", From: nil, To: float64(50)},
+		{Field: "attributes.STR", From: float64(60), To: float64(65)},
+	}, changes)
+}
+
+func TestDiffEntityRevisions_NoChanges(t *testing.T) {
+	r := &models.EntityRevision{
+		EntityType:       models.EntityTypeLocation,
+		Name:             "Innsmouth",
+		SourceConfidence: models.SourceConfidenceAuthoritative,
+	}
+
+	assert.Empty(t, diffEntityRevisions(r, r))
+}
+
+func TestDiffEntityRevisions_NonObjectAttributes(t *testing.T) {
+	from := &models.EntityRevision{Name: "Relic", Attributes: json.RawMessage(`[1]`)}
+	to := &models.EntityRevision{Name: "Relic", Attributes: json.RawMessage(`{"weight": 2}`)}
+
+	changes := diffEntityRevisions(from, to)
+
+	assert.Len(t, changes, 1)
+	assert.Equal(t, "attributes", changes[0].Field)
+}
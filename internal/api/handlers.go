@@ -22,6 +22,8 @@ import (
 	"github.com/antonypegg/imagineer/internal/analysis"
 	"github.com/antonypegg/imagineer/internal/auth"
 	"github.com/antonypegg/imagineer/internal/database"
+	"github.com/antonypegg/imagineer/internal/embedding"
+	"github.com/antonypegg/imagineer/internal/llm"
 	"github.com/antonypegg/imagineer/internal/models"
 	"github.com/go-chi/chi/v5"
 )
@@ -70,10 +72,12 @@ func parsePhases(r *http.Request) []string {
 	return phases
 }
 
-// EntityWithAnalysis wraps an entity response with optional analysis metadata.
+// EntityWithAnalysis wraps an entity response with optional analysis metadata
+// and the attribute problems found by warn-only validation.
 type EntityWithAnalysis struct {
 	*models.Entity
-	Analysis *models.AnalysisSummary `json:"_analysis,omitempty"`
+	Analysis          *models.AnalysisSummary      `json:"_analysis,omitempty"`
+	AttributeWarnings []models.AttributeFieldError `json:"_attributeWarnings,omitempty"`
 }
 
 // CampaignWithAnalysis wraps a campaign response with optional analysis metadata.
@@ -524,7 +528,8 @@ func (h *Handler) CreateEntity(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Verify the user owns this campaign
-	if _, ok := h.verifyCampaignOwnership(w, r, campaignID); !ok {
+	userID, ok := h.verifyCampaignOwnership(w, r, campaignID)
+	if !ok {
 		return
 	}
 
@@ -544,14 +549,19 @@ func (h *Handler) CreateEntity(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	entity, err := h.db.CreateEntity(r.Context(), campaignID, req)
+	warnings, ok := validateEntityAttributes(w, r, h.db, campaignID, req.EntityType, req.Attributes)
+	if !ok {
+		return
+	}
+
+	entity, err := h.db.CreateEntity(userRevisionContext(r.Context(), userID), campaignID, req)
 	if err != nil {
 		log.Printf("Error creating entity: %v", err)
 		respondError(w, http.StatusInternalServerError, "Failed to create entity")
 		return
 	}
 
-	respondJSON(w, http.StatusCreated, entity)
+	respondJSON(w, http.StatusCreated, EntityWithAnalysis{Entity: entity, AttributeWarnings: warnings})
 }
 
 // GetEntity handles GET /api/entities/:id
@@ -602,7 +612,8 @@ func (h *Handler) UpdateEntity(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Verify the user owns the entity's campaign
-	if _, ok := h.verifyCampaignOwnership(w, r, existingEntity.CampaignID); !ok {
+	userID, ok := h.verifyCampaignOwnership(w, r, existingEntity.CampaignID)
+	if !ok {
 		return
 	}
 
@@ -612,7 +623,25 @@ func (h *Handler) UpdateEntity(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	entity, err := h.db.UpdateEntity(r.Context(), id, req)
+	// Attributes are only validated when the update changes them or
+	// the entity type they are checked against, so that entities saved
+	// before validation can still be edited.
+	var warnings []models.AttributeFieldError
+	if req.Attributes != nil || req.EntityType != nil {
+		entityType, attributes := existingEntity.EntityType, existingEntity.Attributes
+		if req.EntityType != nil {
+			entityType = *req.EntityType
+		}
+		if req.Attributes != nil {
+			attributes = req.Attributes
+		}
+		warnings, ok = validateEntityAttributes(w, r, h.db, existingEntity.CampaignID, entityType, attributes)
+		if !ok {
+			return
+		}
+	}
+
+	entity, err := h.db.UpdateEntity(userRevisionContext(r.Context(), userID), id, req)
 	if err != nil {
 		log.Printf("Error updating entity: %v", err)
 		respondError(w, http.StatusInternalServerError, "Failed to update entity")
@@ -622,10 +651,9 @@ func (h *Handler) UpdateEntity(w http.ResponseWriter, r *http.Request) {
 	shouldAnalyze := r.URL.Query().Get("analyze") == "true"
 	shouldEnrich := r.URL.Query().Get("enrich") == "true"
 	phases := parsePhases(r)
-	userID, _ := auth.GetUserIDFromContext(r.Context())
 
 	// Trigger content analysis if description changed and analysis requested
-	response := EntityWithAnalysis{Entity: entity}
+	response := EntityWithAnalysis{Entity: entity, AttributeWarnings: warnings}
 	if req.Description != nil {
 		content := *req.Description
 		if shouldAnalyze {
@@ -1520,6 +1548,7 @@ func (h *Handler) GetUserSettings(w http.ResponseWriter, r *http.Request) {
 		EmbeddingAPIKey:   maskAPIKey(settings.EmbeddingAPIKey),
 		ImageGenService:   settings.ImageGenService,
 		ImageGenAPIKey:    maskAPIKey(settings.ImageGenAPIKey),
+		LLMRouting:        maskRouting(settings.LLMRouting),
 		CreatedAt:         settings.CreatedAt,
 		UpdatedAt:         settings.UpdatedAt,
 	}
@@ -1536,6 +1565,23 @@ func isMaskedAPIKey(key *string) bool {
 	return len(k) >= 4 && k[:4] == "****"
 }
 
+// contentGenAPIKey returns the API key for the user's content
+// generation service and whether content generation is configured. The
+// replay service needs no key, but is only configured when the
+// operator has enabled it.
+func contentGenAPIKey(settings *models.UserSettings) (string, bool) {
+	if settings == nil || settings.ContentGenService == nil {
+		return "", false
+	}
+	if *settings.ContentGenService == models.LLMServiceReplay && !llm.ReplayEnabled() {
+		return "", false
+	}
+	if settings.ContentGenAPIKey != nil {
+		return *settings.ContentGenAPIKey, true
+	}
+	return "", *settings.ContentGenService == models.LLMServiceReplay
+}
+
 // UpdateUserSettings handles PUT /api/user/settings
 // Updates the current user's settings.
 func (h *Handler) UpdateUserSettings(w http.ResponseWriter, r *http.Request) {
@@ -1551,6 +1597,12 @@ func (h *Handler) UpdateUserSettings(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.ContentGenService != nil && *req.ContentGenService == models.LLMServiceReplay &&
+		!llm.ReplayEnabled() {
+		respondError(w, http.StatusBadRequest, "The replay content generation service is not enabled on this server")
+		return
+	}
+
 	// Clear masked API keys from request to prevent overwriting with masked values
 	if isMaskedAPIKey(req.ContentGenAPIKey) {
 		req.ContentGenAPIKey = nil
@@ -1562,6 +1614,26 @@ func (h *Handler) UpdateUserSettings(w http.ResponseWriter, r *http.Request) {
 		req.ImageGenAPIKey = nil
 	}
 
+	// Routing keys are restored rather than cleared because the whole
+	// routing is replaced on update.
+	if req.LLMRouting != nil {
+		if err := validateRouting(req.LLMRouting); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid LLM routing: "+err.Error())
+			return
+		}
+		existing, err := h.db.GetUserSettings(r.Context(), userID)
+		if err != nil {
+			log.Printf("Error getting user settings: %v", err)
+			respondError(w, http.StatusInternalServerError, "Failed to update user settings")
+			return
+		}
+		var existingRouting *models.LLMRouting
+		if existing != nil {
+			existingRouting = existing.LLMRouting
+		}
+		restoreMaskedRoutingKeys(req.LLMRouting, existingRouting)
+	}
+
 	settings, err := h.db.UpdateUserSettings(r.Context(), userID, req)
 	if err != nil {
 		log.Printf("Error updating user settings: %v", err)
@@ -1578,6 +1650,7 @@ func (h *Handler) UpdateUserSettings(w http.ResponseWriter, r *http.Request) {
 		EmbeddingAPIKey:   maskAPIKey(settings.EmbeddingAPIKey),
 		ImageGenService:   settings.ImageGenService,
 		ImageGenAPIKey:    maskAPIKey(settings.ImageGenAPIKey),
+		LLMRouting:        maskRouting(settings.LLMRouting),
 		CreatedAt:         settings.CreatedAt,
 		UpdatedAt:         settings.UpdatedAt,
 	}
@@ -2306,9 +2379,61 @@ func (h *Handler) UpdateSession(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 	}
+
+	// Extract session memories for the GM to review once play is over.
+	if req.Stage != nil && isPostPlayStage(*req.Stage) &&
+		!isPostPlayStage(existing.Stage) && h.caHandler != nil {
+		h.caHandler.RunSessionMemoryExtraction(r.Context(), session, userID)
+	}
+
+	// Propose entities from the play notes written since the last save.
+	if req.PlayNotes != nil && session.Stage == models.SessionStagePlay && h.caHandler != nil {
+		var previous string
+		if existing.PlayNotes != nil {
+			previous = *existing.PlayNotes
+		}
+		if added := addedNoteLines(previous, *req.PlayNotes); added != "" {
+			h.caHandler.RunEntityExtraction(r.Context(), session, userID,
+				models.EntityExtractionSourcePlayNotes, added)
+		}
+	}
+
 	respondJSON(w, http.StatusOK, response)
 }
 
+// isPostPlayStage reports whether stage comes after play, when a
+// session's memories are extracted.
+func isPostPlayStage(stage models.SessionStage) bool {
+	return stage == models.SessionStageWrapUp || stage == models.SessionStageCompleted
+}
+
+// addedNoteLines returns the lines of notes that are not in previous,
+// joined by newlines. The last line is left out unless it is
+// terminated, since notes are saved while the GM is still typing it.
+func addedNoteLines(previous, notes string) string {
+	old := make(map[string]int)
+	for _, line := range strings.Split(previous, "\n") {
+		old[strings.TrimSpace(line)]++
+	}
+
+	lines := strings.Split(notes, "\n")
+	lines = lines[:len(lines)-1]
+
+	var added []string
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if old[line] > 0 {
+			old[line]--
+			continue
+		}
+		added = append(added, line)
+	}
+	return strings.Join(added, "\n")
+}
+
 // DeleteSession handles DELETE /api/campaigns/{id}/sessions/{sessionId}
 // Deletes a session.
 func (h *Handler) DeleteSession(w http.ResponseWriter, r *http.Request) {
@@ -2604,7 +2729,9 @@ func (h *Handler) DeleteChapterEntity(w http.ResponseWriter, r *http.Request) {
 }
 
 // SearchCampaignContent handles GET /api/campaigns/{id}/search
-// Performs hybrid vector+BM25 search across all campaign content.
+// Performs hybrid vector+BM25 search across all campaign content. The
+// pgedge_vectorizer extension is used when installed; otherwise the
+// Go-side embedding index is queried with the user's embedding service.
 func (h *Handler) SearchCampaignContent(w http.ResponseWriter, r *http.Request) {
 	campaignID, err := parseInt64(r, "id")
 	if err != nil {
@@ -2612,7 +2739,8 @@ func (h *Handler) SearchCampaignContent(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if _, ok := h.verifyCampaignOwnership(w, r, campaignID); !ok {
+	userID, ok := h.verifyCampaignOwnership(w, r, campaignID)
+	if !ok {
 		return
 	}
 
@@ -2629,13 +2757,15 @@ func (h *Handler) SearchCampaignContent(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
-	if !h.db.IsVectorizationAvailable(r.Context()) {
+	embedder := h.embedderForUser(r.Context(), userID)
+	if !embedding.Available(r.Context(), h.db, embedder) {
 		respondError(w, http.StatusServiceUnavailable,
-			"Semantic search is not available. The vectorizer extension is not installed.")
+			"Semantic search is not available. Install the vectorizer extension, "+
+				"or install pgvector and configure an embedding service.")
 		return
 	}
 
-	results, err := h.db.SearchCampaignContent(r.Context(), campaignID, query, limit)
+	results, err := embedding.Search(r.Context(), h.db, embedder, campaignID, query, limit)
 	if err != nil {
 		log.Printf("Error searching campaign content: %v", err)
 		respondError(w, http.StatusInternalServerError, "Failed to search campaign content")
@@ -2648,3 +2778,53 @@ func (h *Handler) SearchCampaignContent(w http.ResponseWriter, r *http.Request)
 
 	respondJSON(w, http.StatusOK, results)
 }
+
+// ReindexCampaignContent handles POST /api/campaigns/{id}/search/reindex
+// Brings the Go-side embedding index for a campaign up to date using the
+// user's embedding service. Only sources whose content or embedding
+// model changed are re-embedded.
+func (h *Handler) ReindexCampaignContent(w http.ResponseWriter, r *http.Request) {
+	campaignID, err := parseInt64(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid campaign ID")
+		return
+	}
+
+	userID, ok := h.verifyCampaignOwnership(w, r, campaignID)
+	if !ok {
+		return
+	}
+
+	if !h.db.IsEmbeddingIndexAvailable(r.Context()) {
+		respondError(w, http.StatusServiceUnavailable,
+			"The embedding index is not available. Install the pgvector extension.")
+		return
+	}
+
+	embedder := h.embedderForUser(r.Context(), userID)
+	if embedder == nil {
+		respondError(w, http.StatusBadRequest,
+			"No embedding service configured. Configure one in settings.")
+		return
+	}
+
+	result, err := embedding.NewIndexer(h.db, embedder).SyncCampaign(r.Context(), campaignID)
+	if err != nil {
+		log.Printf("Error reindexing campaign %d: %v", campaignID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to reindex campaign content")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// embedderForUser returns the embedder configured in the user's
+// settings, or nil if none is configured or the settings cannot be
+// loaded.
+func (h *Handler) embedderForUser(ctx context.Context, userID int64) llm.Embedder {
+	settings, err := h.db.GetUserSettings(ctx, userID)
+	if err != nil {
+		return nil
+	}
+	return embedding.EmbedderForSettings(settings)
+}
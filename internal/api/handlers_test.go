@@ -18,6 +18,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/antonypegg/imagineer/internal/auth"
 	"github.com/antonypegg/imagineer/internal/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -45,7 +46,7 @@ func TestHealthEndpoint(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create router without database (health endpoint doesn't need it)
-			router, err := NewRouter(nil, nil, testJWTSecret)
+			router, err := NewRouter(nil, nil, testJWTSecret, nil, nil)
 			require.NoError(t, err)
 
 			// Create request
@@ -243,7 +244,7 @@ func TestAPIErrorResponse_Structure(t *testing.T) {
 
 func TestCORSHeaders(t *testing.T) {
 	// Test that CORS headers are set correctly
-	router, err := NewRouter(nil, nil, testJWTSecret)
+	router, err := NewRouter(nil, nil, testJWTSecret, nil, nil)
 	require.NoError(t, err)
 
 	// Create OPTIONS preflight request
@@ -302,7 +303,7 @@ func TestJSONRequestDecoding(t *testing.T) {
 
 func TestContentTypeHeader(t *testing.T) {
 	// Verify that all JSON responses have correct Content-Type
-	router, err := NewRouter(nil, nil, testJWTSecret)
+	router, err := NewRouter(nil, nil, testJWTSecret, nil, nil)
 	require.NoError(t, err)
 
 	endpoints := []struct {
@@ -327,7 +328,7 @@ func TestContentTypeHeader(t *testing.T) {
 
 func TestRouterMiddleware(t *testing.T) {
 	// Test that the router has required middleware
-	router, err := NewRouter(nil, nil, testJWTSecret)
+	router, err := NewRouter(nil, nil, testJWTSecret, nil, nil)
 	require.NoError(t, err)
 
 	// Test request ID middleware by checking response headers
@@ -341,7 +342,7 @@ func TestRouterMiddleware(t *testing.T) {
 }
 
 func TestHealthEndpoint_ResponseFormat(t *testing.T) {
-	router, err := NewRouter(nil, nil, testJWTSecret)
+	router, err := NewRouter(nil, nil, testJWTSecret, nil, nil)
 	require.NoError(t, err)
 
 	req := httptest.NewRequest(http.MethodGet, "/health", nil)
@@ -356,7 +357,7 @@ func TestHealthEndpoint_ResponseFormat(t *testing.T) {
 
 func TestNewRouter_MissingJWTSecret(t *testing.T) {
 	// Test that NewRouter returns an error when jwtSecret is empty
-	router, err := NewRouter(nil, nil, "")
+	router, err := NewRouter(nil, nil, "", nil, nil)
 
 	assert.Nil(t, router)
 	assert.Error(t, err)
@@ -585,3 +586,313 @@ func TestFilterEntitiesGMNotes_MixedNotes(t *testing.T) {
 	assert.Equal(t, "NPC with notes", entities[0].Name)
 	assert.Equal(t, "Location without notes", entities[1].Name)
 }
+
+func TestContentGenAPIKey(t *testing.T) {
+	t.Setenv("LLM_REPLAY_DIR", t.TempDir())
+	anthropic := models.LLMServiceAnthropic
+	replay := models.LLMServiceReplay
+	key := "sk-test"
+
+	tests := []struct {
+		name      string
+		settings  *models.UserSettings
+		wantKey   string
+		wantReady bool
+	}{
+		{"nil settings", nil, "", false},
+		{"no service", &models.UserSettings{ContentGenAPIKey: &key}, "", false},
+		{"service without key", &models.UserSettings{ContentGenService: &anthropic}, "", false},
+		{"service with key", &models.UserSettings{ContentGenService: &anthropic, ContentGenAPIKey: &key}, key, true},
+		{"replay without key", &models.UserSettings{ContentGenService: &replay}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotKey, gotReady := contentGenAPIKey(tt.settings)
+			assert.Equal(t, tt.wantKey, gotKey)
+			assert.Equal(t, tt.wantReady, gotReady)
+		})
+	}
+}
+
+func TestContentGenAPIKey_ReplayNotEnabled(t *testing.T) {
+	t.Setenv("LLM_REPLAY_DIR", "")
+	replay := models.LLMServiceReplay
+
+	_, ready := contentGenAPIKey(&models.UserSettings{ContentGenService: &replay})
+	assert.False(t, ready)
+}
+
+func TestValidateRouting_Replay(t *testing.T) {
+	routing := &models.LLMRouting{
+		Fallbacks: []models.LLMRoute{{Service: models.LLMServiceReplay}},
+	}
+
+	t.Setenv("LLM_REPLAY_DIR", "")
+	assert.Error(t, validateRouting(routing))
+
+	t.Setenv("LLM_REPLAY_DIR", t.TempDir())
+	assert.NoError(t, validateRouting(routing))
+}
+
+func TestUpdateUserSettings_ReplayNotEnabled(t *testing.T) {
+	t.Setenv("LLM_REPLAY_DIR", "")
+	h := &Handler{}
+
+	claims := &auth.JWTClaims{UserID: "1", Exp: time.Now().Add(time.Hour).Unix()}
+	token, err := claims.Sign([]byte(testJWTSecret))
+	require.NoError(t, err)
+
+	body := bytes.NewBufferString(`{"contentGenService": "replay"}`)
+	req := httptest.NewRequest(http.MethodPut, "/api/user/settings", body)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	// The request is rejected before the database is used.
+	auth.AuthMiddleware(testJWTSecret)(http.HandlerFunc(h.UpdateUserSettings)).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestValidateRouting(t *testing.T) {
+	assert.NoError(t, validateRouting(nil))
+	assert.NoError(t, validateRouting(&models.LLMRouting{
+		Fallbacks: []models.LLMRoute{{Service: models.LLMServiceOpenAI}},
+		AgentRoutes: map[string]models.LLMRoute{
+			"canon": {Service: models.LLMServiceAnthropic, Model: "claude-haiku-4-5"},
+		},
+	}))
+
+	assert.Error(t, validateRouting(&models.LLMRouting{
+		Fallbacks: []models.LLMRoute{{Service: models.LLMServiceVoyage}},
+	}))
+	assert.Error(t, validateRouting(&models.LLMRouting{
+		AgentRoutes: map[string]models.LLMRoute{
+			"canon-expert": {Service: models.LLMServiceOpenAI},
+		},
+	}))
+}
+
+func TestRoutingKeys(t *testing.T) {
+	anthropic := models.LLMServiceAnthropic
+	primaryKey := "sk-ant-primary"
+	openaiKey := "sk-openai-1234"
+
+	settings := &models.UserSettings{
+		ContentGenService: &anthropic,
+		ContentGenAPIKey:  &primaryKey,
+		LLMRouting: &models.LLMRouting{
+			Fallbacks: []models.LLMRoute{
+				{Service: models.LLMServiceOpenAI, APIKey: &openaiKey},
+			},
+			AgentRoutes: map[string]models.LLMRoute{
+				"graph": {Service: models.LLMServiceOpenAI, Model: "gpt-4o-mini"},
+			},
+		},
+	}
+
+	t.Run("route key resolution", func(t *testing.T) {
+		assert.Equal(t, openaiKey, routeAPIKey(settings, primaryKey,
+			models.LLMRoute{Service: models.LLMServiceOpenAI}))
+		assert.Equal(t, primaryKey, routeAPIKey(settings, primaryKey,
+			models.LLMRoute{Service: models.LLMServiceAnthropic, Model: "claude-haiku-4-5"}))
+		assert.Equal(t, "", routeAPIKey(settings, primaryKey,
+			models.LLMRoute{Service: models.LLMServiceOllama}))
+	})
+
+	t.Run("mask and restore", func(t *testing.T) {
+		masked := maskRouting(settings.LLMRouting)
+		require.NotNil(t, masked)
+		require.NotNil(t, masked.Fallbacks[0].APIKey)
+		assert.Equal(t, "****1234", *masked.Fallbacks[0].APIKey)
+		assert.Nil(t, masked.AgentRoutes["graph"].APIKey)
+		assert.Equal(t, openaiKey, *settings.LLMRouting.Fallbacks[0].APIKey,
+			"masking must not modify the original routing")
+
+		restoreMaskedRoutingKeys(masked, settings.LLMRouting)
+		require.NotNil(t, masked.Fallbacks[0].APIKey)
+		assert.Equal(t, openaiKey, *masked.Fallbacks[0].APIKey)
+	})
+}
+
+func TestParseLastEventID(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		query   string
+		want    int64
+		wantErr bool
+	}{
+		{name: "absent", want: 0},
+		{name: "header", header: "42", want: 42},
+		{name: "query", query: "17", want: 17},
+		{name: "header wins", header: "5", query: "9", want: 5},
+		{name: "not a number", header: "abc", wantErr: true},
+		{name: "negative", query: "-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := "/stream"
+			if tt.query != "" {
+				target += "?lastEventId=" + tt.query
+			}
+			req := httptest.NewRequest(http.MethodGet, target, nil)
+			if tt.header != "" {
+				req.Header.Set("Last-Event-ID", tt.header)
+			}
+
+			got, err := parseLastEventID(req)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWriteSSEEvent(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writeSSEEvent(rec, 12, "item_created", []byte(`{"id":3}`))
+	writeSSEEvent(rec, 0, "job_complete", []byte(`{"status":"completed"}`))
+
+	assert.Equal(t,
+		"id: 12\nevent: item_created\ndata: {\"id\":3}\n\n"+
+			"event: job_complete\ndata: {\"status\":\"completed\"}\n\n",
+		rec.Body.String())
+}
+
+func TestRequestTimeout_SkipsEventStreams(t *testing.T) {
+	var deadlines []bool
+	handler := requestTimeout(time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, ok := r.Context().Deadline()
+		deadlines = append(deadlines, ok)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(),
+		httptest.NewRequest(http.MethodGet, "/api/campaigns/1/analysis/jobs/2", nil))
+	handler.ServeHTTP(httptest.NewRecorder(),
+		httptest.NewRequest(http.MethodGet, "/api/campaigns/1/analysis/jobs/2/enrichment-stream", nil))
+	handler.ServeHTTP(httptest.NewRecorder(),
+		httptest.NewRequest(http.MethodPost, "/api/campaigns/1/sessions/3/chat", nil))
+
+	assert.Equal(t, []bool{true, false, false}, deadlines)
+}
+
+func TestParseCampaignMemoryFilter(t *testing.T) {
+	spoiler := false
+	tests := []struct {
+		name    string
+		query   string
+		want    models.CampaignMemoryFilter
+		wantErr bool
+	}{
+		{"no filters", "", models.CampaignMemoryFilter{}, false},
+		{"all filters", "?type=plot_thread&minImportance=7&spoiler=false",
+			models.CampaignMemoryFilter{
+				MemoryType:    models.MemoryTypePlotThread,
+				MinImportance: 7,
+				IsSpoiler:     &spoiler,
+			}, false},
+		{"unknown type", "?type=rumour", models.CampaignMemoryFilter{}, true},
+		{"importance out of range", "?minImportance=11", models.CampaignMemoryFilter{}, true},
+		{"importance not a number", "?minImportance=high", models.CampaignMemoryFilter{}, true},
+		{"invalid spoiler", "?spoiler=maybe", models.CampaignMemoryFilter{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/campaigns/1/memories"+tt.query, nil)
+			got, err := parseCampaignMemoryFilter(req)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidateCampaignMemoryRequests(t *testing.T) {
+	importance := 8
+	tooImportant := 11
+	empty := ""
+	gmNote := models.MemoryTypeGMNote
+	badType := models.MemoryType("rumour")
+
+	assert.NoError(t, validateCreateCampaignMemoryRequest(models.CreateCampaignMemoryRequest{
+		MemoryType: models.MemoryTypePremise,
+		Content:    "The investigators inherit a haunted estate.",
+		Importance: &importance,
+	}))
+	assert.Error(t, validateCreateCampaignMemoryRequest(models.CreateCampaignMemoryRequest{
+		Content: "Missing a type.",
+	}))
+	assert.Error(t, validateCreateCampaignMemoryRequest(models.CreateCampaignMemoryRequest{
+		MemoryType: models.MemoryTypeTheme,
+	}))
+	assert.Error(t, validateCreateCampaignMemoryRequest(models.CreateCampaignMemoryRequest{
+		MemoryType: badType,
+		Content:    "Unknown type.",
+	}))
+	assert.Error(t, validateCreateCampaignMemoryRequest(models.CreateCampaignMemoryRequest{
+		MemoryType: models.MemoryTypeTheme,
+		Content:    "Too important.",
+		Importance: &tooImportant,
+	}))
+
+	assert.NoError(t, validateUpdateCampaignMemoryRequest(models.UpdateCampaignMemoryRequest{}))
+	assert.NoError(t, validateUpdateCampaignMemoryRequest(models.UpdateCampaignMemoryRequest{
+		MemoryType: &gmNote,
+		Importance: &importance,
+	}))
+	assert.Error(t, validateUpdateCampaignMemoryRequest(models.UpdateCampaignMemoryRequest{
+		MemoryType: &badType,
+	}))
+	assert.Error(t, validateUpdateCampaignMemoryRequest(models.UpdateCampaignMemoryRequest{
+		Content: &empty,
+	}))
+	assert.Error(t, validateUpdateCampaignMemoryRequest(models.UpdateCampaignMemoryRequest{
+		Importance: &tooImportant,
+	}))
+}
+
+func TestCompactStrings(t *testing.T) {
+	assert.Nil(t, compactStrings(nil), "nil must stay nil to mean no change")
+	assert.Equal(t, []string{}, compactStrings([]string{}))
+	assert.Equal(t, []string{"Find the heir", "Stop the cult"},
+		compactStrings([]string{" Find the heir ", "", "  ", "Stop the cult"}))
+}
+
+func TestHasWrapUpNotes(t *testing.T) {
+	notes := "The party escaped the warehouse."
+	blank := "  \n"
+
+	assert.False(t, hasWrapUpNotes(nil))
+	assert.False(t, hasWrapUpNotes([]models.Session{{ActualNotes: &blank}, {}}))
+	assert.True(t, hasWrapUpNotes([]models.Session{{}, {ActualNotes: &notes}}))
+}
+
+func TestIsPostPlayStage(t *testing.T) {
+	assert.False(t, isPostPlayStage(models.SessionStagePrep))
+	assert.False(t, isPostPlayStage(models.SessionStagePlay))
+	assert.True(t, isPostPlayStage(models.SessionStageWrapUp))
+	assert.True(t, isPostPlayStage(models.SessionStageCompleted))
+}
+
+func TestAddedNoteLines(t *testing.T) {
+	previous := "Arrived at the docks.\nMet the harbourmaster.\n"
+
+	assert.Equal(t, "", addedNoteLines(previous, previous))
+	assert.Equal(t, "Brother Aldric bars the crypt.",
+		addedNoteLines(previous, previous+"Brother Aldric bars the crypt.\nStill typ"),
+		"the unterminated last line is left out")
+	assert.Equal(t, "Met the harbourmaster again.",
+		addedNoteLines(previous, "Arrived at the docks.\nMet the harbourmaster again.\n"))
+	assert.Equal(t, "Met the harbourmaster.",
+		addedNoteLines(previous, previous+"  \nMet the harbourmaster.\n"),
+		"a repeated line is new")
+}
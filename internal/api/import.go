@@ -309,11 +309,19 @@ func (h *ImportHandler) ImportFile(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, response)
 }
 
-// createImportedEntities creates entities in the database from imported data.
+// createImportedEntities creates entities in the database from imported
+// data, attributing their first revisions to the import by the user in
+// ctx.
 func (h *ImportHandler) createImportedEntities(ctx context.Context, campaignID int64, extracted []common.ExtractedEntity) ([]models.Entity, []string) {
 	var created []models.Entity
 	var errors []string
 
+	author := models.EntityRevisionAuthor{Type: models.EntityRevisionAuthorImport}
+	if userID, ok := auth.GetUserIDFromContext(ctx); ok {
+		author.UserID = &userID
+	}
+	ctx = database.WithEntityRevisionAuthor(ctx, author)
+
 	for _, e := range extracted {
 		// Convert attributes to JSON
 		attributes, err := json.Marshal(e.Attributes)
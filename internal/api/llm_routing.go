@@ -0,0 +1,220 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package api
+
+import (
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/antonypegg/imagineer/internal/database"
+	"github.com/antonypegg/imagineer/internal/llm"
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+// newContentGenProvider builds the content generation provider for a
+// job from the user's settings. The primary service is always first;
+// any fallbacks from the user's LLM routing are chained after it, and
+// agent routes are placed in front of that chain for the agents they
+// name. Every provider in the chain is metered individually so that
+// usage is attributed to the service that actually served a request.
+func newContentGenProvider(
+	db *database.DB,
+	settings *models.UserSettings,
+	apiKey string,
+	campaignID int64,
+	jobID *int64,
+) (llm.Provider, error) {
+	primary, err := llm.NewProvider(*settings.ContentGenService, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	chain := []llm.Provider{
+		meterProvider(db, primary, *settings.ContentGenService, campaignID, jobID),
+	}
+
+	routing := settings.LLMRouting
+	if routing == nil {
+		return chain[0], nil
+	}
+
+	newRoute := func(route models.LLMRoute) (llm.Provider, bool) {
+		provider, err := llm.NewProviderWithModel(route.Service,
+			routeAPIKey(settings, apiKey, route), route.Model)
+		if err != nil {
+			log.Printf("Skipping LLM route %s: %v", routeLabel(route), err)
+			return nil, false
+		}
+		return llm.NewMeteredProvider(provider, db, models.LLMUsage{
+			CampaignID: campaignID,
+			JobID:      jobID,
+			Provider:   routeLabel(route),
+		}), true
+	}
+
+	for _, route := range routing.Fallbacks {
+		if provider, ok := newRoute(route); ok {
+			chain = append(chain, provider)
+		}
+	}
+
+	defaultProvider := chain[0]
+	if len(chain) > 1 {
+		if defaultProvider, err = llm.NewFallbackProvider(chain...); err != nil {
+			return nil, err
+		}
+	}
+	if len(routing.AgentRoutes) == 0 {
+		return defaultProvider, nil
+	}
+
+	routes := make(map[string]llm.Provider, len(routing.AgentRoutes))
+	for agent, route := range routing.AgentRoutes {
+		provider, ok := newRoute(route)
+		if !ok {
+			continue
+		}
+		routed, err := llm.NewFallbackProvider(append([]llm.Provider{provider}, chain...)...)
+		if err != nil {
+			return nil, err
+		}
+		routes[agent] = routed
+	}
+
+	return llm.NewRoutedProvider(defaultProvider, routes), nil
+}
+
+// routeLabel identifies a route in usage records and logs, e.g.
+// "openai" or "anthropic/claude-haiku-4-5".
+func routeLabel(route models.LLMRoute) string {
+	if route.Model == "" {
+		return string(route.Service)
+	}
+	return string(route.Service) + "/" + route.Model
+}
+
+// routeAPIKey resolves the API key for a route. A route's own key wins;
+// otherwise the key of the primary service or of another route for the
+// same service is reused, so a user only has to enter each key once.
+func routeAPIKey(settings *models.UserSettings, primaryKey string, route models.LLMRoute) string {
+	if route.APIKey != nil && *route.APIKey != "" {
+		return *route.APIKey
+	}
+	if route.Service == *settings.ContentGenService && primaryKey != "" {
+		return primaryKey
+	}
+	if key := routingKeyForService(settings.LLMRouting, route.Service); key != nil {
+		return *key
+	}
+	return ""
+}
+
+// routingKeyForService returns the first API key configured for service
+// in routing, or nil if there is none.
+func routingKeyForService(routing *models.LLMRouting, service models.LLMService) *string {
+	if routing == nil {
+		return nil
+	}
+	for _, route := range routing.Fallbacks {
+		if route.Service == service && route.APIKey != nil && *route.APIKey != "" {
+			return route.APIKey
+		}
+	}
+	for _, route := range routing.AgentRoutes {
+		if route.Service == service && route.APIKey != nil && *route.APIKey != "" {
+			return route.APIKey
+		}
+	}
+	return nil
+}
+
+// contentGenServices lists the services that can serve content
+// generation and therefore appear in LLM routing.
+var contentGenServices = []models.LLMService{
+	models.LLMServiceAnthropic,
+	models.LLMServiceOpenAI,
+	models.LLMServiceGemini,
+	models.LLMServiceOllama,
+	models.LLMServiceReplay,
+}
+
+// contentGenServiceAvailable reports whether users may select service
+// for content generation. The replay service is only available when
+// the operator has enabled it.
+func contentGenServiceAvailable(service models.LLMService) bool {
+	if service == models.LLMServiceReplay {
+		return llm.ReplayEnabled()
+	}
+	return slices.Contains(contentGenServices, service)
+}
+
+// validateRouting checks that every route names an available content
+// generation service and that agent routes use known agent names.
+func validateRouting(routing *models.LLMRouting) error {
+	if routing == nil {
+		return nil
+	}
+	for i, route := range routing.Fallbacks {
+		if !contentGenServiceAvailable(route.Service) {
+			return fmt.Errorf("fallback %d: unsupported service %q", i+1, route.Service)
+		}
+	}
+	for agent, route := range routing.AgentRoutes {
+		if !slices.Contains(llm.RoutableAgents, agent) {
+			return fmt.Errorf("unknown agent %q", agent)
+		}
+		if !contentGenServiceAvailable(route.Service) {
+			return fmt.Errorf("agent %s: unsupported service %q", agent, route.Service)
+		}
+	}
+	return nil
+}
+
+// maskRouting returns a copy of routing with every API key masked.
+func maskRouting(routing *models.LLMRouting) *models.LLMRouting {
+	if routing == nil {
+		return nil
+	}
+	masked := &models.LLMRouting{
+		Fallbacks:   make([]models.LLMRoute, len(routing.Fallbacks)),
+		AgentRoutes: make(map[string]models.LLMRoute, len(routing.AgentRoutes)),
+	}
+	for i, route := range routing.Fallbacks {
+		route.APIKey = maskAPIKey(route.APIKey)
+		masked.Fallbacks[i] = route
+	}
+	for agent, route := range routing.AgentRoutes {
+		route.APIKey = maskAPIKey(route.APIKey)
+		masked.AgentRoutes[agent] = route
+	}
+	return masked
+}
+
+// restoreMaskedRoutingKeys replaces masked API keys in an updated
+// routing with the stored key for the same service, so that a client
+// can send back the routing it was given without losing its keys.
+func restoreMaskedRoutingKeys(routing, existing *models.LLMRouting) {
+	if routing == nil {
+		return
+	}
+	restore := func(route models.LLMRoute) models.LLMRoute {
+		if isMaskedAPIKey(route.APIKey) {
+			route.APIKey = routingKeyForService(existing, route.Service)
+		}
+		return route
+	}
+	for i, route := range routing.Fallbacks {
+		routing.Fallbacks[i] = restore(route)
+	}
+	for agent, route := range routing.AgentRoutes {
+		routing.AgentRoutes[agent] = restore(route)
+	}
+}
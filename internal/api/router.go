@@ -13,10 +13,13 @@ package api
 import (
 	"errors"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/antonypegg/imagineer/internal/auth"
 	"github.com/antonypegg/imagineer/internal/database"
+	"github.com/antonypegg/imagineer/internal/events"
+	"github.com/antonypegg/imagineer/internal/jobs"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
@@ -36,8 +39,11 @@ var ErrMissingJWTSecret = errors.New("JWT secret is required for authentication"
 // NewRouter creates and returns a configured HTTP router for the API, including middleware, CORS,
 // public routes, and authentication-protected routes for campaign, entity, user, import, agent and
 // statistics endpoints.
+// Enrichment and revision work is run on queue, which may be nil when no
+// background workers are available. Enrichment event streams are fed by
+// broker, which may be nil, in which case they poll the database.
 // If jwtSecret is empty, NewRouter returns ErrMissingJWTSecret.
-func NewRouter(db *database.DB, authHandler *auth.AuthHandler, jwtSecret string) (http.Handler, error) {
+func NewRouter(db *database.DB, authHandler *auth.AuthHandler, jwtSecret string, queue *jobs.Queue, broker *events.Broker) (http.Handler, error) {
 	if jwtSecret == "" {
 		return nil, ErrMissingJWTSecret
 	}
@@ -48,7 +54,7 @@ func NewRouter(db *database.DB, authHandler *auth.AuthHandler, jwtSecret string)
 	r.Use(middleware.RealIP)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(60 * time.Second))
+	r.Use(requestTimeout(60 * time.Second))
 
 	// CORS configuration for localhost:5173 (Vite dev server)
 	r.Use(cors.Handler(cors.Options{
@@ -61,7 +67,7 @@ func NewRouter(db *database.DB, authHandler *auth.AuthHandler, jwtSecret string)
 	}))
 
 	// Create handlers
-	contentAnalysisHandler := NewContentAnalysisHandler(db)
+	contentAnalysisHandler := NewContentAnalysisHandler(db, queue)
 	h := NewHandler(db, contentAnalysisHandler)
 	importHandler := NewImportHandler(db)
 	agentHandler := NewAgentHandler(db)
@@ -70,7 +76,18 @@ func NewRouter(db *database.DB, authHandler *auth.AuthHandler, jwtSecret string)
 	entityLogHandler := NewEntityLogHandler(db)
 	sceneHandler := NewSceneHandler(db)
 	draftHandler := NewDraftHandler(db)
-	enrichmentHandler := NewEnrichmentHandler(db)
+	enrichmentHandler := NewEnrichmentHandler(db, queue, broker)
+	usageHandler := NewUsageHandler(db)
+	campaignMemoryHandler := NewCampaignMemoryHandler(db)
+	chapterMemoryHandler := NewChapterMemoryHandler(db, queue)
+	sessionChatHandler := NewSessionChatHandler(db, contentAnalysisHandler)
+	entityExtractionHandler := NewEntityExtractionHandler(db)
+	sessionRecapHandler := NewSessionRecapHandler(db)
+	sessionEntityHandler := NewSessionEntityHandler(db)
+	canonConflictHandler := NewCanonConflictHandler(db)
+	entityRevisionHandler := NewEntityRevisionHandler(db)
+	entityMergeHandler := NewEntityMergeHandler(db)
+	entityAliasHandler := NewEntityAliasHandler(db)
 
 	// API routes
 	r.Route("/api", func(r chi.Router) {
@@ -114,8 +131,27 @@ func NewRouter(db *database.DB, authHandler *auth.AuthHandler, jwtSecret string)
 					// Campaign stats
 					r.Get("/stats", h.GetCampaignStats)
 
+					// LLM token usage and budget
+					r.Route("/usage", func(r chi.Router) {
+						r.Get("/", usageHandler.GetCampaignUsage)
+						r.Get("/budget", usageHandler.GetCampaignBudget)
+						r.Put("/budget", usageHandler.UpdateCampaignBudget)
+					})
+
+					// Campaign memory
+					r.Get("/memories", campaignMemoryHandler.ListCampaignMemories)
+					r.Post("/memories", campaignMemoryHandler.CreateCampaignMemory)
+					r.Route("/memories/{memoryId}", func(r chi.Router) {
+						r.Get("/", campaignMemoryHandler.GetCampaignMemory)
+						r.Put("/", campaignMemoryHandler.UpdateCampaignMemory)
+						r.Delete("/", campaignMemoryHandler.DeleteCampaignMemory)
+					})
+					r.Get("/chapter-memories", chapterMemoryHandler.ListChapterMemories)
+					r.Get("/chapter-memories/current", chapterMemoryHandler.GetCurrentChapterMemory)
+
 					// Campaign content search
 					r.Get("/search", h.SearchCampaignContent)
+					r.Post("/search/reindex", h.ReindexCampaignContent)
 
 					// Campaign entities
 					r.Get("/entities", h.ListEntities)
@@ -127,6 +163,22 @@ func NewRouter(db *database.DB, authHandler *auth.AuthHandler, jwtSecret string)
 					r.Route("/entities/{entityId}", func(r chi.Router) {
 						r.Get("/relationships", h.GetEntityRelationships)
 						r.Get("/timeline", h.GetEntityTimelineEvents)
+						r.Get("/sessions", sessionEntityHandler.ListEntitySessions)
+						r.Post("/merge", entityMergeHandler.MergeEntity)
+
+						// Entity aliases
+						r.Get("/aliases", entityAliasHandler.ListEntityAliases)
+						r.Post("/aliases", entityAliasHandler.CreateEntityAlias)
+						r.Route("/aliases/{aliasId}", func(r chi.Router) {
+							r.Put("/", entityAliasHandler.UpdateEntityAlias)
+							r.Delete("/", entityAliasHandler.DeleteEntityAlias)
+						})
+
+						// Entity revision history
+						r.Get("/revisions", entityRevisionHandler.ListEntityRevisions)
+						r.Get("/revisions/diff", entityRevisionHandler.DiffEntityRevisions)
+						r.Get("/revisions/{version}", entityRevisionHandler.GetEntityRevision)
+						r.Post("/revisions/{version}/restore", entityRevisionHandler.RestoreEntityRevision)
 
 						// Entity log
 						r.Get("/log", entityLogHandler.ListEntityLogs)
@@ -171,6 +223,15 @@ func NewRouter(db *database.DB, authHandler *auth.AuthHandler, jwtSecret string)
 						r.Get("/sessions", h.ListSessionsByChapter)
 						r.Get("/relationships", h.ListChapterRelationships)
 
+						// Chapter memory
+						r.Route("/memory", func(r chi.Router) {
+							r.Get("/", chapterMemoryHandler.GetChapterMemory)
+							r.Put("/", chapterMemoryHandler.SaveChapterMemory)
+							r.Delete("/", chapterMemoryHandler.DeleteChapterMemory)
+							r.Post("/current", chapterMemoryHandler.SetCurrentChapter)
+							r.Post("/regenerate", chapterMemoryHandler.RegenerateChapterMemory)
+						})
+
 						// Chapter entity links
 						r.Get("/entities", h.ListChapterEntities)
 						r.Post("/entities", h.CreateChapterEntity)
@@ -191,11 +252,32 @@ func NewRouter(db *database.DB, authHandler *auth.AuthHandler, jwtSecret string)
 						// Scenes
 						r.Get("/scenes", sceneHandler.ListScenes)
 						r.Post("/scenes", sceneHandler.CreateScene)
+						r.Post("/scenes/draft", sceneHandler.DraftScenes)
 						r.Route("/scenes/{sceneId}", func(r chi.Router) {
 							r.Get("/", sceneHandler.GetScene)
 							r.Put("/", sceneHandler.UpdateScene)
 							r.Delete("/", sceneHandler.DeleteScene)
 						})
+
+						// Session entity links
+						r.Get("/entities", sessionEntityHandler.ListSessionEntities)
+						r.Post("/entities", sessionEntityHandler.CreateSessionEntity)
+						r.Route("/entities/{linkId}", func(r chi.Router) {
+							r.Put("/", sessionEntityHandler.UpdateSessionEntity)
+							r.Delete("/", sessionEntityHandler.DeleteSessionEntity)
+						})
+
+						// Recap of the previous session for prep
+						r.Get("/previous-recap", sessionRecapHandler.GetPreviousSessionRecap)
+
+						// Play assistant chat
+						r.Get("/chat", sessionChatHandler.ListChatMessages)
+						r.Post("/chat", sessionChatHandler.SendChatMessage)
+
+						// Entities detected during play
+						r.Get("/entity-extractions", entityExtractionHandler.ListEntityExtractions)
+						r.Put("/entity-extractions/{extractionId}",
+							entityExtractionHandler.ResolveEntityExtraction)
 					})
 
 					// Campaign timeline
@@ -207,6 +289,14 @@ func NewRouter(db *database.DB, authHandler *auth.AuthHandler, jwtSecret string)
 						r.Delete("/", h.DeleteTimelineEvent)
 					})
 
+					// Canon conflicts accepted from the canon expert
+					r.Get("/canon-conflicts", canonConflictHandler.ListCanonConflicts)
+					r.Route("/canon-conflicts/{conflictId}", func(r chi.Router) {
+						r.Get("/", canonConflictHandler.GetCanonConflict)
+						r.Post("/acknowledge", canonConflictHandler.AcknowledgeCanonConflict)
+						r.Post("/resolve", canonConflictHandler.ResolveCanonConflict)
+					})
+
 					// Campaign import endpoints
 					r.Route("/import", func(r chi.Router) {
 						r.Post("/evernote", importHandler.ImportEvernote)
@@ -299,3 +389,29 @@ func NewRouter(db *database.DB, authHandler *auth.AuthHandler, jwtSecret string)
 
 	return r, nil
 }
+
+// streamingPathSuffixes identifies routes that serve long-lived
+// Server-Sent Event streams, such as enrichment progress and play
+// assistant replies.
+var streamingPathSuffixes = []string{
+	"/enrichment-stream",
+	"/chat",
+}
+
+// requestTimeout applies middleware.Timeout to every request except
+// event streams, which stay open for as long as the client watches.
+func requestTimeout(timeout time.Duration) func(http.Handler) http.Handler {
+	withTimeout := middleware.Timeout(timeout)
+	return func(next http.Handler) http.Handler {
+		timed := withTimeout(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, suffix := range streamingPathSuffixes {
+				if strings.HasSuffix(r.URL.Path, suffix) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			timed.ServeHTTP(w, r)
+		})
+	}
+}
@@ -11,14 +11,17 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
 
 	"github.com/antonypegg/imagineer/internal/auth"
 	"github.com/antonypegg/imagineer/internal/database"
 	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/antonypegg/imagineer/internal/prep"
 	"github.com/jackc/pgx/v5"
 )
 
@@ -203,10 +206,221 @@ func (h *SceneHandler) CreateScene(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusInternalServerError, "Failed to create scene")
 		return
 	}
+	h.recordSceneEntityMentions(r.Context(), scene)
 
 	respondJSON(w, http.StatusCreated, scene)
 }
 
+// DraftScenes handles POST /api/campaigns/{id}/sessions/{sessionId}/scenes/draft
+// Drafts scenes for the session from its chapter's overview, memory
+// and featured entities, or the current chapter's if the session has
+// none. The scenes are created as AI-sourced drafts for the GM to
+// prune and returned in play order.
+func (h *SceneHandler) DraftScenes(w http.ResponseWriter, r *http.Request) {
+	session, userID, ok := getOwnedSession(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	var req models.DraftScenesRequest
+	if r.Body != nil && r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+	if req.Count != nil && (*req.Count < 1 || *req.Count > prep.MaxSceneCount) {
+		respondError(w, http.StatusBadRequest,
+			fmt.Sprintf("Count must be between 1 and %d", prep.MaxSceneCount))
+		return
+	}
+
+	ctx := r.Context()
+	input, err := h.draftSceneInput(ctx, session)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusBadRequest, "Session has no chapter to draft scenes from")
+			return
+		}
+		log.Printf("Error loading chapter context of session %d: %v", session.ID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to load chapter")
+		return
+	}
+	if req.Count != nil {
+		input.Count = *req.Count
+	}
+	if req.Guidance != nil {
+		input.Guidance = *req.Guidance
+	}
+
+	settings, err := h.db.GetUserSettings(ctx, userID)
+	if err != nil {
+		log.Printf("Error getting user settings: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to get user settings")
+		return
+	}
+	apiKey, configured := contentGenAPIKey(settings)
+	if !configured {
+		respondError(w, http.StatusBadRequest,
+			"LLM service not configured. Configure an LLM in Account Settings.")
+		return
+	}
+	if campaignBudgetExceeded(ctx, h.db, session.CampaignID) {
+		respondError(w, http.StatusTooManyRequests,
+			"Campaign has exceeded its monthly token budget")
+		return
+	}
+	provider, err := newContentGenProvider(h.db, settings, apiKey, session.CampaignID, nil)
+	if err != nil {
+		log.Printf("Error creating LLM provider: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to create LLM provider")
+		return
+	}
+
+	drafts, err := prep.NewSceneDrafter().Draft(ctx, provider, input)
+	if err != nil {
+		if errors.Is(err, prep.ErrNoChapterContext) {
+			respondError(w, http.StatusBadRequest,
+				"Chapter has no overview, memory or featured entities to draft scenes from")
+			return
+		}
+		log.Printf("Scene drafting failed for session %d: %v", session.ID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to draft scenes")
+		return
+	}
+
+	scenes, err := h.createDraftScenes(ctx, session, drafts)
+	if err != nil {
+		log.Printf("Error creating drafted scenes for session %d: %v", session.ID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to create scenes")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, scenes)
+}
+
+// draftSceneInput loads the chapter context scenes for session are
+// drafted from. Returns pgx.ErrNoRows if the session has no chapter
+// and the campaign has no current chapter.
+func (h *SceneHandler) draftSceneInput(ctx context.Context, session *models.Session) (prep.SceneInput, error) {
+	input := prep.SceneInput{Session: *session}
+
+	var memory *models.ChapterMemory
+	var err error
+	if session.ChapterID != nil {
+		memory, err = h.db.GetChapterMemory(ctx, *session.ChapterID)
+	} else {
+		memory, err = h.db.GetCurrentChapterMemory(ctx, session.CampaignID)
+	}
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return input, err
+	}
+	input.Memory = memory
+
+	chapterID := session.ChapterID
+	if chapterID == nil {
+		if memory == nil {
+			return input, pgx.ErrNoRows
+		}
+		chapterID = &memory.ChapterID
+	}
+	chapter, err := h.db.GetChapter(ctx, *chapterID)
+	if err != nil {
+		return input, err
+	}
+	input.Chapter = *chapter
+
+	links, err := h.db.ListChapterEntities(ctx, chapter.ID)
+	if err != nil {
+		return input, err
+	}
+	for _, link := range links {
+		if link.MentionType == models.ChapterEntityMentionFeatured && link.Entity != nil {
+			input.Entities = append(input.Entities, *link.Entity)
+		}
+	}
+
+	if input.Scenes, err = h.db.ListScenesBySession(ctx, session.ID); err != nil {
+		return input, err
+	}
+	return input, nil
+}
+
+// createDraftScenes creates drafts as AI-sourced draft scenes of
+// session in order, then links connected drafts once their IDs are
+// known. If any write fails the scenes created so far are deleted.
+// The scenes' entity mentions are recorded only once every scene has
+// been written, so that a failed draft leaves none behind.
+func (h *SceneHandler) createDraftScenes(
+	ctx context.Context,
+	session *models.Session,
+	drafts []prep.SceneDraft,
+) ([]models.Scene, error) {
+	source := models.SceneSourceAI
+	confidence := models.SourceConfidenceDraft
+
+	scenes := make([]models.Scene, 0, len(drafts))
+	cleanup := func() {
+		for _, scene := range scenes {
+			if err := h.db.DeleteScene(ctx, scene.ID); err != nil {
+				log.Printf("Error deleting drafted scene %d: %v", scene.ID, err)
+			}
+		}
+	}
+
+	for _, draft := range drafts {
+		sceneType := draft.SceneType
+		scene, err := h.db.CreateScene(ctx, session.ID, session.CampaignID, models.CreateSceneRequest{
+			Title:            draft.Title,
+			Description:      optionalString(draft.Description),
+			SceneType:        &sceneType,
+			Objective:        optionalString(draft.Objective),
+			EntityIDs:        draft.EntityIDs,
+			Source:           &source,
+			SourceConfidence: &confidence,
+		})
+		if err != nil {
+			cleanup()
+			return nil, err
+		}
+		scenes = append(scenes, *scene)
+	}
+
+	for i, draft := range drafts {
+		if len(draft.Connections) == 0 {
+			continue
+		}
+		connections := make([]models.SceneConnection, 0, len(draft.Connections))
+		for _, c := range draft.Connections {
+			conn := models.SceneConnection{
+				SceneID:     c.SceneID,
+				EntityID:    c.EntityID,
+				Description: c.Description,
+			}
+			if c.SceneIndex != nil {
+				conn.SceneID = &scenes[*c.SceneIndex].ID
+			}
+			connections = append(connections, conn)
+		}
+		raw, err := json.Marshal(connections)
+		if err != nil {
+			cleanup()
+			return nil, fmt.Errorf("failed to marshal scene connections: %w", err)
+		}
+		scene, err := h.db.UpdateScene(ctx, scenes[i].ID, models.UpdateSceneRequest{Connections: raw})
+		if err != nil {
+			cleanup()
+			return nil, err
+		}
+		scenes[i] = *scene
+	}
+
+	for i := range scenes {
+		h.recordSceneEntityMentions(ctx, &scenes[i])
+	}
+	return scenes, nil
+}
+
 // UpdateScene handles PUT /api/campaigns/{id}/sessions/{sessionId}/scenes/{sceneId}
 // Updates an existing scene.
 func (h *SceneHandler) UpdateScene(w http.ResponseWriter, r *http.Request) {
@@ -273,6 +487,10 @@ func (h *SceneHandler) UpdateScene(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.EntityIDs != nil {
+		h.recordSceneEntityMentions(r.Context(), scene)
+	}
+
 	respondJSON(w, http.StatusOK, scene)
 }
 
@@ -341,3 +559,19 @@ func (h *SceneHandler) DeleteScene(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// recordSceneEntityMentions links the entities of scene to its session
+// as mentioned. Failures are logged and otherwise ignored.
+func (h *SceneHandler) recordSceneEntityMentions(ctx context.Context, scene *models.Scene) {
+	if _, err := h.db.AddSessionEntityMentions(ctx, scene.SessionID, scene.EntityIDs); err != nil {
+		log.Printf("Error recording entity mentions of scene %d: %v", scene.ID, err)
+	}
+}
+
+// optionalString returns a pointer to s, or nil if s is empty.
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,343 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/antonypegg/imagineer/internal/assistant"
+	"github.com/antonypegg/imagineer/internal/auth"
+	"github.com/antonypegg/imagineer/internal/database"
+	"github.com/antonypegg/imagineer/internal/embedding"
+	"github.com/antonypegg/imagineer/internal/enrichment"
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// maxChatMessageChars is the maximum length of a message sent to the
+// play assistant.
+const maxChatMessageChars = 4000
+
+// Events of a play assistant reply stream.
+const (
+	// chatEventMessage carries a persisted models.SessionChatMessage:
+	// first the GM's message, then the assistant's completed reply.
+	chatEventMessage = "message"
+
+	// chatEventDelta carries the next fragment of the reply.
+	chatEventDelta = "delta"
+
+	// chatEventError reports that the reply failed. No reply is
+	// persisted.
+	chatEventError = "error"
+)
+
+// chatDeltaEvent is the payload of a chatEventDelta event.
+type chatDeltaEvent struct {
+	Text string `json:"text"`
+}
+
+// chatErrorEvent is the payload of a chatEventError event.
+type chatErrorEvent struct {
+	Error string `json:"error"`
+}
+
+// SessionChatHandler handles play assistant chat API requests.
+type SessionChatHandler struct {
+	db        *database.DB
+	caHandler *ContentAnalysisHandler
+}
+
+// NewSessionChatHandler creates a new SessionChatHandler. caHandler
+// queues the extraction of entities from the GM's messages during
+// play and may be nil.
+func NewSessionChatHandler(db *database.DB, caHandler *ContentAnalysisHandler) *SessionChatHandler {
+	return &SessionChatHandler{db: db, caHandler: caHandler}
+}
+
+// getOwnedSession returns the session in the URL and the authenticated
+// user's ID if the user owns the campaign in the URL and the session
+// belongs to it, writing an error response and returning false
+// otherwise.
+func getOwnedSession(w http.ResponseWriter, r *http.Request, db *database.DB) (*models.Session, int64, bool) {
+	campaignID, err := parseInt64(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid campaign ID")
+		return nil, 0, false
+	}
+
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Authentication required")
+		return nil, 0, false
+	}
+
+	if err := db.VerifyCampaignOwnership(r.Context(), campaignID, userID); err != nil {
+		respondError(w, http.StatusNotFound, "Campaign not found")
+		return nil, 0, false
+	}
+
+	sessionID, err := parseInt64(r, "sessionId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid session ID")
+		return nil, 0, false
+	}
+
+	session, err := db.GetSession(r.Context(), sessionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "Session not found")
+			return nil, 0, false
+		}
+		log.Printf("Error getting session %d: %v", sessionID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to get session")
+		return nil, 0, false
+	}
+	if session.CampaignID != campaignID {
+		respondError(w, http.StatusNotFound, "Session not found")
+		return nil, 0, false
+	}
+
+	return session, userID, true
+}
+
+// ListChatMessages handles GET /api/campaigns/{id}/sessions/{sessionId}/chat
+// Returns the session's play assistant chat in conversation order.
+func (h *SessionChatHandler) ListChatMessages(w http.ResponseWriter, r *http.Request) {
+	session, _, ok := getOwnedSession(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	messages, err := h.db.ListSessionChatMessages(r.Context(), session.ID)
+	if err != nil {
+		log.Printf("Error listing chat messages for session %d: %v", session.ID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to list chat messages")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, messages)
+}
+
+// SendChatMessage handles POST /api/campaigns/{id}/sessions/{sessionId}/chat
+// Persists the GM's message and streams the play assistant's reply as
+// Server-Sent Events: a "message" event with the saved GM message,
+// "delta" events as the reply is generated, then a "message" event
+// with the saved reply, or an "error" event if the reply fails. The
+// reply is only persisted once it is complete.
+func (h *SessionChatHandler) SendChatMessage(w http.ResponseWriter, r *http.Request) {
+	session, userID, ok := getOwnedSession(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	var req models.SendSessionChatMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		respondError(w, http.StatusBadRequest, "Message content is required")
+		return
+	}
+	if len([]rune(content)) > maxChatMessageChars {
+		respondError(w, http.StatusBadRequest, "Message is too long")
+		return
+	}
+
+	ctx := r.Context()
+	settings, err := h.db.GetUserSettings(ctx, userID)
+	if err != nil {
+		log.Printf("Error getting user settings: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to get user settings")
+		return
+	}
+	apiKey, configured := contentGenAPIKey(settings)
+	if !configured {
+		respondError(w, http.StatusBadRequest,
+			"LLM service not configured. Configure an LLM in Account Settings.")
+		return
+	}
+	if campaignBudgetExceeded(ctx, h.db, session.CampaignID) {
+		respondError(w, http.StatusTooManyRequests,
+			"Campaign has exceeded its monthly token budget")
+		return
+	}
+	provider, err := newContentGenProvider(h.db, settings, apiKey, session.CampaignID, nil)
+	if err != nil {
+		log.Printf("Error creating LLM provider: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to create LLM provider")
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondError(w, http.StatusInternalServerError, "Streaming not supported")
+		return
+	}
+
+	history, err := h.db.ListSessionChatMessages(ctx, session.ID)
+	if err != nil {
+		log.Printf("Error listing chat messages for session %d: %v", session.ID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to load chat history")
+		return
+	}
+
+	summary, err := h.db.GetLatestSessionChatSummary(ctx, session.ID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		log.Printf("Error getting chat summary for session %d: %v", session.ID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to load chat history")
+		return
+	}
+
+	input := h.buildAssistantInput(ctx, session, settings, content)
+	input.History = assistant.BuildHistory(summary, history)
+
+	userMessage, err := h.db.CreateSessionChatMessage(ctx, session.ID, models.ChatRoleUser, content)
+	if err != nil {
+		log.Printf("Error saving chat message for session %d: %v", session.ID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to save chat message")
+		return
+	}
+
+	if session.Stage == models.SessionStagePlay && h.caHandler != nil {
+		h.caHandler.RunEntityExtraction(ctx, session, userID,
+			models.EntityExtractionSourceChat, content)
+	}
+
+	// A reply can outlast the server's write timeout.
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		log.Printf("SSE: could not clear write deadline for session %d chat: %v", session.ID, err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	writeChatEvent(w, chatEventMessage, userMessage)
+	flusher.Flush()
+
+	reply, err := assistant.NewAssistant().Reply(ctx, provider, input, func(delta string) {
+		writeChatEvent(w, chatEventDelta, chatDeltaEvent{Text: delta})
+		flusher.Flush()
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("Play assistant failed for session %d: %v", session.ID, err)
+			writeChatEvent(w, chatEventError, chatErrorEvent{Error: "The assistant could not reply"})
+			flusher.Flush()
+		}
+		return
+	}
+
+	replyMessage, err := h.db.CreateSessionChatMessage(ctx, session.ID, models.ChatRoleAssistant, reply)
+	if err != nil {
+		log.Printf("Error saving assistant reply for session %d: %v", session.ID, err)
+		writeChatEvent(w, chatEventError, chatErrorEvent{Error: "Failed to save the reply"})
+		flusher.Flush()
+		return
+	}
+
+	writeChatEvent(w, chatEventMessage, replyMessage)
+	flusher.Flush()
+
+	messages := append(history, *userMessage, *replyMessage)
+	if h.caHandler != nil && len(assistant.MessagesToCompress(summary, messages)) > 0 {
+		h.caHandler.RunChatCompression(ctx, session, userID)
+	}
+}
+
+// buildAssistantInput gathers the session's scenes, the entities
+// linked to it through its scenes and chapter, and the campaign
+// context relevant to message. Sources that fail to load are left
+// out.
+func (h *SessionChatHandler) buildAssistantInput(
+	ctx context.Context,
+	session *models.Session,
+	settings *models.UserSettings,
+	message string,
+) assistant.Input {
+	input := assistant.Input{Session: *session, Message: message}
+
+	scenes, err := h.db.ListScenesBySession(ctx, session.ID)
+	if err != nil {
+		log.Printf("Play assistant: failed to list scenes of session %d: %v", session.ID, err)
+	}
+	input.Scenes = scenes
+
+	linked := make(map[int64]bool)
+	for _, scene := range scenes {
+		for _, id := range scene.EntityIDs {
+			linked[id] = true
+		}
+	}
+	if session.ChapterID != nil {
+		links, err := h.db.ListChapterEntities(ctx, *session.ChapterID)
+		if err != nil {
+			log.Printf("Play assistant: failed to list entities of chapter %d: %v",
+				*session.ChapterID, err)
+		}
+		for _, link := range links {
+			linked[link.EntityID] = true
+		}
+	}
+	if len(linked) > 0 {
+		entities, err := h.db.ListEntitiesByCampaign(ctx, session.CampaignID)
+		if err != nil {
+			log.Printf("Play assistant: failed to list entities of campaign %d: %v",
+				session.CampaignID, err)
+		}
+		for _, entity := range entities {
+			if linked[entity.ID] {
+				input.Entities = append(input.Entities, entity)
+			}
+		}
+	}
+
+	var gameSystemCode string
+	campaign, err := h.db.GetCampaign(ctx, session.CampaignID)
+	if err != nil {
+		log.Printf("Play assistant: failed to get campaign %d: %v", session.CampaignID, err)
+	} else if campaign.System != nil {
+		gameSystemCode = campaign.System.Code
+	}
+
+	ctxBuilder := enrichment.NewContextBuilder(h.db, "").
+		WithEmbedder(embedding.EmbedderForSettings(settings))
+	ragCtx, err := ctxBuilder.BuildContext(ctx, session.CampaignID, message,
+		gameSystemCode, input.Entities)
+	if err != nil {
+		log.Printf("Play assistant: failed to build context for session %d: %v", session.ID, err)
+	}
+	input.Context = ragCtx
+
+	return input
+}
+
+// writeChatEvent writes a play assistant stream event with payload
+// encoded as JSON.
+func writeChatEvent(w http.ResponseWriter, event string, payload any) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("SSE: failed to encode %s event: %v", event, err)
+		return
+	}
+	writeSSEEvent(w, 0, event, data)
+}
@@ -0,0 +1,254 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/antonypegg/imagineer/internal/auth"
+	"github.com/antonypegg/imagineer/internal/database"
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// SessionEntityHandler handles session-entity link API requests.
+type SessionEntityHandler struct {
+	db *database.DB
+}
+
+// NewSessionEntityHandler creates a new SessionEntityHandler.
+func NewSessionEntityHandler(db *database.DB) *SessionEntityHandler {
+	return &SessionEntityHandler{db: db}
+}
+
+// ListSessionEntities handles GET /api/campaigns/{id}/sessions/{sessionId}/entities
+// Returns all entities linked to a session.
+func (h *SessionEntityHandler) ListSessionEntities(w http.ResponseWriter, r *http.Request) {
+	session, _, ok := getOwnedSession(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	links, err := h.db.ListSessionEntities(r.Context(), session.ID)
+	if err != nil {
+		log.Printf("Error listing session entities: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to list session entities")
+		return
+	}
+
+	if links == nil {
+		links = []models.SessionEntity{}
+	}
+
+	respondJSON(w, http.StatusOK, links)
+}
+
+// CreateSessionEntity handles POST /api/campaigns/{id}/sessions/{sessionId}/entities
+// Links an entity to a session.
+func (h *SessionEntityHandler) CreateSessionEntity(w http.ResponseWriter, r *http.Request) {
+	session, _, ok := getOwnedSession(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	var req models.CreateSessionEntityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.EntityID == 0 {
+		respondError(w, http.StatusBadRequest, "Entity ID is required")
+		return
+	}
+	if msg := validateSessionEntityLink(req.MentionType, req.Role); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	// Verify the entity exists and belongs to the same campaign
+	entity, err := h.db.GetEntity(r.Context(), req.EntityID)
+	if err != nil {
+		log.Printf("Error getting entity: %v", err)
+		respondError(w, http.StatusBadRequest, "Entity not found")
+		return
+	}
+
+	if entity.CampaignID != session.CampaignID {
+		respondError(w, http.StatusBadRequest, "Entity does not belong to this campaign")
+		return
+	}
+
+	link, err := h.db.CreateSessionEntity(r.Context(), session.ID, req)
+	if err != nil {
+		log.Printf("Error creating session entity: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to link entity to session")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, link)
+}
+
+// UpdateSessionEntity handles PUT /api/campaigns/{id}/sessions/{sessionId}/entities/{linkId}
+// Updates a session-entity link (e.g., change mention_type or role).
+func (h *SessionEntityHandler) UpdateSessionEntity(w http.ResponseWriter, r *http.Request) {
+	session, _, ok := getOwnedSession(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	linkID, ok := h.getSessionLinkID(w, r, session.ID)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateSessionEntityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if msg := validateSessionEntityLink(req.MentionType, req.Role); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	link, err := h.db.UpdateSessionEntity(r.Context(), linkID, req)
+	if err != nil {
+		log.Printf("Error updating session entity: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to update session entity link")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, link)
+}
+
+// DeleteSessionEntity handles DELETE /api/campaigns/{id}/sessions/{sessionId}/entities/{linkId}
+// Removes an entity link from a session.
+func (h *SessionEntityHandler) DeleteSessionEntity(w http.ResponseWriter, r *http.Request) {
+	session, _, ok := getOwnedSession(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	linkID, ok := h.getSessionLinkID(w, r, session.ID)
+	if !ok {
+		return
+	}
+
+	if err := h.db.DeleteSessionEntity(r.Context(), linkID); err != nil {
+		log.Printf("Error deleting session entity: %v", err)
+		respondError(w, http.StatusNotFound, "Session entity link not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListEntitySessions handles GET /api/campaigns/{id}/entities/{entityId}/sessions
+// Returns the sessions an entity appeared in, most recent first. With
+// played=true only completed sessions are returned, so the first one
+// is where the party last saw the entity.
+func (h *SessionEntityHandler) ListEntitySessions(w http.ResponseWriter, r *http.Request) {
+	campaignID, err := parseInt64(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid campaign ID")
+		return
+	}
+
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	if err := h.db.VerifyCampaignOwnership(r.Context(), campaignID, userID); err != nil {
+		respondError(w, http.StatusNotFound, "Campaign not found")
+		return
+	}
+
+	entityID, err := parseInt64(r, "entityId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid entity ID")
+		return
+	}
+
+	entity, err := h.db.GetEntity(r.Context(), entityID)
+	if err != nil || entity.CampaignID != campaignID {
+		respondError(w, http.StatusNotFound, "Entity not found")
+		return
+	}
+
+	playedOnly := r.URL.Query().Get("played") == "true"
+	appearances, err := h.db.ListEntitySessions(r.Context(), entityID, playedOnly)
+	if err != nil {
+		log.Printf("Error listing sessions of entity %d: %v", entityID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to list entity sessions")
+		return
+	}
+
+	if appearances == nil {
+		appearances = []models.EntitySessionAppearance{}
+	}
+
+	respondJSON(w, http.StatusOK, appearances)
+}
+
+// getSessionLinkID returns the link ID in the URL if the link belongs
+// to the session, writing an error response and returning false
+// otherwise.
+func (h *SessionEntityHandler) getSessionLinkID(w http.ResponseWriter, r *http.Request, sessionID int64) (int64, bool) {
+	linkID, err := parseInt64(r, "linkId")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid link ID")
+		return 0, false
+	}
+
+	existingLink, err := h.db.GetSessionEntity(r.Context(), linkID)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			log.Printf("Error getting session entity: %v", err)
+		}
+		respondError(w, http.StatusNotFound, "Session entity link not found")
+		return 0, false
+	}
+
+	if existingLink.SessionID != sessionID {
+		respondError(w, http.StatusNotFound, "Session entity link not found")
+		return 0, false
+	}
+
+	return linkID, true
+}
+
+// validateSessionEntityLink checks the mention type and role of a
+// session-entity link request, returning an error message or "" if
+// they are valid. Nil values are valid.
+func validateSessionEntityLink(mentionType *models.SessionEntityMentionType, role *string) string {
+	if mentionType != nil {
+		switch *mentionType {
+		case models.SessionEntityMentionLinked, models.SessionEntityMentionMentioned,
+			models.SessionEntityMentionFeatured:
+		default:
+			return "Mention type must be linked, mentioned or featured"
+		}
+	}
+	if role != nil {
+		switch *role {
+		case "appeared", "introduced", "major", "minor":
+		default:
+			return "Role must be appeared, introduced, major or minor"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,30 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package api
+
+import (
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSessionEntityLink(t *testing.T) {
+	featured := models.SessionEntityMentionFeatured
+	badType := models.SessionEntityMentionType("starring")
+	major := "major"
+	badRole := "cameo"
+
+	assert.Empty(t, validateSessionEntityLink(nil, nil))
+	assert.Empty(t, validateSessionEntityLink(&featured, &major))
+	assert.Contains(t, validateSessionEntityLink(&badType, nil), "Mention type")
+	assert.Contains(t, validateSessionEntityLink(nil, &badRole), "Role")
+}
@@ -0,0 +1,158 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/antonypegg/imagineer/internal/database"
+	"github.com/antonypegg/imagineer/internal/memory"
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// SessionRecapHandler handles previous-session recap API requests.
+type SessionRecapHandler struct {
+	db *database.DB
+}
+
+// NewSessionRecapHandler creates a new SessionRecapHandler.
+func NewSessionRecapHandler(db *database.DB) *SessionRecapHandler {
+	return &SessionRecapHandler{db: db}
+}
+
+// GetPreviousSessionRecap handles GET /api/campaigns/{id}/sessions/{sessionId}/previous-recap
+// Returns a recap of the session played before this one, for the GM
+// or, with audience=player, player-safe. A recap is generated on the
+// first request and cached until the previous session's wrap-up notes,
+// memories or entity log entries change.
+func (h *SessionRecapHandler) GetPreviousSessionRecap(w http.ResponseWriter, r *http.Request) {
+	session, userID, ok := getOwnedSession(w, r, h.db)
+	if !ok {
+		return
+	}
+
+	audience := models.RecapAudience(r.URL.Query().Get("audience"))
+	switch audience {
+	case "":
+		audience = models.RecapAudienceGM
+	case models.RecapAudienceGM, models.RecapAudiencePlayer:
+	default:
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid audience: %s", audience))
+		return
+	}
+
+	ctx := r.Context()
+	previous, err := h.db.GetPreviousSession(ctx, session)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "No previous session")
+			return
+		}
+		log.Printf("Error getting session before session %d: %v", session.ID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to get previous session")
+		return
+	}
+
+	input, err := h.recapInput(ctx, previous)
+	if err != nil {
+		log.Printf("Error loading recap sources of session %d: %v", previous.ID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to load previous session")
+		return
+	}
+	sourceHash := memory.RecapSourceHash(input, audience)
+
+	cached, err := h.db.GetSessionRecap(ctx, previous.ID, audience)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		log.Printf("Error getting recap of session %d: %v", previous.ID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to get recap")
+		return
+	}
+	if cached != nil && cached.SourceHash == sourceHash {
+		respondJSON(w, http.StatusOK, withSessionHeading(cached, previous))
+		return
+	}
+
+	if strings.TrimSpace(memory.RecapSources(input, audience)) == "" {
+		respondError(w, http.StatusNotFound, "Previous session has nothing to recap")
+		return
+	}
+
+	settings, err := h.db.GetUserSettings(ctx, userID)
+	if err != nil {
+		log.Printf("Error getting user settings: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to get user settings")
+		return
+	}
+	apiKey, configured := contentGenAPIKey(settings)
+	if !configured {
+		respondError(w, http.StatusBadRequest,
+			"LLM service not configured. Configure an LLM in Account Settings.")
+		return
+	}
+	if campaignBudgetExceeded(ctx, h.db, session.CampaignID) {
+		respondError(w, http.StatusTooManyRequests,
+			"Campaign has exceeded its monthly token budget")
+		return
+	}
+	provider, err := newContentGenProvider(h.db, settings, apiKey, session.CampaignID, nil)
+	if err != nil {
+		log.Printf("Error creating LLM provider: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to create LLM provider")
+		return
+	}
+
+	recap, err := memory.NewRecapper().Recap(ctx, provider, input, audience)
+	if err != nil {
+		log.Printf("Recap generation failed for session %d: %v", previous.ID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to generate recap")
+		return
+	}
+
+	saved, err := h.db.SaveSessionRecap(ctx, previous.ID, audience, recap, sourceHash)
+	if err != nil {
+		log.Printf("Error saving recap of session %d: %v", previous.ID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to save recap")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, withSessionHeading(saved, previous))
+}
+
+// recapInput loads the material a recap of session is written from.
+func (h *SessionRecapHandler) recapInput(ctx context.Context, session *models.Session) (memory.RecapInput, error) {
+	input := memory.RecapInput{Session: *session}
+
+	var err error
+	if input.Memories, err = h.db.ListSessionMemories(ctx, session.ID); err != nil {
+		return input, err
+	}
+	if input.EntityLogs, err = h.db.ListSessionEntityLogs(ctx, session.ID); err != nil {
+		return input, err
+	}
+	if input.Entities, err = h.db.ListEntitiesByCampaign(ctx, session.CampaignID); err != nil {
+		return input, err
+	}
+	return input, nil
+}
+
+// withSessionHeading sets the number and title of the recapped session
+// on recap.
+func withSessionHeading(recap *models.SessionRecap, session *models.Session) *models.SessionRecap {
+	recap.SessionNumber = session.SessionNumber
+	recap.Title = session.Title
+	return recap
+}
@@ -13,6 +13,8 @@ package api
 import (
 	"context"
 	"fmt"
+	"os"
+	"strconv"
 
 	"github.com/antonypegg/imagineer/internal/agents/canon"
 	"github.com/antonypegg/imagineer/internal/agents/graph"
@@ -64,7 +66,8 @@ func fetchSourceContent(
 // buildDefaultPipeline creates a Pipeline with the standard two-stage
 // layout used for content enrichment: an analysis stage (TTRPG expert
 // + canon expert) followed by an enrichment stage (enrichment agent +
-// graph expert).
+// graph expert). ENRICHMENT_AGENT_CONCURRENCY overrides how many agents
+// in a stage may run at once.
 func buildDefaultPipeline(db *database.DB) *enrichment.Pipeline {
 	ttrpgAgent := ttrpg.NewExpert()
 	canonAgent := canon.NewExpert()
@@ -82,5 +85,16 @@ func buildDefaultPipeline(db *database.DB) *enrichment.Pipeline {
 			Phase:  "enrichment",
 			Agents: []enrichment.PipelineAgent{enrichAgent, graphAgent},
 		},
-	})
+	}).WithConcurrency(agentConcurrency())
+}
+
+// agentConcurrency reads the per-stage agent concurrency limit from
+// ENRICHMENT_AGENT_CONCURRENCY, falling back to the pipeline default.
+func agentConcurrency() int {
+	if v := os.Getenv("ENRICHMENT_AGENT_CONCURRENCY"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			return parsed
+		}
+	}
+	return enrichment.DefaultAgentConcurrency
 }
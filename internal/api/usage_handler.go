@@ -0,0 +1,170 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/antonypegg/imagineer/internal/auth"
+	"github.com/antonypegg/imagineer/internal/database"
+	"github.com/antonypegg/imagineer/internal/llm"
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+// UsageHandler handles LLM usage reporting and budget API requests.
+type UsageHandler struct {
+	db *database.DB
+}
+
+// NewUsageHandler creates a new UsageHandler.
+func NewUsageHandler(db *database.DB) *UsageHandler {
+	return &UsageHandler{db: db}
+}
+
+// meterProvider wraps provider so that the tokens used by each
+// completion are recorded against the campaign and job.
+func meterProvider(
+	db *database.DB,
+	provider llm.Provider,
+	service models.LLMService,
+	campaignID int64,
+	jobID *int64,
+) llm.Provider {
+	return llm.NewMeteredProvider(provider, db, models.LLMUsage{
+		CampaignID: campaignID,
+		JobID:      jobID,
+		Provider:   string(service),
+	})
+}
+
+// campaignBudgetExceeded reports whether a campaign has used up its
+// monthly token budget. Lookup failures are logged and treated as not
+// exceeded so that accounting problems never block enrichment.
+func campaignBudgetExceeded(ctx context.Context, db *database.DB, campaignID int64) bool {
+	status, err := db.GetCampaignBudgetStatus(ctx, campaignID)
+	if err != nil {
+		log.Printf("Error checking token budget for campaign %d: %v", campaignID, err)
+		return false
+	}
+	return status.Exceeded
+}
+
+// verifyOwnership checks that the authenticated user owns the campaign
+// in the URL, writing an error response and returning false otherwise.
+func (h *UsageHandler) verifyOwnership(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	campaignID, err := parseInt64(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid campaign ID")
+		return 0, false
+	}
+
+	userID, ok := auth.GetUserIDFromContext(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Authentication required")
+		return 0, false
+	}
+
+	if err := h.db.VerifyCampaignOwnership(r.Context(), campaignID, userID); err != nil {
+		respondError(w, http.StatusNotFound, "Campaign not found")
+		return 0, false
+	}
+
+	return campaignID, true
+}
+
+// GetCampaignUsage handles GET /api/campaigns/{id}/usage
+// Returns the campaign's LLM token usage broken down by agent and
+// provider. The optional since query parameter (YYYY-MM-DD) sets the
+// start of the period; it defaults to the start of the current month.
+func (h *UsageHandler) GetCampaignUsage(w http.ResponseWriter, r *http.Request) {
+	campaignID, ok := h.verifyOwnership(w, r)
+	if !ok {
+		return
+	}
+
+	now := time.Now().UTC()
+	since := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if s := r.URL.Query().Get("since"); s != "" {
+		parsed, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid since date, expected YYYY-MM-DD")
+			return
+		}
+		since = parsed
+	}
+
+	summary, err := h.db.GetCampaignUsageSummary(r.Context(), campaignID, since)
+	if err != nil {
+		log.Printf("Error getting LLM usage for campaign %d: %v", campaignID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to get LLM usage")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, summary)
+}
+
+// GetCampaignBudget handles GET /api/campaigns/{id}/usage/budget
+// Returns the campaign's monthly token budget and this month's usage.
+func (h *UsageHandler) GetCampaignBudget(w http.ResponseWriter, r *http.Request) {
+	campaignID, ok := h.verifyOwnership(w, r)
+	if !ok {
+		return
+	}
+
+	status, err := h.db.GetCampaignBudgetStatus(r.Context(), campaignID)
+	if err != nil {
+		log.Printf("Error getting token budget for campaign %d: %v", campaignID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to get token budget")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, status)
+}
+
+// UpdateCampaignBudget handles PUT /api/campaigns/{id}/usage/budget
+// Sets or removes (with a null limit) the campaign's monthly token
+// budget.
+func (h *UsageHandler) UpdateCampaignBudget(w http.ResponseWriter, r *http.Request) {
+	campaignID, ok := h.verifyOwnership(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateCampaignBudgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.MonthlyTokenLimit != nil && *req.MonthlyTokenLimit <= 0 {
+		respondError(w, http.StatusBadRequest, "monthlyTokenLimit must be positive")
+		return
+	}
+
+	if err := h.db.SetCampaignTokenBudget(r.Context(), campaignID, req.MonthlyTokenLimit); err != nil {
+		log.Printf("Error setting token budget for campaign %d: %v", campaignID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to update token budget")
+		return
+	}
+
+	status, err := h.db.GetCampaignBudgetStatus(r.Context(), campaignID)
+	if err != nil {
+		log.Printf("Error getting token budget for campaign %d: %v", campaignID, err)
+		respondError(w, http.StatusInternalServerError, "Failed to get token budget")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, status)
+}
@@ -0,0 +1,227 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+// Package assistant implements the play assistant: an LLM chat that
+// helps the game master during a session, grounded in the session's
+// scenes and entities and in the campaign's memory and rules.
+package assistant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/antonypegg/imagineer/internal/agents"
+	"github.com/antonypegg/imagineer/internal/enrichment"
+	"github.com/antonypegg/imagineer/internal/llm"
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+// AgentName is the name under which play assistant LLM calls are
+// metered and routed.
+const AgentName = "assistant"
+
+const (
+	// contextBudget is the token budget for the campaign context
+	// included in each reply's prompt.
+	contextBudget = 6000
+
+	// historyBudget is the token budget for earlier messages of the
+	// conversation. The most recent messages are kept.
+	historyBudget = 4000
+
+	// maxNotesChars is the maximum number of characters of each of
+	// the session's notes included in the prompt.
+	maxNotesChars = 3000
+
+	// maxSceneFieldChars is the maximum number of characters of each
+	// scene field included in the prompt.
+	maxSceneFieldChars = 400
+
+	// maxEntityDescriptionChars is the maximum number of characters of
+	// each linked entity's description included in the prompt.
+	maxEntityDescriptionChars = 300
+
+	// maxReplyTokens caps the length of a reply.
+	maxReplyTokens = 1024
+)
+
+// ErrEmptyMessage is returned when the message to reply to is blank.
+var ErrEmptyMessage = errors.New("message is empty")
+
+// Input contains everything needed to reply to a message from the GM.
+type Input struct {
+	Session  models.Session
+	Scenes   []models.Scene
+	Entities []models.Entity // Entities linked to the session
+	History  ChatHistory     // The conversation so far
+	Message  string          // The GM's new message
+
+	// Context holds the campaign memory, recent sessions, related
+	// content and game system rules. It may be nil.
+	Context *enrichment.RAGContext
+}
+
+// Assistant replies to the GM's messages during play.
+type Assistant struct{}
+
+// NewAssistant creates a new Assistant.
+func NewAssistant() *Assistant {
+	return &Assistant{}
+}
+
+// Reply asks provider for a reply to input.Message and returns it.
+// onDelta, if not nil, is called with each fragment of the reply as it
+// is generated.
+func (a *Assistant) Reply(
+	ctx context.Context,
+	provider llm.Provider,
+	input Input,
+	onDelta func(delta string),
+) (string, error) {
+	if strings.TrimSpace(input.Message) == "" {
+		return "", ErrEmptyMessage
+	}
+
+	resp, err := llm.StreamCompletion(llm.WithAgentName(ctx, AgentName), provider, llm.CompletionRequest{
+		SystemPrompt: buildSystemPrompt(),
+		UserPrompt:   buildUserPrompt(input),
+		MaxTokens:    maxReplyTokens,
+		Temperature:  0.7,
+	}, onDelta)
+	if err != nil {
+		return "", fmt.Errorf("LLM completion failed: %w", err)
+	}
+
+	reply := strings.TrimSpace(resp.Content)
+	if reply == "" {
+		return "", fmt.Errorf("LLM returned an empty reply")
+	}
+	return reply, nil
+}
+
+// buildSystemPrompt returns the system prompt for the play assistant.
+func buildSystemPrompt() string {
+	return `You are the game master's assistant at the table during a live tabletop RPG session. The GM asks you questions and for ideas while running the game.
+
+Rules:
+- Answer briefly and directly; the GM is mid-session. Use short paragraphs or lists.
+- Ground your answers in the campaign context, session, scenes and entities provided. When they do not answer a question, say so rather than inventing established facts.
+- When the GM asks for ideas (names, descriptions, complications, NPC reactions), offer them, and make clear they are suggestions rather than canon.
+- Use the game system schema, if present, for rules, skills and stats.
+- The GM may see secrets the players have not learned; never suggest revealing them unless asked.`
+}
+
+// buildUserPrompt constructs the user prompt containing the campaign
+// context, the session, its scenes and entities, the conversation so
+// far and the GM's new message.
+func buildUserPrompt(input Input) string {
+	var b strings.Builder
+
+	if block := input.Context.Block(contextBudget); block != "" {
+		b.WriteString(block)
+		b.WriteString("\n")
+	}
+
+	heading := "Current Session"
+	if input.Session.SessionNumber != nil {
+		heading += fmt.Sprintf(": Session %d", *input.Session.SessionNumber)
+	}
+	if input.Session.Title != nil && *input.Session.Title != "" {
+		heading += " - " + *input.Session.Title
+	}
+	fmt.Fprintf(&b, "## %s\n\n", heading)
+
+	writeNotes := func(title string, notes *string) {
+		if notes != nil && strings.TrimSpace(*notes) != "" {
+			fmt.Fprintf(&b, "### %s\n\n%s\n\n", title,
+				agents.TruncateString(strings.TrimSpace(*notes), maxNotesChars))
+		}
+	}
+	writeNotes("Prep Notes", input.Session.PrepNotes)
+	writeNotes("Play Notes", input.Session.PlayNotes)
+
+	if len(input.Scenes) > 0 {
+		b.WriteString("### Scenes\n\n")
+		for i, scene := range input.Scenes {
+			fmt.Fprintf(&b, "%d. %s (%s, %s)\n", i+1, scene.Title, scene.SceneType, scene.Status)
+			writeSceneField := func(label string, value *string) {
+				if value != nil && strings.TrimSpace(*value) != "" {
+					fmt.Fprintf(&b, "   %s: %s\n", label,
+						agents.TruncateString(strings.TrimSpace(*value), maxSceneFieldChars))
+				}
+			}
+			writeSceneField("Description", scene.Description)
+			writeSceneField("Objective", scene.Objective)
+			writeSceneField("GM notes", scene.GMNotes)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(input.Entities) > 0 {
+		b.WriteString("### Entities in This Session\n\n")
+		for _, entity := range input.Entities {
+			fmt.Fprintf(&b, "- **%s** (%s)", entity.Name, entity.EntityType)
+			if entity.Description != nil && strings.TrimSpace(*entity.Description) != "" {
+				b.WriteString(": ")
+				b.WriteString(agents.TruncateString(
+					strings.TrimSpace(*entity.Description), maxEntityDescriptionChars))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if summary := strings.TrimSpace(input.History.Summary); summary != "" {
+		fmt.Fprintf(&b, "## Earlier in the Conversation\n\n%s\n\n", summary)
+	}
+	if transcript := formatHistory(input.History.Messages); transcript != "" {
+		fmt.Fprintf(&b, "## Conversation So Far\n\n%s\n\n", transcript)
+	}
+
+	fmt.Fprintf(&b, "## GM's Message\n\n%s\n", strings.TrimSpace(input.Message))
+
+	return b.String()
+}
+
+// formatHistory renders earlier chat messages one per line, keeping
+// the most recent messages that fit in historyBudget tokens.
+func formatHistory(messages []models.SessionChatMessage) string {
+	lines := make([]string, 0, len(messages))
+	remaining := historyBudget
+	for i := len(messages) - 1; i >= 0; i-- {
+		content := strings.TrimSpace(messages[i].Content)
+		if content == "" {
+			continue
+		}
+		line := formatMessage(messages[i].Role, content)
+		tokens := enrichment.EstimateTokens(line)
+		if tokens > remaining {
+			break
+		}
+		remaining -= tokens
+		lines = append(lines, line)
+	}
+
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatMessage renders a chat message as a transcript line.
+func formatMessage(role, content string) string {
+	speaker := "GM"
+	if role == models.ChatRoleAssistant {
+		speaker = "Assistant"
+	}
+	return fmt.Sprintf("%s: %s", speaker, content)
+}
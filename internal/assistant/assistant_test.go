@@ -0,0 +1,119 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package assistant
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/enrichment"
+	"github.com/antonypegg/imagineer/internal/llm"
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamingProvider streams a canned reply in fragments and records
+// the request it was given.
+type streamingProvider struct {
+	fragments []string
+	request   llm.CompletionRequest
+	agent     string
+}
+
+func (p *streamingProvider) Complete(_ context.Context, _ llm.CompletionRequest) (llm.CompletionResponse, error) {
+	return llm.CompletionResponse{Content: strings.Join(p.fragments, "")}, nil
+}
+
+func (p *streamingProvider) Stream(ctx context.Context, req llm.CompletionRequest) (<-chan llm.StreamEvent, error) {
+	p.request = req
+	p.agent = llm.AgentName(ctx)
+	out := make(chan llm.StreamEvent, len(p.fragments)+1)
+	for _, f := range p.fragments {
+		out <- llm.StreamEvent{Delta: f}
+	}
+	out <- llm.StreamEvent{Done: true}
+	close(out)
+	return out, nil
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestAssistant_Reply(t *testing.T) {
+	provider := &streamingProvider{fragments: []string{"Mick ", "runs the ", "docks. "}}
+	number := 3
+
+	var deltas []string
+	reply, err := NewAssistant().Reply(context.Background(), provider, Input{
+		Session: models.Session{
+			SessionNumber: &number,
+			Title:         strPtr("Fog Over Limehouse"),
+			PrepNotes:     strPtr("The party arrives at the docks."),
+		},
+		Scenes: []models.Scene{
+			{Title: "The Warehouse", SceneType: "exploration", Status: "planned", GMNotes: strPtr("The ledger is hidden here.")},
+		},
+		Entities: []models.Entity{
+			{Name: "Mick", EntityType: models.EntityTypeNPC, Description: strPtr("A dockside informant.")},
+		},
+		History: ChatHistory{
+			Summary: "The GM ruled the fog blocks sight past ten yards.",
+			Messages: []models.SessionChatMessage{
+				{Role: models.ChatRoleUser, Content: "Where is the ledger?"},
+				{Role: models.ChatRoleAssistant, Content: "In the warehouse."},
+			},
+		},
+		Message: "Who runs the docks?",
+		Context: &enrichment.RAGContext{
+			Memories: []models.CampaignMemory{
+				{MemoryType: models.MemoryTypePremise, Content: "Cultists plot to wake a god."},
+			},
+			GameSystemYAML: "name: Call of Cthulhu 7e",
+		},
+	}, func(delta string) { deltas = append(deltas, delta) })
+
+	require.NoError(t, err)
+	assert.Equal(t, "Mick runs the docks.", reply)
+	assert.Equal(t, provider.fragments, deltas)
+	assert.Equal(t, AgentName, provider.agent)
+
+	prompt := provider.request.UserPrompt
+	assert.Contains(t, prompt, "## Campaign Memory")
+	assert.Contains(t, prompt, "Call of Cthulhu 7e")
+	assert.Contains(t, prompt, "## Current Session: Session 3 - Fog Over Limehouse")
+	assert.Contains(t, prompt, "GM notes: The ledger is hidden here.")
+	assert.Contains(t, prompt, "- **Mick** (npc): A dockside informant.")
+	assert.Contains(t, prompt, "## Earlier in the Conversation\n\nThe GM ruled the fog")
+	assert.Contains(t, prompt, "GM: Where is the ledger?\nAssistant: In the warehouse.")
+	assert.True(t, strings.HasSuffix(prompt, "## GM's Message\n\nWho runs the docks?\n"))
+}
+
+func TestAssistant_Reply_EmptyMessage(t *testing.T) {
+	provider := &streamingProvider{}
+
+	_, err := NewAssistant().Reply(context.Background(), provider, Input{Message: "  "}, nil)
+
+	assert.ErrorIs(t, err, ErrEmptyMessage)
+}
+
+func TestFormatHistory_KeepsMostRecent(t *testing.T) {
+	long := strings.Repeat("word ", historyBudget)
+	history := []models.SessionChatMessage{
+		{Role: models.ChatRoleUser, Content: long},
+		{Role: models.ChatRoleAssistant, Content: "Older reply."},
+		{Role: models.ChatRoleUser, Content: "  "},
+		{Role: models.ChatRoleUser, Content: "Latest question?"},
+	}
+
+	assert.Equal(t, "Assistant: Older reply.\nGM: Latest question?", formatHistory(history))
+	assert.Equal(t, "", formatHistory(nil))
+}
@@ -0,0 +1,191 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package assistant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/antonypegg/imagineer/internal/agents"
+	"github.com/antonypegg/imagineer/internal/llm"
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+const (
+	// keepTurns is the number of most recent turns of a chat that are
+	// never folded into a summary. A turn is a message from the GM and
+	// the replies to it.
+	keepTurns = 6
+
+	// compressBatchTurns is the number of older turns that must build
+	// up before they are folded, so that the summary is not rewritten
+	// after every message.
+	compressBatchTurns = 4
+
+	// maxFoldedMessageChars is the maximum number of characters of each
+	// message included in a compression prompt.
+	maxFoldedMessageChars = 2000
+
+	// maxSummaryTokens caps the length of a chat summary.
+	maxSummaryTokens = 800
+)
+
+// ErrNothingToCompress is returned when a chat does not have enough
+// older turns to fold into a new summary.
+var ErrNothingToCompress = errors.New("no chat turns to compress")
+
+// ChatHistory is a play assistant chat as the assistant sees it: a
+// rolling summary of the older turns, followed by the messages after
+// it verbatim.
+type ChatHistory struct {
+	Summary  string
+	Messages []models.SessionChatMessage // Oldest first
+}
+
+// BuildHistory rebuilds the chat history from every message of a chat,
+// in conversation order, and the chat's latest summary, which may be
+// nil. The messages the summary covers are replaced by it. The result
+// depends only on the arguments.
+func BuildHistory(summary *models.SessionChatSummary, messages []models.SessionChatMessage) ChatHistory {
+	h := ChatHistory{Messages: unsummarized(summary, messages)}
+	if summary != nil {
+		h.Summary = strings.TrimSpace(summary.Summary)
+	}
+	return h
+}
+
+// MessagesToCompress returns the messages of a chat to fold into its
+// next summary: the messages after summary, which may be nil, that
+// come before the last keepTurns turns. Nil is returned until they
+// span compressBatchTurns turns.
+func MessagesToCompress(summary *models.SessionChatSummary, messages []models.SessionChatMessage) []models.SessionChatMessage {
+	pending := unsummarized(summary, messages)
+	starts := turnStarts(pending)
+	if len(starts) < keepTurns+compressBatchTurns {
+		return nil
+	}
+	return pending[:starts[len(starts)-keepTurns]]
+}
+
+// unsummarized returns the messages after the last one summary covers.
+func unsummarized(summary *models.SessionChatSummary, messages []models.SessionChatMessage) []models.SessionChatMessage {
+	if summary == nil {
+		return messages
+	}
+	for i, m := range messages {
+		if m.SortOrder > summary.ThroughSortOrder {
+			return messages[i:]
+		}
+	}
+	return nil
+}
+
+// turnStarts returns the indexes of the messages that start a turn:
+// each message from the GM, and the first message.
+func turnStarts(messages []models.SessionChatMessage) []int {
+	var starts []int
+	for i, m := range messages {
+		if i == 0 || m.Role == models.ChatRoleUser {
+			starts = append(starts, i)
+		}
+	}
+	return starts
+}
+
+// Compressor folds the older turns of a play assistant chat into
+// rolling summaries.
+type Compressor struct{}
+
+// NewCompressor creates a new Compressor.
+func NewCompressor() *Compressor {
+	return &Compressor{}
+}
+
+// Compress asks provider to fold the messages returned by
+// MessagesToCompress into summary and returns the new summary, which
+// is not saved. If there is nothing to fold ErrNothingToCompress is
+// returned without an LLM call.
+func (c *Compressor) Compress(
+	ctx context.Context,
+	provider llm.Provider,
+	summary *models.SessionChatSummary,
+	messages []models.SessionChatMessage,
+) (*models.SessionChatSummary, error) {
+	folded := MessagesToCompress(summary, messages)
+	if len(folded) == 0 {
+		return nil, ErrNothingToCompress
+	}
+
+	resp, err := provider.Complete(llm.WithAgentName(ctx, AgentName), llm.CompletionRequest{
+		SystemPrompt: buildCompressSystemPrompt(),
+		UserPrompt:   buildCompressUserPrompt(summary, folded),
+		MaxTokens:    maxSummaryTokens,
+		Temperature:  0.2,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("LLM completion failed: %w", err)
+	}
+
+	text := strings.TrimSpace(resp.Content)
+	if text == "" {
+		return nil, fmt.Errorf("LLM returned an empty summary")
+	}
+
+	last := folded[len(folded)-1]
+	next := &models.SessionChatSummary{
+		SessionID:        last.SessionID,
+		Summary:          text,
+		ThroughSortOrder: last.SortOrder,
+		MessageCount:     len(folded),
+	}
+	if summary != nil {
+		next.MessageCount += summary.MessageCount
+	}
+	return next, nil
+}
+
+// buildCompressSystemPrompt returns the system prompt for chat
+// compression.
+func buildCompressSystemPrompt() string {
+	return `You keep the running notes of a conversation between a game master and their assistant during a live tabletop RPG session. Fold the new part of the conversation into the summary so far.
+
+Rules:
+- Keep every name, decision, ruling and idea the GM adopted, and any open question.
+- Note suggestions the GM rejected only briefly, so they are not offered again.
+- Drop small talk and repetition.
+- Write a single updated summary in short paragraphs or bullets, no longer than needed.
+- Do not invent anything that is not in the summary or the conversation.`
+}
+
+// buildCompressUserPrompt constructs the user prompt containing the
+// summary so far and the messages to fold into it.
+func buildCompressUserPrompt(summary *models.SessionChatSummary, messages []models.SessionChatMessage) string {
+	var b strings.Builder
+
+	if summary != nil && strings.TrimSpace(summary.Summary) != "" {
+		fmt.Fprintf(&b, "## Summary So Far\n\n%s\n\n", strings.TrimSpace(summary.Summary))
+	}
+
+	b.WriteString("## New Conversation\n\n")
+	for _, m := range messages {
+		content := strings.TrimSpace(m.Content)
+		if content == "" {
+			continue
+		}
+		b.WriteString(formatMessage(m.Role,
+			agents.TruncateString(content, maxFoldedMessageChars)))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,119 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package assistant
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/llm"
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// summaryProvider returns a canned summary and records the request it
+// was given.
+type summaryProvider struct {
+	summary string
+	calls   int
+	request llm.CompletionRequest
+	agent   string
+}
+
+func (p *summaryProvider) Complete(ctx context.Context, req llm.CompletionRequest) (llm.CompletionResponse, error) {
+	p.calls++
+	p.request = req
+	p.agent = llm.AgentName(ctx)
+	return llm.CompletionResponse{Content: p.summary}, nil
+}
+
+// chatTurns returns n turns of a chat, each a question from the GM and
+// a reply, with consecutive sort orders.
+func chatTurns(n int) []models.SessionChatMessage {
+	messages := make([]models.SessionChatMessage, 0, 2*n)
+	for i := 0; i < n; i++ {
+		messages = append(messages,
+			models.SessionChatMessage{SessionID: 9, Role: models.ChatRoleUser,
+				Content: fmt.Sprintf("Question %d?", i), SortOrder: 2 * i},
+			models.SessionChatMessage{SessionID: 9, Role: models.ChatRoleAssistant,
+				Content: fmt.Sprintf("Answer %d.", i), SortOrder: 2*i + 1},
+		)
+	}
+	return messages
+}
+
+func TestMessagesToCompress(t *testing.T) {
+	assert.Nil(t, MessagesToCompress(nil, chatTurns(keepTurns+compressBatchTurns-1)),
+		"too few older turns to fold")
+
+	messages := chatTurns(keepTurns + compressBatchTurns)
+	folded := MessagesToCompress(nil, messages)
+	require.Len(t, folded, 2*compressBatchTurns)
+	assert.Equal(t, "Question 0?", folded[0].Content)
+
+	// Turns a summary already covers are not folded again.
+	summary := &models.SessionChatSummary{ThroughSortOrder: folded[len(folded)-1].SortOrder}
+	assert.Nil(t, MessagesToCompress(summary, messages))
+}
+
+func TestBuildHistory(t *testing.T) {
+	messages := chatTurns(3)
+
+	history := BuildHistory(nil, messages)
+	assert.Empty(t, history.Summary)
+	assert.Equal(t, messages, history.Messages)
+
+	summary := &models.SessionChatSummary{Summary: " Earlier turns. ", ThroughSortOrder: 1}
+	history = BuildHistory(summary, messages)
+	assert.Equal(t, "Earlier turns.", history.Summary)
+	assert.Equal(t, messages[2:], history.Messages)
+	assert.Equal(t, history, BuildHistory(summary, messages), "rebuilding is deterministic")
+
+	summary.ThroughSortOrder = 99
+	assert.Empty(t, BuildHistory(summary, messages).Messages)
+}
+
+func TestCompressor_Compress(t *testing.T) {
+	provider := &summaryProvider{summary: " The GM settled on Mick as the informant. "}
+	previous := &models.SessionChatSummary{
+		Summary:          "The party reached Limehouse.",
+		ThroughSortOrder: 3,
+		MessageCount:     4,
+	}
+	messages := chatTurns(2 + keepTurns + compressBatchTurns)
+
+	next, err := NewCompressor().Compress(context.Background(), provider, previous, messages)
+
+	require.NoError(t, err)
+	assert.Equal(t, "The GM settled on Mick as the informant.", next.Summary)
+	assert.Equal(t, int64(9), next.SessionID)
+	assert.Equal(t, 2*(2+compressBatchTurns)-1, next.ThroughSortOrder)
+	assert.Equal(t, 4+2*compressBatchTurns, next.MessageCount)
+
+	assert.Equal(t, AgentName, provider.agent)
+	prompt := provider.request.UserPrompt
+	assert.Contains(t, prompt, "## Summary So Far\n\nThe party reached Limehouse.")
+	assert.Contains(t, prompt, "GM: Question 2?\nAssistant: Answer 2.")
+	assert.NotContains(t, prompt, "Question 1?", "summarized turns are not resent")
+	assert.NotContains(t, prompt, fmt.Sprintf("Question %d?", 2+compressBatchTurns),
+		"recent turns are kept verbatim")
+}
+
+func TestCompressor_NothingToCompress(t *testing.T) {
+	provider := &summaryProvider{}
+
+	_, err := NewCompressor().Compress(context.Background(), provider, nil, chatTurns(keepTurns))
+
+	assert.ErrorIs(t, err, ErrNothingToCompress)
+	assert.Zero(t, provider.calls)
+}
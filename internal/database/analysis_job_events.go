@@ -0,0 +1,140 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// AnalysisJobEventsChannel is the LISTEN/NOTIFY channel on which new
+// analysis job events are announced. Each notification payload is a
+// JSON object holding the event "id" and "jobId".
+const AnalysisJobEventsChannel = "analysis_job_events"
+
+// analysisJobEventColumns lists the columns read by scanAnalysisJobEvent.
+const analysisJobEventColumns = `id, job_id, event_type, payload, created_at`
+
+// scanAnalysisJobEvent scans a row selected with analysisJobEventColumns.
+func scanAnalysisJobEvent(row pgx.Row) (*models.AnalysisJobEvent, error) {
+	var e models.AnalysisJobEvent
+	if err := row.Scan(&e.ID, &e.JobID, &e.EventType, &e.Payload, &e.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// PublishAnalysisJobEvent records an event for a content analysis job.
+// The insert notifies listeners on AnalysisJobEventsChannel once the
+// statement commits.
+func (db *DB) PublishAnalysisJobEvent(ctx context.Context, jobID int64, eventType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event payload: %w", err)
+	}
+
+	err = db.Exec(ctx, `
+        INSERT INTO analysis_job_events (job_id, event_type, payload)
+        VALUES ($1, $2, $3)`,
+		jobID, eventType, data,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to publish analysis job event: %w", err)
+	}
+	return nil
+}
+
+// GetAnalysisJobEvent retrieves a single analysis job event by ID.
+func (db *DB) GetAnalysisJobEvent(ctx context.Context, id int64) (*models.AnalysisJobEvent, error) {
+	e, err := scanAnalysisJobEvent(db.QueryRow(ctx,
+		`SELECT `+analysisJobEventColumns+` FROM analysis_job_events WHERE id = $1`, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get analysis job event: %w", err)
+	}
+	return e, nil
+}
+
+// ListAnalysisJobEvents returns the events of a content analysis job
+// with an ID greater than afterID, oldest first. Pass 0 to list every
+// event of the job.
+func (db *DB) ListAnalysisJobEvents(ctx context.Context, jobID int64, afterID int64) ([]models.AnalysisJobEvent, error) {
+	rows, err := db.Query(ctx, `
+        SELECT `+analysisJobEventColumns+`
+          FROM analysis_job_events
+         WHERE job_id = $1 AND id > $2
+         ORDER BY id`,
+		jobID, afterID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list analysis job events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.AnalysisJobEvent
+	for rows.Next() {
+		e, err := scanAnalysisJobEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan analysis job event: %w", err)
+		}
+		events = append(events, *e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating analysis job events: %w", err)
+	}
+
+	return events, nil
+}
+
+// Listen subscribes a dedicated connection to a NOTIFY channel and
+// calls onNotify with the payload of every notification until ctx is
+// done or the connection fails. onListening, if non-nil, is called once
+// the connection is listening. Listen always returns a non-nil error;
+// callers that need to keep listening should call it again, bearing in
+// mind that notifications sent while no connection was listening are
+// lost.
+func (db *DB) Listen(
+	ctx context.Context,
+	channel string,
+	onListening func(),
+	onNotify func(payload string),
+) error {
+	conn, err := db.Pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire listener connection: %w", err)
+	}
+	// A connection that has issued LISTEN must not be reused by the
+	// pool, so it is closed rather than returned.
+	defer conn.Release()
+	defer conn.Conn().Close(context.WithoutCancel(ctx)) //nolint:errcheck // best-effort close
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", channel, err)
+	}
+	if onListening != nil {
+		onListening()
+	}
+
+	for {
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			return fmt.Errorf("failed waiting for notification: %w", err)
+		}
+		onNotify(n.Payload)
+	}
+}
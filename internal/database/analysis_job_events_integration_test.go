@@ -0,0 +1,153 @@
+//go:build integration
+
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+func createEnrichingJob(t *testing.T, db *DB, campaignID int64) *models.ContentAnalysisJob {
+	t.Helper()
+	job, err := db.CreateAnalysisJob(context.Background(), &models.ContentAnalysisJob{
+		CampaignID:  campaignID,
+		SourceTable: "campaigns",
+		SourceID:    campaignID,
+		SourceField: "description",
+		Status:      "enriching",
+	})
+	if err != nil {
+		t.Fatalf("failed to create analysis job: %v", err)
+	}
+	return job
+}
+
+func TestIntegration_AnalysisJobEvents(t *testing.T) {
+	db := setupIntegrationDB(t)
+	campaignID, _ := createTestCampaign(t, db)
+	ctx := context.Background()
+	job := createEnrichingJob(t, db, campaignID)
+
+	if err := db.PublishAnalysisJobEvent(ctx, job.ID, models.AnalysisEventPhaseChanged,
+		models.PhaseChangedEvent{Stage: "analysis", Phase: "analysis"}); err != nil {
+		t.Fatalf("failed to publish event: %v", err)
+	}
+
+	runID := int64(99)
+	saved, err := db.AddEnrichmentItems(ctx, job.ID, []models.ContentAnalysisItem{
+		{JobID: job.ID, DetectionType: "description_update", MatchedText: "a",
+			Resolution: "pending", Phase: "enrichment", PipelineRunID: &runID},
+		{JobID: job.ID, DetectionType: "log_entry", MatchedText: "b",
+			Resolution: "pending", Phase: "enrichment", PipelineRunID: &runID},
+	})
+	if err != nil {
+		t.Fatalf("failed to add enrichment items: %v", err)
+	}
+	if len(saved) != 2 || saved[0].ID == 0 || saved[1].ID <= saved[0].ID {
+		t.Fatalf("expected saved items with increasing IDs, got %+v", saved)
+	}
+
+	events, err := db.ListAnalysisJobEvents(ctx, job.ID, 0)
+	if err != nil {
+		t.Fatalf("failed to list events: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(events))
+	}
+	var item models.ContentAnalysisItem
+	if err := json.Unmarshal(events[1].Payload, &item); err != nil {
+		t.Fatalf("failed to decode item event: %v", err)
+	}
+	if events[1].EventType != models.AnalysisEventItemCreated || item.ID != saved[0].ID {
+		t.Errorf("unexpected item event: %+v", events[1])
+	}
+
+	after, err := db.ListAnalysisJobEvents(ctx, job.ID, events[1].ID)
+	if err != nil || len(after) != 1 || after[0].ID != events[2].ID {
+		t.Errorf("expected only the last event after %d, got %+v (%v)", events[1].ID, after, err)
+	}
+
+	// Discarding the run removes its items and announces their IDs.
+	if err := db.DiscardEnrichmentRun(ctx, job.ID, runID); err != nil {
+		t.Fatalf("failed to discard run: %v", err)
+	}
+	current, err := db.GetAnalysisJob(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("failed to get job: %v", err)
+	}
+	if current.EnrichmentTotal != 0 {
+		t.Errorf("expected enrichment total 0 after discard, got %d", current.EnrichmentTotal)
+	}
+
+	if err := db.CompleteAnalysisJob(ctx, job.ID, false); err != nil {
+		t.Fatalf("failed to complete job: %v", err)
+	}
+	events, err = db.ListAnalysisJobEvents(ctx, job.ID, events[2].ID)
+	if err != nil {
+		t.Fatalf("failed to list events: %v", err)
+	}
+	if len(events) != 2 ||
+		events[0].EventType != models.AnalysisEventItemsRemoved ||
+		events[1].EventType != models.AnalysisEventJobComplete {
+		t.Errorf("expected items_removed then job_complete, got %+v", events)
+	}
+}
+
+func TestIntegration_ListenReceivesEventNotifications(t *testing.T) {
+	db := setupIntegrationDB(t)
+	campaignID, _ := createTestCampaign(t, db)
+	job := createEnrichingJob(t, db, campaignID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	listening := make(chan struct{})
+	payloads := make(chan string, 1)
+	go func() {
+		_ = db.Listen(ctx, AnalysisJobEventsChannel,
+			func() { close(listening) },
+			func(payload string) { payloads <- payload })
+	}()
+	<-listening
+
+	if err := db.SetJobFailureReason(ctx, job.ID, "API quota exceeded"); err != nil {
+		t.Fatalf("failed to fail job: %v", err)
+	}
+
+	select {
+	case payload := <-payloads:
+		var n struct {
+			ID    int64 `json:"id"`
+			JobID int64 `json:"jobId"`
+		}
+		if err := json.Unmarshal([]byte(payload), &n); err != nil {
+			t.Fatalf("failed to decode notification %q: %v", payload, err)
+		}
+		if n.JobID != job.ID {
+			t.Errorf("expected notification for job %d, got %+v", job.ID, n)
+		}
+		event, err := db.GetAnalysisJobEvent(ctx, n.ID)
+		if err != nil {
+			t.Fatalf("failed to get event: %v", err)
+		}
+		if event.EventType != models.AnalysisEventJobComplete {
+			t.Errorf("expected job_complete event, got %s", event.EventType)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for notification")
+	}
+}
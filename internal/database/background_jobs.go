@@ -0,0 +1,279 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// backgroundJobColumns is the column list scanned by scanBackgroundJob.
+const backgroundJobColumns = `
+        id, kind, campaign_id, analysis_job_id, payload, status,
+        attempts, max_attempts, run_after, locked_by, lease_expires_at,
+        last_error, result, created_at, updated_at`
+
+// scanBackgroundJob scans a row selected with backgroundJobColumns.
+func scanBackgroundJob(row pgx.Row) (*models.BackgroundJob, error) {
+	var j models.BackgroundJob
+	err := row.Scan(
+		&j.ID, &j.Kind, &j.CampaignID, &j.AnalysisJobID, &j.Payload, &j.Status,
+		&j.Attempts, &j.MaxAttempts, &j.RunAfter, &j.LockedBy, &j.LeaseExpiresAt,
+		&j.LastError, &j.Result, &j.CreatedAt, &j.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+// EnqueueBackgroundJob adds a job to the background job queue. The
+// job's Kind, CampaignID, AnalysisJobID, Payload, and MaxAttempts are
+// used; a zero MaxAttempts selects the column default.
+func (db *DB) EnqueueBackgroundJob(ctx context.Context, job *models.BackgroundJob) (*models.BackgroundJob, error) {
+	query := `
+        INSERT INTO background_jobs (kind, campaign_id, analysis_job_id, payload, max_attempts)
+        VALUES ($1, $2, $3, COALESCE($4::jsonb, '{}'), COALESCE(NULLIF($5, 0), 3))
+        RETURNING` + backgroundJobColumns
+
+	created, err := scanBackgroundJob(db.QueryRow(ctx, query,
+		job.Kind, job.CampaignID, job.AnalysisJobID, []byte(job.Payload), job.MaxAttempts,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue background job: %w", err)
+	}
+	return created, nil
+}
+
+// GetBackgroundJob retrieves a background job by ID.
+func (db *DB) GetBackgroundJob(ctx context.Context, id int64) (*models.BackgroundJob, error) {
+	query := `SELECT` + backgroundJobColumns + `
+          FROM background_jobs
+         WHERE id = $1`
+
+	job, err := scanBackgroundJob(db.QueryRow(ctx, query, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get background job: %w", err)
+	}
+	return job, nil
+}
+
+// ClaimBackgroundJob atomically claims the next runnable job of one of
+// the given kinds for workerID and leases it for the given duration.
+// A job is runnable when it is queued and due, or when it is running
+// but its lease has expired because the worker holding it died.
+// Concurrent workers never claim the same job thanks to FOR UPDATE
+// SKIP LOCKED. It returns nil when no job is available.
+func (db *DB) ClaimBackgroundJob(
+	ctx context.Context,
+	workerID string,
+	kinds []string,
+	lease time.Duration,
+) (*models.BackgroundJob, error) {
+	query := `
+        UPDATE background_jobs
+           SET status = 'running',
+               attempts = attempts + 1,
+               locked_by = $1,
+               lease_expires_at = NOW() + $2 * INTERVAL '1 millisecond'
+         WHERE id = (
+                SELECT id
+                  FROM background_jobs
+                 WHERE kind = ANY($3)
+                   AND ((status = 'queued' AND run_after <= NOW())
+                     OR (status = 'running' AND lease_expires_at < NOW()))
+                 ORDER BY run_after, id
+                 LIMIT 1
+                   FOR UPDATE SKIP LOCKED
+               )
+        RETURNING` + backgroundJobColumns
+
+	job, err := scanBackgroundJob(db.QueryRow(ctx, query,
+		workerID, lease.Milliseconds(), kinds,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim background job: %w", err)
+	}
+	return job, nil
+}
+
+// ExtendBackgroundJobLease renews the lease workerID holds on a running
+// job. It reports false when the lease has been lost, which happens
+// when the job was cancelled, deleted, or reclaimed by another worker.
+func (db *DB) ExtendBackgroundJobLease(
+	ctx context.Context,
+	id int64,
+	workerID string,
+	lease time.Duration,
+) (bool, error) {
+	query := `
+        UPDATE background_jobs
+           SET lease_expires_at = NOW() + $3 * INTERVAL '1 millisecond'
+         WHERE id = $1 AND locked_by = $2 AND status = 'running'`
+
+	result, err := db.Pool.Exec(ctx, query, id, workerID, lease.Milliseconds())
+	if err != nil {
+		return false, fmt.Errorf("failed to extend background job lease: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// CompleteBackgroundJob marks a running job held by workerID as
+// completed and stores its result.
+func (db *DB) CompleteBackgroundJob(ctx context.Context, id int64, workerID string, result []byte) error {
+	query := `
+        UPDATE background_jobs
+           SET status = 'completed', result = $3::jsonb,
+               locked_by = NULL, lease_expires_at = NULL
+         WHERE id = $1 AND locked_by = $2 AND status = 'running'`
+
+	if err := db.Exec(ctx, query, id, workerID, result); err != nil {
+		return fmt.Errorf("failed to complete background job: %w", err)
+	}
+	return nil
+}
+
+// RetryBackgroundJob returns a running job held by workerID to the
+// queue after a failed attempt, recording the error and delaying the
+// next attempt until runAfter.
+func (db *DB) RetryBackgroundJob(
+	ctx context.Context,
+	id int64,
+	workerID string,
+	lastError string,
+	runAfter time.Time,
+) error {
+	query := `
+        UPDATE background_jobs
+           SET status = 'queued', last_error = $3, run_after = $4,
+               locked_by = NULL, lease_expires_at = NULL
+         WHERE id = $1 AND locked_by = $2 AND status = 'running'`
+
+	if err := db.Exec(ctx, query, id, workerID, lastError, runAfter); err != nil {
+		return fmt.Errorf("failed to requeue background job: %w", err)
+	}
+	return nil
+}
+
+// FailBackgroundJob marks a running job held by workerID as
+// permanently failed.
+func (db *DB) FailBackgroundJob(ctx context.Context, id int64, workerID string, lastError string) error {
+	query := `
+        UPDATE background_jobs
+           SET status = 'failed', last_error = $3,
+               locked_by = NULL, lease_expires_at = NULL
+         WHERE id = $1 AND locked_by = $2 AND status = 'running'`
+
+	if err := db.Exec(ctx, query, id, workerID, lastError); err != nil {
+		return fmt.Errorf("failed to mark background job failed: %w", err)
+	}
+	return nil
+}
+
+// ReleaseBackgroundJob returns a running job held by workerID to the
+// queue without counting the attempt, for use when a worker shuts down
+// mid-job.
+func (db *DB) ReleaseBackgroundJob(ctx context.Context, id int64, workerID string) error {
+	query := `
+        UPDATE background_jobs
+           SET status = 'queued', attempts = GREATEST(attempts - 1, 0),
+               run_after = NOW(), locked_by = NULL, lease_expires_at = NULL
+         WHERE id = $1 AND locked_by = $2 AND status = 'running'`
+
+	if err := db.Exec(ctx, query, id, workerID); err != nil {
+		return fmt.Errorf("failed to release background job: %w", err)
+	}
+	return nil
+}
+
+// CancelBackgroundJobsForAnalysisJob cancels every queued or running
+// background job attached to a content analysis job and returns the
+// IDs of the jobs that were cancelled.
+func (db *DB) CancelBackgroundJobsForAnalysisJob(ctx context.Context, analysisJobID int64) ([]int64, error) {
+	query := `
+        UPDATE background_jobs
+           SET status = 'cancelled', locked_by = NULL, lease_expires_at = NULL
+         WHERE analysis_job_id = $1 AND status IN ('queued', 'running')
+        RETURNING id`
+
+	rows, err := db.Query(ctx, query, analysisJobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel background jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan cancelled background job: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating cancelled background jobs: %w", err)
+	}
+
+	return ids, nil
+}
+
+// RecoverBackgroundJobs repairs queue state left behind by workers that
+// died without finishing their jobs, typically because the server was
+// restarted. Running jobs whose lease has expired are requeued, or
+// failed if they have used up their attempts, and content analysis
+// jobs left in the enriching status with no live background job are
+// marked failed, with a job_complete event for clients still
+// watching them. It returns the number of background jobs requeued and
+// analysis jobs failed.
+func (db *DB) RecoverBackgroundJobs(ctx context.Context) (requeued int64, orphaned int64, err error) {
+	result, err := db.Pool.Exec(ctx, `
+        UPDATE background_jobs
+           SET status = CASE WHEN attempts >= max_attempts THEN 'failed' ELSE 'queued' END,
+               last_error = COALESCE(last_error, 'worker lease expired'),
+               run_after = NOW(), locked_by = NULL, lease_expires_at = NULL
+         WHERE status = 'running' AND lease_expires_at < NOW()`)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to requeue expired background jobs: %w", err)
+	}
+	requeued = result.RowsAffected()
+
+	result, err = db.Pool.Exec(ctx, `
+        WITH orphaned AS (
+            UPDATE content_analysis_jobs j
+               SET status = 'failed',
+                   failure_reason = 'Enrichment was interrupted'
+             WHERE j.status = 'enriching'
+               AND NOT EXISTS (
+                    SELECT 1
+                      FROM background_jobs b
+                     WHERE b.analysis_job_id = j.id
+                       AND b.status IN ('queued', 'running')
+                   )
+            RETURNING j.id, j.failure_reason
+        )
+        INSERT INTO analysis_job_events (job_id, event_type, payload)
+        SELECT id, 'job_complete',
+               jsonb_build_object('status', 'failed', 'failureReason', failure_reason)
+          FROM orphaned`)
+	if err != nil {
+		return requeued, 0, fmt.Errorf("failed to recover orphaned analysis jobs: %w", err)
+	}
+	return requeued, result.RowsAffected(), nil
+}
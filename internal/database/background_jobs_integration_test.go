@@ -0,0 +1,147 @@
+//go:build integration
+
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+func TestIntegration_BackgroundJobLifecycle(t *testing.T) {
+	db := setupIntegrationDB(t)
+	campaignID, _ := createTestCampaign(t, db)
+	ctx := context.Background()
+
+	analysisJob, err := db.CreateAnalysisJob(ctx, &models.ContentAnalysisJob{
+		CampaignID:  campaignID,
+		SourceTable: "campaigns",
+		SourceID:    campaignID,
+		SourceField: "description",
+		Status:      "enriching",
+	})
+	if err != nil {
+		t.Fatalf("failed to create analysis job: %v", err)
+	}
+
+	job, err := db.EnqueueBackgroundJob(ctx, &models.BackgroundJob{
+		Kind:          "integration-test",
+		CampaignID:    &campaignID,
+		AnalysisJobID: &analysisJob.ID,
+		Payload:       []byte(`{"userId":1}`),
+		MaxAttempts:   2,
+	})
+	if err != nil {
+		t.Fatalf("failed to enqueue job: %v", err)
+	}
+	if job.Status != models.BackgroundJobQueued || job.MaxAttempts != 2 {
+		t.Fatalf("unexpected enqueued job: %+v", job)
+	}
+
+	kinds := []string{"integration-test"}
+	claimed, err := db.ClaimBackgroundJob(ctx, "worker-a", kinds, time.Minute)
+	if err != nil || claimed == nil || claimed.ID != job.ID {
+		t.Fatalf("expected to claim job %d, got %+v (%v)", job.ID, claimed, err)
+	}
+	if claimed.Attempts != 1 || claimed.Status != models.BackgroundJobRunning {
+		t.Errorf("unexpected claimed job: %+v", claimed)
+	}
+
+	// A second worker must not see the leased job.
+	other, err := db.ClaimBackgroundJob(ctx, "worker-b", kinds, time.Minute)
+	if err != nil || other != nil {
+		t.Fatalf("expected no job for second worker, got %+v (%v)", other, err)
+	}
+
+	// Only the lease holder can renew the lease.
+	if held, _ := db.ExtendBackgroundJobLease(ctx, job.ID, "worker-b", time.Minute); held {
+		t.Error("expected lease renewal by another worker to fail")
+	}
+	if held, _ := db.ExtendBackgroundJobLease(ctx, job.ID, "worker-a", time.Millisecond); !held {
+		t.Error("expected lease renewal by the holder to succeed")
+	}
+
+	// Once the lease has expired another worker reclaims the job.
+	time.Sleep(10 * time.Millisecond)
+	reclaimed, err := db.ClaimBackgroundJob(ctx, "worker-b", kinds, time.Minute)
+	if err != nil || reclaimed == nil || reclaimed.Attempts != 2 {
+		t.Fatalf("expected worker-b to reclaim job, got %+v (%v)", reclaimed, err)
+	}
+
+	if err := db.CompleteBackgroundJob(ctx, job.ID, "worker-b", []byte(`{"ok":true}`)); err != nil {
+		t.Fatalf("failed to complete job: %v", err)
+	}
+	done, err := db.GetBackgroundJob(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("failed to get job: %v", err)
+	}
+	if done.Status != models.BackgroundJobCompleted || string(done.Result) != `{"ok": true}` {
+		t.Errorf("unexpected completed job: %+v", done)
+	}
+
+	// With no live background job left, recovery fails the analysis job.
+	_, orphaned, err := db.RecoverBackgroundJobs(ctx)
+	if err != nil {
+		t.Fatalf("failed to recover jobs: %v", err)
+	}
+	if orphaned < 1 {
+		t.Errorf("expected the enriching analysis job to be recovered, got %d", orphaned)
+	}
+	recovered, err := db.GetAnalysisJob(ctx, analysisJob.ID)
+	if err != nil {
+		t.Fatalf("failed to get analysis job: %v", err)
+	}
+	if recovered.Status != "failed" {
+		t.Errorf("expected analysis job to be failed, got %q", recovered.Status)
+	}
+}
+
+func TestIntegration_CancelBackgroundJobsForAnalysisJob(t *testing.T) {
+	db := setupIntegrationDB(t)
+	campaignID, _ := createTestCampaign(t, db)
+	ctx := context.Background()
+
+	analysisJob, err := db.CreateAnalysisJob(ctx, &models.ContentAnalysisJob{
+		CampaignID:  campaignID,
+		SourceTable: "campaigns",
+		SourceID:    campaignID,
+		SourceField: "description",
+		Status:      "enriching",
+	})
+	if err != nil {
+		t.Fatalf("failed to create analysis job: %v", err)
+	}
+
+	job, err := db.EnqueueBackgroundJob(ctx, &models.BackgroundJob{
+		Kind:          "integration-test",
+		AnalysisJobID: &analysisJob.ID,
+	})
+	if err != nil {
+		t.Fatalf("failed to enqueue job: %v", err)
+	}
+
+	ids, err := db.CancelBackgroundJobsForAnalysisJob(ctx, analysisJob.ID)
+	if err != nil {
+		t.Fatalf("failed to cancel jobs: %v", err)
+	}
+	if len(ids) != 1 || ids[0] != job.ID {
+		t.Errorf("expected job %d to be cancelled, got %v", job.ID, ids)
+	}
+
+	claimed, err := db.ClaimBackgroundJob(ctx, "worker-a", []string{"integration-test"}, time.Minute)
+	if err != nil || claimed != nil {
+		t.Errorf("expected cancelled job not to be claimed, got %+v (%v)", claimed, err)
+	}
+}
@@ -0,0 +1,206 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// campaignMemoryColumns is the standard column list for campaign
+// memory queries.
+const campaignMemoryColumns = `id, campaign_id, memory_type, title, content,
+	source_session_id, importance, is_spoiler, gm_created,
+	created_at, updated_at`
+
+// scanCampaignMemory scans a single row into a models.CampaignMemory.
+func scanCampaignMemory(row pgx.Row) (*models.CampaignMemory, error) {
+	var m models.CampaignMemory
+	err := row.Scan(
+		&m.ID, &m.CampaignID, &m.MemoryType, &m.Title, &m.Content,
+		&m.SourceSessionID, &m.Importance, &m.IsSpoiler, &m.GMCreated,
+		&m.CreatedAt, &m.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// scanCampaignMemories scans multiple campaign memory rows.
+func scanCampaignMemories(rows pgx.Rows) ([]models.CampaignMemory, error) {
+	var memories []models.CampaignMemory
+	for rows.Next() {
+		m, err := scanCampaignMemory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan campaign memory: %w", err)
+		}
+		memories = append(memories, *m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating campaign memories: %w", err)
+	}
+
+	return memories, nil
+}
+
+// CreateCampaignMemory inserts a new campaign memory. gmCreated records
+// whether the GM wrote the memory, as opposed to it being extracted
+// from play by the AI.
+func (db *DB) CreateCampaignMemory(ctx context.Context, campaignID int64, req models.CreateCampaignMemoryRequest, gmCreated bool) (*models.CampaignMemory, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO campaign_memories
+			(campaign_id, memory_type, title, content, source_session_id,
+			 importance, is_spoiler, gm_created)
+		VALUES ($1, $2, $3, $4, $5, COALESCE($6, 5), $7, $8)
+		RETURNING %s`, campaignMemoryColumns)
+
+	m, err := scanCampaignMemory(db.QueryRow(ctx, query,
+		campaignID, req.MemoryType, req.Title, req.Content,
+		req.SourceSessionID, req.Importance, req.IsSpoiler, gmCreated,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create campaign memory: %w", err)
+	}
+
+	return m, nil
+}
+
+// GetCampaignMemory retrieves a campaign memory by ID.
+// Returns pgx.ErrNoRows (unwrapped) when the memory does not exist.
+func (db *DB) GetCampaignMemory(ctx context.Context, id int64) (*models.CampaignMemory, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM campaign_memories
+		WHERE id = $1`, campaignMemoryColumns)
+
+	m, err := scanCampaignMemory(db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get campaign memory: %w", err)
+	}
+
+	return m, nil
+}
+
+// ListCampaignMemories retrieves the memories of a campaign that match
+// filter, most important first, then oldest first.
+func (db *DB) ListCampaignMemories(ctx context.Context, campaignID int64, filter models.CampaignMemoryFilter) ([]models.CampaignMemory, error) {
+	conditions := []string{"campaign_id = $1"}
+	args := []interface{}{campaignID}
+
+	if filter.MemoryType != "" {
+		args = append(args, filter.MemoryType)
+		conditions = append(conditions, fmt.Sprintf("memory_type = $%d", len(args)))
+	}
+	if filter.MinImportance > 0 {
+		args = append(args, filter.MinImportance)
+		conditions = append(conditions, fmt.Sprintf("importance >= $%d", len(args)))
+	}
+	if filter.IsSpoiler != nil {
+		args = append(args, *filter.IsSpoiler)
+		conditions = append(conditions, fmt.Sprintf("is_spoiler = $%d", len(args)))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM campaign_memories
+		WHERE %s
+		ORDER BY importance DESC, created_at ASC, id ASC`,
+		campaignMemoryColumns, strings.Join(conditions, " AND "))
+
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list campaign memories: %w", err)
+	}
+	defer rows.Close()
+
+	return scanCampaignMemories(rows)
+}
+
+// ListTopCampaignMemories retrieves up to limit memories of a campaign
+// in retrieval priority order: the premise first, then themes, then
+// everything else by importance. Within a type, more important and
+// more recently updated memories come first.
+func (db *DB) ListTopCampaignMemories(ctx context.Context, campaignID int64, limit int) ([]models.CampaignMemory, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM campaign_memories
+		WHERE campaign_id = $1
+		ORDER BY CASE memory_type
+		             WHEN 'premise' THEN 0
+		             WHEN 'theme' THEN 1
+		             ELSE 2
+		         END,
+		         importance DESC, updated_at DESC, id ASC
+		LIMIT $2`, campaignMemoryColumns)
+
+	rows, err := db.Query(ctx, query, campaignID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list top campaign memories: %w", err)
+	}
+	defer rows.Close()
+
+	return scanCampaignMemories(rows)
+}
+
+// UpdateCampaignMemory updates a campaign memory and returns the
+// updated record. Uses COALESCE to preserve existing values when
+// fields are nil. Returns pgx.ErrNoRows (unwrapped) when the memory
+// does not exist.
+func (db *DB) UpdateCampaignMemory(ctx context.Context, id int64, req models.UpdateCampaignMemoryRequest) (*models.CampaignMemory, error) {
+	query := fmt.Sprintf(`
+		UPDATE campaign_memories
+		SET memory_type       = COALESCE($2, memory_type),
+		    title             = COALESCE($3, title),
+		    content           = COALESCE($4, content),
+		    source_session_id = COALESCE($5, source_session_id),
+		    importance        = COALESCE($6, importance),
+		    is_spoiler        = COALESCE($7, is_spoiler)
+		WHERE id = $1
+		RETURNING %s`, campaignMemoryColumns)
+
+	m, err := scanCampaignMemory(db.QueryRow(ctx, query,
+		id, req.MemoryType, req.Title, req.Content,
+		req.SourceSessionID, req.Importance, req.IsSpoiler,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to update campaign memory: %w", err)
+	}
+
+	return m, nil
+}
+
+// DeleteCampaignMemory deletes a campaign memory by ID. Returns
+// pgx.ErrNoRows when the memory does not exist.
+func (db *DB) DeleteCampaignMemory(ctx context.Context, id int64) error {
+	result, err := db.Pool.Exec(ctx, "DELETE FROM campaign_memories WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete campaign memory: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
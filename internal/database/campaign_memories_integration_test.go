@@ -0,0 +1,94 @@
+//go:build integration
+
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestIntegration_CampaignMemories(t *testing.T) {
+	db := setupIntegrationDB(t)
+	campaignID, _ := createTestCampaign(t, db)
+	ctx := context.Background()
+
+	importance := func(n int) *int { return &n }
+	for _, req := range []models.CreateCampaignMemoryRequest{
+		{MemoryType: models.MemoryTypeGMNote, Content: "The butler did it.", Importance: importance(10), IsSpoiler: true},
+		{MemoryType: models.MemoryTypePlotThread, Content: "The heir is missing.", Importance: importance(7)},
+		{MemoryType: models.MemoryTypeTheme, Content: "Cosmic insignificance.", Importance: importance(3)},
+		{MemoryType: models.MemoryTypePremise, Content: "A cult stirs in London."},
+	} {
+		if _, err := db.CreateCampaignMemory(ctx, campaignID, req, true); err != nil {
+			t.Fatalf("failed to create memory: %v", err)
+		}
+	}
+
+	all, err := db.ListCampaignMemories(ctx, campaignID, models.CampaignMemoryFilter{})
+	if err != nil {
+		t.Fatalf("failed to list memories: %v", err)
+	}
+	if len(all) != 4 || all[0].MemoryType != models.MemoryTypeGMNote {
+		t.Fatalf("expected 4 memories ordered by importance, got %+v", all)
+	}
+	if all[2].Importance != 5 || !all[2].GMCreated {
+		t.Errorf("expected default importance 5 and gm_created, got %+v", all[2])
+	}
+
+	noSpoilers := false
+	filtered, err := db.ListCampaignMemories(ctx, campaignID, models.CampaignMemoryFilter{
+		MinImportance: 5,
+		IsSpoiler:     &noSpoilers,
+	})
+	if err != nil {
+		t.Fatalf("failed to list filtered memories: %v", err)
+	}
+	if len(filtered) != 2 || filtered[0].MemoryType != models.MemoryTypePlotThread ||
+		filtered[1].MemoryType != models.MemoryTypePremise {
+		t.Errorf("unexpected filtered memories: %+v", filtered)
+	}
+
+	top, err := db.ListTopCampaignMemories(ctx, campaignID, 3)
+	if err != nil {
+		t.Fatalf("failed to list top memories: %v", err)
+	}
+	if len(top) != 3 || top[0].MemoryType != models.MemoryTypePremise ||
+		top[1].MemoryType != models.MemoryTypeTheme || top[2].MemoryType != models.MemoryTypeGMNote {
+		t.Errorf("expected premise, theme, then most important, got %+v", top)
+	}
+
+	content := "The heir has been found."
+	updated, err := db.UpdateCampaignMemory(ctx, filtered[0].ID, models.UpdateCampaignMemoryRequest{
+		Content: &content,
+	})
+	if err != nil {
+		t.Fatalf("failed to update memory: %v", err)
+	}
+	if updated.Content != content || updated.Importance != 7 {
+		t.Errorf("unexpected updated memory: %+v", updated)
+	}
+
+	if err := db.DeleteCampaignMemory(ctx, updated.ID); err != nil {
+		t.Fatalf("failed to delete memory: %v", err)
+	}
+	if _, err := db.GetCampaignMemory(ctx, updated.ID); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("expected pgx.ErrNoRows after delete, got %v", err)
+	}
+	if err := db.DeleteCampaignMemory(ctx, updated.ID); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("expected pgx.ErrNoRows deleting twice, got %v", err)
+	}
+}
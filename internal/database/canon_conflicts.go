@@ -0,0 +1,238 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// canonConflictColumns is the standard column list for canon conflict
+// queries.
+const canonConflictColumns = `id, campaign_id, entity_id, field_name,
+	conflicting_values, conflict_type, severity, description,
+	analysis_item_id, status, resolution, superseded_entity_id,
+	superseded_scene_id, resolved_at, created_at, updated_at`
+
+// scanCanonConflict scans a single row into a models.CanonConflict.
+func scanCanonConflict(row pgx.Row) (*models.CanonConflict, error) {
+	var c models.CanonConflict
+	err := row.Scan(
+		&c.ID, &c.CampaignID, &c.EntityID, &c.FieldName,
+		&c.ConflictingValues, &c.ConflictType, &c.Severity, &c.Description,
+		&c.AnalysisItemID, &c.Status, &c.Resolution, &c.SupersededEntityID,
+		&c.SupersededSceneID, &c.ResolvedAt, &c.CreatedAt, &c.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// CreateCanonConflict records a canon conflict in a campaign. A
+// conflict accepted from an analysis item is recorded once; recording
+// it again returns the existing conflict.
+func (db *DB) CreateCanonConflict(
+	ctx context.Context,
+	campaignID int64,
+	req models.CreateCanonConflictRequest,
+) (*models.CanonConflict, error) {
+	values := req.ConflictingValues
+	if values == nil {
+		values = []models.ConflictingValue{}
+	}
+	valuesJSON, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal conflicting values: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO canon_conflicts
+			(campaign_id, entity_id, field_name, conflicting_values,
+			 conflict_type, severity, description, analysis_item_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (analysis_item_id) WHERE analysis_item_id IS NOT NULL
+			DO UPDATE SET analysis_item_id = EXCLUDED.analysis_item_id
+		RETURNING %s`, canonConflictColumns)
+
+	c, err := scanCanonConflict(db.QueryRow(ctx, query,
+		campaignID, req.EntityID, req.FieldName, valuesJSON,
+		req.ConflictType, req.Severity, req.Description, req.AnalysisItemID,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create canon conflict: %w", err)
+	}
+
+	return c, nil
+}
+
+// GetCanonConflict retrieves a canon conflict by ID. Returns
+// pgx.ErrNoRows (unwrapped) when it does not exist.
+func (db *DB) GetCanonConflict(ctx context.Context, id int64) (*models.CanonConflict, error) {
+	query := fmt.Sprintf(`SELECT %s FROM canon_conflicts WHERE id = $1`, canonConflictColumns)
+
+	c, err := scanCanonConflict(db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get canon conflict: %w", err)
+	}
+
+	return c, nil
+}
+
+// ListCanonConflicts retrieves the canon conflicts of a campaign, newest
+// first. An empty status lists conflicts in any status, and a nil
+// entityID conflicts about any entity.
+func (db *DB) ListCanonConflicts(
+	ctx context.Context,
+	campaignID int64,
+	status models.ConflictStatus,
+	entityID *int64,
+) ([]models.CanonConflict, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM canon_conflicts
+		WHERE campaign_id = $1
+		  AND ($2 = '' OR status = $2)
+		  AND ($3::bigint IS NULL OR entity_id = $3)
+		ORDER BY created_at DESC, id DESC`, canonConflictColumns)
+
+	rows, err := db.Query(ctx, query, campaignID, string(status), entityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query canon conflicts: %w", err)
+	}
+	defer rows.Close()
+
+	var conflicts []models.CanonConflict
+	for rows.Next() {
+		c, err := scanCanonConflict(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan canon conflict: %w", err)
+		}
+		conflicts = append(conflicts, *c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating canon conflicts: %w", err)
+	}
+
+	return conflicts, nil
+}
+
+// AcknowledgeCanonConflict moves a DETECTED canon conflict to
+// ACKNOWLEDGED. Returns pgx.ErrNoRows (unwrapped) if the conflict is
+// not DETECTED.
+func (db *DB) AcknowledgeCanonConflict(ctx context.Context, id int64) (*models.CanonConflict, error) {
+	query := fmt.Sprintf(`
+		UPDATE canon_conflicts
+		SET status = 'ACKNOWLEDGED'
+		WHERE id = $1 AND status = 'DETECTED'
+		RETURNING %s`, canonConflictColumns)
+
+	c, err := scanCanonConflict(db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to acknowledge canon conflict: %w", err)
+	}
+
+	return c, nil
+}
+
+// ResolveCanonConflict resolves an unresolved canon conflict and, in
+// the same transaction, marks the entity or scene holding the losing
+// value SUPERSEDED, recording the entity's new revision. The
+// superseded entity and scene must belong to the conflict's campaign.
+// Returns pgx.ErrNoRows (unwrapped) if the conflict is already
+// resolved.
+func (db *DB) ResolveCanonConflict(
+	ctx context.Context,
+	id int64,
+	req models.ResolveCanonConflictRequest,
+) (*models.CanonConflict, error) {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback is a no-op if already committed
+
+	query := fmt.Sprintf(`
+		UPDATE canon_conflicts
+		SET status = 'RESOLVED', resolution = $2, resolved_at = NOW(),
+		    superseded_entity_id = $3, superseded_scene_id = $4
+		WHERE id = $1 AND status <> 'RESOLVED'
+		RETURNING %s`, canonConflictColumns)
+
+	c, err := scanCanonConflict(tx.QueryRow(ctx, query,
+		id, req.Resolution, req.SupersededEntityID, req.SupersededSceneID,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to resolve canon conflict: %w", err)
+	}
+
+	if req.SupersededEntityID != nil {
+		var e models.Entity
+		err := tx.QueryRow(ctx, `
+			UPDATE entities
+			SET source_confidence = 'SUPERSEDED', version = version + 1
+			WHERE id = $1 AND campaign_id = $2
+			RETURNING id, campaign_id, entity_type, name, description, attributes,
+			          tags, gm_notes, discovered_session, source_document,
+			          source_confidence, version, created_at, updated_at`,
+			*req.SupersededEntityID, c.CampaignID,
+		).Scan(
+			&e.ID, &e.CampaignID, &e.EntityType, &e.Name, &e.Description,
+			&e.Attributes, &e.Tags, &e.GMNotes, &e.DiscoveredSession,
+			&e.SourceDocument, &e.SourceConfidence, &e.Version,
+			&e.CreatedAt, &e.UpdatedAt,
+		)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("superseded entity %d not found in campaign", *req.SupersededEntityID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to supersede entity: %w", err)
+		}
+		if err := insertEntityRevision(ctx, tx, &e, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.SupersededSceneID != nil {
+		result, err := tx.Exec(ctx, `
+			UPDATE scenes
+			SET source_confidence = 'SUPERSEDED', updated_at = NOW()
+			WHERE id = $1 AND campaign_id = $2`,
+			*req.SupersededSceneID, c.CampaignID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to supersede scene: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return nil, fmt.Errorf("superseded scene %d not found in campaign", *req.SupersededSceneID)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return c, nil
+}
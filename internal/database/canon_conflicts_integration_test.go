@@ -0,0 +1,100 @@
+//go:build integration
+
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestIntegration_CanonConflicts(t *testing.T) {
+	db := setupIntegrationDB(t)
+	campaignID, entityID := createTestCampaign(t, db)
+	ctx := context.Background()
+
+	field := "description"
+	temporal := "temporal"
+	conflict, err := db.CreateCanonConflict(ctx, campaignID, models.CreateCanonConflictRequest{
+		EntityID:  &entityID,
+		FieldName: &field,
+		ConflictingValues: []models.ConflictingValue{
+			{Value: "The siege ended in 1120.", Source: "Chapter 2"},
+			{Value: "The siege is still under way.", Source: "Entity description"},
+		},
+		ConflictType: &temporal,
+	})
+	if err != nil {
+		t.Fatalf("failed to create canon conflict: %v", err)
+	}
+	if conflict.Status != models.ConflictStatusDetected {
+		t.Errorf("expected status DETECTED, got %q", conflict.Status)
+	}
+
+	var values []models.ConflictingValue
+	if err := json.Unmarshal(conflict.ConflictingValues, &values); err != nil || len(values) != 2 {
+		t.Errorf("expected 2 conflicting values, got %s (%v)", conflict.ConflictingValues, err)
+	}
+
+	listed, err := db.ListCanonConflicts(ctx, campaignID, models.ConflictStatusDetected, &entityID)
+	if err != nil {
+		t.Fatalf("failed to list canon conflicts: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ID != conflict.ID {
+		t.Errorf("expected the conflict to be listed, got %+v", listed)
+	}
+
+	if _, err := db.AcknowledgeCanonConflict(ctx, conflict.ID); err != nil {
+		t.Fatalf("failed to acknowledge canon conflict: %v", err)
+	}
+	if _, err := db.AcknowledgeCanonConflict(ctx, conflict.ID); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("expected ErrNoRows acknowledging twice, got %v", err)
+	}
+
+	before, err := db.GetEntity(ctx, entityID)
+	if err != nil {
+		t.Fatalf("failed to get entity: %v", err)
+	}
+
+	resolved, err := db.ResolveCanonConflict(ctx, conflict.ID, models.ResolveCanonConflictRequest{
+		Resolution:         "The chapter is canon.",
+		SupersededEntityID: &entityID,
+	})
+	if err != nil {
+		t.Fatalf("failed to resolve canon conflict: %v", err)
+	}
+	if resolved.Status != models.ConflictStatusResolved || resolved.ResolvedAt == nil {
+		t.Errorf("expected a resolved conflict, got %+v", resolved)
+	}
+
+	after, err := db.GetEntity(ctx, entityID)
+	if err != nil {
+		t.Fatalf("failed to get entity: %v", err)
+	}
+	if after.SourceConfidence != models.SourceConfidenceSuperseded {
+		t.Errorf("expected entity to be SUPERSEDED, got %q", after.SourceConfidence)
+	}
+	if after.Version != before.Version+1 {
+		t.Errorf("expected entity version %d, got %d", before.Version+1, after.Version)
+	}
+
+	if _, err := db.ResolveCanonConflict(ctx, conflict.ID, models.ResolveCanonConflictRequest{
+		Resolution: "again",
+	}); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("expected ErrNoRows resolving twice, got %v", err)
+	}
+}
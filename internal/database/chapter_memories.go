@@ -0,0 +1,201 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// chapterMemoryColumns is the standard column list for chapter memory
+// queries.
+const chapterMemoryColumns = `cm.id, cm.campaign_id, cm.chapter_id, cm.goals,
+	cm.active_threads, cm.summary, cm.is_current, cm.created_at, cm.updated_at`
+
+// scanChapterMemory scans a single row into a models.ChapterMemory.
+func scanChapterMemory(row pgx.Row) (*models.ChapterMemory, error) {
+	var m models.ChapterMemory
+	err := row.Scan(
+		&m.ID, &m.CampaignID, &m.ChapterID, &m.Goals,
+		&m.ActiveThreads, &m.Summary, &m.IsCurrent, &m.CreatedAt, &m.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if m.Goals == nil {
+		m.Goals = []string{}
+	}
+	if m.ActiveThreads == nil {
+		m.ActiveThreads = []string{}
+	}
+	return &m, nil
+}
+
+// GetChapterMemory retrieves the memory of a chapter.
+// Returns pgx.ErrNoRows (unwrapped) when the chapter has no memory.
+func (db *DB) GetChapterMemory(ctx context.Context, chapterID int64) (*models.ChapterMemory, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM chapter_memories cm
+		WHERE cm.chapter_id = $1`, chapterMemoryColumns)
+
+	m, err := scanChapterMemory(db.QueryRow(ctx, query, chapterID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get chapter memory: %w", err)
+	}
+
+	return m, nil
+}
+
+// GetCurrentChapterMemory retrieves the memory of a campaign's current
+// chapter. Returns pgx.ErrNoRows (unwrapped) when no chapter is
+// current.
+func (db *DB) GetCurrentChapterMemory(ctx context.Context, campaignID int64) (*models.ChapterMemory, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM chapter_memories cm
+		WHERE cm.campaign_id = $1 AND cm.is_current`, chapterMemoryColumns)
+
+	m, err := scanChapterMemory(db.QueryRow(ctx, query, campaignID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get current chapter memory: %w", err)
+	}
+
+	return m, nil
+}
+
+// ListChapterMemories retrieves the chapter memories of a campaign in
+// chapter order.
+func (db *DB) ListChapterMemories(ctx context.Context, campaignID int64) ([]models.ChapterMemory, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM chapter_memories cm
+		JOIN chapters c ON c.id = cm.chapter_id
+		WHERE cm.campaign_id = $1
+		ORDER BY c.sort_order ASC, c.created_at ASC`, chapterMemoryColumns)
+
+	rows, err := db.Query(ctx, query, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chapter memories: %w", err)
+	}
+	defer rows.Close()
+
+	var memories []models.ChapterMemory
+	for rows.Next() {
+		m, err := scanChapterMemory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan chapter memory: %w", err)
+		}
+		memories = append(memories, *m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating chapter memories: %w", err)
+	}
+
+	return memories, nil
+}
+
+// SaveChapterMemory creates the memory of a chapter, or updates it if
+// it already exists. Nil fields of req keep their current value.
+func (db *DB) SaveChapterMemory(ctx context.Context, campaignID, chapterID int64, req models.SaveChapterMemoryRequest) (*models.ChapterMemory, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO chapter_memories AS cm
+			(campaign_id, chapter_id, goals, active_threads, summary)
+		VALUES ($1, $2, COALESCE($3, '{}'::TEXT[]), COALESCE($4, '{}'::TEXT[]), $5)
+		ON CONFLICT (chapter_id) DO UPDATE
+		SET goals          = COALESCE($3, cm.goals),
+		    active_threads = COALESCE($4, cm.active_threads),
+		    summary        = COALESCE($5, cm.summary)
+		RETURNING %s`, chapterMemoryColumns)
+
+	m, err := scanChapterMemory(db.QueryRow(ctx, query,
+		campaignID, chapterID, req.Goals, req.ActiveThreads, req.Summary,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save chapter memory: %w", err)
+	}
+
+	return m, nil
+}
+
+// SetCurrentChapter marks a chapter as its campaign's current chapter,
+// creating an empty memory for it if needed, and clears the flag on
+// every other chapter of the campaign.
+func (db *DB) SetCurrentChapter(ctx context.Context, campaignID, chapterID int64) (*models.ChapterMemory, error) {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback is a no-op if already committed
+
+	// Lock the campaign row to serialize concurrent switches.
+	_, err = tx.Exec(ctx,
+		"SELECT id FROM campaigns WHERE id = $1 FOR NO KEY UPDATE", campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock campaign: %w", err)
+	}
+
+	// Clear the previous current chapter first so the unique index on
+	// current chapters is never violated.
+	_, err = tx.Exec(ctx, `
+		UPDATE chapter_memories
+		SET is_current = false
+		WHERE campaign_id = $1 AND is_current AND chapter_id <> $2`,
+		campaignID, chapterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clear current chapter: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO chapter_memories AS cm
+			(campaign_id, chapter_id, goals, active_threads, is_current)
+		VALUES ($1, $2, '{}', '{}', true)
+		ON CONFLICT (chapter_id) DO UPDATE
+		SET is_current = true
+		RETURNING %s`, chapterMemoryColumns)
+
+	m, err := scanChapterMemory(tx.QueryRow(ctx, query, campaignID, chapterID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set current chapter: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return m, nil
+}
+
+// DeleteChapterMemory deletes the memory of a chapter. Returns
+// pgx.ErrNoRows when the chapter has no memory.
+func (db *DB) DeleteChapterMemory(ctx context.Context, chapterID int64) error {
+	result, err := db.Pool.Exec(ctx, "DELETE FROM chapter_memories WHERE chapter_id = $1", chapterID)
+	if err != nil {
+		return fmt.Errorf("failed to delete chapter memory: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+//go:build integration
+
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestIntegration_ChapterMemories(t *testing.T) {
+	db := setupIntegrationDB(t)
+	campaignID, _ := createTestCampaign(t, db)
+	ctx := context.Background()
+
+	var chapterIDs []int64
+	for _, title := range []string{"Arrival", "Descent"} {
+		chapter, err := db.CreateChapter(ctx, campaignID, models.CreateChapterRequest{Title: title})
+		if err != nil {
+			t.Fatalf("failed to create chapter: %v", err)
+		}
+		chapterIDs = append(chapterIDs, chapter.ID)
+	}
+
+	summary := "The investigators arrived."
+	saved, err := db.SaveChapterMemory(ctx, campaignID, chapterIDs[0], models.SaveChapterMemoryRequest{
+		Goals:   []string{"Find the heir"},
+		Summary: &summary,
+	})
+	if err != nil {
+		t.Fatalf("failed to create chapter memory: %v", err)
+	}
+	if len(saved.Goals) != 1 || len(saved.ActiveThreads) != 0 || saved.IsCurrent {
+		t.Errorf("unexpected new chapter memory: %+v", saved)
+	}
+
+	// Saving again only changes the fields given.
+	saved, err = db.SaveChapterMemory(ctx, campaignID, chapterIDs[0], models.SaveChapterMemoryRequest{
+		ActiveThreads: []string{"Who funds the cult?"},
+	})
+	if err != nil {
+		t.Fatalf("failed to update chapter memory: %v", err)
+	}
+	if saved.Summary == nil || *saved.Summary != summary || len(saved.Goals) != 1 ||
+		len(saved.ActiveThreads) != 1 {
+		t.Errorf("unexpected updated chapter memory: %+v", saved)
+	}
+
+	if _, err := db.SetCurrentChapter(ctx, campaignID, chapterIDs[0]); err != nil {
+		t.Fatalf("failed to set current chapter: %v", err)
+	}
+	current, err := db.SetCurrentChapter(ctx, campaignID, chapterIDs[1])
+	if err != nil {
+		t.Fatalf("failed to switch current chapter: %v", err)
+	}
+	if current.ChapterID != chapterIDs[1] || !current.IsCurrent {
+		t.Errorf("expected chapter %d to be current, got %+v", chapterIDs[1], current)
+	}
+
+	memories, err := db.ListChapterMemories(ctx, campaignID)
+	if err != nil {
+		t.Fatalf("failed to list chapter memories: %v", err)
+	}
+	if len(memories) != 2 || memories[0].IsCurrent || !memories[1].IsCurrent {
+		t.Errorf("expected only the second chapter to be current, got %+v", memories)
+	}
+
+	got, err := db.GetCurrentChapterMemory(ctx, campaignID)
+	if err != nil || got.ChapterID != chapterIDs[1] {
+		t.Errorf("unexpected current chapter memory: %+v, %v", got, err)
+	}
+
+	if err := db.DeleteChapterMemory(ctx, chapterIDs[1]); err != nil {
+		t.Fatalf("failed to delete chapter memory: %v", err)
+	}
+	if _, err := db.GetCurrentChapterMemory(ctx, campaignID); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("expected no current chapter after delete, got %v", err)
+	}
+}
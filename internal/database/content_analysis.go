@@ -12,6 +12,7 @@ package database
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -196,6 +197,12 @@ func (db *DB) CreateAnalysisItems(ctx context.Context, items []models.ContentAna
 		return nil
 	}
 
+	query, args := analysisItemsInsert(items)
+	return db.Exec(ctx, query, args...)
+}
+
+// analysisItemsInsert builds a multi-row INSERT statement for items.
+func analysisItemsInsert(items []models.ContentAnalysisItem) (string, []interface{}) {
 	const cols = 14 // number of columns per row
 	valueStrings := make([]string, 0, len(items))
 	args := make([]interface{}, 0, len(items)*cols)
@@ -225,7 +232,147 @@ func (db *DB) CreateAnalysisItems(ctx context.Context, items []models.ContentAna
 			 agent_name, pipeline_run_id)
 		VALUES %s`, strings.Join(valueStrings, ", "))
 
-	return db.Exec(ctx, query, args...)
+	return query, args
+}
+
+// AddEnrichmentItems saves enrichment items produced for a job, adds
+// them to the job's enrichment_total, and publishes an item_created
+// event for each, all in one transaction. It returns the saved items
+// with their IDs, creation times, and entity names populated, as they
+// were sent in the events.
+func (db *DB) AddEnrichmentItems(
+	ctx context.Context,
+	jobID int64,
+	items []models.ContentAnalysisItem,
+) ([]models.ContentAnalysisItem, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback is a no-op if already committed
+
+	saved := make([]models.ContentAnalysisItem, len(items))
+	copy(saved, items)
+
+	query, args := analysisItemsInsert(saved)
+	rows, err := tx.Query(ctx, query+" RETURNING id, created_at", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert enrichment items: %w", err)
+	}
+	// Rows of a multi-row VALUES insert are returned in VALUES order.
+	for i := 0; rows.Next(); i++ {
+		if err := rows.Scan(&saved[i].ID, &saved[i].CreatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan enrichment item: %w", err)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating enrichment items: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx,
+		"UPDATE content_analysis_jobs SET enrichment_total = enrichment_total + $1 WHERE id = $2",
+		len(saved), jobID,
+	); err != nil {
+		return nil, fmt.Errorf("failed to update enrichment total: %w", err)
+	}
+
+	entityIDs := make([]int64, 0, len(saved))
+	for _, item := range saved {
+		if item.EntityID != nil {
+			entityIDs = append(entityIDs, *item.EntityID)
+		}
+	}
+	if len(entityIDs) > 0 {
+		type entityLabel struct {
+			name       string
+			entityType models.EntityType
+		}
+		labels := make(map[int64]entityLabel, len(entityIDs))
+		rows, err := tx.Query(ctx,
+			"SELECT id, name, entity_type FROM entities WHERE id = ANY($1)", entityIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up enrichment item entities: %w", err)
+		}
+		for rows.Next() {
+			var id int64
+			var l entityLabel
+			if err := rows.Scan(&id, &l.name, &l.entityType); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan enrichment item entity: %w", err)
+			}
+			labels[id] = l
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("error iterating enrichment item entities: %w", err)
+		}
+		for i := range saved {
+			if saved[i].EntityID == nil {
+				continue
+			}
+			if l, ok := labels[*saved[i].EntityID]; ok {
+				saved[i].EntityName = &l.name
+				saved[i].EntityType = &l.entityType
+			}
+		}
+	}
+
+	for _, item := range saved {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal enrichment item: %w", err)
+		}
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO analysis_job_events (job_id, event_type, payload)
+			VALUES ($1, $2, $3)`,
+			jobID, models.AnalysisEventItemCreated, data,
+		); err != nil {
+			return nil, fmt.Errorf("failed to publish item event: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return saved, nil
+}
+
+// DiscardEnrichmentRun deletes the pending enrichment items of a job
+// that were produced by the given pipeline run, subtracts them from
+// the job's enrichment_total, and publishes an items_removed event.
+// It is used to discard the partial output of an interrupted attempt
+// before the run is retried.
+func (db *DB) DiscardEnrichmentRun(ctx context.Context, jobID int64, runID int64) error {
+	query := `
+		WITH removed AS (
+			DELETE FROM content_analysis_items
+			WHERE job_id = $1
+			  AND phase = 'enrichment'
+			  AND pipeline_run_id = $2
+			  AND resolution = 'pending'
+			RETURNING id
+		), recounted AS (
+			UPDATE content_analysis_jobs
+			SET enrichment_total = GREATEST(
+				enrichment_total - (SELECT COUNT(*) FROM removed), 0)
+			WHERE id = $1
+		)
+		INSERT INTO analysis_job_events (job_id, event_type, payload)
+		SELECT $1, $3, jsonb_build_object('itemIds', jsonb_agg(id ORDER BY id))
+		FROM removed
+		HAVING COUNT(*) > 0`
+
+	if err := db.Exec(ctx, query, jobID, runID, models.AnalysisEventItemsRemoved); err != nil {
+		return fmt.Errorf("failed to discard enrichment run: %w", err)
+	}
+	return nil
 }
 
 // ListAnalysisItemsByJob retrieves analysis items for a job, joining on
@@ -402,14 +549,38 @@ func (db *DB) DeleteAnalysisJobsForSource(ctx context.Context, campaignID int64,
 }
 
 // SetJobFailureReason updates the failure_reason and sets the status
-// to 'failed' for a content analysis job.
+// to 'failed' for a content analysis job, publishing a job_complete
+// event.
 func (db *DB) SetJobFailureReason(ctx context.Context, jobID int64, reason string) error {
 	query := `
-		UPDATE content_analysis_jobs
-		SET status = 'failed',
-		    failure_reason = $2
-		WHERE id = $1`
-	return db.Exec(ctx, query, jobID, reason)
+		WITH job AS (
+			UPDATE content_analysis_jobs
+			SET status = 'failed',
+			    failure_reason = $2
+			WHERE id = $1
+			RETURNING id
+		)
+		INSERT INTO analysis_job_events (job_id, event_type, payload)
+		SELECT id, $3, jsonb_build_object('status', 'failed', 'failureReason', $2::TEXT)
+		FROM job`
+	return db.Exec(ctx, query, jobID, reason, models.AnalysisEventJobComplete)
+}
+
+// CompleteAnalysisJob sets the status of a content analysis job to
+// 'completed' and publishes a job_complete event. cancelled records
+// that enrichment was stopped by the user rather than finishing.
+func (db *DB) CompleteAnalysisJob(ctx context.Context, jobID int64, cancelled bool) error {
+	query := `
+		WITH job AS (
+			UPDATE content_analysis_jobs
+			SET status = 'completed'
+			WHERE id = $1
+			RETURNING id
+		)
+		INSERT INTO analysis_job_events (job_id, event_type, payload)
+		SELECT id, $2, jsonb_build_object('status', 'completed', 'cancelled', $3::BOOLEAN)
+		FROM job`
+	return db.Exec(ctx, query, jobID, models.AnalysisEventJobComplete, cancelled)
 }
 
 // SetJobCurrentPhase updates the current_phase of a content analysis job.
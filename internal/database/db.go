@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/antonypegg/imagineer/internal/crypto"
+	"github.com/antonypegg/imagineer/internal/gamesystem"
 	"github.com/antonypegg/imagineer/internal/ontology"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -48,9 +49,10 @@ type UserConfig struct {
 
 // DB wraps a pgxpool.Pool with helper methods.
 type DB struct {
-	Pool      *pgxpool.Pool
-	Encryptor *crypto.Encryptor  // nil = no encryption
-	Ontology  *ontology.Ontology // nil = legacy template mode
+	Pool        *pgxpool.Pool
+	Encryptor   *crypto.Encryptor    // nil = no encryption
+	Ontology    *ontology.Ontology   // nil = legacy template mode
+	GameSystems *gamesystem.Registry // nil = attributes not validated
 }
 
 // LoadConfig reads the database configuration from a JSON file.
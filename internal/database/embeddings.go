@@ -0,0 +1,299 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+// EmbeddingSource is a single text column of a campaign row that can be
+// chunked and embedded into the content_embeddings table.
+type EmbeddingSource struct {
+	CampaignID   int64
+	SourceTable  string
+	SourceID     int64
+	SourceColumn string
+	SourceName   string
+	Content      string
+}
+
+// IndexedSource describes a source that already has embeddings stored,
+// identified by table, row, and column.
+type IndexedSource struct {
+	SourceTable    string
+	SourceID       int64
+	SourceColumn   string
+	ContentHash    string
+	EmbeddingModel string
+}
+
+// ContentChunk is one chunk of a source and its embedding vector.
+type ContentChunk struct {
+	Content   string
+	Embedding []float32
+}
+
+// IsEmbeddingIndexAvailable checks whether the content_embeddings table
+// created by migration 008 exists. It is only present when the pgvector
+// extension is installed.
+func (db *DB) IsEmbeddingIndexAvailable(ctx context.Context) bool {
+	var exists bool
+	err := db.QueryRow(ctx,
+		`SELECT to_regclass('public.content_embeddings') IS NOT NULL`,
+	).Scan(&exists)
+	return err == nil && exists
+}
+
+// ListEmbeddingSources returns every non-empty text column of a
+// campaign that is indexed for semantic search. The set of columns
+// mirrors the pgedge_vectorizer configuration in migration 001.
+func (db *DB) ListEmbeddingSources(ctx context.Context, campaignID int64) ([]EmbeddingSource, error) {
+	query := `
+        SELECT 'campaigns', id, 'description', name, description
+          FROM campaigns
+         WHERE id = $1 AND COALESCE(description, '') <> ''
+        UNION ALL
+        SELECT 'entities', id, 'name', name, name
+          FROM entities
+         WHERE campaign_id = $1
+        UNION ALL
+        SELECT 'entities', id, 'description', name, description
+          FROM entities
+         WHERE campaign_id = $1 AND COALESCE(description, '') <> ''
+        UNION ALL
+        SELECT 'chapters', id, 'overview', title, overview
+          FROM chapters
+         WHERE campaign_id = $1 AND COALESCE(overview, '') <> ''
+        UNION ALL
+        SELECT 'sessions', id, 'prep_notes',
+               COALESCE(title, 'Session #' || session_number::TEXT, ''), prep_notes
+          FROM sessions
+         WHERE campaign_id = $1 AND COALESCE(prep_notes, '') <> ''
+        UNION ALL
+        SELECT 'sessions', id, 'actual_notes',
+               COALESCE(title, 'Session #' || session_number::TEXT, ''), actual_notes
+          FROM sessions
+         WHERE campaign_id = $1 AND COALESCE(actual_notes, '') <> ''
+        UNION ALL
+        SELECT 'campaign_memories', id, 'content', COALESCE(title, memory_type), content
+          FROM campaign_memories
+         WHERE campaign_id = $1 AND content <> ''
+        UNION ALL
+        SELECT 'scenes', id, 'description', title, description
+          FROM scenes
+         WHERE campaign_id = $1 AND COALESCE(description, '') <> ''
+        UNION ALL
+        SELECT 'scenes', id, 'gm_notes', title, gm_notes
+          FROM scenes
+         WHERE campaign_id = $1 AND COALESCE(gm_notes, '') <> ''
+        UNION ALL
+        SELECT 'session_chat_messages', id, 'content', role || ': ' || LEFT(content, 50), content
+          FROM session_chat_messages
+         WHERE campaign_id = $1 AND content <> ''`
+
+	rows, err := db.Query(ctx, query, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list embedding sources: %w", err)
+	}
+	defer rows.Close()
+
+	var sources []EmbeddingSource
+	for rows.Next() {
+		s := EmbeddingSource{CampaignID: campaignID}
+		if err := rows.Scan(
+			&s.SourceTable, &s.SourceID, &s.SourceColumn,
+			&s.SourceName, &s.Content,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan embedding source: %w", err)
+		}
+		sources = append(sources, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating embedding sources: %w", err)
+	}
+
+	return sources, nil
+}
+
+// ListIndexedSources returns the sources of a campaign that currently
+// have embeddings stored, with the hash and model they were indexed
+// with.
+func (db *DB) ListIndexedSources(ctx context.Context, campaignID int64) ([]IndexedSource, error) {
+	query := `
+        SELECT DISTINCT source_table, source_id, source_column,
+               content_hash, embedding_model
+          FROM content_embeddings
+         WHERE campaign_id = $1`
+
+	rows, err := db.Query(ctx, query, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexed sources: %w", err)
+	}
+	defer rows.Close()
+
+	var sources []IndexedSource
+	for rows.Next() {
+		var s IndexedSource
+		if err := rows.Scan(
+			&s.SourceTable, &s.SourceID, &s.SourceColumn,
+			&s.ContentHash, &s.EmbeddingModel,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan indexed source: %w", err)
+		}
+		sources = append(sources, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating indexed sources: %w", err)
+	}
+
+	return sources, nil
+}
+
+// ReplaceContentEmbeddings replaces all stored chunks for a source with
+// the given chunks in a single transaction.
+func (db *DB) ReplaceContentEmbeddings(
+	ctx context.Context,
+	src EmbeddingSource,
+	contentHash string,
+	model string,
+	chunks []ContentChunk,
+) error {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback is a no-op if already committed
+
+	_, err = tx.Exec(ctx,
+		`DELETE FROM content_embeddings
+          WHERE source_table = $1 AND source_id = $2 AND source_column = $3`,
+		src.SourceTable, src.SourceID, src.SourceColumn,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete content embeddings: %w", err)
+	}
+
+	query := `
+        INSERT INTO content_embeddings (
+            campaign_id, source_table, source_id, source_column, source_name,
+            chunk_index, content, content_hash, embedding_model, embedding
+        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10::vector)`
+
+	for i, chunk := range chunks {
+		_, err = tx.Exec(ctx, query,
+			src.CampaignID, src.SourceTable, src.SourceID, src.SourceColumn,
+			src.SourceName, i, chunk.Content, contentHash, model,
+			formatVector(chunk.Embedding),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert content embedding: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteContentEmbeddings removes all stored chunks for a source.
+func (db *DB) DeleteContentEmbeddings(
+	ctx context.Context,
+	sourceTable string,
+	sourceID int64,
+	sourceColumn string,
+) error {
+	err := db.Exec(ctx,
+		`DELETE FROM content_embeddings
+          WHERE source_table = $1 AND source_id = $2 AND source_column = $3`,
+		sourceTable, sourceID, sourceColumn,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete content embeddings: %w", err)
+	}
+	return nil
+}
+
+// SearchContentEmbeddings performs hybrid vector+text search over the
+// content_embeddings table, scoring results the same way as the
+// search_campaign_content SQL function (70% cosine similarity, 30%
+// text rank). Only vectors produced by the given model are compared.
+func (db *DB) SearchContentEmbeddings(
+	ctx context.Context,
+	campaignID int64,
+	query string,
+	queryEmbedding []float32,
+	model string,
+	limit int,
+) ([]models.SearchResult, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	rows, err := db.Query(ctx,
+		`SELECT source_table, source_id, COALESCE(source_name, ''), content,
+                (1 - (embedding <=> $1::vector))::FLOAT AS vector_score,
+                (0.7 * (1 - (embedding <=> $1::vector)) +
+                 0.3 * ts_rank(to_tsvector('english', content),
+                    plainto_tsquery('english', $2)))::FLOAT AS combined_score
+           FROM content_embeddings
+          WHERE campaign_id = $3 AND embedding_model = $4
+          ORDER BY combined_score DESC
+          LIMIT $5`,
+		formatVector(queryEmbedding), query, campaignID, model, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search content embeddings: %w", err)
+	}
+	defer rows.Close()
+
+	var results []models.SearchResult
+	for rows.Next() {
+		var r models.SearchResult
+		if err := rows.Scan(
+			&r.SourceTable, &r.SourceID, &r.SourceName,
+			&r.ChunkContent, &r.VectorScore, &r.CombinedScore,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		results = append(results, r)
+	}
+
+	return results, rows.Err()
+}
+
+// formatVector renders a vector in pgvector's text input format, e.g.
+// "[0.1,0.2,0.3]", so it can be passed as a parameter and cast with
+// ::vector without a driver-level type registration.
+func formatVector(v []float32) string {
+	var b strings.Builder
+	b.Grow(len(v) * 10)
+	b.WriteByte('[')
+	for i, f := range v {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(float64(f), 'f', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
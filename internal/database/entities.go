@@ -13,6 +13,7 @@ package database
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/antonypegg/imagineer/internal/models"
@@ -80,7 +81,8 @@ func (db *DB) GetEntity(ctx context.Context, id int64) (*models.Entity, error) {
 	return &e, nil
 }
 
-// CreateEntity creates a new entity.
+// CreateEntity creates a new entity and records it as its first
+// revision.
 func (db *DB) CreateEntity(ctx context.Context, campaignID int64, req models.CreateEntityRequest) (*models.Entity, error) {
 	attributes := req.Attributes
 	if attributes == nil {
@@ -106,8 +108,16 @@ func (db *DB) CreateEntity(ctx context.Context, campaignID int64, req models.Cre
                   tags, gm_notes, discovered_session, source_document,
                   source_confidence, version, created_at, updated_at`
 
+	// Use a transaction so the entity and its first revision are
+	// created together.
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback is a no-op if already committed
+
 	var e models.Entity
-	err := db.QueryRow(ctx, query,
+	err = tx.QueryRow(ctx, query,
 		campaignID, req.EntityType, req.Name, req.Description,
 		attributes, tags, req.GMNotes, req.DiscoveredSession,
 		req.SourceDocument, sourceConfidence,
@@ -121,12 +131,21 @@ func (db *DB) CreateEntity(ctx context.Context, campaignID int64, req models.Cre
 		return nil, fmt.Errorf("failed to create entity: %w", err)
 	}
 
+	if err := insertEntityRevision(ctx, tx, &e, nil); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return &e, nil
 }
 
-// UpdateEntity updates an existing entity. If the entity name changes,
-// all wiki links referencing the old name are updated across campaign
-// content within the same transaction.
+// UpdateEntity updates an existing entity and records the new version
+// as a revision. If the entity name changes, all wiki links referencing
+// the old name are updated across campaign content within the same
+// transaction.
 func (db *DB) UpdateEntity(ctx context.Context, id int64, req models.UpdateEntityRequest) (*models.Entity, error) {
 	// First get the existing entity
 	existing, err := db.GetEntity(ctx, id)
@@ -180,10 +199,35 @@ func (db *DB) UpdateEntity(ctx context.Context, id int64, req models.UpdateEntit
 		sourceConfidence = *req.SourceConfidence
 	}
 
-	nameChanged := req.Name != nil && *req.Name != existing.Name
+	next := *existing
+	next.EntityType = entityType
+	next.Name = name
+	next.Description = description
+	next.Attributes = attributes
+	next.Tags = tags
+	next.GMNotes = gmNotes
+	next.DiscoveredSession = discoveredSession
+	next.SourceDocument = sourceDocument
+	next.SourceConfidence = sourceConfidence
+
+	return db.saveEntity(ctx, existing, &next, nil)
+}
 
-	// Use a transaction so the entity update and wiki link propagation
-	// are atomic.
+// saveEntity writes next over existing as the entity's next version
+// and records the revision, with the author in ctx. restoredFrom is
+// the older version being restored, if any. If the entity name
+// changes, all wiki links referencing the old name are updated across
+// campaign content within the same transaction.
+func (db *DB) saveEntity(
+	ctx context.Context,
+	existing *models.Entity,
+	next *models.Entity,
+	restoredFrom *int,
+) (*models.Entity, error) {
+	nameChanged := next.Name != existing.Name
+
+	// Use a transaction so the entity update, its revision and wiki
+	// link propagation are atomic.
 	tx, err := db.Pool.Begin(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
@@ -202,9 +246,9 @@ func (db *DB) UpdateEntity(ctx context.Context, id int64, req models.UpdateEntit
 
 	var e models.Entity
 	err = tx.QueryRow(ctx, query,
-		id, entityType, name, description, attributes,
-		tags, gmNotes, discoveredSession,
-		sourceDocument, sourceConfidence,
+		existing.ID, next.EntityType, next.Name, next.Description, next.Attributes,
+		next.Tags, next.GMNotes, next.DiscoveredSession,
+		next.SourceDocument, next.SourceConfidence,
 	).Scan(
 		&e.ID, &e.CampaignID, &e.EntityType, &e.Name, &e.Description,
 		&e.Attributes, &e.Tags, &e.GMNotes, &e.DiscoveredSession,
@@ -215,9 +259,13 @@ func (db *DB) UpdateEntity(ctx context.Context, id int64, req models.UpdateEntit
 		return nil, fmt.Errorf("failed to update entity: %w", err)
 	}
 
+	if err := insertEntityRevision(ctx, tx, &e, restoredFrom); err != nil {
+		return nil, err
+	}
+
 	// Propagate the name change to all wiki links in campaign content
 	if nameChanged {
-		_, err = PropagateEntityRename(ctx, tx, existing.CampaignID, existing.Name, *req.Name)
+		_, err = PropagateEntityRename(ctx, tx, existing.CampaignID, existing.Name, next.Name)
 		if err != nil {
 			return nil, fmt.Errorf("failed to propagate entity rename: %w", err)
 		}
@@ -230,6 +278,57 @@ func (db *DB) UpdateEntity(ctx context.Context, id int64, req models.UpdateEntit
 	return &e, nil
 }
 
+// UpdateEntityContentField sets an entity's description or gm_notes to
+// content as the entity's next version and records the revision, with
+// the author in ctx. Returns pgx.ErrNoRows (unwrapped) if the entity
+// does not exist.
+func (db *DB) UpdateEntityContentField(ctx context.Context, id int64, field, content string) (*models.Entity, error) {
+	// Each field uses a hardcoded query to prevent SQL injection.
+	var query string
+	switch field {
+	case "description":
+		query = `UPDATE entities SET description = $2, version = version + 1 WHERE id = $1`
+	case "gm_notes":
+		query = `UPDATE entities SET gm_notes = $2, version = version + 1 WHERE id = $1`
+	default:
+		return nil, fmt.Errorf("unsupported entity content field %q", field)
+	}
+	query += `
+        RETURNING id, campaign_id, entity_type, name, description, attributes,
+                  tags, gm_notes, discovered_session, source_document,
+                  source_confidence, version, created_at, updated_at`
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback is a no-op if already committed
+
+	var e models.Entity
+	err = tx.QueryRow(ctx, query, id, content).Scan(
+		&e.ID, &e.CampaignID, &e.EntityType, &e.Name, &e.Description,
+		&e.Attributes, &e.Tags, &e.GMNotes, &e.DiscoveredSession,
+		&e.SourceDocument, &e.SourceConfidence, &e.Version,
+		&e.CreatedAt, &e.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to update entity %s: %w", field, err)
+	}
+
+	if err := insertEntityRevision(ctx, tx, &e, nil); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &e, nil
+}
+
 // DeleteEntity deletes an entity by ID.
 func (db *DB) DeleteEntity(ctx context.Context, id int64) error {
 	query := `DELETE FROM entities WHERE id = $1`
@@ -281,15 +380,23 @@ func (db *DB) CountEntitiesByType(ctx context.Context) (map[string]int, error) {
 	return counts, nil
 }
 
-// SearchEntitiesByName searches for entities with similar names.
+// SearchEntitiesByName searches for entities with names or aliases
+// similar to name, best match first.
 func (db *DB) SearchEntitiesByName(ctx context.Context, campaignID int64, name string, limit int) ([]models.Entity, error) {
 	query := `
-        SELECT id, campaign_id, entity_type, name, description, attributes,
-               tags, gm_notes, discovered_session, source_document,
-               source_confidence, version, created_at, updated_at
-        FROM entities
-        WHERE campaign_id = $1 AND name % $2
-        ORDER BY similarity(name, $2) DESC
+        SELECT e.id, e.campaign_id, e.entity_type, e.name, e.description,
+               e.attributes, e.tags, e.gm_notes, e.discovered_session,
+               e.source_document, e.source_confidence, e.version,
+               e.created_at, e.updated_at
+        FROM entities e
+        LEFT JOIN LATERAL (
+            SELECT MAX(similarity(a.alias, $2)) AS similarity
+            FROM entity_aliases a
+            WHERE a.entity_id = e.id AND a.alias % $2
+        ) alias_match ON true
+        WHERE e.campaign_id = $1
+          AND (e.name % $2 OR alias_match.similarity IS NOT NULL)
+        ORDER BY GREATEST(similarity(e.name, $2), alias_match.similarity) DESC
         LIMIT $3`
 
 	rows, err := db.Query(ctx, query, campaignID, name, limit)
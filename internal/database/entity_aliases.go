@@ -0,0 +1,183 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// entityAliasColumns is the standard column list for entity alias
+// queries.
+const entityAliasColumns = `id, entity_id, campaign_id, alias, source,
+	created_at, updated_at`
+
+// scanEntityAlias scans a single row into a models.EntityAlias.
+func scanEntityAlias(row pgx.Row) (*models.EntityAlias, error) {
+	var a models.EntityAlias
+	err := row.Scan(
+		&a.ID, &a.EntityID, &a.CampaignID, &a.Alias, &a.Source,
+		&a.CreatedAt, &a.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// scanEntityAliases scans multiple entity alias rows.
+func scanEntityAliases(rows pgx.Rows) ([]models.EntityAlias, error) {
+	var aliases []models.EntityAlias
+	for rows.Next() {
+		a, err := scanEntityAlias(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan entity alias: %w", err)
+		}
+		aliases = append(aliases, *a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating entity aliases: %w", err)
+	}
+
+	return aliases, nil
+}
+
+// CreateEntityAlias adds an alias to an entity, in the entity's
+// campaign. An alias already used in the campaign fails with a
+// unique_violation.
+func (db *DB) CreateEntityAlias(
+	ctx context.Context,
+	entityID int64,
+	alias string,
+	source models.EntityAliasSource,
+) (*models.EntityAlias, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO entity_aliases (entity_id, campaign_id, alias, source)
+		SELECT id, campaign_id, $2, $3
+		FROM entities
+		WHERE id = $1
+		RETURNING %s`, entityAliasColumns)
+
+	a, err := scanEntityAlias(db.QueryRow(ctx, query, entityID, alias, source))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create entity alias: %w", err)
+	}
+
+	return a, nil
+}
+
+// GetEntityAlias retrieves an entity alias by ID. Returns pgx.ErrNoRows
+// (unwrapped) when there is no such alias.
+func (db *DB) GetEntityAlias(ctx context.Context, id int64) (*models.EntityAlias, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM entity_aliases
+		WHERE id = $1`, entityAliasColumns)
+
+	a, err := scanEntityAlias(db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get entity alias: %w", err)
+	}
+
+	return a, nil
+}
+
+// ListEntityAliases retrieves the aliases of an entity, alphabetically.
+func (db *DB) ListEntityAliases(ctx context.Context, entityID int64) ([]models.EntityAlias, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM entity_aliases
+		WHERE entity_id = $1
+		ORDER BY lower(alias)`, entityAliasColumns)
+
+	rows, err := db.Query(ctx, query, entityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query entity aliases: %w", err)
+	}
+	defer rows.Close()
+
+	return scanEntityAliases(rows)
+}
+
+// ListCampaignEntityAliases retrieves the aliases of every entity in a
+// campaign, ordered by entity and then alphabetically.
+func (db *DB) ListCampaignEntityAliases(ctx context.Context, campaignID int64) ([]models.EntityAlias, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM entity_aliases
+		WHERE campaign_id = $1
+		ORDER BY entity_id, lower(alias)`, entityAliasColumns)
+
+	rows, err := db.Query(ctx, query, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query campaign entity aliases: %w", err)
+	}
+	defer rows.Close()
+
+	return scanEntityAliases(rows)
+}
+
+// UpdateEntityAlias renames an alias and/or reassigns it to another
+// entity. Nil fields are left unchanged. The caller must check that a
+// new entity belongs to the alias's campaign. Returns pgx.ErrNoRows
+// (unwrapped) when there is no such alias.
+func (db *DB) UpdateEntityAlias(
+	ctx context.Context,
+	id int64,
+	req models.UpdateEntityAliasRequest,
+) (*models.EntityAlias, error) {
+	query := fmt.Sprintf(`
+		UPDATE entity_aliases
+		SET alias     = COALESCE($2, alias),
+		    entity_id = COALESCE($3, entity_id)
+		WHERE id = $1
+		RETURNING %s`, entityAliasColumns)
+
+	a, err := scanEntityAlias(db.QueryRow(ctx, query, id, req.Alias, req.EntityID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to update entity alias: %w", err)
+	}
+
+	return a, nil
+}
+
+// DeleteEntityAlias deletes an entity alias by ID.
+func (db *DB) DeleteEntityAlias(ctx context.Context, id int64) error {
+	return db.Exec(ctx, "DELETE FROM entity_aliases WHERE id = $1", id)
+}
+
+// EntityNameExists reports whether an entity of the campaign is named
+// name, ignoring case.
+func (db *DB) EntityNameExists(ctx context.Context, campaignID int64, name string) (bool, error) {
+	var exists bool
+	err := db.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM entities
+			WHERE campaign_id = $1 AND lower(name) = lower($2)
+		)`,
+		campaignID, name,
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check entity name: %w", err)
+	}
+	return exists, nil
+}
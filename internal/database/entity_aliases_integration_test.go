@@ -0,0 +1,95 @@
+//go:build integration
+
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestIntegration_EntityAliases(t *testing.T) {
+	db := setupIntegrationDB(t)
+	campaignID, entityID := createTestCampaign(t, db)
+	ctx := context.Background()
+
+	other, err := db.CreateEntity(ctx, campaignID, models.CreateEntityRequest{
+		EntityType: models.EntityTypeLocation,
+		Name:       "18 Grosvenor Square",
+	})
+	if err != nil {
+		t.Fatalf("failed to create entity: %v", err)
+	}
+
+	alias, err := db.CreateEntityAlias(ctx, entityID, "The Orphean Society building", models.EntityAliasSourceManual)
+	if err != nil {
+		t.Fatalf("failed to create alias: %v", err)
+	}
+	if alias.CampaignID != campaignID || alias.Source != models.EntityAliasSourceManual {
+		t.Errorf("unexpected alias: %+v", alias)
+	}
+
+	// Aliases are unique in a campaign, ignoring case.
+	_, err = db.CreateEntityAlias(ctx, other.ID, "the orphean society BUILDING", models.EntityAliasSourceManual)
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+		t.Errorf("expected a unique violation, got %v", err)
+	}
+
+	results, err := db.ResolveEntityByName(ctx, campaignID, "Orphean Society building", 5)
+	if err != nil {
+		t.Fatalf("failed to resolve entity by name: %v", err)
+	}
+	if len(results) == 0 || results[0].ID != entityID {
+		t.Fatalf("expected the alias to resolve to entity %d, got %+v", entityID, results)
+	}
+	if results[0].MatchedAlias == nil || *results[0].MatchedAlias != alias.Alias {
+		t.Errorf("expected the matched alias to be reported, got %v", results[0].MatchedAlias)
+	}
+
+	found, err := db.SearchEntitiesByName(ctx, campaignID, "The Orphean Society building", 10)
+	if err != nil {
+		t.Fatalf("failed to search entities: %v", err)
+	}
+	if len(found) == 0 || found[0].ID != entityID {
+		t.Errorf("expected search to find entity %d by its alias, got %d results", entityID, len(found))
+	}
+
+	// Reassigning the alias moves it to the other entity.
+	updated, err := db.UpdateEntityAlias(ctx, alias.ID, models.UpdateEntityAliasRequest{EntityID: &other.ID})
+	if err != nil {
+		t.Fatalf("failed to update alias: %v", err)
+	}
+	if updated.EntityID != other.ID || updated.Alias != alias.Alias {
+		t.Errorf("expected alias to move to entity %d, got %+v", other.ID, updated)
+	}
+
+	aliases, err := db.ListCampaignEntityAliases(ctx, campaignID)
+	if err != nil {
+		t.Fatalf("failed to list campaign aliases: %v", err)
+	}
+	if len(aliases) != 1 || aliases[0].EntityID != other.ID {
+		t.Errorf("expected one alias of entity %d, got %+v", other.ID, aliases)
+	}
+
+	if err := db.DeleteEntityAlias(ctx, alias.ID); err != nil {
+		t.Fatalf("failed to delete alias: %v", err)
+	}
+	if _, err := db.GetEntityAlias(ctx, alias.ID); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("expected pgx.ErrNoRows after delete, got %v", err)
+	}
+}
@@ -88,6 +88,26 @@ func (db *DB) ListEntityLogs(ctx context.Context, entityID int64) ([]models.Enti
 	return scanEntityLogs(rows)
 }
 
+// ListSessionEntityLogs retrieves the entity log entries recorded for a
+// session, across all entities, in the order they were logged.
+func (db *DB) ListSessionEntityLogs(ctx context.Context, sessionID int64) ([]models.EntityLog, error) {
+	query := `
+		SELECT id, entity_id, campaign_id, chapter_id, session_id,
+		       source_table, source_id, content, occurred_at,
+		       sort_order, created_at
+		FROM entity_log
+		WHERE session_id = $1
+		ORDER BY created_at ASC, id ASC`
+
+	rows, err := db.Query(ctx, query, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list session entity logs: %w", err)
+	}
+	defer rows.Close()
+
+	return scanEntityLogs(rows)
+}
+
 // UpdateEntityLog updates an entity log entry and returns the updated
 // record. Uses COALESCE to preserve existing values when fields are nil.
 func (db *DB) UpdateEntityLog(ctx context.Context, id int64, req models.UpdateEntityLogRequest) (*models.EntityLog, error) {
@@ -0,0 +1,340 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// entityReferenceColumns lists the columns, other than links and
+// arrays, that reference an entity and are moved to the target of a
+// merge.
+var entityReferenceColumns = []struct{ table, column string }{
+	{"entity_log", "entity_id"},
+	{"player_characters", "entity_id"},
+	{"content_analysis_items", "entity_id"},
+	{"content_analysis_items", "resolved_entity_id"},
+	{"memory_entity_extractions", "entity_id"},
+	{"memory_entity_extractions", "matched_entity_id"},
+	{"canon_conflicts", "entity_id"},
+	{"canon_conflicts", "superseded_entity_id"},
+	{"entity_aliases", "entity_id"},
+}
+
+// MergeEntities folds the source entity into the target entity and
+// deletes the source, in one transaction. Relationships, chapter and
+// session links, scene, timeline and session memory entity lists,
+// scene connections, log entries, player characters and other
+// references move to the target. Relationships that would duplicate
+// one of the target's, directly or as an inverse the
+// prevent_inverse_relationship trigger rejects, are dropped, as are
+// relationships between the two. Wiki links to the source are renamed
+// to the target, and the source's name is recorded as an alias of the
+// target. The target keeps its own fields, taking the source's
+// description and GM notes only where it has none, the source's tags,
+// and the source's attributes it does not have; the change is recorded
+// as a revision attributed to the author in ctx. Returns pgx.ErrNoRows
+// (unwrapped) if either entity does not exist.
+func (db *DB) MergeEntities(ctx context.Context, sourceID, targetID int64) (*models.EntityMergeResult, error) {
+	if sourceID == targetID {
+		return nil, fmt.Errorf("cannot merge an entity into itself")
+	}
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck // Rollback is a no-op if already committed
+
+	var sourceName, targetName string
+	var sourceCampaign, targetCampaign int64
+	err = tx.QueryRow(ctx, `
+		SELECT s.name, s.campaign_id, t.name, t.campaign_id
+		FROM entities s, entities t
+		WHERE s.id = $1 AND t.id = $2
+		FOR UPDATE`,
+		sourceID, targetID,
+	).Scan(&sourceName, &sourceCampaign, &targetName, &targetCampaign)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to lock entities: %w", err)
+	}
+	if sourceCampaign != targetCampaign {
+		return nil, fmt.Errorf("cannot merge entities of different campaigns")
+	}
+	campaignID := targetCampaign
+
+	result := &models.EntityMergeResult{MergedEntityID: sourceID}
+
+	result.RelationshipsMoved, result.RelationshipsDropped, err = mergeRelationships(ctx, tx, sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Archived relationships keep their history, except those between
+	// the two entities.
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM relationship_archive
+		WHERE (source_entity_id = $1 AND target_entity_id = $2)
+		   OR (source_entity_id = $2 AND target_entity_id = $1)`,
+		sourceID, targetID,
+	); err != nil {
+		return nil, fmt.Errorf("failed to drop archived relationships: %w", err)
+	}
+	for _, column := range []string{"source_entity_id", "target_entity_id"} {
+		if _, err := tx.Exec(ctx, fmt.Sprintf(
+			`UPDATE relationship_archive SET %[1]s = $2 WHERE %[1]s = $1`, column),
+			sourceID, targetID,
+		); err != nil {
+			return nil, fmt.Errorf("failed to move archived relationships: %w", err)
+		}
+	}
+
+	result.LinksMoved, err = mergeEntityLinks(ctx, tx, sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ref := range entityReferenceColumns {
+		if _, err := tx.Exec(ctx, fmt.Sprintf(
+			`UPDATE %s SET %[2]s = $2 WHERE %[2]s = $1`, ref.table, ref.column),
+			sourceID, targetID,
+		); err != nil {
+			return nil, fmt.Errorf("failed to move %s.%s: %w", ref.table, ref.column, err)
+		}
+	}
+
+	if sourceName != targetName {
+		result.ContentUpdated, err = PropagateEntityRename(ctx, tx, campaignID, sourceName, targetName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to rename wiki links: %w", err)
+		}
+	}
+
+	var e models.Entity
+	err = tx.QueryRow(ctx, `
+		UPDATE entities t
+		SET description = COALESCE(NULLIF(t.description, ''), s.description),
+		    gm_notes = COALESCE(NULLIF(t.gm_notes, ''), s.gm_notes),
+		    tags = ARRAY(
+		        SELECT tag
+		        FROM unnest(COALESCE(t.tags, '{}') || COALESCE(s.tags, '{}'))
+		             WITH ORDINALITY AS u(tag, ord)
+		        GROUP BY tag
+		        ORDER BY min(ord)),
+		    attributes = CASE
+		        WHEN jsonb_typeof(s.attributes) = 'object'
+		         AND jsonb_typeof(t.attributes) = 'object'
+		        THEN s.attributes || t.attributes
+		        ELSE COALESCE(t.attributes, s.attributes)
+		    END,
+		    version = t.version + 1
+		FROM entities s
+		WHERE t.id = $1 AND s.id = $2
+		RETURNING t.id, t.campaign_id, t.entity_type, t.name, t.description,
+		          t.attributes, t.tags, t.gm_notes, t.discovered_session,
+		          t.source_document, t.source_confidence, t.version,
+		          t.created_at, t.updated_at`,
+		targetID, sourceID,
+	).Scan(
+		&e.ID, &e.CampaignID, &e.EntityType, &e.Name, &e.Description,
+		&e.Attributes, &e.Tags, &e.GMNotes, &e.DiscoveredSession,
+		&e.SourceDocument, &e.SourceConfidence, &e.Version,
+		&e.CreatedAt, &e.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge entity fields: %w", err)
+	}
+	if err := insertEntityRevision(ctx, tx, &e, nil); err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(sourceName, targetName) {
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO entity_aliases (entity_id, campaign_id, alias, source)
+			VALUES ($1, $2, $3, 'merge')
+			ON CONFLICT DO NOTHING`,
+			targetID, campaignID, sourceName,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record alias: %w", err)
+		}
+		result.AliasRecorded = tag.RowsAffected() > 0
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM entities WHERE id = $1`, sourceID); err != nil {
+		return nil, fmt.Errorf("failed to delete merged entity: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	result.Entity = &e
+	return result, nil
+}
+
+// mergeRelationships moves the source entity's relationships to the
+// target. Each move runs in a savepoint, so that one the unique
+// constraint or the prevent_inverse_relationship trigger rejects as a
+// duplicate of the target's is dropped instead. Relationships between
+// the two entities are dropped.
+func mergeRelationships(ctx context.Context, tx pgx.Tx, sourceID, targetID int64) (moved, dropped int, err error) {
+	rows, err := tx.Query(ctx, `
+		SELECT id, source_entity_id, target_entity_id
+		FROM relationships
+		WHERE source_entity_id = $1 OR target_entity_id = $1
+		ORDER BY id`,
+		sourceID,
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to query relationships: %w", err)
+	}
+
+	type edge struct{ id, source, target int64 }
+	var edges []edge
+	for rows.Next() {
+		var e edge
+		if err := rows.Scan(&e.id, &e.source, &e.target); err != nil {
+			rows.Close()
+			return 0, 0, fmt.Errorf("failed to scan relationship: %w", err)
+		}
+		edges = append(edges, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, fmt.Errorf("error iterating relationships: %w", err)
+	}
+
+	for _, e := range edges {
+		if e.source == targetID || e.target == targetID {
+			if err := deleteRelationshipTx(ctx, tx, e.id); err != nil {
+				return 0, 0, err
+			}
+			dropped++
+			continue
+		}
+
+		sp, err := tx.Begin(ctx)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to begin savepoint: %w", err)
+		}
+		_, err = sp.Exec(ctx, `
+			UPDATE relationships
+			SET source_entity_id = CASE WHEN source_entity_id = $2 THEN $3 ELSE source_entity_id END,
+			    target_entity_id = CASE WHEN target_entity_id = $2 THEN $3 ELSE target_entity_id END,
+			    updated_at = NOW()
+			WHERE id = $1`,
+			e.id, sourceID, targetID,
+		)
+		if err != nil {
+			_ = sp.Rollback(ctx)
+			var pgErr *pgconn.PgError
+			if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+				return 0, 0, fmt.Errorf("failed to move relationship %d: %w", e.id, err)
+			}
+			if err := deleteRelationshipTx(ctx, tx, e.id); err != nil {
+				return 0, 0, err
+			}
+			dropped++
+			continue
+		}
+		if err := sp.Commit(ctx); err != nil {
+			return 0, 0, fmt.Errorf("failed to release savepoint: %w", err)
+		}
+		moved++
+	}
+
+	return moved, dropped, nil
+}
+
+// deleteRelationshipTx deletes a relationship within tx.
+func deleteRelationshipTx(ctx context.Context, tx pgx.Tx, id int64) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM relationships WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to drop relationship %d: %w", id, err)
+	}
+	return nil
+}
+
+// mergeEntityLinks moves the source entity's chapter and session links
+// to the target, where the target is not already linked, and replaces
+// the source in scene, timeline event and session memory entity lists
+// and in scene connections. Returns the number of rows changed.
+func mergeEntityLinks(ctx context.Context, tx pgx.Tx, sourceID, targetID int64) (int64, error) {
+	var total int64
+
+	for _, link := range []struct{ table, parent string }{
+		{"chapter_entities", "chapter_id"},
+		{"session_entities", "session_id"},
+	} {
+		tag, err := tx.Exec(ctx, fmt.Sprintf(`
+			UPDATE %[1]s l SET entity_id = $2
+			WHERE l.entity_id = $1
+			  AND NOT EXISTS (
+			      SELECT 1 FROM %[1]s o
+			      WHERE o.%[2]s = l.%[2]s AND o.entity_id = $2)`,
+			link.table, link.parent),
+			sourceID, targetID,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to move %s: %w", link.table, err)
+		}
+		total += tag.RowsAffected()
+	}
+
+	for _, list := range []struct{ table, column string }{
+		{"scenes", "entity_ids"},
+		{"timeline_events", "entity_ids"},
+		{"session_memories", "entities_mentioned"},
+	} {
+		tag, err := tx.Exec(ctx, fmt.Sprintf(`
+			UPDATE %[1]s SET %[2]s = CASE
+			    WHEN $2 = ANY(%[2]s) THEN array_remove(%[2]s, $1)
+			    ELSE array_replace(%[2]s, $1, $2)
+			END
+			WHERE $1 = ANY(%[2]s)`,
+			list.table, list.column),
+			sourceID, targetID,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to update %s.%s: %w", list.table, list.column, err)
+		}
+		total += tag.RowsAffected()
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE scenes SET connections = (
+		    SELECT jsonb_agg(CASE
+		        WHEN (c->>'entityId')::bigint = $1
+		        THEN jsonb_set(c, '{entityId}', to_jsonb($2::bigint))
+		        ELSE c
+		    END ORDER BY ord)
+		    FROM jsonb_array_elements(connections) WITH ORDINALITY AS x(c, ord))
+		WHERE jsonb_typeof(connections) = 'array'
+		  AND connections @> jsonb_build_array(jsonb_build_object('entityId', $1::bigint))`,
+		sourceID, targetID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update scene connections: %w", err)
+	}
+	total += tag.RowsAffected()
+
+	return total, nil
+}
@@ -0,0 +1,158 @@
+//go:build integration
+
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestIntegration_MergeEntities(t *testing.T) {
+	db := setupIntegrationDB(t)
+	campaignID, _ := createTestCampaign(t, db)
+	ctx := context.Background()
+
+	create := func(name string, description *string, tags []string) *models.Entity {
+		t.Helper()
+		e, err := db.CreateEntity(ctx, campaignID, models.CreateEntityRequest{
+			EntityType:  models.EntityTypeNPC,
+			Name:        name,
+			Description: description,
+			Tags:        tags,
+		})
+		if err != nil {
+			t.Fatalf("failed to create entity %q: %v", name, err)
+		}
+		return e
+	}
+	sourceDesc := "Drinks at the Blue Anchor."
+	source := create("Cpt. Hale", &sourceDesc, []string{"sailor", "suspect"})
+	target := create("Captain Hale", nil, []string{"sailor"})
+	friendDesc := "Owes money to [[Cpt. Hale]]."
+	friend := create("Marta", &friendDesc, nil)
+	rival := create("Silas", nil, nil)
+
+	types, err := db.ListRelationshipTypes(ctx, campaignID)
+	if err != nil {
+		t.Fatalf("failed to list relationship types: %v", err)
+	}
+	var symmetric *models.RelationshipType
+	for i := range types {
+		if types[i].IsSymmetric {
+			symmetric = &types[i]
+			break
+		}
+	}
+	if symmetric == nil {
+		t.Skip("no symmetric relationship type seeded")
+	}
+
+	relate := func(from, to int64) {
+		t.Helper()
+		if _, err := db.CreateRelationship(ctx, campaignID, models.CreateRelationshipRequest{
+			SourceEntityID:     from,
+			TargetEntityID:     to,
+			RelationshipTypeID: symmetric.ID,
+		}); err != nil {
+			t.Fatalf("failed to relate %d and %d: %v", from, to, err)
+		}
+	}
+	relate(target.ID, friend.ID)
+	relate(friend.ID, source.ID) // inverse of the target's once moved
+	relate(source.ID, rival.ID)
+	relate(source.ID, target.ID)
+
+	session, err := db.CreateSession(ctx, campaignID, models.CreateSessionRequest{})
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	if _, err := db.AddSessionEntityMentions(ctx, session.ID, []int64{source.ID}); err != nil {
+		t.Fatalf("failed to link source to session: %v", err)
+	}
+	if _, err := db.CreateEntityLog(ctx, source.ID, campaignID, models.CreateEntityLogRequest{
+		Content: "Seen at the docks.",
+	}); err != nil {
+		t.Fatalf("failed to create entity log: %v", err)
+	}
+
+	result, err := db.MergeEntities(ctx, source.ID, target.ID)
+	if err != nil {
+		t.Fatalf("failed to merge entities: %v", err)
+	}
+	if result.RelationshipsMoved != 1 || result.RelationshipsDropped != 2 {
+		t.Errorf("expected 1 relationship moved and 2 dropped, got %d and %d",
+			result.RelationshipsMoved, result.RelationshipsDropped)
+	}
+	if !result.AliasRecorded {
+		t.Error("expected the source name to be recorded as an alias")
+	}
+	if result.Entity.Description == nil || *result.Entity.Description != sourceDesc {
+		t.Errorf("expected the target to take the source description, got %v", result.Entity.Description)
+	}
+	if len(result.Entity.Tags) != 2 {
+		t.Errorf("expected merged tags [sailor suspect], got %v", result.Entity.Tags)
+	}
+
+	if _, err := db.GetEntity(ctx, source.ID); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("expected the source entity to be deleted, got %v", err)
+	}
+
+	rels, err := db.GetEntityRelationships(ctx, target.ID)
+	if err != nil {
+		t.Fatalf("failed to get target relationships: %v", err)
+	}
+	related := map[int64]bool{}
+	for _, r := range rels {
+		related[r.SourceEntityID] = true
+		related[r.TargetEntityID] = true
+	}
+	if !related[friend.ID] || !related[rival.ID] {
+		t.Errorf("expected the target to be related to Marta and Silas, got %+v", rels)
+	}
+
+	links, err := db.ListSessionEntities(ctx, session.ID)
+	if err != nil {
+		t.Fatalf("failed to list session entities: %v", err)
+	}
+	if len(links) != 1 || links[0].EntityID != target.ID {
+		t.Errorf("expected the session link to move to the target, got %+v", links)
+	}
+
+	logs, err := db.ListEntityLogs(ctx, target.ID)
+	if err != nil {
+		t.Fatalf("failed to list entity logs: %v", err)
+	}
+	if len(logs) != 1 {
+		t.Errorf("expected the log entry to move to the target, got %d", len(logs))
+	}
+
+	updatedFriend, err := db.GetEntity(ctx, friend.ID)
+	if err != nil {
+		t.Fatalf("failed to get entity: %v", err)
+	}
+	if *updatedFriend.Description != "Owes money to [[Captain Hale]]." {
+		t.Errorf("expected wiki link to be renamed, got %q", *updatedFriend.Description)
+	}
+
+	var aliasOwner int64
+	if err := db.QueryRow(ctx,
+		`SELECT entity_id FROM entity_aliases WHERE campaign_id = $1 AND alias = $2`,
+		campaignID, source.Name,
+	).Scan(&aliasOwner); err != nil || aliasOwner != target.ID {
+		t.Errorf("expected alias %q of entity %d, got %d (%v)", source.Name, target.ID, aliasOwner, err)
+	}
+}
@@ -18,8 +18,10 @@ import (
 )
 
 // ResolveEntityByName performs a fuzzy name match against entities in a
-// campaign using the pg_trgm similarity operator (%). It returns up to
-// limit results ordered by descending similarity score.
+// campaign using the pg_trgm similarity operator (%), matching their
+// aliases as well as their names. Each entity is scored by its best
+// matching name or alias. It returns up to limit results ordered by
+// descending similarity score.
 func (db *DB) ResolveEntityByName(
 	ctx context.Context,
 	campaignID int64,
@@ -34,9 +36,25 @@ func (db *DB) ResolveEntityByName(
 	}
 
 	query := `
-		SELECT id, name, entity_type, similarity(name, $2) AS similarity
-		FROM entities
-		WHERE campaign_id = $1 AND name % $2
+		WITH matches AS (
+			SELECT id, name, entity_type,
+			       similarity(name, $2) AS similarity,
+			       NULL::text AS matched_alias
+			FROM entities
+			WHERE campaign_id = $1 AND name % $2
+			UNION ALL
+			SELECT e.id, e.name, e.entity_type,
+			       similarity(a.alias, $2), a.alias
+			FROM entity_aliases a
+			JOIN entities e ON e.id = a.entity_id
+			WHERE a.campaign_id = $1 AND a.alias % $2
+		)
+		SELECT id, name, entity_type, similarity, matched_alias
+		FROM (
+			SELECT DISTINCT ON (id) *
+			FROM matches
+			ORDER BY id, similarity DESC, matched_alias NULLS FIRST
+		) best
 		ORDER BY similarity DESC
 		LIMIT $3`
 
@@ -49,7 +67,7 @@ func (db *DB) ResolveEntityByName(
 	var results []models.EntityResolveResult
 	for rows.Next() {
 		var r models.EntityResolveResult
-		if err := rows.Scan(&r.ID, &r.Name, &r.EntityType, &r.Similarity); err != nil {
+		if err := rows.Scan(&r.ID, &r.Name, &r.EntityType, &r.Similarity, &r.MatchedAlias); err != nil {
 			return nil, fmt.Errorf("failed to scan entity resolve result: %w", err)
 		}
 		results = append(results, r)
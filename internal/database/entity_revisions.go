@@ -0,0 +1,200 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+type revisionAuthorKey struct{}
+
+// WithEntityRevisionAuthor returns a context that attributes the entity
+// revisions recorded with it to author.
+func WithEntityRevisionAuthor(ctx context.Context, author models.EntityRevisionAuthor) context.Context {
+	return context.WithValue(ctx, revisionAuthorKey{}, author)
+}
+
+// entityRevisionAuthor returns the author stored in ctx by
+// WithEntityRevisionAuthor. ok is false when the author is unknown.
+func entityRevisionAuthor(ctx context.Context) (author models.EntityRevisionAuthor, ok bool) {
+	author, ok = ctx.Value(revisionAuthorKey{}).(models.EntityRevisionAuthor)
+	return author, ok
+}
+
+// entityRevisionColumns is the standard column list for entity
+// revision queries.
+const entityRevisionColumns = `id, entity_id, campaign_id, version,
+	entity_type, name, description, attributes, tags, gm_notes,
+	discovered_session, source_document, source_confidence,
+	author_type, author_user_id, author_agent, analysis_item_id,
+	restored_from_version, created_at`
+
+// scanEntityRevision scans a single row into a models.EntityRevision.
+func scanEntityRevision(row pgx.Row) (*models.EntityRevision, error) {
+	var r models.EntityRevision
+	err := row.Scan(
+		&r.ID, &r.EntityID, &r.CampaignID, &r.Version,
+		&r.EntityType, &r.Name, &r.Description, &r.Attributes, &r.Tags, &r.GMNotes,
+		&r.DiscoveredSession, &r.SourceDocument, &r.SourceConfidence,
+		&r.AuthorType, &r.AuthorUserID, &r.AuthorAgent, &r.AnalysisItemID,
+		&r.RestoredFromVersion, &r.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// insertEntityRevision records e, as just written, as a revision of the
+// entity, attributed to the author in ctx. restoredFrom is the older
+// version e restores, if any. It must be called in the transaction
+// that wrote e.
+func insertEntityRevision(ctx context.Context, tx pgx.Tx, e *models.Entity, restoredFrom *int) error {
+	var authorType *models.EntityRevisionAuthorType
+	var authorUserID, analysisItemID *int64
+	var authorAgent *string
+	if author, ok := entityRevisionAuthor(ctx); ok {
+		authorType = &author.Type
+		authorUserID = author.UserID
+		analysisItemID = author.AnalysisItemID
+		if author.Agent != "" {
+			authorAgent = &author.Agent
+		}
+	}
+
+	attributes := e.Attributes
+	if attributes == nil {
+		attributes = []byte("{}")
+	}
+	tags := e.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO entity_revisions
+			(entity_id, campaign_id, version, entity_type, name, description,
+			 attributes, tags, gm_notes, discovered_session, source_document,
+			 source_confidence, author_type, author_user_id, author_agent,
+			 analysis_item_id, restored_from_version)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)`,
+		e.ID, e.CampaignID, e.Version, e.EntityType, e.Name, e.Description,
+		attributes, tags, e.GMNotes, e.DiscoveredSession, e.SourceDocument,
+		e.SourceConfidence, authorType, authorUserID, authorAgent,
+		analysisItemID, restoredFrom,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record entity revision: %w", err)
+	}
+	return nil
+}
+
+// ListEntityRevisions retrieves the revisions of an entity, newest
+// first.
+func (db *DB) ListEntityRevisions(ctx context.Context, entityID int64) ([]models.EntityRevision, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM entity_revisions
+		WHERE entity_id = $1
+		ORDER BY version DESC`, entityRevisionColumns)
+
+	rows, err := db.Query(ctx, query, entityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query entity revisions: %w", err)
+	}
+	defer rows.Close()
+
+	var revisions []models.EntityRevision
+	for rows.Next() {
+		r, err := scanEntityRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan entity revision: %w", err)
+		}
+		revisions = append(revisions, *r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating entity revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// GetEntityRevision retrieves the revision of an entity at a version.
+// Returns pgx.ErrNoRows (unwrapped) when there is no such revision.
+func (db *DB) GetEntityRevision(ctx context.Context, entityID int64, version int) (*models.EntityRevision, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM entity_revisions
+		WHERE entity_id = $1 AND version = $2`, entityRevisionColumns)
+
+	r, err := scanEntityRevision(db.QueryRow(ctx, query, entityID, version))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get entity revision: %w", err)
+	}
+
+	return r, nil
+}
+
+// RestoreEntityRevision writes the entity as it was at version as its
+// next version, attributed to the author in ctx. Fields the older
+// version did not have are cleared, as is a discovered session that no
+// longer exists, and a name change is propagated to wiki links as with
+// UpdateEntity. Returns pgx.ErrNoRows (unwrapped) when there is no
+// such revision.
+func (db *DB) RestoreEntityRevision(ctx context.Context, entityID int64, version int) (*models.Entity, error) {
+	revision, err := db.GetEntityRevision(ctx, entityID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := db.GetEntity(ctx, entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	next := *existing
+	next.EntityType = revision.EntityType
+	next.Name = revision.Name
+	next.Description = revision.Description
+	next.Attributes = revision.Attributes
+	next.Tags = revision.Tags
+	next.GMNotes = revision.GMNotes
+	next.DiscoveredSession = revision.DiscoveredSession
+	next.SourceDocument = revision.SourceDocument
+	next.SourceConfidence = revision.SourceConfidence
+
+	// The session the entity was discovered in may have been deleted
+	// since.
+	if next.DiscoveredSession != nil {
+		var exists bool
+		err := db.QueryRow(ctx,
+			`SELECT EXISTS (SELECT 1 FROM sessions WHERE id = $1)`,
+			*next.DiscoveredSession,
+		).Scan(&exists)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check discovered session: %w", err)
+		}
+		if !exists {
+			next.DiscoveredSession = nil
+		}
+	}
+
+	return db.saveEntity(ctx, existing, &next, &version)
+}
@@ -0,0 +1,174 @@
+//go:build integration
+
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestIntegration_EntityRevisions(t *testing.T) {
+	db := setupIntegrationDB(t)
+	campaignID, _ := createTestCampaign(t, db)
+	ctx := context.Background()
+
+	original := "A retired sea captain."
+	entity, err := db.CreateEntity(ctx, campaignID, models.CreateEntityRequest{
+		EntityType:  models.EntityTypeNPC,
+		Name:        "Captain Hale",
+		Description: &original,
+	})
+	if err != nil {
+		t.Fatalf("failed to create entity: %v", err)
+	}
+
+	asAgent := WithEntityRevisionAuthor(ctx, models.EntityRevisionAuthor{
+		Type:  models.EntityRevisionAuthorAgent,
+		Agent: "enrichment",
+	})
+	enriched := "A retired sea captain, secretly a smuggler."
+	updated, err := db.UpdateEntity(asAgent, entity.ID, models.UpdateEntityRequest{
+		Description: &enriched,
+	})
+	if err != nil {
+		t.Fatalf("failed to update entity: %v", err)
+	}
+
+	revisions, err := db.ListEntityRevisions(ctx, entity.ID)
+	if err != nil {
+		t.Fatalf("failed to list entity revisions: %v", err)
+	}
+	if len(revisions) != 2 || revisions[0].Version != updated.Version {
+		t.Fatalf("expected 2 revisions, newest first, got %+v", revisions)
+	}
+	if revisions[0].AuthorType == nil || *revisions[0].AuthorType != models.EntityRevisionAuthorAgent ||
+		revisions[0].AuthorAgent == nil || *revisions[0].AuthorAgent != "enrichment" {
+		t.Errorf("expected the update to be attributed to the enrichment agent, got %+v", revisions[0])
+	}
+	if revisions[1].AuthorType != nil {
+		t.Errorf("expected the creation to have no author, got %q", *revisions[1].AuthorType)
+	}
+
+	restored, err := db.RestoreEntityRevision(ctx, entity.ID, entity.Version)
+	if err != nil {
+		t.Fatalf("failed to restore entity revision: %v", err)
+	}
+	if restored.Version != updated.Version+1 {
+		t.Errorf("expected restore to make version %d, got %d", updated.Version+1, restored.Version)
+	}
+	if restored.Description == nil || *restored.Description != original {
+		t.Errorf("expected the original description to be restored, got %v", restored.Description)
+	}
+
+	latest, err := db.GetEntityRevision(ctx, entity.ID, restored.Version)
+	if err != nil {
+		t.Fatalf("failed to get entity revision: %v", err)
+	}
+	if latest.RestoredFromVersion == nil || *latest.RestoredFromVersion != entity.Version {
+		t.Errorf("expected revision to record the restored version, got %v", latest.RestoredFromVersion)
+	}
+
+	if _, err := db.GetEntityRevision(ctx, entity.ID, 99); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("expected ErrNoRows for a missing version, got %v", err)
+	}
+}
+
+func TestIntegration_EntityContentFieldRevision(t *testing.T) {
+	db := setupIntegrationDB(t)
+	campaignID, _ := createTestCampaign(t, db)
+	ctx := context.Background()
+
+	original := "Hale drinks at the Anchor."
+	entity, err := db.CreateEntity(ctx, campaignID, models.CreateEntityRequest{
+		EntityType:  models.EntityTypeNPC,
+		Name:        "Captain Hale",
+		Description: &original,
+	})
+	if err != nil {
+		t.Fatalf("failed to create entity: %v", err)
+	}
+
+	// An accepted content fix wraps a mention in a wiki link.
+	asUser := WithEntityRevisionAuthor(ctx, models.EntityRevisionAuthor{
+		Type: models.EntityRevisionAuthorUser,
+	})
+	fixed := "Hale drinks at the [[Anchor]]."
+	updated, err := db.UpdateEntityContentField(asUser, entity.ID, "description", fixed)
+	if err != nil {
+		t.Fatalf("failed to update entity description: %v", err)
+	}
+	if updated.Version != entity.Version+1 {
+		t.Errorf("expected version %d, got %d", entity.Version+1, updated.Version)
+	}
+
+	revision, err := db.GetEntityRevision(ctx, entity.ID, updated.Version)
+	if err != nil {
+		t.Fatalf("failed to get entity revision: %v", err)
+	}
+	if revision.Description == nil || *revision.Description != fixed {
+		t.Errorf("expected the revision to hold the fixed description, got %v", revision.Description)
+	}
+	if revision.AuthorType == nil || *revision.AuthorType != models.EntityRevisionAuthorUser {
+		t.Errorf("expected the fix to be attributed to the user, got %+v", revision)
+	}
+
+	if _, err := db.UpdateEntityContentField(asUser, entity.ID, "name", "Hale"); err == nil {
+		t.Error("expected an error for a field other than description or gm_notes")
+	}
+	if _, err := db.UpdateEntityContentField(asUser, -1, "gm_notes", "x"); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("expected ErrNoRows for a missing entity, got %v", err)
+	}
+}
+
+func TestIntegration_EntityRenameRevisions(t *testing.T) {
+	db := setupIntegrationDB(t)
+	campaignID, _ := createTestCampaign(t, db)
+	ctx := context.Background()
+
+	inn, err := db.CreateEntity(ctx, campaignID, models.CreateEntityRequest{
+		EntityType: models.EntityTypeLocation,
+		Name:       "The Anchor",
+	})
+	if err != nil {
+		t.Fatalf("failed to create entity: %v", err)
+	}
+	notes := "Meets contacts at [[The Anchor]]."
+	hale, err := db.CreateEntity(ctx, campaignID, models.CreateEntityRequest{
+		EntityType: models.EntityTypeNPC,
+		Name:       "Captain Hale",
+		GMNotes:    &notes,
+	})
+	if err != nil {
+		t.Fatalf("failed to create entity: %v", err)
+	}
+
+	renamed := "The Rusty Anchor"
+	if _, err := db.UpdateEntity(ctx, inn.ID, models.UpdateEntityRequest{Name: &renamed}); err != nil {
+		t.Fatalf("failed to rename entity: %v", err)
+	}
+
+	revisions, err := db.ListEntityRevisions(ctx, hale.ID)
+	if err != nil {
+		t.Fatalf("failed to list entity revisions: %v", err)
+	}
+	if len(revisions) != 2 || revisions[0].Version != hale.Version+1 {
+		t.Fatalf("expected the renamed link to make a new revision, got %+v", revisions)
+	}
+	if revisions[0].GMNotes == nil || *revisions[0].GMNotes != "Meets contacts at [[The Rusty Anchor]]." {
+		t.Errorf("expected the revision to hold the renamed link, got %v", revisions[0].GMNotes)
+	}
+}
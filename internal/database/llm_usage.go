@@ -0,0 +1,159 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+// RecordLLMUsage stores the token usage of a single LLM completion.
+// It satisfies llm.UsageRecorder.
+func (db *DB) RecordLLMUsage(ctx context.Context, usage models.LLMUsage) error {
+	query := `
+        INSERT INTO llm_usage (campaign_id, job_id, agent_name, provider, tokens_used)
+        VALUES ($1, $2, $3, $4, $5)`
+
+	if err := db.Exec(ctx, query,
+		usage.CampaignID, usage.JobID, usage.AgentName,
+		usage.Provider, usage.TokensUsed,
+	); err != nil {
+		return fmt.Errorf("failed to record LLM usage: %w", err)
+	}
+	return nil
+}
+
+// GetCampaignUsageSummary aggregates a campaign's LLM usage recorded at
+// or after since, broken down by agent and by provider.
+func (db *DB) GetCampaignUsageSummary(
+	ctx context.Context,
+	campaignID int64,
+	since time.Time,
+) (*models.CampaignUsageSummary, error) {
+	summary := &models.CampaignUsageSummary{
+		CampaignID: campaignID,
+		Since:      since,
+	}
+
+	err := db.QueryRow(ctx, `
+        SELECT COUNT(*), COALESCE(SUM(tokens_used), 0)
+          FROM llm_usage
+         WHERE campaign_id = $1 AND created_at >= $2`,
+		campaignID, since,
+	).Scan(&summary.Completions, &summary.TokensUsed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum LLM usage: %w", err)
+	}
+
+	summary.ByAgent, err = db.usageBreakdown(ctx, "agent_name", campaignID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	summary.ByProvider, err = db.usageBreakdown(ctx, "provider", campaignID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// usageBreakdown groups a campaign's usage by the given column, which
+// must be a trusted column name, ordered by tokens descending.
+func (db *DB) usageBreakdown(
+	ctx context.Context,
+	column string,
+	campaignID int64,
+	since time.Time,
+) ([]models.LLMUsageBreakdown, error) {
+	query := fmt.Sprintf(`
+        SELECT %[1]s, COUNT(*), COALESCE(SUM(tokens_used), 0)
+          FROM llm_usage
+         WHERE campaign_id = $1 AND created_at >= $2
+         GROUP BY %[1]s
+         ORDER BY 3 DESC, 1`, column)
+
+	rows, err := db.Query(ctx, query, campaignID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query LLM usage by %s: %w", column, err)
+	}
+	defer rows.Close()
+
+	breakdown := []models.LLMUsageBreakdown{}
+	for rows.Next() {
+		var b models.LLMUsageBreakdown
+		if err := rows.Scan(&b.Name, &b.Completions, &b.TokensUsed); err != nil {
+			return nil, fmt.Errorf("failed to scan LLM usage: %w", err)
+		}
+		breakdown = append(breakdown, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating LLM usage: %w", err)
+	}
+
+	return breakdown, nil
+}
+
+// GetCampaignBudgetStatus returns a campaign's monthly token limit and
+// the tokens it has used in the current calendar month (UTC).
+func (db *DB) GetCampaignBudgetStatus(ctx context.Context, campaignID int64) (*models.CampaignBudgetStatus, error) {
+	status := &models.CampaignBudgetStatus{CampaignID: campaignID}
+
+	err := db.QueryRow(ctx, `
+        SELECT date_trunc('month', NOW() AT TIME ZONE 'UTC') AT TIME ZONE 'UTC',
+               (SELECT monthly_token_limit
+                  FROM campaign_llm_budgets
+                 WHERE campaign_id = $1),
+               (SELECT COALESCE(SUM(tokens_used), 0)
+                  FROM llm_usage
+                 WHERE campaign_id = $1
+                   AND created_at >= date_trunc('month', NOW() AT TIME ZONE 'UTC') AT TIME ZONE 'UTC')`,
+		campaignID,
+	).Scan(&status.PeriodStart, &status.MonthlyTokenLimit, &status.TokensUsed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign budget status: %w", err)
+	}
+
+	status.Exceeded = status.MonthlyTokenLimit != nil &&
+		status.TokensUsed >= *status.MonthlyTokenLimit
+
+	return status, nil
+}
+
+// SetCampaignTokenBudget sets a campaign's monthly token limit. A nil
+// limit removes the cap.
+func (db *DB) SetCampaignTokenBudget(ctx context.Context, campaignID int64, limit *int64) error {
+	if limit == nil {
+		if err := db.Exec(ctx,
+			`DELETE FROM campaign_llm_budgets WHERE campaign_id = $1`,
+			campaignID,
+		); err != nil {
+			return fmt.Errorf("failed to remove campaign budget: %w", err)
+		}
+		return nil
+	}
+
+	query := `
+        INSERT INTO campaign_llm_budgets (campaign_id, monthly_token_limit)
+        VALUES ($1, $2)
+        ON CONFLICT (campaign_id) DO UPDATE SET
+            monthly_token_limit = EXCLUDED.monthly_token_limit,
+            updated_at = NOW()`
+
+	if err := db.Exec(ctx, query, campaignID, *limit); err != nil {
+		return fmt.Errorf("failed to set campaign budget: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+//go:build integration
+
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+func TestIntegration_LLMUsageAndBudget(t *testing.T) {
+	db := setupIntegrationDB(t)
+	campaignID, _ := createTestCampaign(t, db)
+	ctx := context.Background()
+
+	for _, u := range []models.LLMUsage{
+		{CampaignID: campaignID, AgentName: "canon-expert", Provider: "anthropic", TokensUsed: 100},
+		{CampaignID: campaignID, AgentName: "canon-expert", Provider: "anthropic", TokensUsed: 50},
+		{CampaignID: campaignID, AgentName: "enrichment", Provider: "openai", TokensUsed: 30},
+	} {
+		if err := db.RecordLLMUsage(ctx, u); err != nil {
+			t.Fatalf("failed to record usage: %v", err)
+		}
+	}
+
+	summary, err := db.GetCampaignUsageSummary(ctx, campaignID, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("failed to get usage summary: %v", err)
+	}
+	if summary.Completions != 3 || summary.TokensUsed != 180 {
+		t.Errorf("unexpected totals: %+v", summary)
+	}
+	if len(summary.ByAgent) != 2 || summary.ByAgent[0].Name != "canon-expert" ||
+		summary.ByAgent[0].TokensUsed != 150 {
+		t.Errorf("unexpected agent breakdown: %+v", summary.ByAgent)
+	}
+
+	status, err := db.GetCampaignBudgetStatus(ctx, campaignID)
+	if err != nil {
+		t.Fatalf("failed to get budget status: %v", err)
+	}
+	if status.MonthlyTokenLimit != nil || status.Exceeded {
+		t.Errorf("expected no budget, got %+v", status)
+	}
+
+	limit := int64(150)
+	if err := db.SetCampaignTokenBudget(ctx, campaignID, &limit); err != nil {
+		t.Fatalf("failed to set budget: %v", err)
+	}
+	status, err = db.GetCampaignBudgetStatus(ctx, campaignID)
+	if err != nil {
+		t.Fatalf("failed to get budget status: %v", err)
+	}
+	if status.TokensUsed != 180 || !status.Exceeded {
+		t.Errorf("expected exceeded budget, got %+v", status)
+	}
+
+	if err := db.SetCampaignTokenBudget(ctx, campaignID, nil); err != nil {
+		t.Fatalf("failed to remove budget: %v", err)
+	}
+	status, err = db.GetCampaignBudgetStatus(ctx, campaignID)
+	if err != nil {
+		t.Fatalf("failed to get budget status: %v", err)
+	}
+	if status.Exceeded {
+		t.Errorf("expected budget to be removed, got %+v", status)
+	}
+}
@@ -0,0 +1,185 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// memoryEntityExtractionColumns is the standard column list for entity
+// extraction queries. The extraction is aliased x and its matched
+// entity me.
+const memoryEntityExtractionColumns = `x.id, x.session_id, x.session_memory_id,
+	x.source, x.extracted_name, COALESCE(x.extracted_type, 'other'),
+	x.description, x.evidence_text, x.matched_entity_id, me.name,
+	x.status, x.entity_id, x.created_at, x.updated_at`
+
+// scanMemoryEntityExtraction scans a single row into a
+// models.MemoryEntityExtraction.
+func scanMemoryEntityExtraction(row pgx.Row) (*models.MemoryEntityExtraction, error) {
+	var x models.MemoryEntityExtraction
+	err := row.Scan(
+		&x.ID, &x.SessionID, &x.SessionMemoryID,
+		&x.Source, &x.Name, &x.EntityType,
+		&x.Description, &x.EvidenceText, &x.MatchedEntityID, &x.MatchedEntityName,
+		&x.Status, &x.EntityID, &x.CreatedAt, &x.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &x, nil
+}
+
+// CreateMemoryEntityExtractions queues proposals detected in a
+// session's source text for review and returns the extractions that
+// were queued. A proposal whose name was already proposed during the
+// session is skipped, as is a matched entity outside the session's
+// campaign, which leaves the proposal as a new entity.
+func (db *DB) CreateMemoryEntityExtractions(
+	ctx context.Context,
+	sessionID int64,
+	source models.EntityExtractionSource,
+	proposals []models.EntityExtractionProposal,
+) ([]models.MemoryEntityExtraction, error) {
+	query := fmt.Sprintf(`
+		WITH x AS (
+			INSERT INTO memory_entity_extractions
+				(session_id, source, extracted_name, extracted_type,
+				 description, evidence_text, matched_entity_id, match_confidence)
+			SELECT s.id, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''),
+			       e.id, CASE WHEN e.id IS NULL THEN NULL ELSE 1.0 END
+			FROM sessions s
+			LEFT JOIN entities e ON e.id = $7 AND e.campaign_id = s.campaign_id
+			WHERE s.id = $1
+			ON CONFLICT (session_id, lower(extracted_name))
+				WHERE session_memory_id IS NULL
+				DO NOTHING
+			RETURNING *
+		)
+		SELECT %s
+		FROM x
+		LEFT JOIN entities me ON me.id = x.matched_entity_id`,
+		memoryEntityExtractionColumns)
+
+	extractions := []models.MemoryEntityExtraction{}
+	for _, p := range proposals {
+		x, err := scanMemoryEntityExtraction(db.QueryRow(ctx, query,
+			sessionID, string(source), p.Name, string(p.EntityType),
+			p.Description, p.Evidence, p.MatchedEntityID,
+		))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to create entity extraction: %w", err)
+		}
+		extractions = append(extractions, *x)
+	}
+
+	return extractions, nil
+}
+
+// GetMemoryEntityExtraction retrieves an entity extraction by ID.
+// Returns pgx.ErrNoRows (unwrapped) if the extraction does not exist.
+func (db *DB) GetMemoryEntityExtraction(ctx context.Context, id int64) (*models.MemoryEntityExtraction, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM memory_entity_extractions x
+		LEFT JOIN entities me ON me.id = x.matched_entity_id
+		WHERE x.id = $1`, memoryEntityExtractionColumns)
+
+	x, err := scanMemoryEntityExtraction(db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get entity extraction: %w", err)
+	}
+
+	return x, nil
+}
+
+// ListMemoryEntityExtractions retrieves the entity extractions of a
+// session in the order they were detected. An empty status lists
+// extractions of every status.
+func (db *DB) ListMemoryEntityExtractions(
+	ctx context.Context,
+	sessionID int64,
+	status models.EntityExtractionStatus,
+) ([]models.MemoryEntityExtraction, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM memory_entity_extractions x
+		LEFT JOIN entities me ON me.id = x.matched_entity_id
+		WHERE x.session_id = $1
+		  AND ($2 = '' OR x.status = $2)
+		ORDER BY x.created_at ASC, x.id ASC`, memoryEntityExtractionColumns)
+
+	rows, err := db.Query(ctx, query, sessionID, string(status))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list entity extractions: %w", err)
+	}
+	defer rows.Close()
+
+	extractions := []models.MemoryEntityExtraction{}
+	for rows.Next() {
+		x, err := scanMemoryEntityExtraction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan entity extraction: %w", err)
+		}
+		extractions = append(extractions, *x)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating entity extractions: %w", err)
+	}
+
+	return extractions, nil
+}
+
+// ResolveMemoryEntityExtraction moves an entity extraction from status
+// from to status to, recording the entity created for it, if any.
+// Returns pgx.ErrNoRows (unwrapped) if the extraction does not exist
+// or is no longer in status from.
+func (db *DB) ResolveMemoryEntityExtraction(
+	ctx context.Context,
+	id int64,
+	from, to models.EntityExtractionStatus,
+	entityID *int64,
+) (*models.MemoryEntityExtraction, error) {
+	query := fmt.Sprintf(`
+		WITH x AS (
+			UPDATE memory_entity_extractions
+			SET status = $3, entity_id = $4, updated_at = NOW()
+			WHERE id = $1 AND status = $2
+			RETURNING *
+		)
+		SELECT %s
+		FROM x
+		LEFT JOIN entities me ON me.id = x.matched_entity_id`,
+		memoryEntityExtractionColumns)
+
+	x, err := scanMemoryEntityExtraction(db.QueryRow(ctx, query,
+		id, string(from), string(to), entityID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to resolve entity extraction: %w", err)
+	}
+
+	return x, nil
+}
@@ -0,0 +1,107 @@
+//go:build integration
+
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestIntegration_MemoryEntityExtractions(t *testing.T) {
+	db := setupIntegrationDB(t)
+	campaignID, entityID := createTestCampaign(t, db)
+	ctx := context.Background()
+
+	session, err := db.CreateSession(ctx, campaignID, models.CreateSessionRequest{})
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+
+	created, err := db.CreateMemoryEntityExtractions(ctx, session.ID,
+		models.EntityExtractionSourceChat, []models.EntityExtractionProposal{
+			{Name: "Brother Aldric", EntityType: models.EntityTypeNPC, Description: "A wary monk."},
+			{Name: "Existing", EntityType: models.EntityTypeNPC, Description: "Now missing an eye.",
+				MatchedEntityID: &entityID},
+		})
+	if err != nil {
+		t.Fatalf("failed to create extractions: %v", err)
+	}
+	if len(created) != 2 {
+		t.Fatalf("expected 2 extractions, got %d", len(created))
+	}
+	if created[0].Status != models.EntityExtractionStatusPending || created[0].MatchedEntityID != nil {
+		t.Errorf("unexpected new entity extraction: %+v", created[0])
+	}
+	if created[1].MatchedEntityID == nil || *created[1].MatchedEntityID != entityID ||
+		created[1].MatchedEntityName == nil {
+		t.Errorf("expected update extraction to match entity %d: %+v", entityID, created[1])
+	}
+
+	// A name already proposed during the session is not queued again.
+	again, err := db.CreateMemoryEntityExtractions(ctx, session.ID,
+		models.EntityExtractionSourcePlayNotes, []models.EntityExtractionProposal{
+			{Name: "brother aldric", EntityType: models.EntityTypeNPC},
+		})
+	if err != nil {
+		t.Fatalf("failed to create extractions: %v", err)
+	}
+	if len(again) != 0 {
+		t.Errorf("expected duplicate name to be skipped, got %+v", again)
+	}
+
+	draft, err := db.CreateEntity(ctx, campaignID, models.CreateEntityRequest{
+		EntityType: models.EntityTypeNPC, Name: "Brother Aldric",
+	})
+	if err != nil {
+		t.Fatalf("failed to create entity: %v", err)
+	}
+	deferred, err := db.ResolveMemoryEntityExtraction(ctx, created[0].ID,
+		models.EntityExtractionStatusPending, models.EntityExtractionStatusDeferred, &draft.ID)
+	if err != nil {
+		t.Fatalf("failed to defer extraction: %v", err)
+	}
+	if deferred.EntityID == nil || *deferred.EntityID != draft.ID {
+		t.Errorf("expected deferred extraction to record entity %d: %+v", draft.ID, deferred)
+	}
+
+	// Resolving from a status the extraction is no longer in fails.
+	_, err = db.ResolveMemoryEntityExtraction(ctx, created[0].ID,
+		models.EntityExtractionStatusPending, models.EntityExtractionStatusRejected, nil)
+	if !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("expected pgx.ErrNoRows for a stale status, got %v", err)
+	}
+
+	listed, err := db.ListMemoryEntityExtractions(ctx, session.ID, models.EntityExtractionStatusDeferred)
+	if err != nil {
+		t.Fatalf("failed to list extractions: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ID != created[0].ID {
+		t.Errorf("expected only the deferred extraction, got %+v", listed)
+	}
+
+	all, err := db.ListMemoryEntityExtractions(ctx, session.ID, "")
+	if err != nil {
+		t.Fatalf("failed to list extractions: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("expected 2 extractions, got %d", len(all))
+	}
+
+	if _, err := db.GetMemoryEntityExtraction(ctx, -1); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("expected pgx.ErrNoRows for a missing extraction, got %v", err)
+	}
+}
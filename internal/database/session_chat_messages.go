@@ -0,0 +1,128 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// sessionChatMessageColumns is the standard column list for session
+// chat message queries.
+const sessionChatMessageColumns = `id, session_id, campaign_id, role, content,
+	sort_order, created_at`
+
+// scanSessionChatMessage scans a single row into a
+// models.SessionChatMessage.
+func scanSessionChatMessage(row pgx.Row) (*models.SessionChatMessage, error) {
+	var m models.SessionChatMessage
+	err := row.Scan(
+		&m.ID, &m.SessionID, &m.CampaignID, &m.Role, &m.Content,
+		&m.SortOrder, &m.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// CreateSessionChatMessage appends a message to the chat of a session,
+// after every message already in it, and copies it to the session's
+// full chat log. The session row is locked while the next sort order
+// is taken so that concurrent messages are never given the same
+// position. Returns pgx.ErrNoRows (unwrapped) if the session does not
+// exist.
+func (db *DB) CreateSessionChatMessage(ctx context.Context, sessionID int64, role, content string) (*models.SessionChatMessage, error) {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	var campaignID int64
+	err = tx.QueryRow(ctx,
+		`SELECT campaign_id FROM sessions WHERE id = $1 FOR UPDATE`,
+		sessionID).Scan(&campaignID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to lock session: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO session_chat_messages
+			(session_id, campaign_id, role, content, sort_order)
+		VALUES ($1, $2, $3, $4, (
+			SELECT COALESCE(MAX(sort_order) + 1, 0)
+			FROM session_chat_messages
+			WHERE session_id = $1
+		))
+		RETURNING %s`, sessionChatMessageColumns)
+
+	m, err := scanSessionChatMessage(tx.QueryRow(ctx, query,
+		sessionID, campaignID, role, content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session chat message: %w", err)
+	}
+
+	// The log keeps every message verbatim, whatever the chat context
+	// is later compressed to.
+	_, err = tx.Exec(ctx, `
+		INSERT INTO session_chat_logs (session_id, message_index, role, content, timestamp)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (session_id, message_index) DO NOTHING`,
+		m.SessionID, m.SortOrder, m.Role, m.Content, m.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to log session chat message: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit session chat message: %w", err)
+	}
+
+	return m, nil
+}
+
+// ListSessionChatMessages retrieves the chat messages of a session in
+// conversation order.
+func (db *DB) ListSessionChatMessages(ctx context.Context, sessionID int64) ([]models.SessionChatMessage, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM session_chat_messages
+		WHERE session_id = $1
+		ORDER BY sort_order ASC, created_at ASC, id ASC`, sessionChatMessageColumns)
+
+	rows, err := db.Query(ctx, query, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query session chat messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := []models.SessionChatMessage{}
+	for rows.Next() {
+		m, err := scanSessionChatMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session chat message: %w", err)
+		}
+		messages = append(messages, *m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating session chat messages: %w", err)
+	}
+
+	return messages, nil
+}
@@ -0,0 +1,84 @@
+//go:build integration
+
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestIntegration_SessionChatMessages(t *testing.T) {
+	db := setupIntegrationDB(t)
+	campaignID, _ := createTestCampaign(t, db)
+	ctx := context.Background()
+
+	session, err := db.CreateSession(ctx, campaignID, models.CreateSessionRequest{})
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+
+	first, err := db.CreateSessionChatMessage(ctx, session.ID, models.ChatRoleUser, "Who runs the docks?")
+	if err != nil {
+		t.Fatalf("failed to create chat message: %v", err)
+	}
+	if first.SortOrder != 0 || first.CampaignID != campaignID || first.Role != models.ChatRoleUser {
+		t.Errorf("unexpected first message: %+v", first)
+	}
+
+	// Concurrent messages must still get distinct positions.
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := db.CreateSessionChatMessage(ctx, session.ID, models.ChatRoleAssistant, "Mick's crew."); err != nil {
+				t.Errorf("failed to create chat message: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	messages, err := db.ListSessionChatMessages(ctx, session.ID)
+	if err != nil {
+		t.Fatalf("failed to list chat messages: %v", err)
+	}
+	if len(messages) != 6 {
+		t.Fatalf("expected 6 messages, got %d", len(messages))
+	}
+	for i, m := range messages {
+		if m.SortOrder != i {
+			t.Errorf("message %d has sort order %d", i, m.SortOrder)
+		}
+	}
+
+	// Every message is copied to the full chat log.
+	var logged int
+	if err := db.QueryRow(ctx,
+		`SELECT COUNT(DISTINCT message_index) FROM session_chat_logs WHERE session_id = $1`,
+		session.ID).Scan(&logged); err != nil {
+		t.Fatalf("failed to count chat log messages: %v", err)
+	}
+	if logged != len(messages) {
+		t.Errorf("expected %d logged messages, got %d", len(messages), logged)
+	}
+
+	_, err = db.CreateSessionChatMessage(ctx, -1, models.ChatRoleUser, "Hello?")
+	if !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("expected pgx.ErrNoRows for a missing session, got %v", err)
+	}
+}
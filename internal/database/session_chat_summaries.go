@@ -0,0 +1,82 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// sessionChatSummaryColumns is the standard column list for session
+// chat summary queries.
+const sessionChatSummaryColumns = `id, session_id, summary, through_sort_order,
+	message_count, created_at`
+
+// scanSessionChatSummary scans a single row into a
+// models.SessionChatSummary.
+func scanSessionChatSummary(row pgx.Row) (*models.SessionChatSummary, error) {
+	var s models.SessionChatSummary
+	err := row.Scan(
+		&s.ID, &s.SessionID, &s.Summary, &s.ThroughSortOrder,
+		&s.MessageCount, &s.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// CreateSessionChatSummary saves a rolling summary of a session's
+// chat. A summary through the same message already saved, for example
+// by a concurrent compression, is kept and returned instead.
+func (db *DB) CreateSessionChatSummary(ctx context.Context, sessionID int64, summary string, throughSortOrder, messageCount int) (*models.SessionChatSummary, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO session_chat_summaries
+			(session_id, summary, through_sort_order, message_count)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (session_id, through_sort_order)
+			DO UPDATE SET session_id = EXCLUDED.session_id
+		RETURNING %s`, sessionChatSummaryColumns)
+
+	s, err := scanSessionChatSummary(db.QueryRow(ctx, query,
+		sessionID, summary, throughSortOrder, messageCount))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session chat summary: %w", err)
+	}
+
+	return s, nil
+}
+
+// GetLatestSessionChatSummary retrieves the summary of a session's chat
+// that covers the most messages. Returns pgx.ErrNoRows (unwrapped) if
+// the chat has not been summarized.
+func (db *DB) GetLatestSessionChatSummary(ctx context.Context, sessionID int64) (*models.SessionChatSummary, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM session_chat_summaries
+		WHERE session_id = $1
+		ORDER BY through_sort_order DESC
+		LIMIT 1`, sessionChatSummaryColumns)
+
+	s, err := scanSessionChatSummary(db.QueryRow(ctx, query, sessionID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get session chat summary: %w", err)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,61 @@
+//go:build integration
+
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestIntegration_SessionChatSummaries(t *testing.T) {
+	db := setupIntegrationDB(t)
+	campaignID, _ := createTestCampaign(t, db)
+	ctx := context.Background()
+
+	session, err := db.CreateSession(ctx, campaignID, models.CreateSessionRequest{})
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+
+	if _, err := db.GetLatestSessionChatSummary(ctx, session.ID); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("expected pgx.ErrNoRows before any summary, got %v", err)
+	}
+
+	if _, err := db.CreateSessionChatSummary(ctx, session.ID, "First turns.", 7, 8); err != nil {
+		t.Fatalf("failed to create summary: %v", err)
+	}
+	if _, err := db.CreateSessionChatSummary(ctx, session.ID, "More turns.", 15, 16); err != nil {
+		t.Fatalf("failed to create summary: %v", err)
+	}
+
+	// A concurrent compression of the same turns keeps the first summary.
+	dup, err := db.CreateSessionChatSummary(ctx, session.ID, "Duplicate.", 15, 16)
+	if err != nil {
+		t.Fatalf("failed to create duplicate summary: %v", err)
+	}
+	if dup.Summary != "More turns." {
+		t.Errorf("expected the existing summary to be kept, got %q", dup.Summary)
+	}
+
+	latest, err := db.GetLatestSessionChatSummary(ctx, session.ID)
+	if err != nil {
+		t.Fatalf("failed to get latest summary: %v", err)
+	}
+	if latest.ThroughSortOrder != 15 || latest.MessageCount != 16 {
+		t.Errorf("unexpected latest summary: %+v", latest)
+	}
+}
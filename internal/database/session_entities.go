@@ -0,0 +1,251 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// sessionEntityColumns is the list of columns selected for a
+// session-entity link and its joined entity, in scan order.
+const sessionEntityColumns = `
+        se.id, se.session_id, se.entity_id, se.mention_type,
+        COALESCE(se.role, 'appeared'), se.notes, se.created_at,
+        e.id, e.campaign_id, e.entity_type, e.name, e.description,
+        e.attributes, e.tags, e.gm_notes, e.discovered_session,
+        e.source_document, e.source_confidence, e.version,
+        e.created_at, e.updated_at`
+
+// scanSessionEntity scans a row of sessionEntityColumns.
+func scanSessionEntity(row pgx.Row) (*models.SessionEntity, error) {
+	var se models.SessionEntity
+	var e models.Entity
+	err := row.Scan(
+		&se.ID, &se.SessionID, &se.EntityID, &se.MentionType,
+		&se.Role, &se.Notes, &se.CreatedAt,
+		&e.ID, &e.CampaignID, &e.EntityType, &e.Name, &e.Description,
+		&e.Attributes, &e.Tags, &e.GMNotes, &e.DiscoveredSession,
+		&e.SourceDocument, &e.SourceConfidence, &e.Version,
+		&e.CreatedAt, &e.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	se.Entity = &e
+	return &se, nil
+}
+
+// ListSessionEntities retrieves all entity links for a session.
+func (db *DB) ListSessionEntities(ctx context.Context, sessionID int64) ([]models.SessionEntity, error) {
+	query := fmt.Sprintf(`
+        SELECT %s
+        FROM session_entities se
+        JOIN entities e ON se.entity_id = e.id
+        WHERE se.session_id = $1
+        ORDER BY se.mention_type, e.name ASC`, sessionEntityColumns)
+
+	rows, err := db.Query(ctx, query, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query session entities: %w", err)
+	}
+	defer rows.Close()
+
+	var links []models.SessionEntity
+	for rows.Next() {
+		se, err := scanSessionEntity(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session entity: %w", err)
+		}
+		links = append(links, *se)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating session entities: %w", err)
+	}
+
+	return links, nil
+}
+
+// GetSessionEntity retrieves a session-entity link by ID.
+func (db *DB) GetSessionEntity(ctx context.Context, id int64) (*models.SessionEntity, error) {
+	query := fmt.Sprintf(`
+        SELECT %s
+        FROM session_entities se
+        JOIN entities e ON se.entity_id = e.id
+        WHERE se.id = $1`, sessionEntityColumns)
+
+	se, err := scanSessionEntity(db.QueryRow(ctx, query, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session entity: %w", err)
+	}
+
+	return se, nil
+}
+
+// CreateSessionEntity creates a new link between a session and an entity.
+func (db *DB) CreateSessionEntity(ctx context.Context, sessionID int64, req models.CreateSessionEntityRequest) (*models.SessionEntity, error) {
+	mentionType := models.SessionEntityMentionLinked
+	if req.MentionType != nil {
+		mentionType = *req.MentionType
+	}
+
+	role := "appeared"
+	if req.Role != nil {
+		role = *req.Role
+	}
+
+	query := `
+        INSERT INTO session_entities (session_id, entity_id, mention_type, role, notes)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id, session_id, entity_id, mention_type, role, notes, created_at`
+
+	var se models.SessionEntity
+	err := db.QueryRow(ctx, query, sessionID, req.EntityID, mentionType, role, req.Notes).Scan(
+		&se.ID, &se.SessionID, &se.EntityID, &se.MentionType, &se.Role, &se.Notes, &se.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session entity: %w", err)
+	}
+
+	return &se, nil
+}
+
+// UpdateSessionEntity updates an existing session-entity link.
+func (db *DB) UpdateSessionEntity(ctx context.Context, id int64, req models.UpdateSessionEntityRequest) (*models.SessionEntity, error) {
+	// First get the existing link
+	existing, err := db.GetSessionEntity(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply updates
+	mentionType := existing.MentionType
+	if req.MentionType != nil {
+		mentionType = *req.MentionType
+	}
+	role := existing.Role
+	if req.Role != nil {
+		role = *req.Role
+	}
+	notes := existing.Notes
+	if req.Notes != nil {
+		notes = req.Notes
+	}
+
+	query := `
+        UPDATE session_entities
+        SET mention_type = $2, role = $3, notes = $4
+        WHERE id = $1
+        RETURNING id, session_id, entity_id, mention_type, role, notes, created_at`
+
+	var se models.SessionEntity
+	err = db.QueryRow(ctx, query, id, mentionType, role, notes).Scan(
+		&se.ID, &se.SessionID, &se.EntityID, &se.MentionType, &se.Role, &se.Notes, &se.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update session entity: %w", err)
+	}
+
+	return &se, nil
+}
+
+// DeleteSessionEntity deletes a session-entity link by ID.
+func (db *DB) DeleteSessionEntity(ctx context.Context, id int64) error {
+	query := `DELETE FROM session_entities WHERE id = $1`
+	result, err := db.Pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete session entity: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("session entity not found")
+	}
+
+	return nil
+}
+
+// AddSessionEntityMentions links the given entities to a session as
+// mentioned. Entities that are already linked keep their link, and
+// entities outside the session's campaign are ignored. Returns the
+// number of links added.
+func (db *DB) AddSessionEntityMentions(ctx context.Context, sessionID int64, entityIDs []int64) (int64, error) {
+	if len(entityIDs) == 0 {
+		return 0, nil
+	}
+
+	query := `
+        INSERT INTO session_entities (session_id, entity_id, mention_type)
+        SELECT s.id, e.id, 'mentioned'
+        FROM sessions s
+        JOIN entities e ON e.campaign_id = s.campaign_id
+        WHERE s.id = $1 AND e.id = ANY($2)
+        ON CONFLICT (session_id, entity_id) DO NOTHING`
+
+	result, err := db.Pool.Exec(ctx, query, sessionID, entityIDs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add session entity mentions: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// ListEntitySessions retrieves the sessions an entity is linked to,
+// most recent first: by session number or, for unnumbered sessions,
+// by actual or planned date. With playedOnly only completed sessions
+// are returned, so the first is the last one the entity was seen in.
+func (db *DB) ListEntitySessions(ctx context.Context, entityID int64, playedOnly bool) ([]models.EntitySessionAppearance, error) {
+	query := `
+        SELECT s.id, s.campaign_id, s.chapter_id, s.title, s.session_number, s.planned_date, s.actual_date,
+               s.status, s.stage, s.prep_notes, s.actual_notes, s.play_notes, s.created_at, s.updated_at,
+               se.id, se.mention_type, COALESCE(se.role, 'appeared'), se.notes
+        FROM session_entities se
+        JOIN sessions s ON s.id = se.session_id
+        WHERE se.entity_id = $1
+          AND (NOT $2 OR s.status = 'COMPLETED')
+        ORDER BY s.session_number DESC NULLS LAST,
+                 COALESCE(s.actual_date, s.planned_date) DESC NULLS LAST,
+                 s.created_at DESC`
+
+	rows, err := db.Query(ctx, query, entityID, playedOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query entity sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var appearances []models.EntitySessionAppearance
+	for rows.Next() {
+		var a models.EntitySessionAppearance
+		var stage *string
+		s := &a.Session
+		err := rows.Scan(
+			&s.ID, &s.CampaignID, &s.ChapterID, &s.Title, &s.SessionNumber, &s.PlannedDate, &s.ActualDate,
+			&s.Status, &stage, &s.PrepNotes, &s.ActualNotes, &s.PlayNotes, &s.CreatedAt, &s.UpdatedAt,
+			&a.LinkID, &a.MentionType, &a.Role, &a.Notes,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan entity session: %w", err)
+		}
+		if stage != nil {
+			s.Stage = models.SessionStage(*stage)
+		}
+		appearances = append(appearances, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating entity sessions: %w", err)
+	}
+
+	return appearances, nil
+}
@@ -0,0 +1,103 @@
+//go:build integration
+
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+func TestIntegration_SessionEntities(t *testing.T) {
+	db := setupIntegrationDB(t)
+	campaignID, entityID := createTestCampaign(t, db)
+	_, otherEntityID := createTestCampaign(t, db)
+	ctx := context.Background()
+
+	create := func(number int, status models.SessionStatus) *models.Session {
+		t.Helper()
+		s, err := db.CreateSession(ctx, campaignID, models.CreateSessionRequest{SessionNumber: &number})
+		if err != nil {
+			t.Fatalf("failed to create session %d: %v", number, err)
+		}
+		if _, err := db.UpdateSession(ctx, s.ID, models.UpdateSessionRequest{Status: &status}); err != nil {
+			t.Fatalf("failed to set status of session %d: %v", number, err)
+		}
+		return s
+	}
+	first := create(1, models.SessionStatusCompleted)
+	second := create(2, models.SessionStatusCompleted)
+	third := create(3, models.SessionStatusPlanned)
+
+	featured := models.SessionEntityMentionFeatured
+	link, err := db.CreateSessionEntity(ctx, second.ID, models.CreateSessionEntityRequest{
+		EntityID:    entityID,
+		MentionType: &featured,
+	})
+	if err != nil {
+		t.Fatalf("failed to link entity: %v", err)
+	}
+	if link.Role != "appeared" {
+		t.Errorf("expected default role appeared, got %q", link.Role)
+	}
+
+	for _, s := range []*models.Session{first, second, third} {
+		if _, err := db.AddSessionEntityMentions(ctx, s.ID, []int64{entityID, otherEntityID}); err != nil {
+			t.Fatalf("failed to add mentions to session %d: %v", s.ID, err)
+		}
+	}
+
+	links, err := db.ListSessionEntities(ctx, second.ID)
+	if err != nil {
+		t.Fatalf("failed to list session entities: %v", err)
+	}
+	if len(links) != 1 {
+		t.Fatalf("expected 1 link (other campaign's entity ignored), got %d", len(links))
+	}
+	if links[0].MentionType != models.SessionEntityMentionFeatured {
+		t.Errorf("expected mention to keep the featured link, got %q", links[0].MentionType)
+	}
+
+	all, err := db.ListEntitySessions(ctx, entityID, false)
+	if err != nil {
+		t.Fatalf("failed to list entity sessions: %v", err)
+	}
+	if len(all) != 3 || all[0].Session.ID != third.ID {
+		t.Errorf("expected 3 sessions, newest first, got %+v", all)
+	}
+
+	played, err := db.ListEntitySessions(ctx, entityID, true)
+	if err != nil {
+		t.Fatalf("failed to list played entity sessions: %v", err)
+	}
+	if len(played) != 2 || played[0].Session.ID != second.ID {
+		t.Errorf("expected session 2 to be the last played, got %+v", played)
+	}
+
+	major := "major"
+	updated, err := db.UpdateSessionEntity(ctx, link.ID, models.UpdateSessionEntityRequest{Role: &major})
+	if err != nil {
+		t.Fatalf("failed to update link: %v", err)
+	}
+	if updated.Role != "major" || updated.MentionType != models.SessionEntityMentionFeatured {
+		t.Errorf("expected role major and featured kept, got %q %q", updated.Role, updated.MentionType)
+	}
+
+	if err := db.DeleteSessionEntity(ctx, link.ID); err != nil {
+		t.Fatalf("failed to delete link: %v", err)
+	}
+	if err := db.DeleteSessionEntity(ctx, link.ID); err == nil {
+		t.Error("expected an error deleting a deleted link")
+	}
+}
@@ -0,0 +1,161 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// sessionMemoryColumns is the standard column list for session memory
+// queries.
+const sessionMemoryColumns = `id, session_id, memory_type, title, content,
+	scene_index, entities_mentioned, COALESCE(importance, 5),
+	COALESCE(is_player_visible, false), COALESCE(gm_edited, false),
+	created_at, updated_at`
+
+// scanSessionMemory scans a single row into a models.SessionMemory.
+func scanSessionMemory(row pgx.Row) (*models.SessionMemory, error) {
+	var m models.SessionMemory
+	err := row.Scan(
+		&m.ID, &m.SessionID, &m.MemoryType, &m.Title, &m.Content,
+		&m.SceneIndex, &m.EntitiesMentioned, &m.Importance,
+		&m.IsPlayerVisible, &m.GMEdited,
+		&m.CreatedAt, &m.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if m.EntitiesMentioned == nil {
+		m.EntitiesMentioned = []int64{}
+	}
+	return &m, nil
+}
+
+// CreateSessionMemory inserts a new memory for a session. Mentioned
+// entities that are not in the session's campaign are dropped.
+// gmEdited records that the GM changed the AI-generated content before
+// saving it.
+func (db *DB) CreateSessionMemory(ctx context.Context, sessionID int64, req models.CreateSessionMemoryRequest, gmEdited bool) (*models.SessionMemory, error) {
+	entityIDs := req.EntitiesMentioned
+	if entityIDs == nil {
+		entityIDs = []int64{}
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO session_memories
+			(session_id, memory_type, title, content, scene_index,
+			 entities_mentioned, importance, is_player_visible, gm_edited)
+		VALUES ($1, $2, $3, $4, $5,
+			ARRAY(
+				SELECT e.id
+				FROM entities e
+				JOIN sessions s ON s.campaign_id = e.campaign_id
+				WHERE s.id = $1 AND e.id = ANY($6::BIGINT[])
+				ORDER BY e.id
+			),
+			COALESCE($7, 5), $8, $9)
+		RETURNING %s`, sessionMemoryColumns)
+
+	m, err := scanSessionMemory(db.QueryRow(ctx, query,
+		sessionID, req.MemoryType, req.Title, req.Content, req.SceneIndex,
+		entityIDs, req.Importance, req.IsPlayerVisible, gmEdited,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session memory: %w", err)
+	}
+
+	return m, nil
+}
+
+// ListSessionMemories retrieves the memories of a session, the summary
+// first, then scene memories in scene order, then everything else by
+// importance.
+func (db *DB) ListSessionMemories(ctx context.Context, sessionID int64) ([]models.SessionMemory, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM session_memories
+		WHERE session_id = $1
+		ORDER BY memory_type <> 'summary',
+		         scene_index ASC NULLS LAST,
+		         importance DESC NULLS LAST,
+		         created_at ASC, id ASC`, sessionMemoryColumns)
+
+	rows, err := db.Query(ctx, query, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list session memories: %w", err)
+	}
+	defer rows.Close()
+
+	memories := []models.SessionMemory{}
+	for rows.Next() {
+		m, err := scanSessionMemory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session memory: %w", err)
+		}
+		memories = append(memories, *m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating session memories: %w", err)
+	}
+
+	return memories, nil
+}
+
+// ListRecentSessionSummaries retrieves the summaries of up to limit of
+// a campaign's most recently played sessions, newest first. A session's
+// summary memory is preferred over its wrap-up notes; sessions with
+// neither are skipped.
+func (db *DB) ListRecentSessionSummaries(ctx context.Context, campaignID int64, limit int) ([]models.SessionSummary, error) {
+	query := `
+		SELECT s.id, s.session_number, s.title,
+		       COALESCE(NULLIF(TRIM(sm.content), ''), TRIM(s.actual_notes))
+		FROM sessions s
+		LEFT JOIN LATERAL (
+			SELECT content
+			FROM session_memories
+			WHERE session_id = s.id AND memory_type = 'summary'
+			ORDER BY importance DESC NULLS LAST, created_at DESC
+			LIMIT 1
+		) sm ON true
+		WHERE s.campaign_id = $1
+		  AND (s.stage IN ('wrap_up', 'completed') OR s.status = 'COMPLETED')
+		  AND COALESCE(NULLIF(TRIM(sm.content), ''),
+		               NULLIF(TRIM(s.actual_notes), '')) IS NOT NULL
+		ORDER BY s.session_number DESC NULLS LAST,
+		         s.actual_date DESC NULLS LAST, s.created_at DESC
+		LIMIT $2`
+
+	rows, err := db.Query(ctx, query, campaignID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recent session summaries: %w", err)
+	}
+	defer rows.Close()
+
+	summaries := []models.SessionSummary{}
+	for rows.Next() {
+		var s models.SessionSummary
+		if err := rows.Scan(&s.SessionID, &s.SessionNumber, &s.Title, &s.Summary); err != nil {
+			return nil, fmt.Errorf("failed to scan session summary: %w", err)
+		}
+		summaries = append(summaries, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating session summaries: %w", err)
+	}
+
+	return summaries, nil
+}
@@ -0,0 +1,135 @@
+//go:build integration
+
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+func TestIntegration_SessionMemories(t *testing.T) {
+	db := setupIntegrationDB(t)
+	campaignID, entityID := createTestCampaign(t, db)
+	_, otherEntityID := createTestCampaign(t, db)
+	ctx := context.Background()
+
+	session, err := db.CreateSession(ctx, campaignID, models.CreateSessionRequest{})
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+
+	title := "Ambush"
+	scene := 1
+	importance := 8
+	created, err := db.CreateSessionMemory(ctx, session.ID, models.CreateSessionMemoryRequest{
+		MemoryType:        models.SessionMemoryTypeScene,
+		Title:             &title,
+		Content:           "Cultists ambushed the party.",
+		SceneIndex:        &scene,
+		EntitiesMentioned: []int64{entityID, otherEntityID},
+		Importance:        &importance,
+	}, true)
+	if err != nil {
+		t.Fatalf("failed to create session memory: %v", err)
+	}
+	if len(created.EntitiesMentioned) != 1 || created.EntitiesMentioned[0] != entityID {
+		t.Errorf("expected only entity %d of the session's campaign to be linked, got %v",
+			entityID, created.EntitiesMentioned)
+	}
+	if !created.GMEdited || created.Importance != 8 || created.IsPlayerVisible {
+		t.Errorf("unexpected session memory: %+v", created)
+	}
+
+	summary, err := db.CreateSessionMemory(ctx, session.ID, models.CreateSessionMemoryRequest{
+		MemoryType:      models.SessionMemoryTypeSummary,
+		Content:         "The party reached the docks.",
+		IsPlayerVisible: true,
+	}, false)
+	if err != nil {
+		t.Fatalf("failed to create summary memory: %v", err)
+	}
+	if summary.Importance != 5 || len(summary.EntitiesMentioned) != 0 {
+		t.Errorf("expected default importance and no entities, got %+v", summary)
+	}
+
+	memories, err := db.ListSessionMemories(ctx, session.ID)
+	if err != nil {
+		t.Fatalf("failed to list session memories: %v", err)
+	}
+	if len(memories) != 2 || memories[0].ID != summary.ID || memories[1].ID != created.ID {
+		t.Errorf("expected the summary first, got %+v", memories)
+	}
+}
+
+func TestIntegration_ListRecentSessionSummaries(t *testing.T) {
+	db := setupIntegrationDB(t)
+	campaignID, _ := createTestCampaign(t, db)
+	ctx := context.Background()
+
+	wrapUp := models.SessionStageWrapUp
+	notes := "  The party fled the burning mill.  "
+	createSession := func(number int, stage *models.SessionStage, actualNotes *string) int64 {
+		t.Helper()
+		session, err := db.CreateSession(ctx, campaignID, models.CreateSessionRequest{
+			SessionNumber: &number,
+			Stage:         stage,
+		})
+		if err != nil {
+			t.Fatalf("failed to create session: %v", err)
+		}
+		if actualNotes != nil {
+			if _, err := db.UpdateSession(ctx, session.ID, models.UpdateSessionRequest{
+				ActualNotes: actualNotes,
+			}); err != nil {
+				t.Fatalf("failed to update session: %v", err)
+			}
+		}
+		return session.ID
+	}
+
+	notesOnly := createSession(1, &wrapUp, &notes)
+	summarised := createSession(2, &wrapUp, &notes)
+	createSession(3, &wrapUp, nil) // nothing to summarise
+	createSession(4, nil, &notes)  // not played yet
+
+	if _, err := db.CreateSessionMemory(ctx, summarised, models.CreateSessionMemoryRequest{
+		MemoryType: models.SessionMemoryTypeSummary,
+		Content:    "The party escaped with the ledger.",
+	}, false); err != nil {
+		t.Fatalf("failed to create summary memory: %v", err)
+	}
+
+	summaries, err := db.ListRecentSessionSummaries(ctx, campaignID, 5)
+	if err != nil {
+		t.Fatalf("failed to list recent session summaries: %v", err)
+	}
+	if len(summaries) != 2 {
+		t.Fatalf("expected 2 session summaries, got %+v", summaries)
+	}
+	if summaries[0].SessionID != summarised || summaries[0].Summary != "The party escaped with the ledger." {
+		t.Errorf("expected the summary memory of session 2 first, got %+v", summaries[0])
+	}
+	if summaries[1].SessionID != notesOnly || summaries[1].Summary != "The party fled the burning mill." {
+		t.Errorf("expected the trimmed notes of session 1 second, got %+v", summaries[1])
+	}
+
+	limited, err := db.ListRecentSessionSummaries(ctx, campaignID, 1)
+	if err != nil {
+		t.Fatalf("failed to list recent session summaries: %v", err)
+	}
+	if len(limited) != 1 || limited[0].SessionID != summarised {
+		t.Errorf("expected only the most recent summary, got %+v", limited)
+	}
+}
@@ -0,0 +1,71 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetSessionRecap retrieves the cached recap of a session for
+// audience. SessionNumber and Title are not set. Returns pgx.ErrNoRows
+// (unwrapped) if no recap is cached.
+func (db *DB) GetSessionRecap(ctx context.Context, sessionID int64, audience models.RecapAudience) (*models.SessionRecap, error) {
+	query := `
+		SELECT session_id, audience, recap, source_hash, updated_at
+		FROM session_recaps
+		WHERE session_id = $1 AND audience = $2`
+
+	var r models.SessionRecap
+	err := db.QueryRow(ctx, query, sessionID, string(audience)).Scan(
+		&r.SessionID, &r.Audience, &r.Recap, &r.SourceHash, &r.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get session recap: %w", err)
+	}
+
+	return &r, nil
+}
+
+// SaveSessionRecap caches the recap of a session for audience,
+// replacing any earlier one. SessionNumber and Title are not set.
+func (db *DB) SaveSessionRecap(
+	ctx context.Context,
+	sessionID int64,
+	audience models.RecapAudience,
+	recap, sourceHash string,
+) (*models.SessionRecap, error) {
+	query := `
+		INSERT INTO session_recaps (session_id, audience, recap, source_hash)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (session_id, audience) DO UPDATE
+		SET recap = EXCLUDED.recap,
+		    source_hash = EXCLUDED.source_hash,
+		    updated_at = NOW()
+		RETURNING session_id, audience, recap, source_hash, updated_at`
+
+	var r models.SessionRecap
+	err := db.QueryRow(ctx, query, sessionID, string(audience), recap, sourceHash).Scan(
+		&r.SessionID, &r.Audience, &r.Recap, &r.SourceHash, &r.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save session recap: %w", err)
+	}
+
+	return &r, nil
+}
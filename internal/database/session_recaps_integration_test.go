@@ -0,0 +1,91 @@
+//go:build integration
+
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestIntegration_GetPreviousSession(t *testing.T) {
+	db := setupIntegrationDB(t)
+	campaignID, _ := createTestCampaign(t, db)
+	ctx := context.Background()
+
+	create := func(number int) *models.Session {
+		t.Helper()
+		s, err := db.CreateSession(ctx, campaignID, models.CreateSessionRequest{SessionNumber: &number})
+		if err != nil {
+			t.Fatalf("failed to create session %d: %v", number, err)
+		}
+		return s
+	}
+	first := create(1)
+	second := create(2)
+	fourth := create(4)
+
+	skipped := models.SessionStatusSkipped
+	third := create(3)
+	if _, err := db.UpdateSession(ctx, third.ID, models.UpdateSessionRequest{Status: &skipped}); err != nil {
+		t.Fatalf("failed to skip session: %v", err)
+	}
+
+	previous, err := db.GetPreviousSession(ctx, fourth)
+	if err != nil {
+		t.Fatalf("failed to get previous session: %v", err)
+	}
+	if previous.ID != second.ID {
+		t.Errorf("expected session 2 before session 4 (skipping 3), got session %d", previous.ID)
+	}
+
+	if _, err := db.GetPreviousSession(ctx, first); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("expected pgx.ErrNoRows before the first session, got %v", err)
+	}
+}
+
+func TestIntegration_SessionRecaps(t *testing.T) {
+	db := setupIntegrationDB(t)
+	campaignID, _ := createTestCampaign(t, db)
+	ctx := context.Background()
+
+	session, err := db.CreateSession(ctx, campaignID, models.CreateSessionRequest{})
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+
+	if _, err := db.GetSessionRecap(ctx, session.ID, models.RecapAudienceGM); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("expected pgx.ErrNoRows before any recap, got %v", err)
+	}
+
+	if _, err := db.SaveSessionRecap(ctx, session.ID, models.RecapAudienceGM, "First.", "a"); err != nil {
+		t.Fatalf("failed to save recap: %v", err)
+	}
+	if _, err := db.SaveSessionRecap(ctx, session.ID, models.RecapAudienceGM, "Second.", "b"); err != nil {
+		t.Fatalf("failed to replace recap: %v", err)
+	}
+	if _, err := db.SaveSessionRecap(ctx, session.ID, models.RecapAudiencePlayer, "Players.", "c"); err != nil {
+		t.Fatalf("failed to save player recap: %v", err)
+	}
+
+	gm, err := db.GetSessionRecap(ctx, session.ID, models.RecapAudienceGM)
+	if err != nil {
+		t.Fatalf("failed to get recap: %v", err)
+	}
+	if gm.Recap != "Second." || gm.SourceHash != "b" {
+		t.Errorf("expected the replaced GM recap, got %+v", gm)
+	}
+}
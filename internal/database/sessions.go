@@ -126,6 +126,55 @@ func (db *DB) GetSession(ctx context.Context, id int64) (*models.Session, error)
 	return &s, nil
 }
 
+// GetPreviousSession retrieves the session played before session in
+// its campaign: the one with the next lower session number or, if
+// session has no number, the one with the latest earlier actual or
+// planned date. Sessions without either fall back to creation order.
+// Skipped sessions are passed over. Returns pgx.ErrNoRows (unwrapped)
+// when there is no previous session.
+func (db *DB) GetPreviousSession(ctx context.Context, session *models.Session) (*models.Session, error) {
+	date := session.ActualDate
+	if date == nil {
+		date = session.PlannedDate
+	}
+
+	query := `
+        SELECT id, campaign_id, chapter_id, title, session_number, planned_date, actual_date,
+               status, stage, prep_notes, actual_notes, play_notes, created_at, updated_at
+        FROM sessions
+        WHERE campaign_id = $1 AND id <> $2
+          AND COALESCE(status, '') <> 'SKIPPED'
+          AND CASE
+                WHEN $3::int IS NOT NULL THEN session_number < $3::int
+                WHEN $4::date IS NOT NULL THEN COALESCE(actual_date, planned_date) < $4::date
+                ELSE created_at < $5
+              END
+        ORDER BY session_number DESC NULLS LAST,
+                 COALESCE(actual_date, planned_date) DESC NULLS LAST,
+                 created_at DESC
+        LIMIT 1`
+
+	var s models.Session
+	var stage *string
+	err := db.QueryRow(ctx, query,
+		session.CampaignID, session.ID, session.SessionNumber, date, session.CreatedAt,
+	).Scan(
+		&s.ID, &s.CampaignID, &s.ChapterID, &s.Title, &s.SessionNumber, &s.PlannedDate, &s.ActualDate,
+		&s.Status, &stage, &s.PrepNotes, &s.ActualNotes, &s.PlayNotes, &s.CreatedAt, &s.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get previous session: %w", err)
+	}
+	if stage != nil {
+		s.Stage = models.SessionStage(*stage)
+	}
+
+	return &s, nil
+}
+
 // CreateSession creates a new session in a campaign.
 func (db *DB) CreateSession(ctx context.Context, campaignID int64, req models.CreateSessionRequest) (*models.Session, error) {
 	// Default status to PLANNED
@@ -12,6 +12,7 @@ package database
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"strings"
@@ -27,17 +28,18 @@ func (db *DB) GetUserSettings(ctx context.Context, userID int64) (*models.UserSe
         SELECT user_id, content_gen_service, content_gen_api_key,
                embedding_service, embedding_api_key,
                image_gen_service, image_gen_api_key,
-               created_at, updated_at
+               llm_routing, created_at, updated_at
         FROM user_settings
         WHERE user_id = $1`
 
 	var s models.UserSettings
 	var contentGenService, embeddingService, imageGenService *string
+	var routing []byte
 	err := db.QueryRow(ctx, query, userID).Scan(
 		&s.UserID, &contentGenService, &s.ContentGenAPIKey,
 		&embeddingService, &s.EmbeddingAPIKey,
 		&imageGenService, &s.ImageGenAPIKey,
-		&s.CreatedAt, &s.UpdatedAt,
+		&routing, &s.CreatedAt, &s.UpdatedAt,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -50,6 +52,7 @@ func (db *DB) GetUserSettings(ctx context.Context, userID int64) (*models.UserSe
 	s.ContentGenAPIKey = db.decryptKey(s.ContentGenAPIKey)
 	s.EmbeddingAPIKey = db.decryptKey(s.EmbeddingAPIKey)
 	s.ImageGenAPIKey = db.decryptKey(s.ImageGenAPIKey)
+	s.LLMRouting = db.decodeRouting(routing)
 
 	// Convert string pointers to LLMService pointers
 	if contentGenService != nil {
@@ -111,15 +114,19 @@ func (db *DB) UpdateUserSettings(ctx context.Context, userID int64, req models.U
 	if err != nil {
 		return nil, err
 	}
+	routing, err := db.encodeRouting(req.LLMRouting)
+	if err != nil {
+		return nil, err
+	}
 
 	// Use COALESCE to atomically merge: NULL in request preserves existing value
 	query := `
         INSERT INTO user_settings (
             user_id, content_gen_service, content_gen_api_key,
             embedding_service, embedding_api_key,
-            image_gen_service, image_gen_api_key
+            image_gen_service, image_gen_api_key, llm_routing
         )
-        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
         ON CONFLICT (user_id) DO UPDATE SET
             content_gen_service = COALESCE(EXCLUDED.content_gen_service, user_settings.content_gen_service),
             content_gen_api_key = COALESCE(EXCLUDED.content_gen_api_key, user_settings.content_gen_api_key),
@@ -127,23 +134,25 @@ func (db *DB) UpdateUserSettings(ctx context.Context, userID int64, req models.U
             embedding_api_key = COALESCE(EXCLUDED.embedding_api_key, user_settings.embedding_api_key),
             image_gen_service = COALESCE(EXCLUDED.image_gen_service, user_settings.image_gen_service),
             image_gen_api_key = COALESCE(EXCLUDED.image_gen_api_key, user_settings.image_gen_api_key),
+            llm_routing = COALESCE(EXCLUDED.llm_routing, user_settings.llm_routing),
             updated_at = NOW()
         RETURNING user_id, content_gen_service, content_gen_api_key,
                   embedding_service, embedding_api_key,
                   image_gen_service, image_gen_api_key,
-                  created_at, updated_at`
+                  llm_routing, created_at, updated_at`
 
 	var s models.UserSettings
 	var retContentGenService, retEmbeddingService, retImageGenService *string
+	var retRouting []byte
 	err = db.QueryRow(ctx, query,
 		userID, contentGenService, contentGenAPIKey,
 		embeddingService, embeddingAPIKey,
-		imageGenService, imageGenAPIKey,
+		imageGenService, imageGenAPIKey, routing,
 	).Scan(
 		&s.UserID, &retContentGenService, &s.ContentGenAPIKey,
 		&retEmbeddingService, &s.EmbeddingAPIKey,
 		&retImageGenService, &s.ImageGenAPIKey,
-		&s.CreatedAt, &s.UpdatedAt,
+		&retRouting, &s.CreatedAt, &s.UpdatedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update user settings: %w", err)
@@ -153,6 +162,7 @@ func (db *DB) UpdateUserSettings(ctx context.Context, userID int64, req models.U
 	s.ContentGenAPIKey = db.decryptKey(s.ContentGenAPIKey)
 	s.EmbeddingAPIKey = db.decryptKey(s.EmbeddingAPIKey)
 	s.ImageGenAPIKey = db.decryptKey(s.ImageGenAPIKey)
+	s.LLMRouting = db.decodeRouting(retRouting)
 
 	// Convert string pointers to LLMService pointers
 	if retContentGenService != nil {
@@ -171,6 +181,64 @@ func (db *DB) UpdateUserSettings(ctx context.Context, userID int64, req models.U
 	return &s, nil
 }
 
+// encodeRouting marshals LLM routing for storage, encrypting the API
+// key of every route. A nil routing encodes to nil so that COALESCE
+// preserves the stored value.
+func (db *DB) encodeRouting(routing *models.LLMRouting) ([]byte, error) {
+	if routing == nil {
+		return nil, nil
+	}
+
+	enc := models.LLMRouting{
+		Fallbacks:   make([]models.LLMRoute, len(routing.Fallbacks)),
+		AgentRoutes: make(map[string]models.LLMRoute, len(routing.AgentRoutes)),
+	}
+	for i, route := range routing.Fallbacks {
+		key, err := db.encryptKey(route.APIKey)
+		if err != nil {
+			return nil, err
+		}
+		route.APIKey = key
+		enc.Fallbacks[i] = route
+	}
+	for agent, route := range routing.AgentRoutes {
+		key, err := db.encryptKey(route.APIKey)
+		if err != nil {
+			return nil, err
+		}
+		route.APIKey = key
+		enc.AgentRoutes[agent] = route
+	}
+
+	data, err := json.Marshal(enc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal LLM routing: %w", err)
+	}
+	return data, nil
+}
+
+// decodeRouting unmarshals stored LLM routing and decrypts its API
+// keys. Unreadable routing is logged and treated as absent.
+func (db *DB) decodeRouting(data []byte) *models.LLMRouting {
+	if len(data) == 0 {
+		return nil
+	}
+
+	var routing models.LLMRouting
+	if err := json.Unmarshal(data, &routing); err != nil {
+		log.Printf("WARNING: failed to parse stored LLM routing: %v", err)
+		return nil
+	}
+	for i := range routing.Fallbacks {
+		routing.Fallbacks[i].APIKey = db.decryptKey(routing.Fallbacks[i].APIKey)
+	}
+	for agent, route := range routing.AgentRoutes {
+		route.APIKey = db.decryptKey(route.APIKey)
+		routing.AgentRoutes[agent] = route
+	}
+	return &routing
+}
+
 // encryptKey encrypts an API key if encryption is configured.
 // Returns the original value if Encryptor is nil or the key is nil/empty.
 func (db *DB) encryptKey(key *string) (*string, error) {
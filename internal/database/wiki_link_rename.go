@@ -29,8 +29,9 @@ func escapeLikePattern(s string) string {
 
 // PropagateEntityRename updates all wiki links throughout campaign content
 // when an entity is renamed. It replaces both [[Old Name]] and [[Old Name|
-// patterns with the new name. This function must be called within an
-// existing transaction.
+// patterns with the new name, recording a revision of each entity it
+// changes with the author in ctx. This function must be called within
+// an existing transaction.
 func PropagateEntityRename(ctx context.Context, tx pgx.Tx, campaignID int64, oldName, newName string) (int64, error) {
 	oldExact := "[[" + oldName + "]]"
 	newExact := "[[" + newName + "]]"
@@ -44,23 +45,38 @@ func PropagateEntityRename(ctx context.Context, tx pgx.Tx, campaignID int64, old
 
 	var totalUpdated int64
 
-	// 1. entities: description, gm_notes
-	result, err := tx.Exec(ctx, `
+	// 1. entities: description, gm_notes. Each entity changed gets a
+	// new version, recorded as a revision with the author in ctx.
+	rows, err := tx.Query(ctx, `
 		UPDATE entities SET
 			description = replace(replace(description, $2, $3), $4, $5),
-			gm_notes = replace(replace(gm_notes, $2, $3), $4, $5)
+			gm_notes = replace(replace(gm_notes, $2, $3), $4, $5),
+			version = version + 1
 		WHERE campaign_id = $1
 			AND (description LIKE $6 ESCAPE '\' OR description LIKE $7 ESCAPE '\'
-				OR gm_notes LIKE $6 ESCAPE '\' OR gm_notes LIKE $7 ESCAPE '\')`,
+				OR gm_notes LIKE $6 ESCAPE '\' OR gm_notes LIKE $7 ESCAPE '\')
+		RETURNING id, campaign_id, entity_type, name, description, attributes,
+		          tags, gm_notes, discovered_session, source_document,
+		          source_confidence, version, created_at, updated_at`,
 		campaignID, oldExact, newExact, oldPiped, newPiped, oldExactLike, oldPipedLike,
 	)
 	if err != nil {
 		return 0, fmt.Errorf("failed to update entities: %w", err)
 	}
-	totalUpdated += result.RowsAffected()
+	entities, err := scanEntities(rows)
+	rows.Close()
+	if err != nil {
+		return 0, fmt.Errorf("failed to update entities: %w", err)
+	}
+	for i := range entities {
+		if err := insertEntityRevision(ctx, tx, &entities[i], nil); err != nil {
+			return 0, err
+		}
+	}
+	totalUpdated += int64(len(entities))
 
 	// 2. chapters: overview
-	result, err = tx.Exec(ctx, `
+	result, err := tx.Exec(ctx, `
 		UPDATE chapters SET
 			overview = replace(replace(overview, $2, $3), $4, $5)
 		WHERE campaign_id = $1
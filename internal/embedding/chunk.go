@@ -0,0 +1,105 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package embedding
+
+import "strings"
+
+// chunkConfig holds the chunk size and overlap, in words, for one
+// indexed column.
+type chunkConfig struct {
+	size    int
+	overlap int
+}
+
+// defaultChunkConfig is used for columns without an explicit entry.
+var defaultChunkConfig = chunkConfig{size: 400, overlap: 50}
+
+// chunkConfigs mirrors the chunk_size/chunk_overlap settings passed to
+// pgedge_vectorizer in migration 001, keyed by "table.column", so that
+// both backends produce comparable chunks. Sizes are applied to words
+// rather than model tokens.
+var chunkConfigs = map[string]chunkConfig{
+	"campaigns.description":         {size: 200, overlap: 25},
+	"entities.name":                 {size: 100, overlap: 10},
+	"entities.description":          {size: 400, overlap: 50},
+	"chapters.overview":             {size: 300, overlap: 30},
+	"sessions.prep_notes":           {size: 500, overlap: 50},
+	"sessions.actual_notes":         {size: 500, overlap: 50},
+	"campaign_memories.content":     {size: 400, overlap: 50},
+	"scenes.description":            {size: 400, overlap: 50},
+	"scenes.gm_notes":               {size: 400, overlap: 50},
+	"session_chat_messages.content": {size: 200, overlap: 20},
+}
+
+// configFor returns the chunk configuration for a table column.
+func configFor(table, column string) chunkConfig {
+	if cfg, ok := chunkConfigs[table+"."+column]; ok {
+		return cfg
+	}
+	return defaultChunkConfig
+}
+
+// ChunkText splits text into chunks of at most size words. Paragraphs
+// are kept together where they fit; a paragraph longer than size is
+// split into overlapping word windows. Consecutive chunks share up to
+// overlap words of context. Empty or whitespace-only text yields no
+// chunks.
+func ChunkText(text string, size, overlap int) []string {
+	if size <= 0 {
+		size = defaultChunkConfig.size
+	}
+	if overlap < 0 || overlap >= size {
+		overlap = 0
+	}
+
+	var chunks []string
+	var current []string
+
+	flush := func() {
+		if len(current) == 0 {
+			return
+		}
+		chunks = append(chunks, strings.Join(current, " "))
+		if overlap > 0 && len(current) > overlap {
+			current = append([]string(nil), current[len(current)-overlap:]...)
+		} else {
+			current = nil
+		}
+	}
+
+	for _, para := range strings.Split(text, "\n\n") {
+		words := strings.Fields(para)
+		if len(words) == 0 {
+			continue
+		}
+
+		// Start a new chunk when the paragraph does not fit in the
+		// remaining space of the current one.
+		if len(current)+len(words) > size && len(current) > overlap {
+			flush()
+		}
+
+		for _, w := range words {
+			if len(current) >= size {
+				flush()
+			}
+			current = append(current, w)
+		}
+	}
+
+	// The trailing chunk is only emitted if it contains words beyond
+	// the overlap carried from the previous chunk.
+	if len(chunks) == 0 || len(current) > overlap {
+		flush()
+	}
+
+	return chunks
+}
@@ -0,0 +1,83 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package embedding
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/database"
+	"github.com/stretchr/testify/assert"
+)
+
+// words returns n space-separated words built from prefix.
+func words(prefix string, n int) string {
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = prefix + strings.Repeat("x", i%3)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestChunkText(t *testing.T) {
+	t.Run("empty text yields no chunks", func(t *testing.T) {
+		assert.Empty(t, ChunkText("", 10, 2))
+		assert.Empty(t, ChunkText("  \n\n  ", 10, 2))
+	})
+
+	t.Run("short text is a single chunk", func(t *testing.T) {
+		assert.Equal(t, []string{"one two three"}, ChunkText("one two  three", 10, 2))
+	})
+
+	t.Run("paragraphs that fit are kept together", func(t *testing.T) {
+		chunks := ChunkText("a b c\n\nd e f", 10, 2)
+		assert.Equal(t, []string{"a b c d e f"}, chunks)
+	})
+
+	t.Run("paragraph that does not fit starts a new chunk", func(t *testing.T) {
+		chunks := ChunkText("a b c d\n\ne f g h", 6, 1)
+		assert.Equal(t, []string{"a b c d", "d e f g h"}, chunks)
+	})
+
+	t.Run("long paragraph is split with overlap", func(t *testing.T) {
+		text := "w1 w2 w3 w4 w5 w6 w7 w8 w9 w10"
+		chunks := ChunkText(text, 4, 1)
+		assert.Equal(t, []string{
+			"w1 w2 w3 w4",
+			"w4 w5 w6 w7",
+			"w7 w8 w9 w10",
+		}, chunks)
+	})
+
+	t.Run("no trailing chunk of only overlap", func(t *testing.T) {
+		chunks := ChunkText("w1 w2 w3 w4", 4, 1)
+		assert.Equal(t, []string{"w1 w2 w3 w4"}, chunks)
+	})
+
+	t.Run("chunks never exceed size", func(t *testing.T) {
+		for _, chunk := range ChunkText(words("w", 1000), 100, 10) {
+			assert.LessOrEqual(t, len(strings.Fields(chunk)), 100)
+		}
+	})
+}
+
+func TestConfigFor(t *testing.T) {
+	assert.Equal(t, chunkConfig{size: 100, overlap: 10}, configFor("entities", "name"))
+	assert.Equal(t, defaultChunkConfig, configFor("unknown", "column"))
+}
+
+func TestContentHash(t *testing.T) {
+	src := database.EmbeddingSource{SourceName: "Name", Content: "content"}
+	a := contentHash(src)
+	assert.Equal(t, a, contentHash(src))
+	assert.NotEqual(t, a, contentHash(database.EmbeddingSource{SourceName: "Other", Content: "content"}))
+	assert.NotEqual(t, a, contentHash(database.EmbeddingSource{SourceName: "Name", Content: "changed"}))
+}
@@ -0,0 +1,152 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+// Package embedding maintains the Go-side embedding index used for
+// semantic search on PostgreSQL installations that have pgvector but
+// not the pgedge_vectorizer extension.
+package embedding
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+
+	"github.com/antonypegg/imagineer/internal/database"
+	"github.com/antonypegg/imagineer/internal/llm"
+)
+
+// maxEmbedBatch is the maximum number of chunks sent to the embedding
+// provider in a single request.
+const maxEmbedBatch = 64
+
+// Indexer chunks campaign content, embeds it with an llm.Embedder, and
+// stores the vectors in the content_embeddings table.
+type Indexer struct {
+	db       *database.DB
+	embedder llm.Embedder
+}
+
+// NewIndexer creates an Indexer that embeds content with embedder.
+func NewIndexer(db *database.DB, embedder llm.Embedder) *Indexer {
+	return &Indexer{
+		db:       db,
+		embedder: embedder,
+	}
+}
+
+// SyncResult summarises a SyncCampaign run.
+type SyncResult struct {
+	Indexed   int `json:"indexed"`
+	Unchanged int `json:"unchanged"`
+	Removed   int `json:"removed"`
+	Failed    int `json:"failed"`
+}
+
+// SyncCampaign brings the embedding index for a campaign up to date.
+// Sources whose content, name, or embedding model changed since they
+// were last indexed are re-embedded; sources that no longer exist (or
+// are now empty) have their chunks removed. A failure to embed one
+// source is logged and counted, and does not stop the sync.
+func (ix *Indexer) SyncCampaign(ctx context.Context, campaignID int64) (*SyncResult, error) {
+	sources, err := ix.db.ListEmbeddingSources(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	indexed, err := ix.db.ListIndexedSources(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]database.IndexedSource, len(indexed))
+	for _, s := range indexed {
+		existing[sourceKey(s.SourceTable, s.SourceID, s.SourceColumn)] = s
+	}
+
+	result := &SyncResult{}
+	model := ix.embedder.Model()
+
+	for _, src := range sources {
+		key := sourceKey(src.SourceTable, src.SourceID, src.SourceColumn)
+		prev, ok := existing[key]
+		delete(existing, key)
+
+		if ok && prev.ContentHash == contentHash(src) && prev.EmbeddingModel == model {
+			result.Unchanged++
+			continue
+		}
+
+		if err := ix.IndexSource(ctx, src); err != nil {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			log.Printf(
+				"embedding: failed to index %s for campaign %d: %v",
+				key, campaignID, err,
+			)
+			result.Failed++
+			continue
+		}
+		result.Indexed++
+	}
+
+	// Anything left in existing no longer has source content.
+	for _, stale := range existing {
+		if err := ix.db.DeleteContentEmbeddings(
+			ctx, stale.SourceTable, stale.SourceID, stale.SourceColumn,
+		); err != nil {
+			return result, err
+		}
+		result.Removed++
+	}
+
+	return result, nil
+}
+
+// IndexSource chunks and embeds a single source, replacing any chunks
+// previously stored for it.
+func (ix *Indexer) IndexSource(ctx context.Context, src database.EmbeddingSource) error {
+	cfg := configFor(src.SourceTable, src.SourceColumn)
+	texts := ChunkText(src.Content, cfg.size, cfg.overlap)
+	if len(texts) == 0 {
+		return ix.db.DeleteContentEmbeddings(ctx, src.SourceTable, src.SourceID, src.SourceColumn)
+	}
+
+	chunks := make([]database.ContentChunk, 0, len(texts))
+	for start := 0; start < len(texts); start += maxEmbedBatch {
+		end := min(start+maxEmbedBatch, len(texts))
+
+		vectors, err := ix.embedder.Embed(ctx, texts[start:end])
+		if err != nil {
+			return fmt.Errorf("failed to embed chunks: %w", err)
+		}
+		for i, v := range vectors {
+			chunks = append(chunks, database.ContentChunk{
+				Content:   texts[start+i],
+				Embedding: v,
+			})
+		}
+	}
+
+	return ix.db.ReplaceContentEmbeddings(ctx, src, contentHash(src), ix.embedder.Model(), chunks)
+}
+
+// sourceKey identifies a source column of a row.
+func sourceKey(table string, id int64, column string) string {
+	return fmt.Sprintf("%s.%s#%d", table, column, id)
+}
+
+// contentHash fingerprints the text and display name of a source so
+// that unchanged sources can be skipped on the next sync.
+func contentHash(src database.EmbeddingSource) string {
+	sum := sha256.Sum256([]byte(src.SourceName + "\x00" + src.Content))
+	return hex.EncodeToString(sum[:])
+}
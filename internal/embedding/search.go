@@ -0,0 +1,84 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package embedding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/antonypegg/imagineer/internal/database"
+	"github.com/antonypegg/imagineer/internal/llm"
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+// ErrSearchUnavailable is returned by Search when neither the
+// pgedge_vectorizer extension nor a Go-side index with a configured
+// embedder is available.
+var ErrSearchUnavailable = errors.New("semantic search is not available")
+
+// Available reports whether semantic search can be served for the
+// given embedder. The pgedge_vectorizer extension is always preferred;
+// otherwise the content_embeddings table must exist and embedder must
+// be non-nil to embed the query.
+func Available(ctx context.Context, db *database.DB, embedder llm.Embedder) bool {
+	if db.IsVectorizationAvailable(ctx) {
+		return true
+	}
+	return embedder != nil && db.IsEmbeddingIndexAvailable(ctx)
+}
+
+// Search performs hybrid semantic search across a campaign's content
+// using whichever backend is available. Results from both backends
+// share the same scoring scheme.
+func Search(
+	ctx context.Context,
+	db *database.DB,
+	embedder llm.Embedder,
+	campaignID int64,
+	query string,
+	limit int,
+) ([]models.SearchResult, error) {
+	if db.IsVectorizationAvailable(ctx) {
+		return db.SearchCampaignContent(ctx, campaignID, query, limit)
+	}
+
+	if embedder == nil || !db.IsEmbeddingIndexAvailable(ctx) {
+		return nil, ErrSearchUnavailable
+	}
+
+	vectors, err := embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed search query: %w", err)
+	}
+
+	return db.SearchContentEmbeddings(ctx, campaignID, query, vectors[0], embedder.Model(), limit)
+}
+
+// EmbedderForSettings returns the embedder configured in a user's
+// settings, or nil when no embedding service is configured. Ollama
+// needs no API key; the cloud services do.
+func EmbedderForSettings(settings *models.UserSettings) llm.Embedder {
+	if settings == nil || settings.EmbeddingService == nil {
+		return nil
+	}
+
+	apiKey := ""
+	if settings.EmbeddingAPIKey != nil {
+		apiKey = *settings.EmbeddingAPIKey
+	}
+
+	embedder, err := llm.NewEmbedder(*settings.EmbeddingService, apiKey)
+	if err != nil {
+		return nil
+	}
+	return embedder
+}
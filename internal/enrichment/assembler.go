@@ -0,0 +1,172 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package enrichment
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Fidelity is the level of detail at which a context item is included
+// in a prompt, after the fidelity levels in
+// docs/memory-system-design.md. Lower values carry more detail.
+type Fidelity int
+
+const (
+	// FidelityFull includes the item verbatim.
+	FidelityFull Fidelity = iota
+	// FidelitySummary includes a condensed form of the item.
+	FidelitySummary
+	// FidelityOneLine includes a single-line stub, such as a name.
+	FidelityOneLine
+)
+
+// ContextItem is a single piece of context in each of its fidelity
+// forms. A form left empty is not available for the item; for example
+// a game system schema has no one-line form.
+type ContextItem struct {
+	Full    string
+	Summary string
+	OneLine string
+}
+
+// form returns the item's text at fidelity f.
+func (it ContextItem) form(f Fidelity) string {
+	switch f {
+	case FidelityFull:
+		return it.Full
+	case FidelitySummary:
+		return it.Summary
+	default:
+		return it.OneLine
+	}
+}
+
+// ContextSection is a titled group of context items from one memory
+// tier or retrieval source, in priority order.
+type ContextSection struct {
+	// Heading is the section's Markdown heading text, without "## ".
+	Heading string
+
+	// Intro is an optional sentence written under the heading.
+	Intro string
+
+	// MaxTokens caps the tokens the section may use, heading included.
+	// Zero means the section may use whatever budget is left.
+	MaxTokens int
+
+	Items []ContextItem
+}
+
+// ContextAssembler builds the campaign context block of a prompt. It
+// fills a token budget with sections in the order given, so earlier
+// sections take priority. Each item is included at the most detailed
+// fidelity that still fits both the section's cap and the remaining
+// budget, falling back to its summary and then its one-line form;
+// items that fit at no fidelity are left out.
+type ContextAssembler struct {
+	budget int
+}
+
+// NewContextAssembler creates a ContextAssembler that keeps the blocks
+// it assembles within budget tokens, as counted by EstimateTokens.
+func NewContextAssembler(budget int) *ContextAssembler {
+	return &ContextAssembler{budget: budget}
+}
+
+// Assemble renders sections as one block of Markdown sections
+// separated by blank lines. Sections without any item that fits are
+// omitted. It returns an empty string when nothing fits.
+func (a *ContextAssembler) Assemble(sections []ContextSection) string {
+	var b strings.Builder
+	remaining := a.budget
+
+	for _, section := range sections {
+		header := "## " + section.Heading + "\n\n"
+		if section.Intro != "" {
+			header += section.Intro + "\n\n"
+		}
+		headerTokens := EstimateTokens(header)
+
+		available := remaining - headerTokens
+		if section.MaxTokens > 0 && section.MaxTokens-headerTokens < available {
+			available = section.MaxTokens - headerTokens
+		}
+
+		var lines []string
+		used := 0
+		for _, item := range section.Items {
+			for f := FidelityFull; f <= FidelityOneLine; f++ {
+				text := strings.TrimRight(item.form(f), "\n")
+				if text == "" {
+					continue
+				}
+				tokens := EstimateTokens(text) + 1 // trailing newline
+				if used+tokens <= available {
+					lines = append(lines, text)
+					used += tokens
+					break
+				}
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(header)
+		for _, line := range lines {
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+		remaining -= headerTokens + used
+	}
+
+	return b.String()
+}
+
+// wideRuneStart is the first code point of the CJK radicals block.
+// Characters from there on are mostly ideographs and syllables that
+// tokenizers encode as one or more tokens each.
+const wideRuneStart = 0x2E80
+
+// EstimateTokens returns an estimate of the number of LLM tokens in s.
+// Wide (CJK) characters count as a token each. For other text the
+// estimate is the larger of one token per four characters and four
+// tokens per three words, which holds for both long-worded prose and
+// short-worded text such as lists and names. It errs on the high side
+// so that assembled context stays within its budget.
+func EstimateTokens(s string) int {
+	var wide, narrow, words int
+	inWord := false
+	for _, r := range s {
+		switch {
+		case r >= wideRuneStart:
+			wide++
+			inWord = false
+		case unicode.IsSpace(r):
+			narrow++
+			inWord = false
+		default:
+			narrow++
+			if !inWord {
+				words++
+				inWord = true
+			}
+		}
+	}
+
+	byChars := (narrow + 3) / 4
+	byWords := (words*4 + 2) / 3
+	return wide + max(byChars, byWords)
+}
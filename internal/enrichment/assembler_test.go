@@ -0,0 +1,95 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package enrichment
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  int
+	}{
+		{"empty", "", 0},
+		{"long word", strings.Repeat("a", 100), 25},
+		{"short words", "a b c d e f", 8},
+		{"wide characters", strings.Repeat("世", 4), 4},
+		{"mixed", "Hello 世界", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, EstimateTokens(tt.input))
+		})
+	}
+}
+
+func TestContextAssembler_FillsSectionsInOrder(t *testing.T) {
+	block := NewContextAssembler(1000).Assemble([]ContextSection{
+		{Heading: "First", Intro: "Intro text.", Items: []ContextItem{{Full: "- one"}}},
+		{Heading: "Empty"},
+		{Heading: "Second", Items: []ContextItem{{Full: "- two"}, {Full: "- three"}}},
+	})
+
+	assert.Equal(t,
+		"## First\n\nIntro text.\n\n- one\n\n## Second\n\n- two\n- three\n",
+		block)
+}
+
+func TestContextAssembler_FallsBackToLowerFidelity(t *testing.T) {
+	long := "- " + strings.Repeat("word ", 200)
+	block := NewContextAssembler(60).Assemble([]ContextSection{{
+		Heading: "Memory",
+		Items: []ContextItem{
+			{Full: long, Summary: "- short summary", OneLine: "- stub"},
+			{Full: long, OneLine: "- second stub"},
+			{Full: long},
+		},
+	}})
+
+	assert.Contains(t, block, "- short summary\n")
+	assert.Contains(t, block, "- second stub\n")
+	assert.NotContains(t, block, "word word")
+	assert.LessOrEqual(t, EstimateTokens(block), 60)
+}
+
+func TestContextAssembler_RespectsSectionCap(t *testing.T) {
+	items := make([]ContextItem, 20)
+	for i := range items {
+		items[i] = ContextItem{Full: "- a fact about the campaign"}
+	}
+
+	block := NewContextAssembler(1000).Assemble([]ContextSection{
+		{Heading: "Capped", MaxTokens: 40, Items: items},
+		{Heading: "Next", Items: []ContextItem{{Full: "- still included"}}},
+	})
+
+	assert.LessOrEqual(t, strings.Count(block, "- a fact"), 5)
+	assert.Contains(t, block, "## Next\n\n- still included\n")
+}
+
+func TestContextAssembler_BudgetExhausted(t *testing.T) {
+	block := NewContextAssembler(20).Assemble([]ContextSection{
+		{Heading: "High", Items: []ContextItem{{Full: "- " + strings.Repeat("x", 60)}}},
+		{Heading: "Low", Items: []ContextItem{{Full: "- " + strings.Repeat("y", 60)}}},
+	})
+
+	assert.Contains(t, block, "## High")
+	assert.NotContains(t, block, "## Low")
+	assert.Equal(t, "", NewContextAssembler(0).Assemble([]ContextSection{
+		{Heading: "High", Items: []ContextItem{{Full: "- x"}}},
+	}))
+}
@@ -12,6 +12,7 @@ package enrichment
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -19,8 +20,12 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/antonypegg/imagineer/internal/agents"
 	"github.com/antonypegg/imagineer/internal/database"
+	"github.com/antonypegg/imagineer/internal/embedding"
+	"github.com/antonypegg/imagineer/internal/llm"
 	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/jackc/pgx/v5"
 )
 
 // maxSearchQueryLen is the maximum number of characters from the source
@@ -28,15 +33,40 @@ import (
 // to this length before being sent to SearchCampaignContent.
 const maxSearchQueryLen = 200
 
-// maxContextTokens is the soft limit for total RAG context tokens
-// across all search results included in a single pipeline run.
-const maxContextTokens = 4000
+// DefaultContextBudget is the number of tokens of campaign context
+// that agents include in a prompt unless they need a smaller block.
+const DefaultContextBudget = 10000
+
+// Per-tier token caps applied within the context budget. The memory
+// and session caps follow the tier budgets in
+// docs/memory-system-design.md.
+const (
+	memoryTokenCap          = 2000
+	chapterTokenCap         = 1000
+	sessionTokenCap         = 2000
+	entityTokenCap          = 1000
+	campaignContextTokenCap = 3000
+)
+
+// Lengths, in characters, of the summary and one-line forms of context
+// items.
+const (
+	summaryChars = 300
+	oneLineChars = 100
+)
 
-// tokensPerChar is a rough estimate of tokens per character, used for
-// token budget tracking. English text averages around 0.25 tokens per
-// character; this intentionally over-estimates slightly to stay within
-// the budget.
-const tokensPerChar = 0.25
+// memoryFetchLimit is the maximum number of campaign memories loaded
+// for the campaign memory tier.
+const memoryFetchLimit = 50
+
+// recentSessionLimit is the number of recently played sessions whose
+// summaries are loaded, matching the "previous two to three sessions"
+// of the session memory tier.
+const recentSessionLimit = 3
+
+// maxRelevantEntities is the maximum number of mentioned campaign
+// entities loaded for the relevant entities tier.
+const maxRelevantEntities = 20
 
 // maxContentSummaryLen is the maximum number of characters taken from
 // the beginning of the source content for the content-summary query.
@@ -51,12 +81,15 @@ const entityBatchSize = 5
 const searchLimitPerQuery = 10
 
 // ContextBuilder assembles shared RAG context for the enrichment
-// pipeline. It performs vector search against campaign content and
-// loads game system schema YAML, degrading gracefully when either
-// source is unavailable.
+// pipeline. It loads the campaign's top memories, the current chapter's
+// memory, recent session summaries and entities mentioned in the
+// content, performs vector search against campaign content and loads
+// game system schema YAML, degrading gracefully when any source is
+// unavailable.
 type ContextBuilder struct {
 	db         *database.DB
 	schemasDir string
+	embedder   llm.Embedder
 }
 
 // NewContextBuilder creates a ContextBuilder. If schemasDir is empty
@@ -71,13 +104,25 @@ func NewContextBuilder(db *database.DB, schemasDir string) *ContextBuilder {
 	}
 }
 
-// BuildContext assembles a RAGContext by deriving multiple search
-// queries from the source content and entity names, executing them
-// via hybrid vector search, deduplicating and trimming results to
-// fit within a token budget, and loading the game system schema YAML.
-// Both search and schema sources are optional: if vectorization is
-// unavailable or the schema file cannot be read, the corresponding
-// field is left empty and no error is returned.
+// WithEmbedder sets the embedder used to embed search queries when the
+// pgedge_vectorizer extension is absent and search falls back to the
+// Go-side content_embeddings index. A nil embedder disables the
+// fallback. It returns cb for chaining.
+func (cb *ContextBuilder) WithEmbedder(embedder llm.Embedder) *ContextBuilder {
+	cb.embedder = embedder
+	return cb
+}
+
+// BuildContext assembles a RAGContext by loading the campaign's top
+// memories, current chapter memory and recent session summaries,
+// finding campaign entities mentioned in the content, deriving multiple
+// search queries from the source content and entity names, executing
+// them via hybrid vector search and deduplicating the results, and
+// loading the game system schema YAML. All sources are optional: if a
+// source cannot be loaded, vectorization is unavailable or the schema
+// file cannot be read, the corresponding field is left empty and no
+// error is returned. Fitting the context to a token budget is left to
+// RAGContext.Block.
 func (cb *ContextBuilder) BuildContext(
 	ctx context.Context,
 	campaignID int64,
@@ -87,9 +132,66 @@ func (cb *ContextBuilder) BuildContext(
 ) (*RAGContext, error) {
 	ragCtx := &RAGContext{}
 
+	// Campaign memory (premise, themes, plot threads) is included
+	// regardless of the content being analysed.
+	memories, err := cb.db.ListTopCampaignMemories(
+		ctx, campaignID, memoryFetchLimit,
+	)
+	if err != nil {
+		log.Printf(
+			"enrichment: failed to load memories for campaign %d: %v",
+			campaignID, err,
+		)
+	} else {
+		ragCtx.Memories = memories
+	}
+
+	// Where the story currently stands: the current chapter and the
+	// last few sessions played.
+	chapterMemory, err := cb.db.GetCurrentChapterMemory(ctx, campaignID)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+	case err != nil:
+		log.Printf(
+			"enrichment: failed to load current chapter memory for campaign %d: %v",
+			campaignID, err,
+		)
+	default:
+		ragCtx.ChapterMemory = chapterMemory
+		if chapter, err := cb.db.GetChapter(ctx, chapterMemory.ChapterID); err == nil {
+			ragCtx.ChapterTitle = chapter.Title
+		}
+	}
+
+	sessions, err := cb.db.ListRecentSessionSummaries(
+		ctx, campaignID, recentSessionLimit,
+	)
+	if err != nil {
+		log.Printf(
+			"enrichment: failed to load recent sessions for campaign %d: %v",
+			campaignID, err,
+		)
+	} else {
+		ragCtx.RecentSessions = sessions
+	}
+
+	if content != "" {
+		campaignEntities, err := cb.db.ListEntitiesByCampaign(ctx, campaignID)
+		if err != nil {
+			log.Printf(
+				"enrichment: failed to load entities for campaign %d: %v",
+				campaignID, err,
+			)
+		} else {
+			ragCtx.RelevantEntities = mentionedEntities(
+				content, campaignEntities, entities,
+			)
+		}
+	}
+
 	// Retrieve relevant campaign content via hybrid vector search
 	// using multiple content-derived queries.
-	if cb.db.IsVectorizationAvailable(ctx) {
+	if embedding.Available(ctx, cb.db, cb.embedder) {
 		queries := cb.buildSearchQueries(content, entities)
 		var allResults []models.SearchResult
 
@@ -97,8 +199,8 @@ func (cb *ContextBuilder) BuildContext(
 			if query == "" {
 				continue
 			}
-			results, err := cb.db.SearchCampaignContent(
-				ctx, campaignID, query, searchLimitPerQuery,
+			results, err := embedding.Search(
+				ctx, cb.db, cb.embedder, campaignID, query, searchLimitPerQuery,
 			)
 			if err != nil {
 				log.Printf(
@@ -113,7 +215,7 @@ func (cb *ContextBuilder) BuildContext(
 			allResults = append(allResults, results...)
 		}
 
-		ragCtx.CampaignResults = deduplicateAndTrim(allResults)
+		ragCtx.CampaignResults = deduplicateResults(allResults)
 	}
 
 	// Load the game system schema YAML if a system code was provided.
@@ -221,10 +323,10 @@ func buildEntityNameQueries(entities []models.Entity) []string {
 	return queries
 }
 
-// deduplicateAndTrim removes duplicate search results (by SourceTable
-// and SourceID, keeping the highest CombinedScore), sorts by
-// CombinedScore descending, and trims to fit within maxContextTokens.
-func deduplicateAndTrim(results []models.SearchResult) []models.SearchResult {
+// deduplicateResults removes duplicate search results (by SourceTable
+// and SourceID, keeping the highest CombinedScore) and sorts them by
+// CombinedScore descending.
+func deduplicateResults(results []models.SearchResult) []models.SearchResult {
 	if len(results) == 0 {
 		return nil
 	}
@@ -256,27 +358,40 @@ func deduplicateAndTrim(results []models.SearchResult) []models.SearchResult {
 		return deduped[i].CombinedScore > deduped[j].CombinedScore
 	})
 
-	// Trim to fit within the token budget.
-	var trimmed []models.SearchResult
-	var totalTokens float64
+	return deduped
+}
 
-	for _, r := range deduped {
-		tokens := estimateTokens(r.ChunkContent)
-		if totalTokens+tokens > maxContextTokens && len(trimmed) > 0 {
+// mentionedEntities returns up to maxRelevantEntities of the campaign
+// entities whose names appear in content, skipping those in exclude,
+// in the order given.
+func mentionedEntities(
+	content string,
+	campaignEntities []models.Entity,
+	exclude []models.Entity,
+) []models.Entity {
+	excluded := make(map[int64]bool, len(exclude))
+	for _, e := range exclude {
+		excluded[e.ID] = true
+	}
+
+	contentLower := strings.ToLower(content)
+	var mentioned []models.Entity
+	for _, e := range campaignEntities {
+		if excluded[e.ID] || e.Name == "" {
+			continue
+		}
+		if !strings.Contains(contentLower, strings.ToLower(e.Name)) {
+			continue
+		}
+		// Never pass GM-only notes to the LLM.
+		e.GMNotes = nil
+		mentioned = append(mentioned, e)
+		if len(mentioned) == maxRelevantEntities {
 			break
 		}
-		trimmed = append(trimmed, r)
-		totalTokens += tokens
 	}
 
-	return trimmed
-}
-
-// estimateTokens returns a rough token count for a string based on
-// its character length. This uses a simple heuristic suitable for
-// budget tracking where precision is not critical.
-func estimateTokens(s string) float64 {
-	return float64(len([]rune(s))) * tokensPerChar
+	return mentioned
 }
 
 // loadGameSystemSchema reads the YAML schema file for the given game
@@ -317,7 +432,186 @@ func truncateQuery(s string) string {
 // configuration for logging and debugging.
 func (cb *ContextBuilder) String() string {
 	return fmt.Sprintf(
-		"ContextBuilder(schemasDir=%q, maxTokens=%d)",
-		cb.schemasDir, maxContextTokens,
+		"ContextBuilder(schemasDir=%q, budget=%d)",
+		cb.schemasDir, DefaultContextBudget,
 	)
 }
+
+// Block renders the context as prompt sections fitted to budget tokens.
+// Tiers are filled in priority order: campaign memory, the current
+// chapter, recent sessions, relevant entities, campaign content from
+// vector search and finally the game system schema. Items that do not
+// fit in full are included as a summary or a single line. Block is safe
+// to call on a nil RAGContext and returns an empty string when there is
+// no context.
+func (c *RAGContext) Block(budget int) string {
+	if c == nil {
+		return ""
+	}
+	return NewContextAssembler(budget).Assemble(c.sections())
+}
+
+// sections returns the context's tiers as assembler sections, highest
+// priority first.
+func (c *RAGContext) sections() []ContextSection {
+	sections := []ContextSection{
+		{
+			Heading:   "Campaign Memory",
+			Intro:     campaignMemoryIntro,
+			MaxTokens: memoryTokenCap,
+			Items:     memoryItems(c.Memories),
+		},
+		c.chapterSection(),
+		{
+			Heading:   "Recent Sessions",
+			Intro:     "Recaps of the most recently played sessions, newest first:",
+			MaxTokens: sessionTokenCap,
+			Items:     sessionItems(c.RecentSessions),
+		},
+		{
+			Heading:   "Related Entities",
+			Intro:     "Other campaign entities mentioned in the content:",
+			MaxTokens: entityTokenCap,
+			Items:     entityItems(c.RelevantEntities),
+		},
+		{
+			Heading:   "Campaign Context",
+			Intro:     "Related content from the campaign (for continuity and context):",
+			MaxTokens: campaignContextTokenCap,
+			Items:     searchResultItems(c.CampaignResults),
+		},
+	}
+
+	// The schema is only useful whole, so it has no shorter forms.
+	if c.GameSystemYAML != "" {
+		sections = append(sections, ContextSection{
+			Heading: "Game System Schema",
+			Items: []ContextItem{{
+				Full: "```yaml\n" + strings.TrimRight(c.GameSystemYAML, "\n") + "\n```",
+			}},
+		})
+	}
+
+	return sections
+}
+
+// chapterSection returns the current chapter tier: the chapter's
+// summary, then its goals and active threads.
+func (c *RAGContext) chapterSection() ContextSection {
+	section := ContextSection{
+		Heading:   "Current Chapter",
+		Intro:     "The chapter the campaign is currently playing through:",
+		MaxTokens: chapterTokenCap,
+	}
+	if c.ChapterTitle != "" {
+		section.Heading += ": " + c.ChapterTitle
+	}
+
+	m := c.ChapterMemory
+	if m == nil {
+		return section
+	}
+	if m.Summary != nil && strings.TrimSpace(*m.Summary) != "" {
+		section.Items = append(section.Items, textItem("", *m.Summary))
+	}
+	for _, goal := range m.Goals {
+		section.Items = append(section.Items, textItem("Goal", goal))
+	}
+	for _, thread := range m.ActiveThreads {
+		section.Items = append(section.Items, textItem("Active thread", thread))
+	}
+
+	return section
+}
+
+// memoryItems returns context items for campaign memories.
+func memoryItems(memories []models.CampaignMemory) []ContextItem {
+	items := make([]ContextItem, 0, len(memories))
+	for _, m := range memories {
+		label, ok := memoryTypeLabels[m.MemoryType]
+		if !ok {
+			label = string(m.MemoryType)
+		}
+		if m.Title != nil && *m.Title != "" {
+			label += ": " + *m.Title
+		}
+		items = append(items, textItem(label, m.Content))
+	}
+	return items
+}
+
+// sessionItems returns context items for session summaries. Only the
+// most recent session may be included in full.
+func sessionItems(sessions []models.SessionSummary) []ContextItem {
+	items := make([]ContextItem, 0, len(sessions))
+	for i, s := range sessions {
+		label := "Session"
+		if s.SessionNumber != nil {
+			label = fmt.Sprintf("Session %d", *s.SessionNumber)
+		}
+		if s.Title != nil && *s.Title != "" {
+			label += ": " + *s.Title
+		}
+		item := textItem(label, s.Summary)
+		if i > 0 {
+			item.Full = ""
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// entityItems returns context items for entities. GM notes are never
+// included.
+func entityItems(entities []models.Entity) []ContextItem {
+	items := make([]ContextItem, 0, len(entities))
+	for _, e := range entities {
+		label := fmt.Sprintf("%s (%s)", e.Name, e.EntityType)
+		var desc string
+		if e.Description != nil {
+			desc = *e.Description
+		}
+		items = append(items, textItem(label, desc))
+	}
+	return items
+}
+
+// searchResultItems returns context items for vector search results.
+func searchResultItems(results []models.SearchResult) []ContextItem {
+	items := make([]ContextItem, 0, len(results))
+	for _, r := range results {
+		label := fmt.Sprintf("%s (%s)", r.SourceName, r.SourceTable)
+		items = append(items, textItem(label, r.ChunkContent))
+	}
+	return items
+}
+
+// textItem returns a Markdown list item for text under a bold label in
+// all three fidelity forms: the text in full, its first summaryChars
+// characters on one line, and its first line cut to oneLineChars
+// characters. An empty label omits the prefix; empty text leaves just
+// the label.
+func textItem(label, text string) ContextItem {
+	prefix := "- "
+	if label != "" {
+		prefix = "- **" + label + "**"
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		if label == "" {
+			return ContextItem{}
+		}
+		return ContextItem{Full: prefix, Summary: prefix, OneLine: prefix}
+	}
+	if label != "" {
+		prefix += ": "
+	}
+
+	firstLine, _, _ := strings.Cut(text, "\n")
+	return ContextItem{
+		Full:    prefix + strings.ReplaceAll(text, "\n", "\n  "),
+		Summary: prefix + agents.TruncateString(strings.Join(strings.Fields(text), " "), summaryChars),
+		OneLine: prefix + agents.TruncateString(strings.TrimSpace(firstLine), oneLineChars),
+	}
+}
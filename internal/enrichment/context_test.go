@@ -185,10 +185,10 @@ func TestBuildSearchQueries_ContentTruncation(t *testing.T) {
 }
 
 // ---------------------------------------------------------------------------
-// Deduplication and trimming tests
+// Deduplication tests
 // ---------------------------------------------------------------------------
 
-func TestDeduplicateAndTrim_NoDuplicates(t *testing.T) {
+func TestDeduplicateResults_NoDuplicates(t *testing.T) {
 	results := []models.SearchResult{
 		{
 			SourceTable:   "chapters",
@@ -213,13 +213,13 @@ func TestDeduplicateAndTrim_NoDuplicates(t *testing.T) {
 		},
 	}
 
-	deduped := deduplicateAndTrim(results)
+	deduped := deduplicateResults(results)
 
 	assert.Len(t, deduped, 3,
 		"all unique results should pass through")
 }
 
-func TestDeduplicateAndTrim_KeepsHighestScore(t *testing.T) {
+func TestDeduplicateResults_KeepsHighestScore(t *testing.T) {
 	results := []models.SearchResult{
 		{
 			SourceTable:   "chapters",
@@ -244,7 +244,7 @@ func TestDeduplicateAndTrim_KeepsHighestScore(t *testing.T) {
 		},
 	}
 
-	deduped := deduplicateAndTrim(results)
+	deduped := deduplicateResults(results)
 
 	require.Len(t, deduped, 2,
 		"duplicate (chapters, 1) should collapse to one entry")
@@ -267,7 +267,7 @@ func TestDeduplicateAndTrim_KeepsHighestScore(t *testing.T) {
 		"should keep the chunk content from the higher-scoring entry")
 }
 
-func TestDeduplicateAndTrim_KeepsHighestScore_ReverseOrder(t *testing.T) {
+func TestDeduplicateResults_KeepsHighestScore_ReverseOrder(t *testing.T) {
 	// Higher score appears first; the lower-scoring duplicate should
 	// not overwrite it.
 	results := []models.SearchResult{
@@ -287,7 +287,7 @@ func TestDeduplicateAndTrim_KeepsHighestScore_ReverseOrder(t *testing.T) {
 		},
 	}
 
-	deduped := deduplicateAndTrim(results)
+	deduped := deduplicateResults(results)
 
 	require.Len(t, deduped, 1)
 	assert.InDelta(t, 0.99, deduped[0].CombinedScore, 0.001,
@@ -295,7 +295,7 @@ func TestDeduplicateAndTrim_KeepsHighestScore_ReverseOrder(t *testing.T) {
 	assert.Equal(t, "High score first.", deduped[0].ChunkContent)
 }
 
-func TestDeduplicateAndTrim_SameTableDifferentIDs(t *testing.T) {
+func TestDeduplicateResults_SameTableDifferentIDs(t *testing.T) {
 	results := []models.SearchResult{
 		{
 			SourceTable:   "chapters",
@@ -311,13 +311,13 @@ func TestDeduplicateAndTrim_SameTableDifferentIDs(t *testing.T) {
 		},
 	}
 
-	deduped := deduplicateAndTrim(results)
+	deduped := deduplicateResults(results)
 
 	assert.Len(t, deduped, 2,
 		"same table with different IDs should not be deduplicated")
 }
 
-func TestDeduplicateAndTrim_DifferentTableSameID(t *testing.T) {
+func TestDeduplicateResults_DifferentTableSameID(t *testing.T) {
 	results := []models.SearchResult{
 		{
 			SourceTable:   "chapters",
@@ -333,27 +333,27 @@ func TestDeduplicateAndTrim_DifferentTableSameID(t *testing.T) {
 		},
 	}
 
-	deduped := deduplicateAndTrim(results)
+	deduped := deduplicateResults(results)
 
 	assert.Len(t, deduped, 2,
 		"different tables with the same ID should not be deduplicated")
 }
 
-func TestDeduplicateAndTrim_NilInput(t *testing.T) {
-	deduped := deduplicateAndTrim(nil)
+func TestDeduplicateResults_NilInput(t *testing.T) {
+	deduped := deduplicateResults(nil)
 
 	assert.Nil(t, deduped,
 		"should return nil for nil input")
 }
 
-func TestDeduplicateAndTrim_EmptySlice(t *testing.T) {
-	deduped := deduplicateAndTrim([]models.SearchResult{})
+func TestDeduplicateResults_EmptySlice(t *testing.T) {
+	deduped := deduplicateResults([]models.SearchResult{})
 
 	assert.Nil(t, deduped,
 		"should return nil for empty input")
 }
 
-func TestDeduplicateAndTrim_ManyDuplicates(t *testing.T) {
+func TestDeduplicateResults_ManyDuplicates(t *testing.T) {
 	// Same (table, ID) appearing many times should collapse to one.
 	results := make([]models.SearchResult, 10)
 	for i := range results {
@@ -365,7 +365,7 @@ func TestDeduplicateAndTrim_ManyDuplicates(t *testing.T) {
 		}
 	}
 
-	deduped := deduplicateAndTrim(results)
+	deduped := deduplicateResults(results)
 
 	require.Len(t, deduped, 1,
 		"all duplicates should collapse to a single entry")
@@ -373,7 +373,7 @@ func TestDeduplicateAndTrim_ManyDuplicates(t *testing.T) {
 		"should keep the highest score from all duplicates")
 }
 
-func TestDeduplicateAndTrim_SortsByScoreDescending(t *testing.T) {
+func TestDeduplicateResults_SortsByScoreDescending(t *testing.T) {
 	results := []models.SearchResult{
 		{
 			SourceTable:   "chapters",
@@ -395,7 +395,7 @@ func TestDeduplicateAndTrim_SortsByScoreDescending(t *testing.T) {
 		},
 	}
 
-	deduped := deduplicateAndTrim(results)
+	deduped := deduplicateResults(results)
 
 	require.Len(t, deduped, 3)
 	for i := 1; i < len(deduped); i++ {
@@ -406,71 +406,6 @@ func TestDeduplicateAndTrim_SortsByScoreDescending(t *testing.T) {
 	}
 }
 
-func TestDeduplicateAndTrim_TrimsToTokenBudget(t *testing.T) {
-	// Create results with large content that exceeds maxContextTokens.
-	// At tokensPerChar=0.25, 400 chars is ~100 tokens.
-	// maxContextTokens is 4000, so 4000/100 = 40 results would fit.
-	// Create 50 results to exceed the budget.
-	results := make([]models.SearchResult, 50)
-	for i := range results {
-		results[i] = models.SearchResult{
-			SourceTable:   "chapters",
-			SourceID:      int64(i + 1),
-			ChunkContent:  strings.Repeat("a", 400),
-			CombinedScore: float64(50-i) * 0.01,
-		}
-	}
-
-	trimmed := deduplicateAndTrim(results)
-
-	assert.Less(t, len(trimmed), len(results),
-		"should return fewer results than the input when over budget")
-	require.NotEmpty(t, trimmed,
-		"should return at least one result")
-
-	// The highest-scoring result should appear first.
-	assert.InDelta(t, 0.50, trimmed[0].CombinedScore, 0.001,
-		"highest-scoring result should appear first")
-}
-
-func TestDeduplicateAndTrim_AlwaysIncludesAtLeastOne(t *testing.T) {
-	// A single result that itself exceeds the budget should still
-	// be included to avoid returning an empty context.
-	results := []models.SearchResult{
-		{
-			SourceTable:   "chapters",
-			SourceID:      1,
-			ChunkContent:  strings.Repeat("x", 50000),
-			CombinedScore: 0.95,
-		},
-	}
-
-	trimmed := deduplicateAndTrim(results)
-
-	require.Len(t, trimmed, 1,
-		"should include at least one result even if it exceeds budget")
-}
-
-// ---------------------------------------------------------------------------
-// estimateTokens tests
-// ---------------------------------------------------------------------------
-
-func TestEstimateTokens_Empty(t *testing.T) {
-	assert.InDelta(t, 0.0, estimateTokens(""), 0.001)
-}
-
-func TestEstimateTokens_KnownInput(t *testing.T) {
-	// 100 characters at 0.25 tokens/char = 25 tokens.
-	input := strings.Repeat("a", 100)
-	assert.InDelta(t, 25.0, estimateTokens(input), 0.001)
-}
-
-func TestEstimateTokens_MultiByte(t *testing.T) {
-	// CJK characters: 4 runes at 0.25 tokens/rune = 1 token.
-	input := strings.Repeat("\u4e16", 4)
-	assert.InDelta(t, 1.0, estimateTokens(input), 0.001)
-}
-
 // ---------------------------------------------------------------------------
 // Game system schema loading tests
 // ---------------------------------------------------------------------------
@@ -603,3 +538,127 @@ func TestRAGContext_ZeroValue(t *testing.T) {
 	assert.Equal(t, "", ragCtx.GameSystemYAML,
 		"zero-value RAGContext should have empty GameSystemYAML")
 }
+
+// ---------------------------------------------------------------------------
+// Campaign memory tests
+// ---------------------------------------------------------------------------
+
+func TestFormatCampaignMemories(t *testing.T) {
+	title := "The Missing Heir"
+	section := FormatCampaignMemories([]models.CampaignMemory{
+		{MemoryType: models.MemoryTypePremise, Content: "Cultists plot to wake a god."},
+		{MemoryType: models.MemoryTypePlotThread, Title: &title, Content: "Lady Ashworth's son vanished.\nHe was last seen at the docks."},
+	})
+
+	assert.True(t, strings.HasPrefix(section, "## Campaign Memory\n\n"))
+	assert.Contains(t, section, "- **Premise**: Cultists plot to wake a god.\n")
+	assert.Contains(t, section,
+		"- **Plot thread: The Missing Heir**: Lady Ashworth's son vanished.\n  He was last seen at the docks.\n")
+	assert.Equal(t, "", FormatCampaignMemories(nil))
+}
+
+// ---------------------------------------------------------------------------
+// Context block tests
+// ---------------------------------------------------------------------------
+
+func TestRAGContextBlock_TiersInPriorityOrder(t *testing.T) {
+	summary := "The party is hunting the cult through Limehouse."
+	number := 4
+	desc := "A dockside informant."
+	ragCtx := &RAGContext{
+		Memories: []models.CampaignMemory{
+			{MemoryType: models.MemoryTypePremise, Content: "Cultists plot to wake a god."},
+		},
+		ChapterMemory: &models.ChapterMemory{
+			Summary:       &summary,
+			Goals:         []string{"Find the ledger"},
+			ActiveThreads: []string{"Who betrayed Viktor?"},
+		},
+		ChapterTitle: "Fog Over Limehouse",
+		RecentSessions: []models.SessionSummary{
+			{SessionNumber: &number, Summary: "The party escaped the burning mill."},
+		},
+		RelevantEntities: []models.Entity{
+			{Name: "Mick", EntityType: models.EntityTypeNPC, Description: &desc},
+		},
+		CampaignResults: []models.SearchResult{
+			{SourceTable: "chapters", SourceName: "Chapter 1", ChunkContent: "Viktor met the party at the docks."},
+		},
+		GameSystemYAML: "name: Call of Cthulhu 7e\n",
+	}
+
+	block := ragCtx.Block(DefaultContextBudget)
+
+	headings := []string{
+		"## Campaign Memory",
+		"## Current Chapter: Fog Over Limehouse",
+		"## Recent Sessions",
+		"## Related Entities",
+		"## Campaign Context",
+		"## Game System Schema",
+	}
+	last := -1
+	for _, heading := range headings {
+		idx := strings.Index(block, heading)
+		require.NotEqual(t, -1, idx, "missing %q", heading)
+		assert.Greater(t, idx, last, "%q out of order", heading)
+		last = idx
+	}
+	assert.Contains(t, block, "- **Premise**: Cultists plot to wake a god.\n")
+	assert.Contains(t, block, "- **Goal**: Find the ledger\n")
+	assert.Contains(t, block, "- **Active thread**: Who betrayed Viktor?\n")
+	assert.Contains(t, block, "- **Session 4**: The party escaped the burning mill.\n")
+	assert.Contains(t, block, "- **Mick (npc)**: A dockside informant.\n")
+	assert.Contains(t, block, "- **Chapter 1 (chapters)**: Viktor met the party at the docks.\n")
+	assert.Contains(t, block, "```yaml\nname: Call of Cthulhu 7e\n```\n")
+}
+
+func TestRAGContextBlock_DegradesToFitBudget(t *testing.T) {
+	long := strings.Repeat("The cult gathers beneath the old mill. ", 100)
+	ragCtx := &RAGContext{
+		Memories: []models.CampaignMemory{
+			{MemoryType: models.MemoryTypePremise, Content: long},
+		},
+		RecentSessions: []models.SessionSummary{
+			{Summary: long},
+			{Summary: long},
+		},
+		GameSystemYAML: long,
+	}
+
+	block := ragCtx.Block(400)
+
+	assert.LessOrEqual(t, EstimateTokens(block), 400)
+	assert.Contains(t, block, "## Campaign Memory")
+	assert.Contains(t, block, "## Recent Sessions")
+	assert.NotContains(t, block, long,
+		"no tier should fit in full within the budget")
+	assert.NotContains(t, block, "## Game System Schema",
+		"the schema has no shorter form and should be dropped")
+}
+
+func TestRAGContextBlock_Empty(t *testing.T) {
+	var nilCtx *RAGContext
+	assert.Equal(t, "", nilCtx.Block(DefaultContextBudget))
+	assert.Equal(t, "", (&RAGContext{}).Block(DefaultContextBudget))
+}
+
+func TestMentionedEntities(t *testing.T) {
+	notes := "GM only"
+	campaign := []models.Entity{
+		{ID: 1, Name: "Viktor"},
+		{ID: 2, Name: "Silver Fox Inn", GMNotes: &notes},
+		{ID: 3, Name: "Irena"},
+		{ID: 4, Name: ""},
+	}
+
+	mentioned := mentionedEntities(
+		"Viktor drinks at the silver fox inn.",
+		campaign,
+		[]models.Entity{{ID: 1}},
+	)
+
+	require.Len(t, mentioned, 1)
+	assert.Equal(t, int64(2), mentioned[0].ID)
+	assert.Nil(t, mentioned[0].GMNotes, "GM notes must not reach the prompt")
+}
@@ -43,17 +43,16 @@ func NewEngine(db *database.DB) *Engine {
 // EnrichmentInput contains everything needed to enrich a single entity
 // from a content source.
 type EnrichmentInput struct {
-	CampaignID      int64
-	JobID           int64
-	SourceTable     string
-	SourceID        int64
-	Content         string // Source content (Markdown)
-	Entity          models.Entity
-	OtherEntities   []models.Entity       // Other entities mentioned in the same content
-	Relationships   []models.Relationship // Existing relationships for this entity
-	CampaignResults []models.SearchResult // RAG: campaign vector search results
-	GameSystemYAML  string                // RAG: game system schema
-	Ontology        *ontology.Ontology    // Optional ontology for type/relationship guidance
+	CampaignID    int64
+	JobID         int64
+	SourceTable   string
+	SourceID      int64
+	Content       string // Source content (Markdown)
+	Entity        models.Entity
+	OtherEntities []models.Entity       // Other entities mentioned in the same content
+	Relationships []models.Relationship // Existing relationships for this entity
+	Context       *RAGContext           // RAG: shared campaign context, with per-entity search results
+	Ontology      *ontology.Ontology    // Optional ontology for type/relationship guidance
 }
 
 // EnrichEntity sends content and entity state to the LLM and returns
@@ -85,12 +84,14 @@ func (e *Engine) EnrichEntity(
 		UserPrompt:   userPrompt,
 		MaxTokens:    2048,
 		Temperature:  0.3,
+		Tools:        []llm.Tool{enrichmentTool},
+		ToolChoice:   enrichmentToolName,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("LLM completion failed: %w", err)
 	}
 
-	parsed, err := parseEnrichmentResponse(resp.Content)
+	parsed, err := parseEnrichmentResponse(llm.StructuredContent(resp, enrichmentToolName))
 	if err != nil {
 		// Log but do not propagate parse errors; return empty items
 		// for graceful degradation.
@@ -132,12 +133,14 @@ func (e *Engine) DetectNewEntities(
 		UserPrompt:   userPrompt,
 		MaxTokens:    2048,
 		Temperature:  0.3,
+		Tools:        []llm.Tool{newEntityTool},
+		ToolChoice:   newEntityToolName,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("LLM completion failed: %w", err)
 	}
 
-	parsed, err := parseNewEntityResponse(resp.Content)
+	parsed, err := parseNewEntityResponse(llm.StructuredContent(resp, newEntityToolName))
 	if err != nil {
 		log.Printf(
 			"enrichment: failed to parse new-entity LLM response "+
@@ -1176,15 +1176,17 @@ func TestBuildUserPrompt_WithRAGContext(t *testing.T) {
 			Name:        "Viktor",
 			Description: &desc,
 		},
-		CampaignResults: []models.SearchResult{
-			{
-				SourceTable:  "chapters",
-				SourceID:     2,
-				SourceName:   "Chapter 2",
-				ChunkContent: "Viktor was first seen arriving at the docks.",
+		Context: &RAGContext{
+			CampaignResults: []models.SearchResult{
+				{
+					SourceTable:  "chapters",
+					SourceID:     2,
+					SourceName:   "Chapter 2",
+					ChunkContent: "Viktor was first seen arriving at the docks.",
+				},
 			},
+			GameSystemYAML: "name: Call of Cthulhu 7e\nskills:\n  - Spot Hidden",
 		},
-		GameSystemYAML: "name: Call of Cthulhu 7e\nskills:\n  - Spot Hidden",
 	}
 
 	prompt := buildUserPrompt(input)
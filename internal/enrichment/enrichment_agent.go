@@ -16,6 +16,7 @@ import (
 	"strings"
 
 	"github.com/antonypegg/imagineer/internal/database"
+	"github.com/antonypegg/imagineer/internal/embedding"
 	"github.com/antonypegg/imagineer/internal/llm"
 	"github.com/antonypegg/imagineer/internal/models"
 )
@@ -117,14 +118,8 @@ func (a *EnrichmentAgent) Run(
 		allKnownEntities = entities
 	}
 
-	// Prepare RAG context. Extract game system YAML
-	// from pipeline context and check vectorization
-	// availability for per-entity search.
-	var gameSystemYAML string
-	if input.Context != nil {
-		gameSystemYAML = input.Context.GameSystemYAML
-	}
-	vectorAvailable := a.db.IsVectorizationAvailable(ctx)
+	// Check semantic search availability for per-entity search.
+	vectorAvailable := embedding.Available(ctx, a.db, input.Embedder)
 
 	// Enrich each entity individually.
 	for i, entity := range entities {
@@ -156,9 +151,9 @@ func (a *EnrichmentAgent) Run(
 		var campaignResults []models.SearchResult
 		if vectorAvailable {
 			results, searchErr :=
-				a.db.SearchCampaignContent(
-					ctx, input.CampaignID,
-					entity.Name, 5)
+				embedding.Search(
+					ctx, a.db, input.Embedder,
+					input.CampaignID, entity.Name, 5)
 			if searchErr != nil {
 				log.Printf(
 					"enrichment-agent: RAG search "+
@@ -169,18 +164,25 @@ func (a *EnrichmentAgent) Run(
 			}
 		}
 
+		// Share the pipeline context, with the search results
+		// about this entity in place of the content-wide ones.
+		var entityCtx RAGContext
+		if input.Context != nil {
+			entityCtx = *input.Context
+		}
+		entityCtx.CampaignResults = campaignResults
+
 		enrichInput := EnrichmentInput{
-			CampaignID:      input.CampaignID,
-			JobID:           input.JobID,
-			SourceTable:     input.SourceTable,
-			SourceID:        input.SourceID,
-			Content:         input.Content,
-			Entity:          entity,
-			OtherEntities:   otherEntities,
-			Relationships:   relationships,
-			CampaignResults: campaignResults,
-			GameSystemYAML:  gameSystemYAML,
-			Ontology:        input.Ontology,
+			CampaignID:    input.CampaignID,
+			JobID:         input.JobID,
+			SourceTable:   input.SourceTable,
+			SourceID:      input.SourceID,
+			Content:       input.Content,
+			Entity:        entity,
+			OtherEntities: otherEntities,
+			Relationships: relationships,
+			Context:       &entityCtx,
+			Ontology:      input.Ontology,
 		}
 
 		items, err := a.engine.EnrichEntity(ctx, provider, enrichInput)
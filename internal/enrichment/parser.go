@@ -17,9 +17,92 @@ import (
 	"time"
 
 	"github.com/antonypegg/imagineer/internal/agents"
+	"github.com/antonypegg/imagineer/internal/llm"
 	"github.com/antonypegg/imagineer/internal/models"
 )
 
+// Tool names the LLM is asked to call with enrichment and new-entity
+// detection results.
+const (
+	enrichmentToolName = "suggest_enrichments"
+	newEntityToolName  = "report_new_entities"
+)
+
+// enrichmentTool describes enrichmentResponse as a JSON Schema.
+var enrichmentTool = llm.Tool{
+	Name:        enrichmentToolName,
+	Description: "Suggest description updates, log entries, and relationships for the entity.",
+	InputSchema: json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"descriptionUpdates": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"properties": {
+						"currentDescription": {"type": "string"},
+						"suggestedDescription": {"type": "string"},
+						"rationale": {"type": "string"}
+					},
+					"required": ["suggestedDescription", "rationale"]
+				}
+			},
+			"logEntries": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"properties": {
+						"content": {"type": "string"},
+						"occurredAt": {"type": "string"}
+					},
+					"required": ["content"]
+				}
+			},
+			"relationships": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"properties": {
+						"sourceEntityId": {"type": "integer"},
+						"sourceEntityName": {"type": "string"},
+						"targetEntityId": {"type": "integer"},
+						"targetEntityName": {"type": "string"},
+						"relationshipType": {"type": "string"},
+						"description": {"type": "string"}
+					},
+					"required": ["sourceEntityName", "targetEntityName", "relationshipType"]
+				}
+			}
+		},
+		"required": ["descriptionUpdates", "logEntries", "relationships"]
+	}`),
+}
+
+// newEntityTool describes newEntityResponse as a JSON Schema.
+var newEntityTool = llm.Tool{
+	Name:        newEntityToolName,
+	Description: "Report named entities mentioned in the content that are not yet in the campaign.",
+	InputSchema: json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"new_entities": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"properties": {
+						"name": {"type": "string"},
+						"entity_type": {"type": "string"},
+						"description": {"type": "string"},
+						"reasoning": {"type": "string"}
+					},
+					"required": ["name", "entity_type"]
+				}
+			}
+		},
+		"required": ["new_entities"]
+	}`),
+}
+
 // enrichmentResponse represents the expected JSON output from the LLM.
 type enrichmentResponse struct {
 	DescriptionUpdates []models.DescriptionUpdateSuggestion `json:"descriptionUpdates"`
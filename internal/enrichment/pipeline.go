@@ -12,8 +12,11 @@ package enrichment
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
+	"sync"
 
 	"github.com/antonypegg/imagineer/internal/database"
 	"github.com/antonypegg/imagineer/internal/llm"
@@ -49,9 +52,25 @@ func ScopeFromSourceTable(table string) SourceScope {
 }
 
 // RAGContext holds retrieved context shared across all pipeline agents.
+// Agents include it in their prompts through Block, which fits it to a
+// token budget.
 type RAGContext struct {
 	CampaignResults []models.SearchResult
 	GameSystemYAML  string
+	Memories        []models.CampaignMemory
+
+	// ChapterMemory is the memory of the campaign's current chapter,
+	// titled ChapterTitle. It is nil when no chapter is current.
+	ChapterMemory *models.ChapterMemory
+	ChapterTitle  string
+
+	// RecentSessions holds the summaries of the most recently played
+	// sessions, newest first.
+	RecentSessions []models.SessionSummary
+
+	// RelevantEntities holds campaign entities mentioned in the
+	// content other than those the pipeline was given.
+	RelevantEntities []models.Entity
 }
 
 // PipelineInput contains everything needed for a pipeline run.
@@ -69,10 +88,17 @@ type PipelineInput struct {
 	Context       *RAGContext
 	Ontology      *ontology.Ontology
 
+	// Embedder embeds search queries for agents that run their own
+	// semantic searches when only the Go-side embedding index is
+	// available. It may be nil.
+	Embedder llm.Embedder
+
 	// PriorResults holds items produced by agents that ran in
 	// earlier pipeline stages. The pipeline populates this field
 	// before each stage so that downstream agents can inspect
-	// upstream output without a database round-trip.
+	// upstream output without a database round-trip. Agents in the
+	// same stage never see each other's items here, whether or not
+	// they ran concurrently.
 	PriorResults []models.ContentAnalysisItem
 }
 
@@ -94,37 +120,83 @@ type Stage struct {
 	Agents []PipelineAgent
 }
 
+// DefaultAgentConcurrency is the maximum number of agents in a stage
+// that a Pipeline runs at the same time unless WithConcurrency is used.
+const DefaultAgentConcurrency = 4
+
+// Observer is notified of a pipeline's progress while it runs, so
+// that results can be saved and reported before the whole pipeline
+// finishes. AgentFinished may be called from several goroutines at
+// once.
+type Observer interface {
+	// StageStarted is called before the agents of a stage run.
+	StageStarted(ctx context.Context, stage Stage)
+	// AgentFinished is called with the items an agent produced as
+	// soon as it finishes. A failed agent reports no items.
+	AgentFinished(ctx context.Context, stage Stage, agentName string, items []models.ContentAnalysisItem)
+}
+
 // Pipeline orchestrates multi-stage content analysis.
 type Pipeline struct {
-	db     *database.DB
-	stages []Stage
+	db          *database.DB
+	stages      []Stage
+	concurrency int
+	observer    Observer
 }
 
 // NewPipeline creates a new Pipeline with the given database handle and
 // ordered stages. Stages are executed sequentially; agents within each
-// stage are sorted by their declared dependencies.
+// stage are sorted by their declared dependencies, and agents that do
+// not depend on one another run concurrently.
 func NewPipeline(db *database.DB, stages []Stage) *Pipeline {
 	return &Pipeline{
-		db:     db,
-		stages: stages,
+		db:          db,
+		stages:      stages,
+		concurrency: DefaultAgentConcurrency,
 	}
 }
 
+// WithConcurrency sets the maximum number of agents in a stage that
+// run at the same time. Values below 1 are treated as 1, which runs
+// every agent sequentially. It returns p for chaining.
+func (p *Pipeline) WithConcurrency(n int) *Pipeline {
+	if n < 1 {
+		n = 1
+	}
+	p.concurrency = n
+	return p
+}
+
+// WithObserver sets an Observer to notify as stages start and agents
+// finish. It returns p for chaining.
+func (p *Pipeline) WithObserver(o Observer) *Pipeline {
+	p.observer = o
+	return p
+}
+
 // Run executes every stage in order, running each stage's agents in
-// dependency order. Items produced by all agents across all stages are
-// collected and returned. If an individual agent fails, the error is
-// logged and the pipeline continues (graceful degradation).
+// dependency order. Agents whose dependencies have all completed run
+// concurrently, up to the pipeline's concurrency limit. Items produced
+// by all agents across all stages are collected and returned in
+// dependency order, regardless of which agent finished first. If an
+// individual agent fails, the error is logged and the pipeline
+// continues (graceful degradation); the items of the agents that
+// succeeded are returned together with the joined agent errors, so
+// that callers can decide whether a partial result is acceptable.
 func (p *Pipeline) Run(
 	ctx context.Context,
 	provider llm.Provider,
 	input PipelineInput,
 ) ([]models.ContentAnalysisItem, error) {
 	var allItems []models.ContentAnalysisItem
+	var errs []error
 
 	for _, stage := range p.stages {
 		// Make items from prior stages available to agents in
-		// this stage via the PriorResults field.
-		input.PriorResults = allItems
+		// this stage via the PriorResults field. The slice is
+		// clipped so that appending this stage's items never
+		// writes into memory an agent may be reading.
+		input.PriorResults = slices.Clip(allItems)
 
 		sorted, skipped := topologicalSort(stage.Agents)
 
@@ -135,26 +207,103 @@ func (p *Pipeline) Run(
 			)
 		}
 
-		for _, agent := range sorted {
-			items, err := agent.Run(ctx, provider, input)
+		if p.observer != nil {
+			p.observer.StageStarted(ctx, stage)
+		}
+
+		for _, wave := range dependencyWaves(sorted) {
+			items, err := p.runWave(ctx, provider, stage, wave, input)
+			allItems = append(allItems, items...)
 			if err != nil {
-				log.Printf(
-					"pipeline: agent %q in stage %q failed: %v",
-					agent.Name(), stage.Name, err,
-				)
-				continue
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return allItems, errors.Join(errs...)
+}
+
+// runWave runs a set of mutually independent agents concurrently,
+// bounded by the pipeline's concurrency limit, and returns their items
+// concatenated in the order the agents were given, along with the
+// joined errors of any agents that failed.
+func (p *Pipeline) runWave(
+	ctx context.Context,
+	provider llm.Provider,
+	stage Stage,
+	agents []PipelineAgent,
+	input PipelineInput,
+) ([]models.ContentAnalysisItem, error) {
+	results := make([][]models.ContentAnalysisItem, len(agents))
+	errs := make([]error, len(agents))
+	sem := make(chan struct{}, max(p.concurrency, 1))
+
+	var wg sync.WaitGroup
+	for i, agent := range agents {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i], errs[i] = runAgent(ctx, provider, stage, agent, input)
+			if p.observer != nil {
+				p.observer.AgentFinished(ctx, stage, agent.Name(), results[i])
 			}
+		}()
+	}
+	wg.Wait()
+
+	return slices.Concat(results...), errors.Join(errs...)
+}
+
+// runAgent runs a single agent with its name in the context, so that
+// its token usage is attributed to it, and tags every item it produces
+// with that name. A failing agent is logged and yields no items.
+func runAgent(
+	ctx context.Context,
+	provider llm.Provider,
+	stage Stage,
+	agent PipelineAgent,
+	input PipelineInput,
+) ([]models.ContentAnalysisItem, error) {
+	agentCtx := llm.WithAgentName(ctx, agent.Name())
+	items, err := agent.Run(agentCtx, provider, input)
+	if err != nil {
+		log.Printf(
+			"pipeline: agent %q in stage %q failed: %v",
+			agent.Name(), stage.Name, err,
+		)
+		return nil, fmt.Errorf("agent %s failed: %w", agent.Name(), err)
+	}
+
+	for i := range items {
+		items[i].AgentName = agent.Name()
+	}
+	return items, nil
+}
 
-			// Tag every item with the agent that produced it.
-			for i := range items {
-				items[i].AgentName = agent.Name()
+// dependencyWaves groups topologically sorted agents into waves that
+// can run concurrently. An agent is placed in the wave after the
+// latest wave containing one of its dependencies; dependencies outside
+// the stage are ignored. Agents keep their sorted order within a wave.
+func dependencyWaves(sorted []PipelineAgent) [][]PipelineAgent {
+	level := make(map[string]int, len(sorted))
+	var waves [][]PipelineAgent
+	for _, agent := range sorted {
+		lvl := 0
+		for _, dep := range agent.DependsOn() {
+			if l, ok := level[dep]; ok && l+1 > lvl {
+				lvl = l + 1
 			}
+		}
+		level[agent.Name()] = lvl
 
-			allItems = append(allItems, items...)
+		if lvl == len(waves) {
+			waves = append(waves, nil)
 		}
+		waves[lvl] = append(waves[lvl], agent)
 	}
-
-	return allItems, nil
+	return waves
 }
 
 // topologicalSort orders agents so that each agent runs after all of
@@ -14,7 +14,10 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/antonypegg/imagineer/internal/llm"
 	"github.com/antonypegg/imagineer/internal/models"
@@ -35,6 +38,7 @@ type mockPipelineAgent struct {
 	err       error
 	called    bool
 	callCount int
+	ctxAgent  string
 }
 
 func (m *mockPipelineAgent) Name() string        { return m.name }
@@ -47,6 +51,7 @@ func (m *mockPipelineAgent) Run(
 ) ([]models.ContentAnalysisItem, error) {
 	m.called = true
 	m.callCount++
+	m.ctxAgent = llm.AgentName(ctx)
 	if m.err != nil {
 		return nil, m.err
 	}
@@ -145,13 +150,31 @@ func TestPipelineRun_AgentFailure(t *testing.T) {
 		Content:    "test content",
 	})
 
-	require.NoError(t, err, "pipeline should not return an error on agent failure")
-	assert.Len(t, items, 1)
+	require.Error(t, err, "pipeline should report the agent failure")
+	assert.ErrorContains(t, err, "failing-agent")
+	assert.Len(t, items, 1, "items from healthy agents should still be returned")
 	assert.Equal(t, "survived", items[0].MatchedText)
 	assert.True(t, failingAgent.called, "failing agent should still have been called")
 	assert.True(t, healthyAgent.called, "healthy agent should have been called despite prior failure")
 }
 
+func TestPipelineRun_QuotaErrorIsReturned(t *testing.T) {
+	quota := &llm.QuotaExceededError{Provider: "anthropic", Message: "quota exhausted"}
+	pipeline := NewPipeline(nil, []Stage{
+		{Name: "analysis", Phase: "analysis", Agents: []PipelineAgent{
+			&mockPipelineAgent{name: "ttrpg-expert", err: quota},
+			&mockPipelineAgent{name: "canon-expert", err: errors.New("timeout")},
+		}},
+	})
+
+	_, err := pipeline.Run(context.Background(), nil, PipelineInput{})
+
+	var qe *llm.QuotaExceededError
+	require.ErrorAs(t, err, &qe)
+	assert.Same(t, quota, qe)
+	assert.ErrorContains(t, err, "timeout")
+}
+
 func TestPipelineRun_EmptyPipeline(t *testing.T) {
 	pipeline := NewPipeline(nil, []Stage{})
 
@@ -239,6 +262,22 @@ func TestPipelineRun_AgentNameTaggingMultipleAgents(t *testing.T) {
 	assert.Equal(t, "agent-beta", items[1].AgentName)
 }
 
+func TestPipelineRun_AgentNameInContext(t *testing.T) {
+	agentA := &mockPipelineAgent{name: "agent-alpha"}
+	agentB := &mockPipelineAgent{name: "agent-beta"}
+
+	pipeline := NewPipeline(nil, []Stage{
+		{Name: "analysis", Phase: "analysis", Agents: []PipelineAgent{agentA}},
+		{Name: "enrichment", Phase: "enrichment", Agents: []PipelineAgent{agentB}},
+	})
+
+	_, err := pipeline.Run(context.Background(), nil, PipelineInput{CampaignID: 1})
+
+	require.NoError(t, err)
+	assert.Equal(t, "agent-alpha", agentA.ctxAgent)
+	assert.Equal(t, "agent-beta", agentB.ctxAgent)
+}
+
 func TestPipelineRun_ContextCancellation(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -258,13 +297,13 @@ func TestPipelineRun_ContextCancellation(t *testing.T) {
 		},
 	}
 
-	// The pipeline runs agents sequentially. After the canceller runs
-	// and cancels the context, the second agent's Run call will receive
-	// a cancelled context. However, the pipeline itself does not check
-	// ctx.Err() between agents -- it is up to each agent to respect
-	// context cancellation. Our mock agents do not check context, so
-	// both agents will run. This test verifies that the pipeline does
-	// not panic and returns results from all agents that completed.
+	// The two agents are independent, so they may run concurrently and
+	// the second agent's Run call may or may not receive a cancelled
+	// context. The pipeline itself does not check ctx.Err() between
+	// agents -- it is up to each agent to respect context cancellation.
+	// Our mock agents do not check context, so both agents will run.
+	// This test verifies that the pipeline does not panic and returns
+	// results from all agents that completed, in agent order.
 	pipeline := NewPipeline(nil, []Stage{
 		{
 			Name:  "analysis",
@@ -312,6 +351,230 @@ func (a *contextCancellerAgent) Run(
 	return a.items, nil
 }
 
+// trackingAgent is a PipelineAgent that records how many agents are
+// running at once and, when a barrier is set, waits until every agent
+// sharing the barrier has started.
+type trackingAgent struct {
+	name     string
+	deps     []string
+	inFlight *atomic.Int32
+	maxSeen  *atomic.Int32
+	barrier  *sync.WaitGroup
+	finished atomic.Bool
+	prior    []models.ContentAnalysisItem
+	onRun    func()
+}
+
+func (a *trackingAgent) Name() string        { return a.name }
+func (a *trackingAgent) DependsOn() []string { return a.deps }
+
+func (a *trackingAgent) Run(
+	ctx context.Context,
+	provider llm.Provider,
+	input PipelineInput,
+) ([]models.ContentAnalysisItem, error) {
+	n := a.inFlight.Add(1)
+	defer a.inFlight.Add(-1)
+	for {
+		m := a.maxSeen.Load()
+		if n <= m || a.maxSeen.CompareAndSwap(m, n) {
+			break
+		}
+	}
+
+	a.prior = input.PriorResults
+	if a.onRun != nil {
+		a.onRun()
+	}
+
+	if a.barrier != nil {
+		a.barrier.Done()
+		done := make(chan struct{})
+		go func() {
+			a.barrier.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			return nil, errors.New("timed out waiting for sibling agents")
+		}
+	} else {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	a.finished.Store(true)
+	return []models.ContentAnalysisItem{{MatchedText: a.name}}, nil
+}
+
+func newTrackingAgents(names ...string) []*trackingAgent {
+	var inFlight, maxSeen atomic.Int32
+	agents := make([]*trackingAgent, len(names))
+	for i, name := range names {
+		agents[i] = &trackingAgent{name: name, inFlight: &inFlight, maxSeen: &maxSeen}
+	}
+	return agents
+}
+
+func TestPipelineRun_IndependentAgentsRunConcurrently(t *testing.T) {
+	agents := newTrackingAgents("ttrpg-expert", "canon-expert")
+	var barrier sync.WaitGroup
+	barrier.Add(len(agents))
+	for _, a := range agents {
+		a.barrier = &barrier
+	}
+
+	pipeline := NewPipeline(nil, []Stage{
+		{Name: "analysis", Phase: "analysis", Agents: []PipelineAgent{agents[0], agents[1]}},
+	})
+
+	items, err := pipeline.Run(context.Background(), nil, PipelineInput{CampaignID: 1})
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), agents[0].maxSeen.Load())
+	require.Len(t, items, 2)
+	// Items are returned in agent order, not completion order.
+	assert.Equal(t, "ttrpg-expert", items[0].AgentName)
+	assert.Equal(t, "canon-expert", items[1].AgentName)
+}
+
+func TestPipelineRun_ConcurrencyLimit(t *testing.T) {
+	agents := newTrackingAgents("a", "b", "c", "d")
+	stageAgents := make([]PipelineAgent, len(agents))
+	for i, a := range agents {
+		stageAgents[i] = a
+	}
+
+	pipeline := NewPipeline(nil, []Stage{
+		{Name: "analysis", Phase: "analysis", Agents: stageAgents},
+	}).WithConcurrency(1)
+
+	items, err := pipeline.Run(context.Background(), nil, PipelineInput{CampaignID: 1})
+
+	require.NoError(t, err)
+	assert.Len(t, items, 4)
+	assert.Equal(t, int32(1), agents[0].maxSeen.Load())
+}
+
+func TestPipelineRun_DependentAgentWaitsForDependency(t *testing.T) {
+	agents := newTrackingAgents("enrichment", "graph-expert")
+	enrich, graph := agents[0], agents[1]
+	graph.deps = []string{"enrichment"}
+
+	var enrichDoneBeforeGraph bool
+	graph.onRun = func() { enrichDoneBeforeGraph = enrich.finished.Load() }
+
+	prior := &mockPipelineAgent{
+		name:  "ttrpg-expert",
+		items: []models.ContentAnalysisItem{{MatchedText: "from-analysis"}},
+	}
+
+	// List the dependent agent first to make sure ordering comes from
+	// the declared dependency rather than the stage layout.
+	pipeline := NewPipeline(nil, []Stage{
+		{Name: "analysis", Phase: "analysis", Agents: []PipelineAgent{prior}},
+		{Name: "enrichment", Phase: "enrichment", Agents: []PipelineAgent{graph, enrich}},
+	})
+
+	items, err := pipeline.Run(context.Background(), nil, PipelineInput{CampaignID: 1})
+
+	require.NoError(t, err)
+	assert.True(t, enrichDoneBeforeGraph)
+	assert.Equal(t, int32(1), enrich.maxSeen.Load())
+	require.Len(t, items, 3)
+	assert.Equal(t, "enrichment", items[1].AgentName)
+	assert.Equal(t, "graph-expert", items[2].AgentName)
+
+	// PriorResults only carries items from earlier stages.
+	for _, a := range agents {
+		require.Len(t, a.prior, 1, a.name)
+		assert.Equal(t, "from-analysis", a.prior[0].MatchedText)
+	}
+}
+
+func TestDependencyWaves(t *testing.T) {
+	agents := []PipelineAgent{
+		&mockPipelineAgent{name: "a"},
+		&mockPipelineAgent{name: "b", deps: []string{"a"}},
+		&mockPipelineAgent{name: "c"},
+		&mockPipelineAgent{name: "d", deps: []string{"b", "c", "external"}},
+	}
+	sorted, skipped := topologicalSort(agents)
+	require.Empty(t, skipped)
+
+	waves := dependencyWaves(sorted)
+
+	names := make([][]string, len(waves))
+	for i, wave := range waves {
+		for _, a := range wave {
+			names[i] = append(names[i], a.Name())
+		}
+	}
+	assert.Equal(t, [][]string{{"a", "c"}, {"b"}, {"d"}}, names)
+}
+
+func TestPipelineWithConcurrency_Minimum(t *testing.T) {
+	pipeline := NewPipeline(nil, nil)
+	assert.Equal(t, DefaultAgentConcurrency, pipeline.concurrency)
+	assert.Equal(t, 1, pipeline.WithConcurrency(0).concurrency)
+}
+
+// recordingObserver records the notifications it receives.
+type recordingObserver struct {
+	mu       sync.Mutex
+	stages   []string
+	finished map[string]int
+}
+
+func (o *recordingObserver) StageStarted(_ context.Context, stage Stage) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.stages = append(o.stages, stage.Name)
+}
+
+func (o *recordingObserver) AgentFinished(
+	_ context.Context,
+	_ Stage,
+	agentName string,
+	items []models.ContentAnalysisItem,
+) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.finished == nil {
+		o.finished = make(map[string]int)
+	}
+	o.finished[agentName] = len(items)
+}
+
+func TestPipelineRun_Observer(t *testing.T) {
+	expert := &mockPipelineAgent{
+		name:  "ttrpg-expert",
+		items: []models.ContentAnalysisItem{{MatchedText: "a"}, {MatchedText: "b"}},
+	}
+	failing := &mockPipelineAgent{name: "canon-expert", err: errors.New("boom")}
+	graph := &mockPipelineAgent{
+		name:  "graph-expert",
+		items: []models.ContentAnalysisItem{{MatchedText: "c"}},
+	}
+
+	observer := &recordingObserver{}
+	pipeline := NewPipeline(nil, []Stage{
+		{Name: "analysis", Phase: "analysis", Agents: []PipelineAgent{expert, failing}},
+		{Name: "enrichment", Phase: "enrichment", Agents: []PipelineAgent{graph}},
+	}).WithObserver(observer)
+
+	items, err := pipeline.Run(context.Background(), nil, PipelineInput{})
+	assert.ErrorContains(t, err, "boom")
+	assert.Len(t, items, 3)
+
+	assert.Equal(t, []string{"analysis", "enrichment"}, observer.stages)
+	assert.Equal(t, map[string]int{
+		"ttrpg-expert": 2,
+		"canon-expert": 0,
+		"graph-expert": 1,
+	}, observer.finished)
+}
+
 // ---------------------------------------------------------------------------
 // topologicalSort tests
 // ---------------------------------------------------------------------------
@@ -23,6 +23,49 @@ import (
 // from the source content in the user prompt.
 const maxContentChars = 4000
 
+// entityContextBudget is the token budget for the campaign context
+// included when enriching each entity. It is smaller than
+// DefaultContextBudget because the context is repeated for every
+// entity in the content.
+const entityContextBudget = 6000
+
+// memoryTypeLabels are the headings used for each campaign memory type
+// in prompts.
+var memoryTypeLabels = map[models.MemoryType]string{
+	models.MemoryTypePremise:        "Premise",
+	models.MemoryTypeTheme:          "Theme",
+	models.MemoryTypeFactionSummary: "Faction",
+	models.MemoryTypePlotThread:     "Plot thread",
+	models.MemoryTypeGMNote:         "GM note",
+}
+
+// campaignMemoryIntro introduces the campaign memory section of a
+// prompt.
+const campaignMemoryIntro = "Long-term facts about this campaign (premise, themes, " +
+	"factions and plot threads). Keep suggestions consistent with them:"
+
+// FormatCampaignMemories renders campaign memories as a "Campaign
+// Memory" prompt section, in full and in the order given, for prompts
+// that do not take a RAGContext. It returns an empty string when there
+// are no memories. The section ends with a single newline so callers
+// control the spacing around it.
+func FormatCampaignMemories(memories []models.CampaignMemory) string {
+	if len(memories) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("## Campaign Memory\n\n")
+	b.WriteString(campaignMemoryIntro)
+	b.WriteString("\n\n")
+	for _, item := range memoryItems(memories) {
+		b.WriteString(item.Full)
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
 // buildSystemPrompt returns the system prompt for the enrichment LLM
 // call. It instructs the model to act as a TTRPG campaign analyst and
 // return structured JSON. When an ontology is provided, the prompt
@@ -244,27 +287,13 @@ func buildUserPrompt(input EnrichmentInput) string {
 		b.WriteString("\n")
 	}
 
-	// Campaign context from RAG (vector search results).
-	if len(input.CampaignResults) > 0 {
-		fmt.Fprintf(&b, "## Campaign Context\n\n")
-		fmt.Fprintf(&b, "Related content from the campaign (for continuity and context):\n\n")
-		for _, r := range input.CampaignResults {
-			chunk := r.ChunkContent
-			if len([]rune(chunk)) > 300 {
-				chunk = string([]rune(chunk)[:300]) + "..."
-			}
-			fmt.Fprintf(&b, "- **%s** (%s): %s\n",
-				r.SourceName, r.SourceTable, chunk)
-		}
+	// Shared campaign context: memory, recent play, related content
+	// and the game system schema.
+	if block := input.Context.Block(entityContextBudget); block != "" {
+		b.WriteString(block)
 		b.WriteString("\n")
 	}
 
-	// Game system schema from RAG.
-	if input.GameSystemYAML != "" {
-		fmt.Fprintf(&b, "## Game System Schema\n\n")
-		fmt.Fprintf(&b, "```yaml\n%s\n```\n\n", input.GameSystemYAML)
-	}
-
 	b.WriteString("Analyse the source content and produce enrichment ")
 	b.WriteString("suggestions for the entity above. Respond with JSON only.")
 
@@ -27,8 +27,7 @@ type RevisionInput struct {
 	AcceptedItems   []models.ContentAnalysisItem // Accepted Stage 1 findings
 	SourceTable     string                       // e.g., "chapters", "sessions"
 	SourceID        int64
-	GameSystemYAML  string                // Optional game system context
-	CampaignResults []models.SearchResult // RAG: campaign search results
+	Context         *RAGContext // RAG: shared campaign context
 }
 
 // RevisionResult contains the generated revision.
@@ -69,7 +68,7 @@ func (ra *RevisionAgent) GenerateRevision(
 	systemPrompt := buildRevisionSystemPrompt()
 	userPrompt := buildRevisionUserPrompt(input)
 
-	resp, err := provider.Complete(ctx, llm.CompletionRequest{
+	resp, err := provider.Complete(llm.WithAgentName(ctx, "revision"), llm.CompletionRequest{
 		SystemPrompt: systemPrompt,
 		UserPrompt:   userPrompt,
 		MaxTokens:    8192,
@@ -128,24 +127,8 @@ func buildRevisionUserPrompt(input RevisionInput) string {
 		b.WriteString("\n")
 	}
 
-	if input.GameSystemYAML != "" {
-		b.WriteString("## Game System Context\n\n")
-		b.WriteString("```yaml\n")
-		b.WriteString(input.GameSystemYAML)
-		b.WriteString("\n```\n\n")
-	}
-
-	if len(input.CampaignResults) > 0 {
-		b.WriteString("## Campaign Context\n\n")
-		b.WriteString("Related content from the campaign (for continuity):\n\n")
-		for _, r := range input.CampaignResults {
-			chunk := r.ChunkContent
-			if len([]rune(chunk)) > 300 {
-				chunk = string([]rune(chunk)[:300]) + "..."
-			}
-			fmt.Fprintf(&b, "- **%s** (%s): %s\n",
-				r.SourceName, r.SourceTable, chunk)
-		}
+	if block := input.Context.Block(DefaultContextBudget); block != "" {
+		b.WriteString(block)
 		b.WriteString("\n")
 	}
 
@@ -237,12 +237,12 @@ func TestRevisionAgent_IncludesGameSystem(t *testing.T) {
 					SuggestedContent: json.RawMessage(suggestedContent),
 				},
 			},
-			GameSystemYAML: gameYAML,
+			Context: &RAGContext{GameSystemYAML: gameYAML},
 		},
 	)
 
 	require.NoError(t, err)
-	assert.Contains(t, captured.UserPrompt, "## Game System Context")
+	assert.Contains(t, captured.UserPrompt, "## Game System Schema")
 	assert.Contains(t, captured.UserPrompt, "Call of Cthulhu 7e")
 	assert.Contains(t, captured.UserPrompt, "Spot Hidden")
 }
@@ -440,12 +440,14 @@ func TestRevisionAgent_CampaignContext(t *testing.T) {
 					SuggestedContent: suggestedContent,
 				},
 			},
-			CampaignResults: []models.SearchResult{
-				{
-					SourceTable:  "chapters",
-					SourceID:     2,
-					SourceName:   "Chapter 2",
-					ChunkContent: "The manor was built in 1823 by Lord Ashton.",
+			Context: &RAGContext{
+				CampaignResults: []models.SearchResult{
+					{
+						SourceTable:  "chapters",
+						SourceID:     2,
+						SourceName:   "Chapter 2",
+						ChunkContent: "The manor was built in 1823 by Lord Ashton.",
+					},
 				},
 			},
 		},
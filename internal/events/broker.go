@@ -0,0 +1,240 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+// Package events pushes content analysis job events to in-process
+// subscribers. Events are written to the analysis_job_events table,
+// which announces each insert with Postgres NOTIFY; a Broker holds one
+// LISTEN connection per server and fans the events out to the
+// subscribers of each job, so that watching a job costs no database
+// queries while nothing is happening.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/antonypegg/imagineer/internal/database"
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+const (
+	// subscriptionBuffer is the number of events a subscription holds
+	// for a subscriber that is not keeping up. A subscription whose
+	// buffer fills is closed.
+	subscriptionBuffer = 64
+
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 30 * time.Second
+)
+
+// Store is the persistence used by a Broker. *database.DB implements
+// it.
+type Store interface {
+	Listen(ctx context.Context, channel string, onListening func(), onNotify func(payload string)) error
+	GetAnalysisJobEvent(ctx context.Context, id int64) (*models.AnalysisJobEvent, error)
+}
+
+// Broker fans out analysis job events announced via LISTEN/NOTIFY to
+// subscribers in this process.
+//
+// A subscription is closed whenever the broker may have missed events
+// for it: when its buffer overflows, when the LISTEN connection is
+// lost or re-established, or when the broker stops. Subscribers are
+// expected to read any events they missed from the database, using the
+// ID of the last event they received, and subscribe again.
+type Broker struct {
+	store Store
+
+	mu      sync.Mutex
+	subs    map[int64]map[*Subscription]struct{}
+	stopped bool
+
+	stop context.CancelFunc
+	done chan struct{}
+}
+
+// Subscription delivers the events of one analysis job.
+type Subscription struct {
+	broker *Broker
+	jobID  int64
+	events chan models.AnalysisJobEvent
+	closed bool // guarded by broker.mu
+}
+
+// Events returns the channel on which events are delivered. It is
+// closed when the subscription ends.
+func (s *Subscription) Events() <-chan models.AnalysisJobEvent {
+	return s.events
+}
+
+// Close ends the subscription. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.remove(s)
+}
+
+// NewBroker creates a broker backed by store. Call Start to begin
+// listening.
+func NewBroker(store Store) *Broker {
+	return &Broker{
+		store: store,
+		subs:  make(map[int64]map[*Subscription]struct{}),
+	}
+}
+
+// Start begins listening for events until ctx is done or Stop is
+// called. The listening connection is re-established with backoff if
+// it fails.
+func (b *Broker) Start(ctx context.Context) {
+	ctx, b.stop = context.WithCancel(ctx)
+	b.done = make(chan struct{})
+	go b.listen(ctx)
+}
+
+// Stop stops listening and closes every subscription.
+func (b *Broker) Stop() {
+	if b.stop != nil {
+		b.stop()
+		<-b.done
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stopped = true
+	b.closeAll()
+}
+
+// Subscribe returns a subscription to the events of an analysis job.
+// Events published before Subscribe returns are not delivered, so
+// callers should subscribe before reading past events from the
+// database. A subscription taken after Stop is already closed.
+func (b *Broker) Subscribe(jobID int64) *Subscription {
+	s := &Subscription{
+		broker: b,
+		jobID:  jobID,
+		events: make(chan models.AnalysisJobEvent, subscriptionBuffer),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.stopped {
+		s.closed = true
+		close(s.events)
+		return s
+	}
+	if b.subs[jobID] == nil {
+		b.subs[jobID] = make(map[*Subscription]struct{})
+	}
+	b.subs[jobID][s] = struct{}{}
+	return s
+}
+
+// listen keeps a LISTEN connection open until ctx is done.
+func (b *Broker) listen(ctx context.Context) {
+	defer close(b.done)
+
+	delay := minReconnectDelay
+	for {
+		err := b.store.Listen(ctx, database.AnalysisJobEventsChannel,
+			func() {
+				// Events may have been published while no
+				// connection was listening.
+				delay = minReconnectDelay
+				b.mu.Lock()
+				b.closeAll()
+				b.mu.Unlock()
+			},
+			func(payload string) { b.dispatch(ctx, payload) },
+		)
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Printf("events: listener failed, reconnecting in %s: %v", delay, err)
+		b.mu.Lock()
+		b.closeAll()
+		b.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxReconnectDelay)
+	}
+}
+
+// dispatch delivers the event announced by a notification to the
+// subscribers of its job. The event is only read from the database
+// when the job has subscribers.
+func (b *Broker) dispatch(ctx context.Context, payload string) {
+	var n struct {
+		ID    int64 `json:"id"`
+		JobID int64 `json:"jobId"`
+	}
+	if err := json.Unmarshal([]byte(payload), &n); err != nil {
+		log.Printf("events: ignoring malformed notification %q: %v", payload, err)
+		return
+	}
+
+	b.mu.Lock()
+	watched := len(b.subs[n.JobID]) > 0
+	b.mu.Unlock()
+	if !watched {
+		return
+	}
+
+	event, err := b.store.GetAnalysisJobEvent(ctx, n.ID)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for s := range b.subs[n.JobID] {
+		if err != nil {
+			// Subscribers recover the event from the database.
+			b.remove(s)
+			continue
+		}
+		select {
+		case s.events <- *event:
+		default:
+			b.remove(s)
+		}
+	}
+	if err != nil && ctx.Err() == nil {
+		log.Printf("events: failed to read event %d: %v", n.ID, err)
+	}
+}
+
+// remove closes a subscription and forgets it. b.mu must be held.
+func (b *Broker) remove(s *Subscription) {
+	if s.closed {
+		return
+	}
+	s.closed = true
+	close(s.events)
+
+	delete(b.subs[s.jobID], s)
+	if len(b.subs[s.jobID]) == 0 {
+		delete(b.subs, s.jobID)
+	}
+}
+
+// closeAll closes every subscription. b.mu must be held.
+func (b *Broker) closeAll() {
+	for _, subs := range b.subs {
+		for s := range subs {
+			b.remove(s)
+		}
+	}
+}
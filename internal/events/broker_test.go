@@ -0,0 +1,218 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+// fakeStore feeds notifications to a Broker from a channel and serves
+// events from memory. Each notification is acknowledged once the
+// broker has dispatched it.
+type fakeStore struct {
+	notify    chan string
+	handled   chan struct{}
+	listening chan struct{}
+	fail      chan error
+
+	mu     sync.Mutex
+	events map[int64]models.AnalysisJobEvent
+	reads  int
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		notify:    make(chan string),
+		handled:   make(chan struct{}),
+		listening: make(chan struct{}, 10),
+		fail:      make(chan error),
+		events:    make(map[int64]models.AnalysisJobEvent),
+	}
+}
+
+func (s *fakeStore) Listen(ctx context.Context, _ string, onListening func(), onNotify func(string)) error {
+	onListening()
+	s.listening <- struct{}{}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-s.fail:
+			return err
+		case payload := <-s.notify:
+			onNotify(payload)
+			s.handled <- struct{}{}
+		}
+	}
+}
+
+func (s *fakeStore) GetAnalysisJobEvent(_ context.Context, id int64) (*models.AnalysisJobEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reads++
+	e, ok := s.events[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &e, nil
+}
+
+// publish stores an event and announces it to the broker.
+func (s *fakeStore) publish(id, jobID int64, eventType string) {
+	s.mu.Lock()
+	s.events[id] = models.AnalysisJobEvent{ID: id, JobID: jobID, EventType: eventType}
+	s.mu.Unlock()
+	s.notify <- fmt.Sprintf(`{"id":%d,"jobId":%d}`, id, jobID)
+	<-s.handled
+}
+
+func startBroker(t *testing.T) (*Broker, *fakeStore) {
+	t.Helper()
+	store := newFakeStore()
+	b := NewBroker(store)
+	b.Start(context.Background())
+	t.Cleanup(b.Stop)
+	<-store.listening
+	return b, store
+}
+
+func receive(t *testing.T, sub *Subscription) (models.AnalysisJobEvent, bool) {
+	t.Helper()
+	select {
+	case ev, ok := <-sub.Events():
+		return ev, ok
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+		return models.AnalysisJobEvent{}, false
+	}
+}
+
+func TestBroker_DeliversEventsToJobSubscribers(t *testing.T) {
+	b, store := startBroker(t)
+
+	sub1 := b.Subscribe(1)
+	sub2 := b.Subscribe(1)
+	other := b.Subscribe(2)
+
+	store.publish(10, 1, models.AnalysisEventItemCreated)
+	store.publish(11, 1, models.AnalysisEventJobComplete)
+
+	for _, sub := range []*Subscription{sub1, sub2} {
+		ev, ok := receive(t, sub)
+		if !ok || ev.ID != 10 {
+			t.Fatalf("expected event 10, got %+v (open=%v)", ev, ok)
+		}
+		ev, ok = receive(t, sub)
+		if !ok || ev.ID != 11 || ev.EventType != models.AnalysisEventJobComplete {
+			t.Fatalf("expected event 11, got %+v (open=%v)", ev, ok)
+		}
+	}
+
+	select {
+	case ev := <-other.Events():
+		t.Errorf("subscriber of another job received %+v", ev)
+	default:
+	}
+}
+
+func TestBroker_SkipsUnwatchedJobs(t *testing.T) {
+	b, store := startBroker(t)
+
+	sub := b.Subscribe(1)
+	store.publish(1, 99, models.AnalysisEventItemCreated)
+	store.publish(2, 1, models.AnalysisEventItemCreated)
+	receive(t, sub)
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.reads != 1 {
+		t.Errorf("expected only the watched event to be read, got %d reads", store.reads)
+	}
+}
+
+func TestBroker_CloseUnsubscribes(t *testing.T) {
+	b, store := startBroker(t)
+
+	sub := b.Subscribe(1)
+	sub.Close()
+	sub.Close()
+
+	if _, ok := <-sub.Events(); ok {
+		t.Error("expected closed subscription")
+	}
+
+	store.publish(1, 1, models.AnalysisEventItemCreated)
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.reads != 0 {
+		t.Errorf("expected no reads without subscribers, got %d", store.reads)
+	}
+}
+
+func TestBroker_SlowSubscriberIsDropped(t *testing.T) {
+	b, store := startBroker(t)
+
+	sub := b.Subscribe(1)
+	for i := range subscriptionBuffer + 1 {
+		store.publish(int64(i+1), 1, models.AnalysisEventItemCreated)
+	}
+
+	count := 0
+	for range sub.Events() {
+		count++
+	}
+	if count != subscriptionBuffer {
+		t.Errorf("expected %d buffered events before close, got %d", subscriptionBuffer, count)
+	}
+}
+
+func TestBroker_ConnectionLossClosesSubscriptions(t *testing.T) {
+	b, store := startBroker(t)
+
+	sub := b.Subscribe(1)
+	store.fail <- errors.New("connection reset")
+
+	if _, ok := receive(t, sub); ok {
+		t.Fatal("expected subscription to be closed when the listener fails")
+	}
+
+	// The broker reconnects, and new subscriptions receive events.
+	select {
+	case <-store.listening:
+	case <-time.After(3 * time.Second):
+		t.Fatal("broker did not reconnect")
+	}
+	sub = b.Subscribe(1)
+	store.publish(5, 1, models.AnalysisEventPhaseChanged)
+	if ev, ok := receive(t, sub); !ok || ev.ID != 5 {
+		t.Errorf("expected event 5 after reconnect, got %+v (open=%v)", ev, ok)
+	}
+}
+
+func TestBroker_SubscribeAfterStop(t *testing.T) {
+	b, _ := startBroker(t)
+	live := b.Subscribe(1)
+	b.Stop()
+
+	if _, ok := <-live.Events(); ok {
+		t.Error("expected Stop to close subscriptions")
+	}
+	if _, ok := <-b.Subscribe(1).Events(); ok {
+		t.Error("expected subscription after Stop to be closed")
+	}
+}
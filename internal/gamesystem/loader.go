@@ -0,0 +1,107 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package gamesystem
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// statBlockSections lists the schema sections that define stat blocks.
+// An entity holds each block in the attribute of the same name, e.g.
+// a Call of Cthulhu NPC's characteristics in "characteristics".
+var statBlockSections = []string{
+	"characteristics",
+	"ability_scores",
+	"primary_attributes",
+	"action_ratings",
+}
+
+// ParseSchema parses a game system schema file.
+func ParseSchema(data []byte) (*Schema, error) {
+	var s Schema
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse game system schema: %w", err)
+	}
+	if s.System.Code == "" {
+		return nil, fmt.Errorf("parse game system schema: missing system code")
+	}
+	s.index()
+	return &s, nil
+}
+
+// LoadSchema reads and parses a game system schema file.
+func LoadSchema(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read game system schema: %w", err)
+	}
+	s, err := ParseSchema(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// LoadRegistry loads every *.yaml schema file directly in dir.
+// Subdirectories, such as the ontology, are not read.
+func LoadRegistry(dir string) (*Registry, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("list game system schemas: %w", err)
+	}
+
+	r := &Registry{schemas: make(map[string]*Schema, len(paths))}
+	for _, path := range paths {
+		s, err := LoadSchema(path)
+		if err != nil {
+			return nil, err
+		}
+		r.schemas[s.System.Code] = s
+	}
+	return r, nil
+}
+
+// index builds the lookups used by ValidateAttributes.
+func (s *Schema) index() {
+	blocks := map[string]map[string]Stat{
+		"characteristics":    s.Characteristics,
+		"ability_scores":     s.AbilityScores,
+		"primary_attributes": s.PrimaryAttributes,
+	}
+	actions := make(map[string]Stat)
+	for _, group := range s.ActionRatings {
+		for _, action := range group {
+			actions[action] = Stat{Name: action}
+		}
+	}
+	blocks["action_ratings"] = actions
+
+	s.statBlocks = make(map[string]map[string]Stat)
+	for _, section := range statBlockSections {
+		if len(blocks[section]) == 0 {
+			continue
+		}
+		stats := make(map[string]Stat, len(blocks[section]))
+		for key, stat := range blocks[section] {
+			stats[normalizeName(key)] = stat
+		}
+		s.statBlocks[section] = stats
+	}
+
+	s.skills = make(map[string]string, len(s.SampleSkills))
+	for key := range s.SampleSkills {
+		s.skills[normalizeName(key)] = key
+	}
+}
@@ -0,0 +1,52 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package gamesystem
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRegistry(t *testing.T) {
+	r, err := LoadRegistry("../../schemas")
+	require.NoError(t, err)
+
+	for _, code := range []string{"coc-7e", "dnd-5e-2024", "fitd", "gurps-4e"} {
+		assert.NotNil(t, r.Schema(code), code)
+	}
+	assert.Equal(t, 4, r.Len())
+	assert.Nil(t, r.Schema("unknown"))
+}
+
+func TestLoadSchema_StatBlocks(t *testing.T) {
+	coc, err := LoadSchema("../../schemas/coc-7e.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, []int{15, 90}, coc.statBlocks["characteristics"]["str"].Range)
+	assert.Equal(t, "Spot_Hidden", coc.skills["spot_hidden"])
+
+	fitd, err := LoadSchema("../../schemas/fitd.yaml")
+	require.NoError(t, err)
+	assert.Contains(t, fitd.statBlocks["action_ratings"], "hunt")
+	assert.Empty(t, fitd.skills)
+}
+
+func TestParseSchema_MissingCode(t *testing.T) {
+	_, err := ParseSchema([]byte("system:\n  name: \"No Code\"\n"))
+	assert.Error(t, err)
+}
+
+func TestNilRegistry(t *testing.T) {
+	var r *Registry
+	assert.Nil(t, r.Schema("coc-7e"))
+	assert.Equal(t, 0, r.Len())
+}
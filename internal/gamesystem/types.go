@@ -0,0 +1,82 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+// Package gamesystem loads the game system schemas in schemas/*.yaml
+// and validates entity attributes against them.
+package gamesystem
+
+// Schema is the part of a game system schema file used to validate
+// entity attributes. Sections not listed here are ignored.
+type Schema struct {
+	System            SystemInfo                     `yaml:"system"`
+	Characteristics   map[string]Stat                `yaml:"characteristics"`
+	AbilityScores     map[string]Stat                `yaml:"ability_scores"`
+	PrimaryAttributes map[string]Stat                `yaml:"primary_attributes"`
+	ActionRatings     map[string][]string            `yaml:"action_ratings"`
+	SampleSkills      map[string]Skill               `yaml:"sample_skills"`
+	EntityAttributes  map[string]EntityAttributeSpec `yaml:"entity_attributes"`
+
+	// statBlocks maps the normalized name of each attribute holding
+	// a stat block to its stats, by normalized stat name.
+	statBlocks map[string]map[string]Stat
+	// skills maps normalized skill names to their names in the
+	// schema.
+	skills map[string]string
+}
+
+// SystemInfo identifies a game system.
+type SystemInfo struct {
+	Name string `yaml:"name"`
+	Code string `yaml:"code"`
+}
+
+// Stat defines a characteristic, ability score, attribute or action
+// rating. Range is the inclusive [min, max] of its value, if declared.
+type Stat struct {
+	Name  string `yaml:"name"`
+	Type  string `yaml:"type"`
+	Range []int  `yaml:"range"`
+}
+
+// Skill defines one of a system's sample skills. Only the skill's name
+// is used; the other fields differ from system to system.
+type Skill struct {
+	Category string `yaml:"category"`
+}
+
+// EntityAttributeSpec lists the attributes an entity type has in a
+// game system.
+type EntityAttributeSpec struct {
+	Required []string `yaml:"required"`
+	Optional []string `yaml:"optional"`
+}
+
+// Registry holds the schemas of the available game systems, by system
+// code.
+type Registry struct {
+	schemas map[string]*Schema
+}
+
+// Schema returns the schema of the game system with the given code, or
+// nil if there is none. It is safe to call on a nil Registry.
+func (r *Registry) Schema(code string) *Schema {
+	if r == nil {
+		return nil
+	}
+	return r.schemas[code]
+}
+
+// Len returns the number of schemas in the registry.
+func (r *Registry) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.schemas)
+}
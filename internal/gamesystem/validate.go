@@ -0,0 +1,305 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package gamesystem
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+// specializationRe matches a trailing specialization such as the
+// " (Painting)" of "Art/Craft (Painting)".
+var specializationRe = regexp.MustCompile(`\s*\([^)]*\)\s*$`)
+
+// separatorRe matches runs of characters that separate the words of a
+// name.
+var separatorRe = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+
+// rangedEntityTypes are the entity types whose stats are checked
+// against the ranges the schemas declare. Those ranges are for player
+// characters and the people they meet; creatures routinely exceed
+// them (a Mythos horror with STR 200), so their stats are only checked
+// for names and types.
+var rangedEntityTypes = map[string]bool{
+	"pc":  true,
+	"npc": true,
+}
+
+// maxSkillDistance caps the edit distance at which an unknown skill is
+// taken for a misspelling of a known one.
+const maxSkillDistance = 2
+
+// ValidateAttributes checks an entity's attributes against the schema
+// and returns the problems found, in a stable order, or nil if there
+// are none.
+//
+// For entity types the schema lists, attribute names must be among the
+// type's required and optional attributes and required attributes must
+// be present; the entity's name is not an attribute. For every type,
+// stat blocks (characteristics, ability scores, primary attributes and
+// action ratings) must hold known stats with numeric values, within
+// their declared range for PCs and NPCs, and skills must have numeric
+// values. The schemas only list sample skills, so an unknown skill is
+// reported only when it looks like a misspelling of a sample skill.
+func (s *Schema) ValidateAttributes(
+	entityType string,
+	attributes json.RawMessage,
+) []models.AttributeFieldError {
+	var attrs map[string]interface{}
+	if len(attributes) > 0 && string(attributes) != "null" {
+		if err := json.Unmarshal(attributes, &attrs); err != nil {
+			return []models.AttributeFieldError{{
+				Field:   "attributes",
+				Code:    models.AttributeErrorInvalidType,
+				Message: "Attributes must be a JSON object",
+			}}
+		}
+	}
+
+	var errs []models.AttributeFieldError
+	add := func(field, code, format string, args ...interface{}) {
+		errs = append(errs, models.AttributeFieldError{
+			Field:   field,
+			Code:    code,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	spec, hasSpec := s.EntityAttributes[strings.ToLower(entityType)]
+	hasSpec = hasSpec && len(spec.Required)+len(spec.Optional) > 0
+	checkRanges := rangedEntityTypes[strings.ToLower(entityType)]
+	known := make(map[string]bool)
+	for _, names := range [][]string{spec.Required, spec.Optional} {
+		for _, name := range names {
+			known[normalizeName(name)] = true
+		}
+	}
+
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	present := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		name := normalizeName(key)
+		present[name] = true
+		field := "attributes." + key
+
+		if hasSpec && !known[name] {
+			add(field, models.AttributeErrorUnknownAttribute,
+				"%q is not an attribute of %s entities in %s", key, entityType, s.System.Name)
+			continue
+		}
+
+		if stats, ok := s.statBlocks[name]; ok {
+			errs = append(errs, s.validateStatBlock(field, key, stats, attrs[key], checkRanges)...)
+		} else if name == "skills" && len(s.skills) > 0 {
+			errs = append(errs, s.validateSkills(field, attrs[key])...)
+		}
+	}
+
+	for _, name := range spec.Required {
+		if normalizeName(name) == "name" || present[normalizeName(name)] {
+			continue
+		}
+		add("attributes."+name, models.AttributeErrorRequired,
+			"%q is required for %s entities in %s", name, entityType, s.System.Name)
+	}
+
+	return errs
+}
+
+// validateStatBlock checks the stats of the stat block held by the
+// attribute key, and their declared ranges if checkRanges is set.
+func (s *Schema) validateStatBlock(
+	field, key string,
+	stats map[string]Stat,
+	value interface{},
+	checkRanges bool,
+) []models.AttributeFieldError {
+	block, ok := value.(map[string]interface{})
+	if !ok {
+		return []models.AttributeFieldError{{
+			Field:   field,
+			Code:    models.AttributeErrorInvalidType,
+			Message: fmt.Sprintf("%q must be an object of stat values", key),
+		}}
+	}
+
+	var errs []models.AttributeFieldError
+	names := make([]string, 0, len(block))
+	for name := range block {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	label := strings.ReplaceAll(key, "_", " ")
+	for _, name := range names {
+		statField := field + "." + name
+		stat, ok := stats[normalizeName(name)]
+		if !ok {
+			errs = append(errs, models.AttributeFieldError{
+				Field:   statField,
+				Code:    models.AttributeErrorUnknownAttribute,
+				Message: fmt.Sprintf("%q is not one of the %s of %s", name, label, s.System.Name),
+			})
+			continue
+		}
+
+		n, ok := block[name].(float64)
+		if !ok || (stat.Type != "" && n != math.Trunc(n)) {
+			kind := "a number"
+			if stat.Type != "" {
+				kind = "a whole number"
+			}
+			errs = append(errs, models.AttributeFieldError{
+				Field:   statField,
+				Code:    models.AttributeErrorInvalidType,
+				Message: fmt.Sprintf("%s must be %s", name, kind),
+			})
+			continue
+		}
+
+		if checkRanges && len(stat.Range) == 2 && (n < float64(stat.Range[0]) || n > float64(stat.Range[1])) {
+			errs = append(errs, models.AttributeFieldError{
+				Field:   statField,
+				Code:    models.AttributeErrorOutOfRange,
+				Message: fmt.Sprintf("%s must be between %d and %d", name, stat.Range[0], stat.Range[1]),
+			})
+		}
+	}
+	return errs
+}
+
+// validateSkills checks a skills attribute, either an object of skill
+// values or a list of skill names.
+func (s *Schema) validateSkills(field string, value interface{}) []models.AttributeFieldError {
+	var errs []models.AttributeFieldError
+	checkName := func(skillField, name string) {
+		if suggestion, ok := s.misspelledSkill(name); ok {
+			errs = append(errs, models.AttributeFieldError{
+				Field:   skillField,
+				Code:    models.AttributeErrorUnknownSkill,
+				Message: fmt.Sprintf("Unknown skill %q; did you mean %q?", name, suggestion),
+			})
+		}
+	}
+
+	switch skills := value.(type) {
+	case map[string]interface{}:
+		names := make([]string, 0, len(skills))
+		for name := range skills {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			skillField := field + "." + name
+			if _, ok := skills[name].(float64); !ok {
+				errs = append(errs, models.AttributeFieldError{
+					Field:   skillField,
+					Code:    models.AttributeErrorInvalidType,
+					Message: fmt.Sprintf("Skill %q must be a number", name),
+				})
+				continue
+			}
+			checkName(skillField, name)
+		}
+	case []interface{}:
+		for i, v := range skills {
+			skillField := fmt.Sprintf("%s[%d]", field, i)
+			name, ok := v.(string)
+			if !ok {
+				errs = append(errs, models.AttributeFieldError{
+					Field:   skillField,
+					Code:    models.AttributeErrorInvalidType,
+					Message: "Skill names must be strings",
+				})
+				continue
+			}
+			checkName(skillField, name)
+		}
+	default:
+		errs = append(errs, models.AttributeFieldError{
+			Field:   field,
+			Code:    models.AttributeErrorInvalidType,
+			Message: "Skills must be an object of skill values or a list of skill names",
+		})
+	}
+	return errs
+}
+
+// misspelledSkill reports whether name is not a sample skill but is
+// within a few edits of one, returning that skill's name.
+func (s *Schema) misspelledSkill(name string) (string, bool) {
+	normalized := normalizeName(name)
+	if _, ok := s.skills[normalized]; ok {
+		return "", false
+	}
+
+	// Allow fewer edits for shorter names, so that distinct short
+	// skills are not taken for each other.
+	maxDistance := utf8.RuneCountInString(normalized) / 4
+	if maxDistance > maxSkillDistance {
+		maxDistance = maxSkillDistance
+	}
+
+	best, bestDistance := "", maxDistance+1
+	for known, skill := range s.skills {
+		d := editDistance(normalized, known)
+		if d < bestDistance || (d == bestDistance && skill < best) {
+			best, bestDistance = skill, d
+		}
+	}
+	if best == "" {
+		return "", false
+	}
+	return strings.ReplaceAll(best, "_", " "), true
+}
+
+// normalizeName lowercases a name, drops a trailing specialization and
+// joins its words with underscores, so that "Art/Craft (Painting)"
+// matches "Art_Craft" and "Spot Hidden" matches "Spot_Hidden".
+func normalizeName(name string) string {
+	name = specializationRe.ReplaceAllString(name, "")
+	name = separatorRe.ReplaceAllString(strings.ToLower(name), "_")
+	return strings.Trim(name, "_")
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
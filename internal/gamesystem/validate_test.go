@@ -0,0 +1,150 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package gamesystem
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadTestSchema(t *testing.T, code string) *Schema {
+	t.Helper()
+	s, err := LoadSchema("../../schemas/" + code + ".yaml")
+	require.NoError(t, err)
+	return s
+}
+
+func fieldCodes(errs []models.AttributeFieldError) map[string]string {
+	codes := make(map[string]string, len(errs))
+	for _, e := range errs {
+		codes[e.Field] = e.Code
+	}
+	return codes
+}
+
+func TestValidateAttributes_Valid(t *testing.T) {
+	s := loadTestSchema(t, "coc-7e")
+	errs := s.ValidateAttributes("npc", json.RawMessage(`{
+		"occupation": "Antiquarian",
+		"characteristics": {"STR": 50, "DEX": 60, "int": 75},
+		"skills": {"Spot Hidden": 45, "Library_Use": 60, "Art/Craft (Painting)": 40, "Pilot Aircraft": 30}
+	}`))
+	assert.Empty(t, errs)
+}
+
+func TestValidateAttributes_OutOfRangeAndMisspelledSkill(t *testing.T) {
+	s := loadTestSchema(t, "coc-7e")
+	errs := s.ValidateAttributes("npc", json.RawMessage(`{
+		"occupation": "Professor",
+		"characteristics": {"STR": 300, "CON": 45.5, "LCK": 50},
+		"skills": {"Spot Hiden": 45, "Stealth": "high"}
+	}`))
+
+	assert.Equal(t, map[string]string{
+		"attributes.characteristics.STR": models.AttributeErrorOutOfRange,
+		"attributes.characteristics.CON": models.AttributeErrorInvalidType,
+		"attributes.characteristics.LCK": models.AttributeErrorUnknownAttribute,
+		"attributes.skills.Spot Hiden":   models.AttributeErrorUnknownSkill,
+		"attributes.skills.Stealth":      models.AttributeErrorInvalidType,
+	}, fieldCodes(errs))
+
+	for _, e := range errs {
+		if e.Field == "attributes.skills.Spot Hiden" {
+			assert.Contains(t, e.Message, `"Spot Hidden"`)
+		}
+		if e.Field == "attributes.characteristics.STR" {
+			assert.Equal(t, "STR must be between 15 and 90", e.Message)
+		}
+	}
+}
+
+func TestValidateAttributes_UnknownAndRequiredAttributes(t *testing.T) {
+	s := loadTestSchema(t, "coc-7e")
+	errs := s.ValidateAttributes("npc", json.RawMessage(`{"hair": "grey"}`))
+
+	assert.Equal(t, map[string]string{
+		"attributes.hair":       models.AttributeErrorUnknownAttribute,
+		"attributes.occupation": models.AttributeErrorRequired,
+	}, fieldCodes(errs))
+}
+
+func TestValidateAttributes_TypeWithoutSpec(t *testing.T) {
+	s := loadTestSchema(t, "coc-7e")
+
+	// Locations have no attribute list in the schema, so any name is
+	// allowed, but stat blocks are still checked.
+	assert.Empty(t, s.ValidateAttributes("location", json.RawMessage(`{"climate": "damp"}`)))
+	errs := s.ValidateAttributes("location", json.RawMessage(`{"characteristics": "strong"}`))
+	assert.Equal(t, map[string]string{
+		"attributes.characteristics": models.AttributeErrorInvalidType,
+	}, fieldCodes(errs))
+}
+
+func TestValidateAttributes_CreatureStatsNotRangeChecked(t *testing.T) {
+	s := loadTestSchema(t, "coc-7e")
+
+	// Investigator ranges do not apply to Mythos creatures, but their
+	// stats must still be known and numeric.
+	assert.Empty(t, s.ValidateAttributes("creature", json.RawMessage(`{
+		"characteristics": {"STR": 200, "CON": 140, "SIZ": 210, "POW": 100}
+	}`)))
+	errs := s.ValidateAttributes("creature", json.RawMessage(`{
+		"characteristics": {"STR": "huge", "LCK": 50}
+	}`))
+	assert.Equal(t, map[string]string{
+		"attributes.characteristics.STR": models.AttributeErrorInvalidType,
+		"attributes.characteristics.LCK": models.AttributeErrorUnknownAttribute,
+	}, fieldCodes(errs))
+}
+
+func TestValidateAttributes_OtherSystems(t *testing.T) {
+	dnd := loadTestSchema(t, "dnd-5e-2024")
+	errs := dnd.ValidateAttributes("npc", json.RawMessage(`{"ability_scores": {"STR": 31, "WIS": 12}}`))
+	assert.Equal(t, map[string]string{
+		"attributes.ability_scores.STR": models.AttributeErrorOutOfRange,
+	}, fieldCodes(errs))
+
+	fitd := loadTestSchema(t, "fitd")
+	errs = fitd.ValidateAttributes("pc", json.RawMessage(`{
+		"playbook": "Cutter",
+		"action_ratings": {"Skirmish": 2, "Juggle": 1}
+	}`))
+	assert.Equal(t, map[string]string{
+		"attributes.action_ratings.Juggle": models.AttributeErrorUnknownAttribute,
+	}, fieldCodes(errs))
+
+	gurps := loadTestSchema(t, "gurps-4e")
+	assert.Empty(t, gurps.ValidateAttributes("npc", json.RawMessage(`{"primary_attributes": {"ST": 14, "IQ": 9}}`)))
+}
+
+func TestValidateAttributes_NotAnObject(t *testing.T) {
+	s := loadTestSchema(t, "coc-7e")
+	errs := s.ValidateAttributes("npc", json.RawMessage(`[1, 2]`))
+	require.Len(t, errs, 1)
+	assert.Equal(t, "attributes", errs[0].Field)
+	assert.Equal(t, models.AttributeErrorInvalidType, errs[0].Code)
+}
+
+func TestNormalizeName(t *testing.T) {
+	assert.Equal(t, "art_craft", normalizeName("Art/Craft (Painting)"))
+	assert.Equal(t, "spot_hidden", normalizeName(" Spot Hidden "))
+	assert.Equal(t, "credit_rating", normalizeName("Credit_Rating"))
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("stealth", "stealth"))
+	assert.Equal(t, 1, editDistance("spot_hiden", "spot_hidden"))
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+}
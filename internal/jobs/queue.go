@@ -0,0 +1,487 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+// Package jobs runs background work from a durable Postgres-backed
+// queue. Jobs are claimed with FOR UPDATE SKIP LOCKED so any number of
+// workers, in one or many server processes, can share the queue. A
+// worker holds a renewable lease on the job it is running; if the
+// worker dies the lease expires and another worker reclaims the job.
+// Failed jobs are retried with exponential backoff up to their maximum
+// number of attempts.
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+const (
+	// DefaultWorkers is the number of jobs a Queue runs at once.
+	DefaultWorkers = 2
+
+	// DefaultTimeout bounds a single attempt of a job whose kind does
+	// not set its own timeout.
+	DefaultTimeout = 10 * time.Minute
+
+	defaultLeaseDuration = time.Minute
+	defaultPollInterval  = 2 * time.Second
+	waitPollInterval     = 500 * time.Millisecond
+	maxRetryDelay        = 10 * time.Minute
+	finalizeTimeout      = 10 * time.Second
+)
+
+// ErrCancelled is the cause of a job's context being cancelled because
+// the job was cancelled or its lease was lost.
+var ErrCancelled = errors.New("job cancelled")
+
+// Store is the persistence used by a Queue. *database.DB implements it.
+type Store interface {
+	EnqueueBackgroundJob(ctx context.Context, job *models.BackgroundJob) (*models.BackgroundJob, error)
+	GetBackgroundJob(ctx context.Context, id int64) (*models.BackgroundJob, error)
+	ClaimBackgroundJob(ctx context.Context, workerID string, kinds []string, lease time.Duration) (*models.BackgroundJob, error)
+	ExtendBackgroundJobLease(ctx context.Context, id int64, workerID string, lease time.Duration) (bool, error)
+	CompleteBackgroundJob(ctx context.Context, id int64, workerID string, result []byte) error
+	RetryBackgroundJob(ctx context.Context, id int64, workerID string, lastError string, runAfter time.Time) error
+	FailBackgroundJob(ctx context.Context, id int64, workerID string, lastError string) error
+	ReleaseBackgroundJob(ctx context.Context, id int64, workerID string) error
+	CancelBackgroundJobsForAnalysisJob(ctx context.Context, analysisJobID int64) ([]int64, error)
+	RecoverBackgroundJobs(ctx context.Context) (requeued int64, orphaned int64, err error)
+}
+
+// Handler runs one attempt of a job. The returned result is stored as
+// JSON on the job when it completes. Returning an error retries the
+// job unless the error is wrapped with Permanent or the job has no
+// attempts left.
+type Handler func(ctx context.Context, job *models.BackgroundJob) (any, error)
+
+// Kind configures how jobs of one kind are run.
+type Kind struct {
+	// Handler runs the job.
+	Handler Handler
+
+	// Timeout bounds a single attempt. Zero selects DefaultTimeout.
+	Timeout time.Duration
+
+	// MaxAttempts is the default number of attempts for jobs of this
+	// kind. Zero selects the database default.
+	MaxAttempts int
+
+	// OnFailure, if set, is called once a job has failed for good so
+	// that the work it belongs to can be marked failed.
+	OnFailure func(ctx context.Context, job *models.BackgroundJob, err error)
+}
+
+// permanentError marks an error that retrying cannot fix.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so that the job fails without further retries.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was wrapped with Permanent.
+func IsPermanent(err error) bool {
+	var pe *permanentError
+	return errors.As(err, &pe)
+}
+
+// Queue enqueues background jobs and runs them on a pool of workers.
+type Queue struct {
+	store         Store
+	workerID      string
+	workers       int
+	leaseDuration time.Duration
+	pollInterval  time.Duration
+
+	mu      sync.RWMutex
+	kinds   map[string]Kind
+	running map[int64]context.CancelCauseFunc
+
+	wake chan struct{}
+	stop context.CancelFunc
+	wg   sync.WaitGroup
+}
+
+// NewQueue creates a queue backed by store. Kinds must be registered
+// before Start is called.
+func NewQueue(store Store) *Queue {
+	return &Queue{
+		store:         store,
+		workerID:      newWorkerID(),
+		workers:       DefaultWorkers,
+		leaseDuration: defaultLeaseDuration,
+		pollInterval:  defaultPollInterval,
+		kinds:         make(map[string]Kind),
+		running:       make(map[int64]context.CancelCauseFunc),
+		wake:          make(chan struct{}, 1),
+	}
+}
+
+// newWorkerID returns an identifier for this process's workers that is
+// unique across restarts, so leases held before a restart are never
+// mistaken for our own.
+func newWorkerID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "worker"
+	}
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(b))
+}
+
+// WithWorkers sets how many jobs the queue runs at once. Values below
+// 1 are treated as 1. It returns q for chaining.
+func (q *Queue) WithWorkers(n int) *Queue {
+	q.workers = max(n, 1)
+	return q
+}
+
+// Register sets the handler configuration for a job kind.
+func (q *Queue) Register(kind string, k Kind) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.kinds[kind] = k
+}
+
+// Enqueue adds a job of the given kind to the queue. payload is
+// marshalled to JSON; campaignID and analysisJobID are optional links
+// used for cancellation and cleanup.
+func (q *Queue) Enqueue(
+	ctx context.Context,
+	kind string,
+	campaignID *int64,
+	analysisJobID *int64,
+	payload any,
+) (*models.BackgroundJob, error) {
+	q.mu.RLock()
+	k, ok := q.kinds[kind]
+	q.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown job kind: %s", kind)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal job payload: %w", err)
+	}
+
+	job, err := q.store.EnqueueBackgroundJob(ctx, &models.BackgroundJob{
+		Kind:          kind,
+		CampaignID:    campaignID,
+		AnalysisJobID: analysisJobID,
+		Payload:       data,
+		MaxAttempts:   k.MaxAttempts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	q.Notify()
+	return job, nil
+}
+
+// Notify wakes an idle worker so that newly enqueued work is picked up
+// without waiting for the next poll.
+func (q *Queue) Notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// CancelAnalysisJob cancels every queued or running background job
+// attached to a content analysis job. Jobs running in this process are
+// interrupted immediately; jobs running elsewhere notice when they
+// next renew their lease. It returns the number of jobs cancelled.
+func (q *Queue) CancelAnalysisJob(ctx context.Context, analysisJobID int64) (int, error) {
+	ids, err := q.store.CancelBackgroundJobsForAnalysisJob(ctx, analysisJobID)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	for _, id := range ids {
+		if cancel, ok := q.running[id]; ok {
+			cancel(ErrCancelled)
+		}
+	}
+	return len(ids), nil
+}
+
+// Wait blocks until a job reaches a final status (completed, failed, or
+// cancelled) or ctx is done, and returns the job.
+func (q *Queue) Wait(ctx context.Context, id int64) (*models.BackgroundJob, error) {
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+
+	for {
+		job, err := q.store.GetBackgroundJob(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		switch job.Status {
+		case models.BackgroundJobCompleted, models.BackgroundJobFailed, models.BackgroundJobCancelled:
+			return job, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Start recovers jobs orphaned by a previous run and starts the
+// workers. The workers run until ctx is done or Stop is called.
+func (q *Queue) Start(ctx context.Context) {
+	requeued, orphaned, err := q.store.RecoverBackgroundJobs(ctx)
+	if err != nil {
+		log.Printf("jobs: failed to recover orphaned jobs: %v", err)
+	} else if requeued > 0 || orphaned > 0 {
+		log.Printf("jobs: recovered %d expired jobs and %d orphaned analysis jobs",
+			requeued, orphaned)
+	}
+
+	ctx, q.stop = context.WithCancel(ctx)
+	for range q.workers {
+		q.wg.Add(1)
+		go q.work(ctx)
+	}
+	log.Printf("jobs: started %d workers as %s", q.workers, q.workerID)
+}
+
+// Stop stops the workers and waits for them to exit. Jobs interrupted
+// by the shutdown are returned to the queue without counting the
+// attempt, so they resume when the server starts again.
+func (q *Queue) Stop() {
+	if q.stop != nil {
+		q.stop()
+	}
+	q.wg.Wait()
+}
+
+// work is the loop run by each worker: run jobs until none are ready,
+// then sleep until the next poll or an enqueue notification.
+func (q *Queue) work(ctx context.Context) {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(q.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil && q.runNext(ctx) {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// registeredKinds returns the names of all registered kinds.
+func (q *Queue) registeredKinds() []string {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	kinds := make([]string, 0, len(q.kinds))
+	for kind := range q.kinds {
+		kinds = append(kinds, kind)
+	}
+	return kinds
+}
+
+// runNext claims and runs one job. It reports whether a job was run.
+func (q *Queue) runNext(ctx context.Context) bool {
+	job, err := q.store.ClaimBackgroundJob(ctx, q.workerID, q.registeredKinds(), q.leaseDuration)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("jobs: failed to claim job: %v", err)
+		}
+		return false
+	}
+	if job == nil {
+		return false
+	}
+
+	q.run(ctx, job)
+	return true
+}
+
+// run executes a claimed job and records its outcome.
+func (q *Queue) run(ctx context.Context, job *models.BackgroundJob) {
+	q.mu.RLock()
+	k := q.kinds[job.Kind]
+	q.mu.RUnlock()
+
+	// Finalizing must survive shutdown of the worker context.
+	finalizeCtx, cancelFinalize := context.WithTimeout(context.WithoutCancel(ctx), finalizeTimeout)
+	defer cancelFinalize()
+
+	// A job reclaimed after its lease expired may already have used up
+	// its attempts, e.g. if it crashes the server every time it runs.
+	if job.Attempts > job.MaxAttempts {
+		q.fail(finalizeCtx, k, job, fmt.Errorf("worker lease expired after %d attempts", job.MaxAttempts))
+		return
+	}
+
+	timeout := k.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	jobCtx, cancelTimeout := context.WithTimeout(runCtx, timeout)
+	defer cancelTimeout()
+
+	q.mu.Lock()
+	q.running[job.ID] = cancel
+	q.mu.Unlock()
+	defer func() {
+		q.mu.Lock()
+		delete(q.running, job.ID)
+		q.mu.Unlock()
+	}()
+
+	heartbeatDone := make(chan struct{})
+	go q.heartbeat(jobCtx, job.ID, cancel, heartbeatDone)
+
+	log.Printf("jobs: running %s job %d (attempt %d of %d)",
+		job.Kind, job.ID, job.Attempts, job.MaxAttempts)
+	result, err := runHandler(jobCtx, k.Handler, job)
+	cancelTimeout()
+	<-heartbeatDone
+
+	switch {
+	case errors.Is(context.Cause(runCtx), ErrCancelled):
+		log.Printf("jobs: %s job %d was cancelled", job.Kind, job.ID)
+	case ctx.Err() != nil:
+		log.Printf("jobs: releasing %s job %d on shutdown", job.Kind, job.ID)
+		if err := q.store.ReleaseBackgroundJob(finalizeCtx, job.ID, q.workerID); err != nil {
+			log.Printf("jobs: %v", err)
+		}
+	case err == nil:
+		q.complete(finalizeCtx, job, result)
+	case IsPermanent(err) || job.Attempts >= job.MaxAttempts:
+		q.fail(finalizeCtx, k, job, err)
+	default:
+		delay := retryDelay(job.Attempts)
+		log.Printf("jobs: %s job %d failed, retrying in %s: %v", job.Kind, job.ID, delay, err)
+		if err := q.store.RetryBackgroundJob(finalizeCtx, job.ID, q.workerID,
+			err.Error(), time.Now().Add(delay)); err != nil {
+			log.Printf("jobs: %v", err)
+		}
+	}
+}
+
+// runHandler calls handler, converting a panic into an error so that a
+// misbehaving job cannot take down its worker.
+func runHandler(ctx context.Context, handler Handler, job *models.BackgroundJob) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	if handler == nil {
+		return nil, Permanent(fmt.Errorf("no handler registered for job kind %s", job.Kind))
+	}
+	return handler(ctx, job)
+}
+
+// heartbeat renews the job's lease until ctx is done, cancelling the
+// job with ErrCancelled if the lease is lost.
+func (q *Queue) heartbeat(ctx context.Context, id int64, cancel context.CancelCauseFunc, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(q.leaseDuration / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			held, err := q.store.ExtendBackgroundJobLease(ctx, id, q.workerID, q.leaseDuration)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("jobs: failed to renew lease on job %d: %v", id, err)
+				}
+				continue
+			}
+			if !held {
+				cancel(ErrCancelled)
+				return
+			}
+		}
+	}
+}
+
+// complete records a job's successful result.
+func (q *Queue) complete(ctx context.Context, job *models.BackgroundJob, result any) {
+	var data []byte
+	if result != nil {
+		var err error
+		if data, err = json.Marshal(result); err != nil {
+			log.Printf("jobs: failed to marshal result of job %d: %v", job.ID, err)
+			data = nil
+		}
+	}
+	if err := q.store.CompleteBackgroundJob(ctx, job.ID, q.workerID, data); err != nil {
+		log.Printf("jobs: %v", err)
+		return
+	}
+	log.Printf("jobs: completed %s job %d", job.Kind, job.ID)
+}
+
+// fail marks a job permanently failed and runs the kind's failure hook.
+func (q *Queue) fail(ctx context.Context, k Kind, job *models.BackgroundJob, err error) {
+	log.Printf("jobs: %s job %d failed: %v", job.Kind, job.ID, err)
+	if dbErr := q.store.FailBackgroundJob(ctx, job.ID, q.workerID, err.Error()); dbErr != nil {
+		log.Printf("jobs: %v", dbErr)
+	}
+	if k.OnFailure != nil {
+		k.OnFailure(ctx, job, err)
+	}
+}
+
+// retryDelay returns the backoff before the next attempt of a job that
+// has failed attempt times: 30s, 1m, 2m, ... capped at maxRetryDelay.
+func retryDelay(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := 30 * time.Second
+	for i := 1; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
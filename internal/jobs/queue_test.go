@@ -0,0 +1,413 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+// memStore is an in-memory Store that records how jobs were finalized.
+type memStore struct {
+	mu       sync.Mutex
+	nextID   int64
+	jobs     map[int64]*models.BackgroundJob
+	retried  map[int64]time.Time
+	released []int64
+	lost     bool
+}
+
+func newMemStore() *memStore {
+	return &memStore{
+		jobs:    make(map[int64]*models.BackgroundJob),
+		retried: make(map[int64]time.Time),
+	}
+}
+
+func (s *memStore) EnqueueBackgroundJob(_ context.Context, job *models.BackgroundJob) (*models.BackgroundJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	j := *job
+	j.ID = s.nextID
+	j.Status = models.BackgroundJobQueued
+	if j.MaxAttempts == 0 {
+		j.MaxAttempts = 3
+	}
+	s.jobs[j.ID] = &j
+	return &j, nil
+}
+
+func (s *memStore) GetBackgroundJob(_ context.Context, id int64) (*models.BackgroundJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	c := *j
+	return &c, nil
+}
+
+func (s *memStore) ClaimBackgroundJob(_ context.Context, _ string, _ []string, _ time.Duration) (*models.BackgroundJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id := int64(1); id <= s.nextID; id++ {
+		j := s.jobs[id]
+		if j != nil && j.Status == models.BackgroundJobQueued {
+			j.Status = models.BackgroundJobRunning
+			j.Attempts++
+			c := *j
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *memStore) ExtendBackgroundJobLease(_ context.Context, id int64, _ string, _ time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !s.lost && s.jobs[id].Status == models.BackgroundJobRunning, nil
+}
+
+func (s *memStore) setStatus(id int64, status, lastError string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[id].Status = status
+	if lastError != "" {
+		s.jobs[id].LastError = &lastError
+	}
+}
+
+func (s *memStore) CompleteBackgroundJob(_ context.Context, id int64, _ string, result []byte) error {
+	s.setStatus(id, models.BackgroundJobCompleted, "")
+	s.mu.Lock()
+	s.jobs[id].Result = result
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *memStore) RetryBackgroundJob(_ context.Context, id int64, _ string, lastError string, runAfter time.Time) error {
+	s.setStatus(id, models.BackgroundJobQueued, lastError)
+	s.mu.Lock()
+	s.retried[id] = runAfter
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *memStore) FailBackgroundJob(_ context.Context, id int64, _ string, lastError string) error {
+	s.setStatus(id, models.BackgroundJobFailed, lastError)
+	return nil
+}
+
+func (s *memStore) ReleaseBackgroundJob(_ context.Context, id int64, _ string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[id].Status = models.BackgroundJobQueued
+	s.jobs[id].Attempts--
+	s.released = append(s.released, id)
+	return nil
+}
+
+func (s *memStore) CancelBackgroundJobsForAnalysisJob(_ context.Context, analysisJobID int64) ([]int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ids []int64
+	for id, j := range s.jobs {
+		if j.AnalysisJobID != nil && *j.AnalysisJobID == analysisJobID &&
+			(j.Status == models.BackgroundJobQueued || j.Status == models.BackgroundJobRunning) {
+			j.Status = models.BackgroundJobCancelled
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (s *memStore) RecoverBackgroundJobs(_ context.Context) (int64, int64, error) {
+	return 0, 0, nil
+}
+
+// enqueueAndRun enqueues a job of kind "test", claims it, and runs it
+// synchronously on q.
+func enqueueAndRun(t *testing.T, ctx context.Context, q *Queue, store *memStore) *models.BackgroundJob {
+	t.Helper()
+	analysisJobID := int64(7)
+	job, err := q.Enqueue(ctx, "test", nil, &analysisJobID, map[string]int{"n": 1})
+	if err != nil {
+		t.Fatalf("failed to enqueue: %v", err)
+	}
+	if !q.runNext(ctx) {
+		t.Fatal("expected a job to run")
+	}
+	got, _ := store.GetBackgroundJob(ctx, job.ID)
+	return got
+}
+
+func TestQueue_CompletesJob(t *testing.T) {
+	store := newMemStore()
+	q := NewQueue(store)
+	q.Register("test", Kind{Handler: func(_ context.Context, job *models.BackgroundJob) (any, error) {
+		if string(job.Payload) != `{"n":1}` {
+			t.Errorf("unexpected payload: %s", job.Payload)
+		}
+		return map[string]int{"itemCount": 2}, nil
+	}})
+
+	job := enqueueAndRun(t, context.Background(), q, store)
+	if job.Status != models.BackgroundJobCompleted {
+		t.Fatalf("expected completed, got %s", job.Status)
+	}
+	if string(job.Result) != `{"itemCount":2}` {
+		t.Errorf("unexpected result: %s", job.Result)
+	}
+}
+
+func TestQueue_RetriesWithBackoff(t *testing.T) {
+	store := newMemStore()
+	q := NewQueue(store)
+	q.Register("test", Kind{Handler: func(context.Context, *models.BackgroundJob) (any, error) {
+		return nil, errors.New("provider unavailable")
+	}})
+
+	before := time.Now()
+	job := enqueueAndRun(t, context.Background(), q, store)
+	if job.Status != models.BackgroundJobQueued {
+		t.Fatalf("expected job to be requeued, got %s", job.Status)
+	}
+	if job.LastError == nil || *job.LastError != "provider unavailable" {
+		t.Errorf("unexpected last error: %v", job.LastError)
+	}
+	if runAfter := store.retried[job.ID]; runAfter.Before(before.Add(30 * time.Second)) {
+		t.Errorf("expected retry to be delayed by at least 30s, got %s", runAfter.Sub(before))
+	}
+}
+
+func TestQueue_FailsWhenAttemptsExhausted(t *testing.T) {
+	store := newMemStore()
+	q := NewQueue(store)
+	var failed error
+	q.Register("test", Kind{
+		MaxAttempts: 1,
+		Handler: func(context.Context, *models.BackgroundJob) (any, error) {
+			return nil, errors.New("boom")
+		},
+		OnFailure: func(_ context.Context, _ *models.BackgroundJob, err error) {
+			failed = err
+		},
+	})
+
+	job := enqueueAndRun(t, context.Background(), q, store)
+	if job.Status != models.BackgroundJobFailed {
+		t.Fatalf("expected failed, got %s", job.Status)
+	}
+	if failed == nil || failed.Error() != "boom" {
+		t.Errorf("expected OnFailure to receive the error, got %v", failed)
+	}
+}
+
+func TestQueue_PermanentErrorSkipsRetry(t *testing.T) {
+	store := newMemStore()
+	q := NewQueue(store)
+	q.Register("test", Kind{Handler: func(context.Context, *models.BackgroundJob) (any, error) {
+		return nil, Permanent(errors.New("quota exceeded"))
+	}})
+
+	job := enqueueAndRun(t, context.Background(), q, store)
+	if job.Status != models.BackgroundJobFailed {
+		t.Fatalf("expected failed, got %s", job.Status)
+	}
+	if job.Attempts != 1 {
+		t.Errorf("expected a single attempt, got %d", job.Attempts)
+	}
+}
+
+func TestQueue_RecoversPanic(t *testing.T) {
+	store := newMemStore()
+	q := NewQueue(store)
+	q.Register("test", Kind{
+		MaxAttempts: 1,
+		Handler: func(context.Context, *models.BackgroundJob) (any, error) {
+			panic("nil map")
+		},
+	})
+
+	job := enqueueAndRun(t, context.Background(), q, store)
+	if job.Status != models.BackgroundJobFailed {
+		t.Fatalf("expected failed, got %s", job.Status)
+	}
+	if job.LastError == nil || *job.LastError != "panic: nil map" {
+		t.Errorf("unexpected last error: %v", job.LastError)
+	}
+}
+
+func TestQueue_CancelInterruptsRunningJob(t *testing.T) {
+	store := newMemStore()
+	q := NewQueue(store)
+	started := make(chan struct{})
+	var cause error
+	q.Register("test", Kind{Handler: func(ctx context.Context, _ *models.BackgroundJob) (any, error) {
+		close(started)
+		<-ctx.Done()
+		cause = context.Cause(ctx)
+		return nil, ctx.Err()
+	}})
+
+	ctx := context.Background()
+	done := make(chan *models.BackgroundJob)
+	go func() { done <- enqueueAndRun(t, ctx, q, store) }()
+
+	<-started
+	n, err := q.CancelAnalysisJob(ctx, 7)
+	if err != nil || n != 1 {
+		t.Fatalf("expected 1 job cancelled, got %d (%v)", n, err)
+	}
+
+	job := <-done
+	if !errors.Is(cause, ErrCancelled) {
+		t.Errorf("expected ErrCancelled cause, got %v", cause)
+	}
+	if job.Status != models.BackgroundJobCancelled {
+		t.Errorf("expected cancelled status to be kept, got %s", job.Status)
+	}
+}
+
+func TestQueue_LostLeaseCancelsJob(t *testing.T) {
+	store := newMemStore()
+	store.lost = true
+	q := NewQueue(store)
+	q.leaseDuration = 30 * time.Millisecond
+	var cause error
+	q.Register("test", Kind{Handler: func(ctx context.Context, _ *models.BackgroundJob) (any, error) {
+		<-ctx.Done()
+		cause = context.Cause(ctx)
+		return nil, ctx.Err()
+	}})
+
+	job := enqueueAndRun(t, context.Background(), q, store)
+	if !errors.Is(cause, ErrCancelled) {
+		t.Errorf("expected ErrCancelled cause, got %v", cause)
+	}
+	if job.Status != models.BackgroundJobRunning {
+		t.Errorf("expected job to be left to its new owner, got %s", job.Status)
+	}
+}
+
+func TestQueue_StopReleasesRunningJob(t *testing.T) {
+	store := newMemStore()
+	q := NewQueue(store)
+	started := make(chan struct{})
+	q.Register("test", Kind{Handler: func(ctx context.Context, _ *models.BackgroundJob) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}})
+
+	q.Start(context.Background())
+	job, err := q.Enqueue(context.Background(), "test", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to enqueue: %v", err)
+	}
+	<-started
+	q.Stop()
+
+	got, _ := store.GetBackgroundJob(context.Background(), job.ID)
+	if got.Status != models.BackgroundJobQueued || got.Attempts != 0 {
+		t.Errorf("expected job to be released without using an attempt, got %+v", got)
+	}
+	if len(store.released) != 1 {
+		t.Errorf("expected 1 released job, got %d", len(store.released))
+	}
+}
+
+func TestQueue_FailsJobPastMaxAttempts(t *testing.T) {
+	store := newMemStore()
+	q := NewQueue(store)
+	ran := false
+	q.Register("test", Kind{Handler: func(context.Context, *models.BackgroundJob) (any, error) {
+		ran = true
+		return nil, nil
+	}})
+
+	job, _ := store.EnqueueBackgroundJob(context.Background(), &models.BackgroundJob{Kind: "test", MaxAttempts: 2})
+	store.jobs[job.ID].Attempts = 2
+	q.runNext(context.Background())
+
+	got, _ := store.GetBackgroundJob(context.Background(), job.ID)
+	if ran {
+		t.Error("handler should not run once attempts are exhausted")
+	}
+	if got.Status != models.BackgroundJobFailed {
+		t.Errorf("expected failed, got %s", got.Status)
+	}
+}
+
+func TestQueue_EnqueueUnknownKind(t *testing.T) {
+	q := NewQueue(newMemStore())
+	if _, err := q.Enqueue(context.Background(), "missing", nil, nil, nil); err == nil {
+		t.Fatal("expected error for unknown kind")
+	}
+}
+
+func TestQueue_WaitReturnsFinishedJob(t *testing.T) {
+	store := newMemStore()
+	q := NewQueue(store)
+	q.Register("test", Kind{Handler: func(context.Context, *models.BackgroundJob) (any, error) {
+		return "ok", nil
+	}})
+
+	job := enqueueAndRun(t, context.Background(), q, store)
+	got, err := q.Wait(context.Background(), job.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Status != models.BackgroundJobCompleted || string(got.Result) != `"ok"` {
+		t.Errorf("unexpected job: %+v", got)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{5, 8 * time.Minute},
+		{6, 10 * time.Minute},
+		{50, 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := retryDelay(tt.attempt); got != tt.want {
+			t.Errorf("retryDelay(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestPermanent(t *testing.T) {
+	if Permanent(nil) != nil {
+		t.Error("Permanent(nil) should be nil")
+	}
+	base := errors.New("base")
+	err := Permanent(base)
+	if !IsPermanent(err) || !errors.Is(err, base) {
+		t.Errorf("expected permanent error wrapping base, got %v", err)
+	}
+	if IsPermanent(base) {
+		t.Error("plain error should not be permanent")
+	}
+}
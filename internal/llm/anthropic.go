@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -30,6 +31,7 @@ const (
 type AnthropicProvider struct {
 	apiKey  string
 	baseURL string
+	model   string // overrides anthropicModel when set
 	client  *http.Client
 }
 
@@ -46,10 +48,13 @@ func NewAnthropicProvider(apiKey string) (*AnthropicProvider, error) {
 }
 
 type anthropicRequest struct {
-	Model     string             `json:"model"`
-	MaxTokens int                `json:"max_tokens"`
-	System    string             `json:"system,omitempty"`
-	Messages  []anthropicMessage `json:"messages"`
+	Model      string               `json:"model"`
+	MaxTokens  int                  `json:"max_tokens"`
+	System     string               `json:"system,omitempty"`
+	Messages   []anthropicMessage   `json:"messages"`
+	Tools      []anthropicTool      `json:"tools,omitempty"`
+	ToolChoice *anthropicToolChoice `json:"tool_choice,omitempty"`
+	Stream     bool                 `json:"stream,omitempty"`
 }
 
 type anthropicMessage struct {
@@ -57,11 +62,30 @@ type anthropicMessage struct {
 	Content string `json:"content"`
 }
 
+type anthropicTool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"input_schema"`
+}
+
+type anthropicToolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+}
+
+// anthropicContentBlock is a single block of a Messages API response.
+// Text blocks carry Text; tool_use blocks carry ID, Name and Input.
+type anthropicContentBlock struct {
+	Type  string          `json:"type"`
+	Text  string          `json:"text"`
+	ID    string          `json:"id"`
+	Name  string          `json:"name"`
+	Input json.RawMessage `json:"input"`
+}
+
 type anthropicResponse struct {
-	Content []struct {
-		Text string `json:"text"`
-	} `json:"content"`
-	Usage struct {
+	Content []anthropicContentBlock `json:"content"`
+	Usage   struct {
 		InputTokens  int `json:"input_tokens"`
 		OutputTokens int `json:"output_tokens"`
 	} `json:"usage"`
@@ -73,15 +97,45 @@ type anthropicError struct {
 	} `json:"error"`
 }
 
-// Complete sends a completion request to the Anthropic API.
-func (p *AnthropicProvider) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+// anthropicStreamEvent covers the fields used from the Messages API
+// streaming events (message_start, content_block_start,
+// content_block_delta, message_delta and error).
+type anthropicStreamEvent struct {
+	Type    string `json:"type"`
+	Index   int    `json:"index"`
+	Message struct {
+		Usage struct {
+			InputTokens int `json:"input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+	ContentBlock anthropicContentBlock `json:"content_block"`
+	Delta        struct {
+		Type        string `json:"type"`
+		Text        string `json:"text"`
+		PartialJSON string `json:"partial_json"`
+	} `json:"delta"`
+	Usage struct {
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// buildRequest converts a CompletionRequest into the Anthropic wire
+// format, applying the default max token limit.
+func (p *AnthropicProvider) buildRequest(req CompletionRequest) anthropicRequest {
 	maxTokens := req.MaxTokens
 	if maxTokens <= 0 {
 		maxTokens = 4096
 	}
+	model := p.model
+	if model == "" {
+		model = anthropicModel
+	}
 
 	body := anthropicRequest{
-		Model:     anthropicModel,
+		Model:     model,
 		MaxTokens: maxTokens,
 		System:    req.SystemPrompt,
 		Messages: []anthropicMessage{
@@ -89,19 +143,47 @@ func (p *AnthropicProvider) Complete(ctx context.Context, req CompletionRequest)
 		},
 	}
 
+	for _, t := range req.Tools {
+		body.Tools = append(body.Tools, anthropicTool{
+			Name:        t.Name,
+			Description: t.Description,
+			InputSchema: toolSchema(t),
+		})
+	}
+	if req.ToolChoice != "" && len(body.Tools) > 0 {
+		body.ToolChoice = &anthropicToolChoice{Type: "tool", Name: req.ToolChoice}
+	}
+
+	return body
+}
+
+// newHTTPRequest creates an authenticated POST request to the Messages
+// API carrying the given JSON payload.
+func (p *AnthropicProvider) newHTTPRequest(ctx context.Context, payload []byte) (*http.Request, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", p.apiKey)
+	httpReq.Header.Set("anthropic-version", anthropicAPIVersion)
+	return httpReq, nil
+}
+
+// Complete sends a completion request to the Anthropic API.
+func (p *AnthropicProvider) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+	body := p.buildRequest(req)
+
 	return doWithRetry(ctx, func(ctx context.Context) (CompletionResponse, int, error) {
 		payload, err := json.Marshal(body)
 		if err != nil {
 			return CompletionResponse{}, 0, fmt.Errorf("failed to marshal request: %w", err)
 		}
 
-		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL, bytes.NewReader(payload))
+		httpReq, err := p.newHTTPRequest(ctx, payload)
 		if err != nil {
 			return CompletionResponse{}, 0, fmt.Errorf("failed to create request: %w", err)
 		}
-		httpReq.Header.Set("Content-Type", "application/json")
-		httpReq.Header.Set("x-api-key", p.apiKey)
-		httpReq.Header.Set("anthropic-version", anthropicAPIVersion)
 
 		resp, err := p.client.Do(httpReq)
 		if err != nil {
@@ -130,9 +212,131 @@ func (p *AnthropicProvider) Complete(ctx context.Context, req CompletionRequest)
 			return CompletionResponse{}, resp.StatusCode, fmt.Errorf("empty response from Anthropic API")
 		}
 
+		var text strings.Builder
+		var toolCalls []ToolCall
+		for _, block := range result.Content {
+			switch block.Type {
+			case "tool_use":
+				toolCalls = append(toolCalls, ToolCall{
+					ID:        block.ID,
+					Name:      block.Name,
+					Arguments: normalizeArguments(block.Input),
+				})
+			default:
+				text.WriteString(block.Text)
+			}
+		}
+
 		return CompletionResponse{
-			Content:    result.Content[0].Text,
+			Content:    text.String(),
 			TokensUsed: result.Usage.InputTokens + result.Usage.OutputTokens,
+			ToolCalls:  toolCalls,
 		}, resp.StatusCode, nil
 	})
 }
+
+// Stream sends a streaming completion request to the Anthropic API and
+// returns a channel of text deltas. Establishing the connection is
+// retried on 429/503 with the same policy as Complete.
+func (p *AnthropicProvider) Stream(ctx context.Context, req CompletionRequest) (<-chan StreamEvent, error) {
+	body := p.buildRequest(req)
+	body.Stream = true
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := openStream(ctx, p.client,
+		func(ctx context.Context) (*http.Request, error) {
+			return p.newHTTPRequest(ctx, payload)
+		},
+		func(status int, respBody []byte) error {
+			var apiErr anthropicError
+			_ = json.Unmarshal(respBody, &apiErr)
+			return fmt.Errorf("anthropic API error (status %d): %s", status, apiErr.Error.Message)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan StreamEvent)
+	go func() {
+		defer close(out)
+		defer resp.Body.Close()
+
+		var inputTokens, outputTokens int
+		var streamErr error
+		done := false
+
+		// Tool input arrives as partial JSON fragments keyed by the
+		// content block index they belong to.
+		var toolCalls []ToolCall
+		toolInputs := map[int]*strings.Builder{}
+		toolIndex := map[int]int{}
+
+		readErr := readSSE(resp.Body, func(_, data string) bool {
+			var ev anthropicStreamEvent
+			if err := json.Unmarshal([]byte(data), &ev); err != nil {
+				streamErr = fmt.Errorf("failed to parse stream event: %w", err)
+				return false
+			}
+
+			switch ev.Type {
+			case "message_start":
+				inputTokens = ev.Message.Usage.InputTokens
+			case "content_block_start":
+				if ev.ContentBlock.Type == "tool_use" {
+					toolIndex[ev.Index] = len(toolCalls)
+					toolInputs[ev.Index] = &strings.Builder{}
+					toolCalls = append(toolCalls, ToolCall{
+						ID:   ev.ContentBlock.ID,
+						Name: ev.ContentBlock.Name,
+					})
+				}
+			case "content_block_delta":
+				if ev.Delta.Type == "input_json_delta" {
+					if buf, ok := toolInputs[ev.Index]; ok {
+						buf.WriteString(ev.Delta.PartialJSON)
+					}
+					return true
+				}
+				if ev.Delta.Text != "" {
+					if !sendEvent(ctx, out, StreamEvent{Delta: ev.Delta.Text}) {
+						return false
+					}
+				}
+			case "message_delta":
+				outputTokens = ev.Usage.OutputTokens
+			case "message_stop":
+				done = true
+				return false
+			case "error":
+				streamErr = fmt.Errorf("anthropic stream error: %s", ev.Error.Message)
+				return false
+			}
+			return true
+		})
+
+		switch {
+		case streamErr != nil:
+			sendEvent(ctx, out, StreamEvent{Err: streamErr})
+		case readErr != nil:
+			sendEvent(ctx, out, StreamEvent{Err: fmt.Errorf("failed to read stream: %w", readErr)})
+		case done:
+			for blockIndex, i := range toolIndex {
+				toolCalls[i].Arguments = normalizeArguments([]byte(toolInputs[blockIndex].String()))
+			}
+			sendEvent(ctx, out, StreamEvent{
+				Done:       true,
+				TokensUsed: inputTokens + outputTokens,
+				ToolCalls:  toolCalls,
+			})
+		case ctx.Err() == nil:
+			sendEvent(ctx, out, StreamEvent{Err: fmt.Errorf("anthropic stream ended unexpectedly")})
+		}
+	}()
+
+	return out, nil
+}
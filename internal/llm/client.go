@@ -9,11 +9,13 @@
  */
 
 // Package llm provides a unified abstraction for LLM service providers
-// (Anthropic, OpenAI, Ollama) used by the enrichment engine.
+// (Anthropic, OpenAI, Gemini, Ollama) used by the enrichment engine,
+// plus a record/replay provider for offline use.
 package llm
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/antonypegg/imagineer/internal/models"
@@ -30,12 +32,41 @@ type CompletionRequest struct {
 	UserPrompt   string
 	MaxTokens    int
 	Temperature  float64
+
+	// Tools lists the tools the model may call. When empty the model
+	// answers in prose only.
+	Tools []Tool
+
+	// ToolChoice forces the model to call the named tool. When empty
+	// the model decides whether to call a tool.
+	ToolChoice string
 }
 
 // CompletionResponse holds the result of an LLM completion call.
 type CompletionResponse struct {
 	Content    string
 	TokensUsed int
+
+	// ToolCalls holds the tool invocations requested by the model, in
+	// the order they were returned.
+	ToolCalls []ToolCall
+}
+
+// Tool describes a function the model may call. InputSchema is a JSON
+// Schema object describing the tool's arguments.
+type Tool struct {
+	Name        string
+	Description string
+	InputSchema json.RawMessage
+}
+
+// ToolCall is a single tool invocation returned by the model. Arguments
+// holds the JSON object the model supplied for the tool's input schema.
+// ID is empty for providers that do not assign call identifiers.
+type ToolCall struct {
+	ID        string
+	Name      string
+	Arguments json.RawMessage
 }
 
 // QuotaExceededError indicates the LLM provider has
@@ -60,8 +91,12 @@ func NewProvider(service models.LLMService, apiKey string) (Provider, error) {
 		return NewAnthropicProvider(apiKey)
 	case models.LLMServiceOpenAI:
 		return NewOpenAIProvider(apiKey)
+	case models.LLMServiceGemini:
+		return NewGeminiProvider(apiKey)
 	case models.LLMServiceOllama:
 		return NewOllamaProvider()
+	case models.LLMServiceReplay:
+		return newReplayProviderFromEnv(apiKey)
 	default:
 		return nil, fmt.Errorf("unsupported LLM service: %s", service)
 	}
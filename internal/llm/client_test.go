@@ -53,6 +53,18 @@ func TestNewProvider(t *testing.T) {
 			apiKey:  "",
 			wantErr: true,
 		},
+		{
+			name:    "gemini with key",
+			service: models.LLMServiceGemini,
+			apiKey:  "test-key",
+			wantErr: false,
+		},
+		{
+			name:    "gemini without key",
+			service: models.LLMServiceGemini,
+			apiKey:  "",
+			wantErr: true,
+		},
 		{
 			name:    "ollama no key needed",
 			service: models.LLMServiceOllama,
@@ -103,9 +115,7 @@ func TestAnthropicProvider_Complete(t *testing.T) {
 		}
 
 		resp := anthropicResponse{
-			Content: []struct {
-				Text string `json:"text"`
-			}{{Text: "test response"}},
+			Content: []anthropicContentBlock{{Type: "text", Text: "test response"}},
 		}
 		resp.Usage.InputTokens = 10
 		resp.Usage.OutputTokens = 5
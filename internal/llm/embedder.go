@@ -0,0 +1,124 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package llm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+// EmbeddingDimensions is the vector size produced by every Embedder.
+// It matches the 1024-dimension columns created by pgedge_vectorizer
+// and the content_embeddings table, so vectors from any backend can be
+// stored and compared in the same index.
+const EmbeddingDimensions = 1024
+
+// Embedder defines the interface for embedding service providers.
+type Embedder interface {
+	// Embed returns one vector per input text, in input order.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+
+	// Model identifies the embedding model. Vectors produced by
+	// different models are not comparable.
+	Model() string
+}
+
+// NewEmbedder creates an embedding provider based on the service type
+// and API key.
+func NewEmbedder(service models.LLMService, apiKey string) (Embedder, error) {
+	switch service {
+	case models.LLMServiceOpenAI:
+		return NewOpenAIEmbedder(apiKey)
+	case models.LLMServiceVoyage:
+		return NewVoyageEmbedder(apiKey)
+	case models.LLMServiceOllama:
+		return NewOllamaEmbedder()
+	default:
+		return nil, fmt.Errorf("unsupported embedding service: %s", service)
+	}
+}
+
+// embeddingData is a single entry in the data array returned by the
+// OpenAI and Voyage embeddings APIs, which share a response shape.
+type embeddingData struct {
+	Index     int       `json:"index"`
+	Embedding []float32 `json:"embedding"`
+}
+
+// orderEmbeddings sorts embedding entries by index and validates that
+// there is exactly one vector of the expected size per input.
+func orderEmbeddings(data []embeddingData, want int) ([][]float32, error) {
+	if len(data) != want {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", want, len(data))
+	}
+
+	sort.Slice(data, func(i, j int) bool { return data[i].Index < data[j].Index })
+
+	vectors := make([][]float32, len(data))
+	for i, d := range data {
+		vectors[i] = d.Embedding
+	}
+	return vectors, checkDimensions(vectors)
+}
+
+// checkDimensions verifies every vector has EmbeddingDimensions
+// entries.
+func checkDimensions(vectors [][]float32) error {
+	for i, v := range vectors {
+		if len(v) != EmbeddingDimensions {
+			return fmt.Errorf(
+				"embedding %d has %d dimensions, expected %d",
+				i, len(v), EmbeddingDimensions,
+			)
+		}
+	}
+	return nil
+}
+
+// postEmbeddingRequest sends the request built by newReq and returns
+// the response body. Rate limits and quota errors are retried and
+// classified with the same policy as completions; errFn converts
+// non-200 responses into errors.
+func postEmbeddingRequest(
+	ctx context.Context,
+	client *http.Client,
+	newReq func(ctx context.Context) (*http.Request, error),
+	errFn func(status int, body []byte) error,
+) ([]byte, error) {
+	return retryRequest(ctx, func(ctx context.Context) ([]byte, int, error) {
+		httpReq, err := newReq(ctx)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		resp, err := client.Do(httpReq)
+		if err != nil {
+			return nil, 0, fmt.Errorf("request failed: %w", err)
+		}
+		defer resp.Body.Close()
+
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, resp.StatusCode, fmt.Errorf("failed to read response: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, resp.StatusCode, errFn(resp.StatusCode, respBody)
+		}
+
+		return respBody, resp.StatusCode, nil
+	})
+}
@@ -0,0 +1,110 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// defaultOllamaEmbedModel is the model pre-pulled into the custom
+// Ollama image and used by pgedge_vectorizer.
+const defaultOllamaEmbedModel = "mxbai-embed-large"
+
+// OllamaEmbedder implements the Embedder interface for Ollama's local
+// embed API.
+type OllamaEmbedder struct {
+	host   string
+	model  string
+	client *http.Client
+}
+
+// NewOllamaEmbedder creates a new Ollama embedding provider. The host
+// is resolved the same way as NewOllamaProvider; the model defaults to
+// mxbai-embed-large and can be overridden via OLLAMA_EMBEDDING_MODEL.
+func NewOllamaEmbedder() (*OllamaEmbedder, error) {
+	host := os.Getenv("OLLAMA_HOST")
+	if host == "" {
+		host = defaultOllamaHost
+	}
+	model := os.Getenv("OLLAMA_EMBEDDING_MODEL")
+	if model == "" {
+		model = defaultOllamaEmbedModel
+	}
+	return &OllamaEmbedder{
+		host:   host,
+		model:  model,
+		client: &http.Client{Timeout: 120 * time.Second},
+	}, nil
+}
+
+type ollamaEmbedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type ollamaEmbedResponse struct {
+	Embeddings [][]float32 `json:"embeddings"`
+}
+
+// Model returns the embedding model name.
+func (e *OllamaEmbedder) Model() string {
+	return "ollama/" + e.model
+}
+
+// Embed sends the texts to the Ollama embed API.
+func (e *OllamaEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return [][]float32{}, nil
+	}
+
+	payload, err := json.Marshal(ollamaEmbedRequest{
+		Model: e.model,
+		Input: texts,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	apiURL := e.host + "/api/embed"
+
+	respBody, err := postEmbeddingRequest(ctx, e.client,
+		func(ctx context.Context) (*http.Request, error) {
+			httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(payload))
+			if err != nil {
+				return nil, err
+			}
+			httpReq.Header.Set("Content-Type", "application/json")
+			return httpReq, nil
+		},
+		func(status int, body []byte) error {
+			return fmt.Errorf("ollama embed API error (status %d): %s", status, string(body))
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ollamaEmbedResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if len(result.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(result.Embeddings))
+	}
+	return result.Embeddings, checkDimensions(result.Embeddings)
+}
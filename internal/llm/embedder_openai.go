@@ -0,0 +1,105 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	openaiEmbeddingsURL   = "https://api.openai.com/v1/embeddings"
+	openaiEmbeddingsModel = "text-embedding-3-small"
+)
+
+// OpenAIEmbedder implements the Embedder interface for OpenAI's
+// embeddings API.
+type OpenAIEmbedder struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+}
+
+// NewOpenAIEmbedder creates a new OpenAI embedding provider.
+func NewOpenAIEmbedder(apiKey string) (*OpenAIEmbedder, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("openai API key is required")
+	}
+	return &OpenAIEmbedder{
+		apiKey:  apiKey,
+		baseURL: openaiEmbeddingsURL,
+		client:  &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+// openaiEmbeddingRequest requests reduced-dimension vectors so that
+// text-embedding-3 output fits the shared 1024-dimension index.
+type openaiEmbeddingRequest struct {
+	Model      string   `json:"model"`
+	Input      []string `json:"input"`
+	Dimensions int      `json:"dimensions"`
+}
+
+type openaiEmbeddingResponse struct {
+	Data []embeddingData `json:"data"`
+}
+
+// Model returns the embedding model name.
+func (e *OpenAIEmbedder) Model() string {
+	return "openai/" + openaiEmbeddingsModel
+}
+
+// Embed sends the texts to the OpenAI embeddings API.
+func (e *OpenAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return [][]float32{}, nil
+	}
+
+	payload, err := json.Marshal(openaiEmbeddingRequest{
+		Model:      openaiEmbeddingsModel,
+		Input:      texts,
+		Dimensions: EmbeddingDimensions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	respBody, err := postEmbeddingRequest(ctx, e.client,
+		func(ctx context.Context) (*http.Request, error) {
+			httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL, bytes.NewReader(payload))
+			if err != nil {
+				return nil, err
+			}
+			httpReq.Header.Set("Content-Type", "application/json")
+			httpReq.Header.Set("Authorization", "Bearer "+e.apiKey)
+			return httpReq, nil
+		},
+		func(status int, body []byte) error {
+			var apiErr openaiError
+			_ = json.Unmarshal(body, &apiErr)
+			return fmt.Errorf("openai embeddings API error (status %d): %s", status, apiErr.Error.Message)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var result openaiEmbeddingResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return orderEmbeddings(result.Data, len(texts))
+}
@@ -0,0 +1,211 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+// testVector returns a vector of EmbeddingDimensions entries filled
+// with v.
+func testVector(v float32) []float32 {
+	vec := make([]float32, EmbeddingDimensions)
+	for i := range vec {
+		vec[i] = v
+	}
+	return vec
+}
+
+func TestNewEmbedder(t *testing.T) {
+	tests := []struct {
+		name    string
+		service models.LLMService
+		apiKey  string
+		wantErr bool
+	}{
+		{"openai with key", models.LLMServiceOpenAI, "key", false},
+		{"openai without key", models.LLMServiceOpenAI, "", true},
+		{"voyage with key", models.LLMServiceVoyage, "key", false},
+		{"voyage without key", models.LLMServiceVoyage, "", true},
+		{"ollama without key", models.LLMServiceOllama, "", false},
+		{"anthropic unsupported", models.LLMServiceAnthropic, "key", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			embedder, err := NewEmbedder(tt.service, tt.apiKey)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if embedder == nil {
+				t.Fatal("expected embedder, got nil")
+			}
+		})
+	}
+}
+
+func TestOpenAIEmbedder_Embed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Error("missing or incorrect Authorization header")
+		}
+
+		var req openaiEmbeddingRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.Dimensions != EmbeddingDimensions {
+			t.Errorf("unexpected dimensions: %d", req.Dimensions)
+		}
+		if len(req.Input) != 2 {
+			t.Fatalf("expected 2 inputs, got %d", len(req.Input))
+		}
+
+		// Return the entries out of order to exercise sorting.
+		_ = json.NewEncoder(w).Encode(openaiEmbeddingResponse{
+			Data: []embeddingData{
+				{Index: 1, Embedding: testVector(0.2)},
+				{Index: 0, Embedding: testVector(0.1)},
+			},
+		})
+	}))
+	defer server.Close()
+
+	embedder := &OpenAIEmbedder{
+		apiKey:  "test-key",
+		baseURL: server.URL,
+		client:  server.Client(),
+	}
+
+	vectors, err := embedder.Embed(context.Background(), []string{"first", "second"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(vectors) != 2 {
+		t.Fatalf("expected 2 vectors, got %d", len(vectors))
+	}
+	if vectors[0][0] != 0.1 || vectors[1][0] != 0.2 {
+		t.Errorf("vectors not in input order: %v, %v", vectors[0][0], vectors[1][0])
+	}
+	if embedder.Model() != "openai/text-embedding-3-small" {
+		t.Errorf("unexpected model: %s", embedder.Model())
+	}
+}
+
+func TestVoyageEmbedder_DimensionMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(voyageEmbeddingResponse{
+			Data: []embeddingData{{Index: 0, Embedding: []float32{0.1, 0.2}}},
+		})
+	}))
+	defer server.Close()
+
+	embedder := &VoyageEmbedder{
+		apiKey:  "test-key",
+		baseURL: server.URL,
+		client:  server.Client(),
+	}
+
+	_, err := embedder.Embed(context.Background(), []string{"text"})
+	if err == nil || !strings.Contains(err.Error(), "dimensions") {
+		t.Fatalf("expected dimension error, got %v", err)
+	}
+}
+
+func TestVoyageEmbedder_QuotaError(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusPaymentRequired)
+		_, _ = w.Write([]byte(`{"detail":"payment required"}`))
+	}))
+	defer server.Close()
+
+	embedder := &VoyageEmbedder{
+		apiKey:  "test-key",
+		baseURL: server.URL,
+		client:  server.Client(),
+	}
+
+	_, err := embedder.Embed(context.Background(), []string{"text"})
+
+	var quotaErr *QuotaExceededError
+	if !errors.As(err, &quotaErr) {
+		t.Fatalf("expected QuotaExceededError, got %T: %v", err, err)
+	}
+	if attempts != 1 {
+		t.Errorf("expected exactly 1 attempt, got %d", attempts)
+	}
+}
+
+func TestOllamaEmbedder_Embed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/embed" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+
+		var req ollamaEmbedRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.Model != "mxbai-embed-large" {
+			t.Errorf("unexpected model: %s", req.Model)
+		}
+
+		vec, _ := json.Marshal(testVector(0.5))
+		fmt.Fprintf(w, `{"embeddings":[%s]}`, vec)
+	}))
+	defer server.Close()
+
+	embedder := &OllamaEmbedder{
+		host:   server.URL,
+		model:  "mxbai-embed-large",
+		client: server.Client(),
+	}
+
+	vectors, err := embedder.Embed(context.Background(), []string{"text"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(vectors) != 1 || len(vectors[0]) != EmbeddingDimensions {
+		t.Fatalf("unexpected vectors shape")
+	}
+	if embedder.Model() != "ollama/mxbai-embed-large" {
+		t.Errorf("unexpected model: %s", embedder.Model())
+	}
+}
+
+func TestEmbed_EmptyInput(t *testing.T) {
+	embedder := &OllamaEmbedder{host: "http://127.0.0.1:0", model: "test", client: http.DefaultClient}
+
+	vectors, err := embedder.Embed(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(vectors) != 0 {
+		t.Errorf("expected no vectors, got %d", len(vectors))
+	}
+}
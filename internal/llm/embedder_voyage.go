@@ -0,0 +1,105 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	voyageEmbeddingsURL   = "https://api.voyageai.com/v1/embeddings"
+	voyageEmbeddingsModel = "voyage-3"
+)
+
+// VoyageEmbedder implements the Embedder interface for Voyage AI's
+// embeddings API. voyage-3 produces 1024-dimension vectors natively.
+type VoyageEmbedder struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+}
+
+// NewVoyageEmbedder creates a new Voyage AI embedding provider.
+func NewVoyageEmbedder(apiKey string) (*VoyageEmbedder, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("voyage API key is required")
+	}
+	return &VoyageEmbedder{
+		apiKey:  apiKey,
+		baseURL: voyageEmbeddingsURL,
+		client:  &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+type voyageEmbeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type voyageEmbeddingResponse struct {
+	Data []embeddingData `json:"data"`
+}
+
+type voyageError struct {
+	Detail string `json:"detail"`
+}
+
+// Model returns the embedding model name.
+func (e *VoyageEmbedder) Model() string {
+	return "voyage/" + voyageEmbeddingsModel
+}
+
+// Embed sends the texts to the Voyage embeddings API.
+func (e *VoyageEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return [][]float32{}, nil
+	}
+
+	payload, err := json.Marshal(voyageEmbeddingRequest{
+		Model: voyageEmbeddingsModel,
+		Input: texts,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	respBody, err := postEmbeddingRequest(ctx, e.client,
+		func(ctx context.Context) (*http.Request, error) {
+			httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL, bytes.NewReader(payload))
+			if err != nil {
+				return nil, err
+			}
+			httpReq.Header.Set("Content-Type", "application/json")
+			httpReq.Header.Set("Authorization", "Bearer "+e.apiKey)
+			return httpReq, nil
+		},
+		func(status int, body []byte) error {
+			var apiErr voyageError
+			_ = json.Unmarshal(body, &apiErr)
+			return fmt.Errorf("voyage API error (status %d): %s", status, apiErr.Detail)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var result voyageEmbeddingResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return orderEmbeddings(result.Data, len(texts))
+}
@@ -0,0 +1,319 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	geminiAPIURL = "https://generativelanguage.googleapis.com/v1beta/models"
+	geminiModel  = "gemini-2.5-flash"
+)
+
+// GeminiProvider implements the Provider interface for Google's Gemini
+// API.
+type GeminiProvider struct {
+	apiKey  string
+	baseURL string
+	model   string
+	client  *http.Client
+}
+
+// NewGeminiProvider creates a new Gemini LLM provider.
+func NewGeminiProvider(apiKey string) (*GeminiProvider, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("gemini API key is required")
+	}
+	return &GeminiProvider{
+		apiKey:  apiKey,
+		baseURL: geminiAPIURL,
+		model:   geminiModel,
+		client:  &http.Client{Timeout: 120 * time.Second},
+	}, nil
+}
+
+type geminiRequest struct {
+	SystemInstruction *geminiContent         `json:"systemInstruction,omitempty"`
+	Contents          []geminiContent        `json:"contents"`
+	GenerationConfig  geminiGenerationConfig `json:"generationConfig"`
+	Tools             []geminiTool           `json:"tools,omitempty"`
+	ToolConfig        *geminiToolConfig      `json:"toolConfig,omitempty"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiPart struct {
+	Text         string              `json:"text,omitempty"`
+	FunctionCall *geminiFunctionCall `json:"functionCall,omitempty"`
+}
+
+type geminiFunctionCall struct {
+	Name string          `json:"name"`
+	Args json.RawMessage `json:"args"`
+}
+
+type geminiGenerationConfig struct {
+	MaxOutputTokens int     `json:"maxOutputTokens,omitempty"`
+	Temperature     float64 `json:"temperature,omitempty"`
+}
+
+type geminiTool struct {
+	FunctionDeclarations []geminiFunctionDeclaration `json:"functionDeclarations"`
+}
+
+type geminiFunctionDeclaration struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters"`
+}
+
+type geminiToolConfig struct {
+	FunctionCallingConfig struct {
+		Mode                 string   `json:"mode"`
+		AllowedFunctionNames []string `json:"allowedFunctionNames,omitempty"`
+	} `json:"functionCallingConfig"`
+}
+
+// geminiResponse is the shape of both generateContent responses and the
+// individual chunks of streamGenerateContent.
+type geminiResponse struct {
+	Candidates []struct {
+		Content      geminiContent `json:"content"`
+		FinishReason string        `json:"finishReason"`
+	} `json:"candidates"`
+	UsageMetadata struct {
+		TotalTokenCount int `json:"totalTokenCount"`
+	} `json:"usageMetadata"`
+}
+
+type geminiError struct {
+	Error struct {
+		Message string `json:"message"`
+		Status  string `json:"status"`
+	} `json:"error"`
+}
+
+// buildRequest converts a CompletionRequest into the Gemini wire
+// format, applying the default max token limit.
+func (p *GeminiProvider) buildRequest(req CompletionRequest) geminiRequest {
+	maxTokens := req.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = 4096
+	}
+
+	body := geminiRequest{
+		Contents: []geminiContent{
+			{Role: "user", Parts: []geminiPart{{Text: req.UserPrompt}}},
+		},
+		GenerationConfig: geminiGenerationConfig{
+			MaxOutputTokens: maxTokens,
+			Temperature:     req.Temperature,
+		},
+	}
+	if req.SystemPrompt != "" {
+		body.SystemInstruction = &geminiContent{
+			Parts: []geminiPart{{Text: req.SystemPrompt}},
+		}
+	}
+
+	if len(req.Tools) > 0 {
+		decls := make([]geminiFunctionDeclaration, 0, len(req.Tools))
+		for _, t := range req.Tools {
+			decls = append(decls, geminiFunctionDeclaration{
+				Name:        t.Name,
+				Description: t.Description,
+				Parameters:  toolSchema(t),
+			})
+		}
+		body.Tools = []geminiTool{{FunctionDeclarations: decls}}
+
+		if req.ToolChoice != "" {
+			cfg := &geminiToolConfig{}
+			cfg.FunctionCallingConfig.Mode = "ANY"
+			cfg.FunctionCallingConfig.AllowedFunctionNames = []string{req.ToolChoice}
+			body.ToolConfig = cfg
+		}
+	}
+
+	return body
+}
+
+// newHTTPRequest creates an authenticated POST request for the given
+// model method (generateContent or streamGenerateContent) carrying the
+// given JSON payload.
+func (p *GeminiProvider) newHTTPRequest(ctx context.Context, method string, payload []byte) (*http.Request, error) {
+	apiURL := fmt.Sprintf("%s/%s:%s", p.baseURL, p.model, method)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-goog-api-key", p.apiKey)
+	return httpReq, nil
+}
+
+// apiError converts a non-200 Gemini response into an error. Gemini
+// reports exhausted quotas as 429 RESOURCE_EXHAUSTED with a message
+// mentioning the quota, which isQuotaError recognises.
+func (p *GeminiProvider) apiError(status int, respBody []byte) error {
+	var apiErr geminiError
+	_ = json.Unmarshal(respBody, &apiErr)
+	return fmt.Errorf("gemini API error (status %d): %s", status, apiErr.Error.Message)
+}
+
+// Complete sends a completion request to the Gemini API.
+func (p *GeminiProvider) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+	body := p.buildRequest(req)
+
+	return doWithRetry(ctx, func(ctx context.Context) (CompletionResponse, int, error) {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return CompletionResponse{}, 0, fmt.Errorf("failed to marshal request: %w", err)
+		}
+
+		httpReq, err := p.newHTTPRequest(ctx, "generateContent", payload)
+		if err != nil {
+			return CompletionResponse{}, 0, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		resp, err := p.client.Do(httpReq)
+		if err != nil {
+			return CompletionResponse{}, 0, fmt.Errorf("request failed: %w", err)
+		}
+		defer resp.Body.Close()
+
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return CompletionResponse{}, resp.StatusCode, fmt.Errorf("failed to read response: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return CompletionResponse{}, resp.StatusCode, p.apiError(resp.StatusCode, respBody)
+		}
+
+		var result geminiResponse
+		if err := json.Unmarshal(respBody, &result); err != nil {
+			return CompletionResponse{}, resp.StatusCode, fmt.Errorf("failed to parse response: %w", err)
+		}
+
+		if len(result.Candidates) == 0 {
+			return CompletionResponse{}, resp.StatusCode, fmt.Errorf("empty response from Gemini API")
+		}
+
+		text, toolCalls := splitGeminiParts(result.Candidates[0].Content.Parts)
+
+		return CompletionResponse{
+			Content:    text,
+			TokensUsed: result.UsageMetadata.TotalTokenCount,
+			ToolCalls:  toolCalls,
+		}, resp.StatusCode, nil
+	})
+}
+
+// Stream sends a streaming completion request to the Gemini API and
+// returns a channel of text deltas. Establishing the connection is
+// retried on 429/503 with the same policy as Complete.
+func (p *GeminiProvider) Stream(ctx context.Context, req CompletionRequest) (<-chan StreamEvent, error) {
+	payload, err := json.Marshal(p.buildRequest(req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := openStream(ctx, p.client,
+		func(ctx context.Context) (*http.Request, error) {
+			return p.newHTTPRequest(ctx, "streamGenerateContent?alt=sse", payload)
+		},
+		p.apiError,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan StreamEvent)
+	go func() {
+		defer close(out)
+		defer resp.Body.Close()
+
+		// Gemini has no terminal event; the stream is complete once a
+		// chunk reports a finish reason and the body is exhausted.
+		var tokens int
+		var toolCalls []ToolCall
+		var streamErr error
+		finished := false
+
+		readErr := readSSE(resp.Body, func(_, data string) bool {
+			var chunk geminiResponse
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				streamErr = fmt.Errorf("failed to parse stream chunk: %w", err)
+				return false
+			}
+
+			if chunk.UsageMetadata.TotalTokenCount > 0 {
+				tokens = chunk.UsageMetadata.TotalTokenCount
+			}
+			for _, cand := range chunk.Candidates {
+				text, calls := splitGeminiParts(cand.Content.Parts)
+				toolCalls = append(toolCalls, calls...)
+				if cand.FinishReason != "" {
+					finished = true
+				}
+				if text != "" {
+					if !sendEvent(ctx, out, StreamEvent{Delta: text}) {
+						return false
+					}
+				}
+			}
+			return true
+		})
+
+		switch {
+		case streamErr != nil:
+			sendEvent(ctx, out, StreamEvent{Err: streamErr})
+		case readErr != nil:
+			sendEvent(ctx, out, StreamEvent{Err: fmt.Errorf("failed to read stream: %w", readErr)})
+		case finished:
+			sendEvent(ctx, out, StreamEvent{Done: true, TokensUsed: tokens, ToolCalls: toolCalls})
+		case ctx.Err() == nil:
+			sendEvent(ctx, out, StreamEvent{Err: fmt.Errorf("gemini stream ended unexpectedly")})
+		}
+	}()
+
+	return out, nil
+}
+
+// splitGeminiParts concatenates the text parts of a candidate and
+// collects any function calls as tool calls.
+func splitGeminiParts(parts []geminiPart) (string, []ToolCall) {
+	var text strings.Builder
+	var calls []ToolCall
+	for _, part := range parts {
+		if part.FunctionCall != nil {
+			calls = append(calls, ToolCall{
+				Name:      part.FunctionCall.Name,
+				Arguments: normalizeArguments(part.FunctionCall.Args),
+			})
+			continue
+		}
+		text.WriteString(part.Text)
+	}
+	return text.String(), calls
+}
@@ -0,0 +1,212 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestGeminiProvider(server *httptest.Server) *GeminiProvider {
+	return &GeminiProvider{
+		apiKey:  "test-key",
+		baseURL: server.URL,
+		model:   geminiModel,
+		client:  server.Client(),
+	}
+}
+
+func TestGeminiProvider_Complete(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/"+geminiModel+":generateContent" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.Header.Get("x-goog-api-key") != "test-key" {
+			t.Error("missing or incorrect x-goog-api-key header")
+		}
+
+		var req geminiRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if req.SystemInstruction == nil || req.SystemInstruction.Parts[0].Text != "You are helpful" {
+			t.Error("system prompt should be sent as systemInstruction")
+		}
+		if len(req.Contents) != 1 || req.Contents[0].Role != "user" ||
+			req.Contents[0].Parts[0].Text != "Hello" {
+			t.Errorf("unexpected contents: %+v", req.Contents)
+		}
+		if req.GenerationConfig.MaxOutputTokens != 4096 {
+			t.Errorf("expected default max tokens, got %d", req.GenerationConfig.MaxOutputTokens)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"candidates": [{"content": {"role": "model", "parts": [{"text": "Hi "}, {"text": "there"}]},
+				"finishReason": "STOP"}],
+			"usageMetadata": {"promptTokenCount": 4, "candidatesTokenCount": 2, "totalTokenCount": 6}
+		}`))
+	}))
+	defer server.Close()
+
+	provider := newTestGeminiProvider(server)
+
+	resp, err := provider.Complete(context.Background(), CompletionRequest{
+		SystemPrompt: "You are helpful",
+		UserPrompt:   "Hello",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Content != "Hi there" {
+		t.Errorf("unexpected content: %q", resp.Content)
+	}
+	if resp.TokensUsed != 6 {
+		t.Errorf("unexpected tokens used: %d", resp.TokensUsed)
+	}
+}
+
+func TestGeminiProvider_CompleteWithTools(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req geminiRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if len(req.Tools) != 1 || len(req.Tools[0].FunctionDeclarations) != 1 ||
+			req.Tools[0].FunctionDeclarations[0].Name != "report" {
+			t.Errorf("unexpected tools: %+v", req.Tools)
+		}
+		if req.ToolConfig == nil || req.ToolConfig.FunctionCallingConfig.Mode != "ANY" {
+			t.Error("expected forced function calling mode")
+		}
+
+		_, _ = w.Write([]byte(`{
+			"candidates": [{"content": {"parts": [
+				{"functionCall": {"name": "report", "args": {"findings": []}}}
+			]}, "finishReason": "STOP"}],
+			"usageMetadata": {"totalTokenCount": 9}
+		}`))
+	}))
+	defer server.Close()
+
+	provider := newTestGeminiProvider(server)
+
+	resp, err := provider.Complete(context.Background(), CompletionRequest{
+		UserPrompt: "Analyse",
+		Tools:      []Tool{testTool},
+		ToolChoice: "report",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := StructuredContent(resp, "report"); got != `{"findings": []}` {
+		t.Errorf("unexpected structured content: %s", got)
+	}
+}
+
+func TestGeminiProvider_EmptyCandidates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"candidates": []}`))
+	}))
+	defer server.Close()
+
+	provider := newTestGeminiProvider(server)
+
+	_, err := provider.Complete(context.Background(), CompletionRequest{UserPrompt: "Hi"})
+	if err == nil {
+		t.Fatal("expected error for empty candidates")
+	}
+}
+
+func TestGeminiProvider_RetryOn503(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"error":{"code":503,"message":"The model is overloaded.","status":"UNAVAILABLE"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"candidates":[{"content":{"parts":[{"text":"ok"}]}}]}`))
+	}))
+	defer server.Close()
+
+	provider := newTestGeminiProvider(server)
+
+	resp, err := provider.Complete(context.Background(), CompletionRequest{UserPrompt: "Hi"})
+	if err != nil {
+		t.Fatalf("unexpected error after retry: %v", err)
+	}
+	if resp.Content != "ok" {
+		t.Errorf("unexpected content: %q", resp.Content)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestGeminiProvider_QuotaExceeded(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error":{"code":429,` +
+			`"message":"You exceeded your current quota, please check your plan and billing details.",` +
+			`"status":"RESOURCE_EXHAUSTED"}}`))
+	}))
+	defer server.Close()
+
+	provider := newTestGeminiProvider(server)
+
+	_, err := provider.Complete(context.Background(), CompletionRequest{UserPrompt: "Hi"})
+
+	var quotaErr *QuotaExceededError
+	if !errors.As(err, &quotaErr) {
+		t.Fatalf("expected QuotaExceededError, got %T: %v", err, err)
+	}
+	if attempts != 1 {
+		t.Errorf("expected exactly 1 attempt, got %d", attempts)
+	}
+}
+
+func TestGeminiProvider_Stream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/"+geminiModel+":streamGenerateContent" || r.URL.Query().Get("alt") != "sse" {
+			t.Errorf("unexpected URL: %s", r.URL.String())
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"candidates":[{"content":{"parts":[{"text":"Once "}]}}]}`+"\n\n")
+		fmt.Fprint(w, `data: {"candidates":[{"content":{"parts":[{"text":"upon"}]},"finishReason":"STOP"}],`+
+			`"usageMetadata":{"totalTokenCount":11}}`+"\n\n")
+	}))
+	defer server.Close()
+
+	provider := newTestGeminiProvider(server)
+
+	var deltas []string
+	resp, err := StreamCompletion(context.Background(), provider, CompletionRequest{UserPrompt: "Tell me"},
+		func(d string) { deltas = append(deltas, d) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Content != "Once upon" || resp.TokensUsed != 11 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if len(deltas) != 2 {
+		t.Errorf("expected 2 deltas, got %d", len(deltas))
+	}
+}
@@ -56,6 +56,7 @@ func NewOllamaProvider() (*OllamaProvider, error) {
 type ollamaRequest struct {
 	Model    string          `json:"model"`
 	Messages []ollamaMessage `json:"messages"`
+	Tools    []openaiTool    `json:"tools,omitempty"`
 	Stream   bool            `json:"stream"`
 }
 
@@ -64,27 +65,59 @@ type ollamaMessage struct {
 	Content string `json:"content"`
 }
 
+// ollamaToolCall is a tool call returned by Ollama. Unlike OpenAI, the
+// arguments are a JSON object and calls carry no identifier.
+type ollamaToolCall struct {
+	Function struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	} `json:"function"`
+}
+
 type ollamaResponse struct {
 	Message struct {
-		Content string `json:"content"`
+		Content   string           `json:"content"`
+		ToolCalls []ollamaToolCall `json:"tool_calls"`
 	} `json:"message"`
-	EvalCount   int `json:"eval_count"`
-	PromptCount int `json:"prompt_eval_count"`
+	Done        bool   `json:"done,omitempty"`
+	Error       string `json:"error,omitempty"`
+	EvalCount   int    `json:"eval_count"`
+	PromptCount int    `json:"prompt_eval_count"`
 }
 
-// Complete sends a completion request to the Ollama API.
-func (p *OllamaProvider) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+// buildRequest converts a CompletionRequest into the Ollama chat wire
+// format. Ollama has no equivalent of a forced tool choice, so
+// ToolChoice is ignored and the model decides whether to call a tool.
+func (p *OllamaProvider) buildRequest(req CompletionRequest, stream bool) ollamaRequest {
 	messages := []ollamaMessage{}
 	if req.SystemPrompt != "" {
 		messages = append(messages, ollamaMessage{Role: "system", Content: req.SystemPrompt})
 	}
 	messages = append(messages, ollamaMessage{Role: "user", Content: req.UserPrompt})
 
-	body := ollamaRequest{
+	return ollamaRequest{
 		Model:    p.model,
 		Messages: messages,
-		Stream:   false,
+		Tools:    buildOpenAITools(req.Tools),
+		Stream:   stream,
 	}
+}
+
+// convertOllamaToolCalls converts tool calls from the wire format.
+func convertOllamaToolCalls(calls []ollamaToolCall) []ToolCall {
+	var out []ToolCall
+	for _, c := range calls {
+		out = append(out, ToolCall{
+			Name:      c.Function.Name,
+			Arguments: normalizeArguments(c.Function.Arguments),
+		})
+	}
+	return out
+}
+
+// Complete sends a completion request to the Ollama API.
+func (p *OllamaProvider) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+	body := p.buildRequest(req, false)
 
 	apiURL := p.host + "/api/chat"
 
@@ -124,6 +157,91 @@ func (p *OllamaProvider) Complete(ctx context.Context, req CompletionRequest) (C
 		return CompletionResponse{
 			Content:    result.Message.Content,
 			TokensUsed: result.EvalCount + result.PromptCount,
+			ToolCalls:  convertOllamaToolCalls(result.Message.ToolCalls),
 		}, resp.StatusCode, nil
 	})
 }
+
+// Stream sends a streaming chat request to the Ollama API and returns a
+// channel of text deltas. Ollama streams newline-delimited JSON objects,
+// the last of which has done set and carries the token counts.
+func (p *OllamaProvider) Stream(ctx context.Context, req CompletionRequest) (<-chan StreamEvent, error) {
+	payload, err := json.Marshal(p.buildRequest(req, true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	apiURL := p.host + "/api/chat"
+
+	resp, err := openStream(ctx, p.client,
+		func(ctx context.Context) (*http.Request, error) {
+			httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(payload))
+			if err != nil {
+				return nil, err
+			}
+			httpReq.Header.Set("Content-Type", "application/json")
+			return httpReq, nil
+		},
+		func(status int, respBody []byte) error {
+			return fmt.Errorf("ollama API error (status %d): %s", status, string(respBody))
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan StreamEvent)
+	go func() {
+		defer close(out)
+		defer resp.Body.Close()
+
+		// Ollama delivers tool calls whole rather than as fragments,
+		// usually in a chunk before the final one.
+		var toolCalls []ToolCall
+
+		scanner := newLineScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Bytes()
+			if len(line) == 0 {
+				continue
+			}
+
+			var chunk ollamaResponse
+			if err := json.Unmarshal(line, &chunk); err != nil {
+				sendEvent(ctx, out, StreamEvent{Err: fmt.Errorf("failed to parse stream chunk: %w", err)})
+				return
+			}
+			if chunk.Error != "" {
+				sendEvent(ctx, out, StreamEvent{Err: fmt.Errorf("ollama stream error: %s", chunk.Error)})
+				return
+			}
+
+			toolCalls = append(toolCalls, convertOllamaToolCalls(chunk.Message.ToolCalls)...)
+
+			if chunk.Message.Content != "" {
+				if !sendEvent(ctx, out, StreamEvent{Delta: chunk.Message.Content}) {
+					return
+				}
+			}
+
+			if chunk.Done {
+				sendEvent(ctx, out, StreamEvent{
+					Done:       true,
+					TokensUsed: chunk.EvalCount + chunk.PromptCount,
+					ToolCalls:  toolCalls,
+				})
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			sendEvent(ctx, out, StreamEvent{Err: fmt.Errorf("failed to read stream: %w", err)})
+			return
+		}
+		if ctx.Err() == nil {
+			sendEvent(ctx, out, StreamEvent{Err: fmt.Errorf("ollama stream ended unexpectedly")})
+		}
+	}()
+
+	return out, nil
+}
@@ -27,8 +27,10 @@ const (
 
 // OpenAIProvider implements the Provider interface for OpenAI's API.
 type OpenAIProvider struct {
-	apiKey string
-	client *http.Client
+	apiKey  string
+	baseURL string
+	model   string // overrides openaiModel when set
+	client  *http.Client
 }
 
 // NewOpenAIProvider creates a new OpenAI LLM provider.
@@ -37,8 +39,9 @@ func NewOpenAIProvider(apiKey string) (*OpenAIProvider, error) {
 		return nil, fmt.Errorf("openai API key is required")
 	}
 	return &OpenAIProvider{
-		apiKey: apiKey,
-		client: &http.Client{Timeout: 120 * time.Second},
+		apiKey:  apiKey,
+		baseURL: openaiAPIURL,
+		client:  &http.Client{Timeout: 120 * time.Second},
 	}, nil
 }
 
@@ -47,6 +50,40 @@ type openaiRequest struct {
 	Messages    []openaiMessage `json:"messages"`
 	MaxTokens   int             `json:"max_tokens,omitempty"`
 	Temperature float64         `json:"temperature,omitempty"`
+	Tools       []openaiTool    `json:"tools,omitempty"`
+	ToolChoice  any             `json:"tool_choice,omitempty"`
+	Stream      bool            `json:"stream,omitempty"`
+
+	StreamOptions *openaiStreamOptions `json:"stream_options,omitempty"`
+}
+
+// openaiTool is a function tool definition. The same shape is accepted
+// by Ollama's chat API.
+type openaiTool struct {
+	Type     string             `json:"type"`
+	Function openaiToolFunction `json:"function"`
+}
+
+type openaiToolFunction struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters"`
+}
+
+// openaiToolCall is a function call returned by the model. Arguments
+// is a JSON-encoded string rather than an object.
+type openaiToolCall struct {
+	Index    int    `json:"index"`
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+type openaiStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
 }
 
 type openaiMessage struct {
@@ -57,7 +94,8 @@ type openaiMessage struct {
 type openaiResponse struct {
 	Choices []struct {
 		Message struct {
-			Content string `json:"content"`
+			Content   string           `json:"content"`
+			ToolCalls []openaiToolCall `json:"tool_calls"`
 		} `json:"message"`
 	} `json:"choices"`
 	Usage struct {
@@ -71,8 +109,24 @@ type openaiError struct {
 	} `json:"error"`
 }
 
-// Complete sends a completion request to the OpenAI API.
-func (p *OpenAIProvider) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+// openaiStreamChunk is a single chat.completion.chunk event. The final
+// chunk carries usage and an empty choices array when include_usage is
+// requested.
+type openaiStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content   string           `json:"content"`
+			ToolCalls []openaiToolCall `json:"tool_calls"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Usage *struct {
+		TotalTokens int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// buildRequest converts a CompletionRequest into the OpenAI chat
+// completions wire format, applying the default max token limit.
+func (p *OpenAIProvider) buildRequest(req CompletionRequest) openaiRequest {
 	maxTokens := req.MaxTokens
 	if maxTokens <= 0 {
 		maxTokens = 4096
@@ -84,25 +138,84 @@ func (p *OpenAIProvider) Complete(ctx context.Context, req CompletionRequest) (C
 	}
 	messages = append(messages, openaiMessage{Role: "user", Content: req.UserPrompt})
 
+	model := p.model
+	if model == "" {
+		model = openaiModel
+	}
+
 	body := openaiRequest{
-		Model:       openaiModel,
+		Model:       model,
 		Messages:    messages,
 		MaxTokens:   maxTokens,
 		Temperature: req.Temperature,
+		Tools:       buildOpenAITools(req.Tools),
+	}
+	if req.ToolChoice != "" && len(body.Tools) > 0 {
+		body.ToolChoice = map[string]any{
+			"type":     "function",
+			"function": map[string]string{"name": req.ToolChoice},
+		}
 	}
 
+	return body
+}
+
+// buildOpenAITools converts tool definitions into function tools.
+func buildOpenAITools(tools []Tool) []openaiTool {
+	var out []openaiTool
+	for _, t := range tools {
+		out = append(out, openaiTool{
+			Type: "function",
+			Function: openaiToolFunction{
+				Name:        t.Name,
+				Description: t.Description,
+				Parameters:  toolSchema(t),
+			},
+		})
+	}
+	return out
+}
+
+// convertOpenAIToolCalls converts function calls from the wire format,
+// decoding the string-encoded arguments into raw JSON.
+func convertOpenAIToolCalls(calls []openaiToolCall) []ToolCall {
+	var out []ToolCall
+	for _, c := range calls {
+		out = append(out, ToolCall{
+			ID:        c.ID,
+			Name:      c.Function.Name,
+			Arguments: normalizeArguments([]byte(c.Function.Arguments)),
+		})
+	}
+	return out
+}
+
+// newHTTPRequest creates an authenticated POST request to the chat
+// completions endpoint carrying the given JSON payload.
+func (p *OpenAIProvider) newHTTPRequest(ctx context.Context, payload []byte) (*http.Request, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	return httpReq, nil
+}
+
+// Complete sends a completion request to the OpenAI API.
+func (p *OpenAIProvider) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+	body := p.buildRequest(req)
+
 	return doWithRetry(ctx, func(ctx context.Context) (CompletionResponse, int, error) {
 		payload, err := json.Marshal(body)
 		if err != nil {
 			return CompletionResponse{}, 0, fmt.Errorf("failed to marshal request: %w", err)
 		}
 
-		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, openaiAPIURL, bytes.NewReader(payload))
+		httpReq, err := p.newHTTPRequest(ctx, payload)
 		if err != nil {
 			return CompletionResponse{}, 0, fmt.Errorf("failed to create request: %w", err)
 		}
-		httpReq.Header.Set("Content-Type", "application/json")
-		httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
 
 		resp, err := p.client.Do(httpReq)
 		if err != nil {
@@ -134,6 +247,106 @@ func (p *OpenAIProvider) Complete(ctx context.Context, req CompletionRequest) (C
 		return CompletionResponse{
 			Content:    result.Choices[0].Message.Content,
 			TokensUsed: result.Usage.TotalTokens,
+			ToolCalls:  convertOpenAIToolCalls(result.Choices[0].Message.ToolCalls),
 		}, resp.StatusCode, nil
 	})
 }
+
+// Stream sends a streaming completion request to the OpenAI API and
+// returns a channel of text deltas. Establishing the connection is
+// retried on 429/503 with the same policy as Complete.
+func (p *OpenAIProvider) Stream(ctx context.Context, req CompletionRequest) (<-chan StreamEvent, error) {
+	body := p.buildRequest(req)
+	body.Stream = true
+	body.StreamOptions = &openaiStreamOptions{IncludeUsage: true}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := openStream(ctx, p.client,
+		func(ctx context.Context) (*http.Request, error) {
+			return p.newHTTPRequest(ctx, payload)
+		},
+		func(status int, respBody []byte) error {
+			var apiErr openaiError
+			_ = json.Unmarshal(respBody, &apiErr)
+			return fmt.Errorf("openai API error (status %d): %s", status, apiErr.Error.Message)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan StreamEvent)
+	go func() {
+		defer close(out)
+		defer resp.Body.Close()
+
+		var tokens int
+		var streamErr error
+		done := false
+
+		// Function call fragments are streamed per call index: the
+		// first fragment carries the ID and name, later ones append to
+		// the arguments string.
+		var toolCalls []openaiToolCall
+
+		readErr := readSSE(resp.Body, func(_, data string) bool {
+			if data == "[DONE]" {
+				done = true
+				return false
+			}
+
+			var chunk openaiStreamChunk
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				streamErr = fmt.Errorf("failed to parse stream chunk: %w", err)
+				return false
+			}
+
+			if chunk.Usage != nil {
+				tokens = chunk.Usage.TotalTokens
+			}
+			for _, choice := range chunk.Choices {
+				for _, tc := range choice.Delta.ToolCalls {
+					for len(toolCalls) <= tc.Index {
+						toolCalls = append(toolCalls, openaiToolCall{})
+					}
+					call := &toolCalls[tc.Index]
+					if tc.ID != "" {
+						call.ID = tc.ID
+					}
+					if tc.Function.Name != "" {
+						call.Function.Name = tc.Function.Name
+					}
+					call.Function.Arguments += tc.Function.Arguments
+				}
+				if choice.Delta.Content == "" {
+					continue
+				}
+				if !sendEvent(ctx, out, StreamEvent{Delta: choice.Delta.Content}) {
+					return false
+				}
+			}
+			return true
+		})
+
+		switch {
+		case streamErr != nil:
+			sendEvent(ctx, out, StreamEvent{Err: streamErr})
+		case readErr != nil:
+			sendEvent(ctx, out, StreamEvent{Err: fmt.Errorf("failed to read stream: %w", readErr)})
+		case done:
+			sendEvent(ctx, out, StreamEvent{
+				Done:       true,
+				TokensUsed: tokens,
+				ToolCalls:  convertOpenAIToolCalls(toolCalls),
+			})
+		case ctx.Err() == nil:
+			sendEvent(ctx, out, StreamEvent{Err: fmt.Errorf("openai stream ended unexpectedly")})
+		}
+	}()
+
+	return out, nil
+}
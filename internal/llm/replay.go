@@ -0,0 +1,177 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+// ErrReplayMiss is returned by a ReplayProvider that is not recording
+// when no recording exists for a request.
+var ErrReplayMiss = errors.New("no recorded response for request")
+
+// ReplayProvider implements the Provider interface by replaying
+// completions previously recorded to disk. Each recording is a JSON
+// file named after a hash of the request, so identical prompts always
+// map to the same response.
+//
+// When constructed with an upstream provider, requests without a
+// recording are forwarded upstream and the response is recorded. Without
+// one, the provider never touches the network and a missing recording
+// is an error.
+type ReplayProvider struct {
+	dir      string
+	upstream Provider
+}
+
+// replayRecord is the on-disk format of a single recording. The request
+// is stored alongside the response so recordings can be inspected and
+// edited by hand.
+type replayRecord struct {
+	Request  CompletionRequest  `json:"request"`
+	Response CompletionResponse `json:"response"`
+}
+
+// NewReplayProvider creates a provider that replays recordings from dir.
+// If upstream is non-nil, cache misses are forwarded to it and recorded.
+func NewReplayProvider(dir string, upstream Provider) (*ReplayProvider, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("replay directory is required")
+	}
+	if upstream != nil {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create replay directory: %w", err)
+		}
+	}
+	return &ReplayProvider{dir: dir, upstream: upstream}, nil
+}
+
+// ReplayEnabled reports whether the operator has enabled the replay
+// service by setting LLM_REPLAY_DIR. The recording directory is shared
+// by every user of the server, so users cannot select the service
+// unless it is enabled.
+func ReplayEnabled() bool {
+	return os.Getenv("LLM_REPLAY_DIR") != ""
+}
+
+// newReplayProviderFromEnv creates a ReplayProvider configured from the
+// environment. LLM_REPLAY_DIR selects the recording directory and must
+// be set. If LLM_REPLAY_RECORD names another LLM service, misses are
+// recorded from that service using apiKey; otherwise the provider only
+// replays.
+func newReplayProviderFromEnv(apiKey string) (*ReplayProvider, error) {
+	if !ReplayEnabled() {
+		return nil, fmt.Errorf("replay service is not enabled: LLM_REPLAY_DIR is not set")
+	}
+	dir := os.Getenv("LLM_REPLAY_DIR")
+
+	var upstream Provider
+	if svc := models.LLMService(os.Getenv("LLM_REPLAY_RECORD")); svc != "" {
+		if svc == models.LLMServiceReplay {
+			return nil, fmt.Errorf("LLM_REPLAY_RECORD cannot be %q", svc)
+		}
+		var err error
+		upstream, err = NewProvider(svc, apiKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create recording provider: %w", err)
+		}
+	}
+
+	return NewReplayProvider(dir, upstream)
+}
+
+// Recording reports whether the provider forwards misses upstream.
+func (p *ReplayProvider) Recording() bool {
+	return p.upstream != nil
+}
+
+// Complete returns the recorded response for req, recording it from the
+// upstream provider first if necessary.
+func (p *ReplayProvider) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+	key, err := ReplayKey(req)
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+	path := filepath.Join(p.dir, key+".json")
+
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		var rec replayRecord
+		if err := json.Unmarshal(data, &rec); err != nil {
+			return CompletionResponse{}, fmt.Errorf("failed to parse recording %s: %w", path, err)
+		}
+		return rec.Response, nil
+	case !errors.Is(err, os.ErrNotExist):
+		return CompletionResponse{}, fmt.Errorf("failed to read recording: %w", err)
+	case p.upstream == nil:
+		return CompletionResponse{}, fmt.Errorf("%w (key %s)", ErrReplayMiss, key)
+	}
+
+	resp, err := p.upstream.Complete(ctx, req)
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+
+	if err := p.record(path, replayRecord{Request: req, Response: resp}); err != nil {
+		return CompletionResponse{}, err
+	}
+	return resp, nil
+}
+
+// record writes a recording atomically so that concurrent agents
+// recording the same prompt never leave a partial file behind.
+func (p *ReplayProvider) record(path string, rec replayRecord) error {
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal recording: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(p.dir, ".recording-*")
+	if err != nil {
+		return fmt.Errorf("failed to create recording: %w", err)
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck // no-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save recording: %w", err)
+	}
+	return nil
+}
+
+// ReplayKey returns the hash that identifies a request's recording. It
+// covers every field of the request, so changing a prompt, sampling
+// parameter, or tool schema produces a new key.
+func ReplayKey(req CompletionRequest) (string, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
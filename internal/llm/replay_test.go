@@ -0,0 +1,173 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+// countingProvider returns a fixed response and counts calls.
+type countingProvider struct {
+	calls int
+}
+
+func (p *countingProvider) Complete(_ context.Context, req CompletionRequest) (CompletionResponse, error) {
+	p.calls++
+	return CompletionResponse{
+		Content:    "echo: " + req.UserPrompt,
+		TokensUsed: 12,
+		ToolCalls: []ToolCall{
+			{ID: "call_1", Name: "report", Arguments: []byte(`{"ok":true}`)},
+		},
+	}, nil
+}
+
+func TestReplayProvider_RecordThenReplay(t *testing.T) {
+	dir := t.TempDir()
+	upstream := &countingProvider{}
+	req := CompletionRequest{SystemPrompt: "sys", UserPrompt: "hello", Tools: []Tool{testTool}}
+
+	recorder, err := NewReplayProvider(dir, upstream)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !recorder.Recording() {
+		t.Error("expected recorder to be recording")
+	}
+
+	want, err := recorder.Complete(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error recording: %v", err)
+	}
+	if _, err := recorder.Complete(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error on second call: %v", err)
+	}
+	if upstream.calls != 1 {
+		t.Errorf("expected upstream to be called once, got %d", upstream.calls)
+	}
+
+	// A replay-only provider serves the recording without upstream.
+	replayer, err := NewReplayProvider(dir, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := replayer.Complete(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error replaying: %v", err)
+	}
+	if got.Content != want.Content || got.TokensUsed != want.TokensUsed {
+		t.Errorf("replayed response %+v does not match recorded %+v", got, want)
+	}
+	if len(got.ToolCalls) != 1 || got.ToolCalls[0].Name != "report" {
+		t.Fatalf("unexpected replayed tool calls: %+v", got.ToolCalls)
+	}
+	var args struct{ OK bool }
+	if err := json.Unmarshal(got.ToolCalls[0].Arguments, &args); err != nil || !args.OK {
+		t.Errorf("unexpected replayed arguments: %s", got.ToolCalls[0].Arguments)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read dir: %v", err)
+	}
+	if len(entries) != 1 || filepath.Ext(entries[0].Name()) != ".json" {
+		t.Errorf("expected a single recording file, got %v", entries)
+	}
+}
+
+func TestReplayProvider_Miss(t *testing.T) {
+	replayer, err := NewReplayProvider(t.TempDir(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = replayer.Complete(context.Background(), CompletionRequest{UserPrompt: "unrecorded"})
+	if !errors.Is(err, ErrReplayMiss) {
+		t.Fatalf("expected ErrReplayMiss, got %v", err)
+	}
+}
+
+func TestReplayKey(t *testing.T) {
+	base := CompletionRequest{SystemPrompt: "sys", UserPrompt: "hello", MaxTokens: 100}
+
+	k1, err := ReplayKey(base)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	k2, _ := ReplayKey(base)
+	if k1 != k2 {
+		t.Error("expected identical requests to share a key")
+	}
+
+	changed := []CompletionRequest{
+		{SystemPrompt: "sys", UserPrompt: "goodbye", MaxTokens: 100},
+		{SystemPrompt: "sys", UserPrompt: "hello", MaxTokens: 200},
+		{SystemPrompt: "sys", UserPrompt: "hello", MaxTokens: 100, Temperature: 0.5},
+		{SystemPrompt: "sys", UserPrompt: "hello", MaxTokens: 100, ToolChoice: "report"},
+	}
+	for i, req := range changed {
+		k, _ := ReplayKey(req)
+		if k == k1 {
+			t.Errorf("request %d: expected a different key", i)
+		}
+	}
+}
+
+func TestNewProvider_Replay(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("LLM_REPLAY_DIR", dir)
+	t.Setenv("LLM_REPLAY_RECORD", "")
+
+	provider, err := NewProvider(models.LLMServiceReplay, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	replay, ok := provider.(*ReplayProvider)
+	if !ok {
+		t.Fatalf("expected *ReplayProvider, got %T", provider)
+	}
+	if replay.Recording() || replay.dir != dir {
+		t.Errorf("unexpected replay provider: %+v", replay)
+	}
+
+	t.Setenv("LLM_REPLAY_RECORD", "openai")
+	provider, err = NewProvider(models.LLMServiceReplay, "test-key")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !provider.(*ReplayProvider).Recording() {
+		t.Error("expected provider to record from openai")
+	}
+
+	t.Setenv("LLM_REPLAY_RECORD", "replay")
+	if _, err := NewProvider(models.LLMServiceReplay, ""); err == nil {
+		t.Error("expected error when recording from replay")
+	}
+}
+
+func TestNewProvider_ReplayNotEnabled(t *testing.T) {
+	t.Setenv("LLM_REPLAY_DIR", "")
+	t.Setenv("LLM_REPLAY_RECORD", "")
+
+	if ReplayEnabled() {
+		t.Error("expected replay to be disabled without LLM_REPLAY_DIR")
+	}
+	if _, err := NewProvider(models.LLMServiceReplay, ""); err == nil {
+		t.Error("expected error when LLM_REPLAY_DIR is not set")
+	}
+}
@@ -12,23 +12,60 @@ package llm
 
 import (
 	"context"
+	"errors"
 	"math"
+	"net/url"
 	"strings"
 	"time"
 )
 
 const maxRetries = 3
 
+// retryBaseDelay is the backoff before the first retry; each further
+// retry waits twice as long. Tests shorten it.
+var retryBaseDelay = time.Second
+
+// ServerError wraps an error caused by a 5xx response that persisted
+// after retries, or by a failure to reach the server at all, such as a
+// refused connection, a DNS failure or a timeout; StatusCode is 0 for
+// the latter. FallbackProvider uses it to move on to the next provider
+// in its chain.
+type ServerError struct {
+	StatusCode int
+	Err        error
+}
+
+func (e *ServerError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ServerError) Unwrap() error {
+	return e.Err
+}
+
 // doWithRetry calls fn up to maxRetries times with exponential backoff
-// when the HTTP status code is 429 (rate limited) or 503 (service
-// unavailable). Quota errors (402 or 429-with-quota-body) fail
-// immediately without retrying. The fn must return
-// (response, httpStatusCode, error).
+// when the HTTP status code is 429 (rate limited) or 5xx (server
+// error). Quota errors (402 or 429-with-quota-body) fail immediately
+// without retrying, as do transport errors, which are returned as a
+// ServerError. The fn must return (response, httpStatusCode, error),
+// with a status code of 0 when no response was received.
 func doWithRetry(
 	ctx context.Context,
 	fn func(ctx context.Context) (CompletionResponse, int, error),
 ) (CompletionResponse, error) {
+	return retryRequest(ctx, fn)
+}
+
+// retryRequest implements the retry loop shared by doWithRetry and the
+// streaming providers. Streaming calls use it to retry establishing the
+// connection; once a stream is open, errors are not retried.
+func retryRequest[T any](
+	ctx context.Context,
+	fn func(ctx context.Context) (T, int, error),
+) (T, error) {
+	var zero T
 	var lastErr error
+	var lastStatus int
 	for attempt := 0; attempt <= maxRetries; attempt++ {
 		resp, statusCode, err := fn(ctx)
 		if err == nil {
@@ -36,31 +73,58 @@ func doWithRetry(
 		}
 
 		lastErr = err
+		lastStatus = statusCode
 
 		// Quota errors fail immediately.
 		if isQuotaError(statusCode, err) {
-			return CompletionResponse{},
+			return zero,
 				&QuotaExceededError{
 					Provider: "llm",
 					Message:  err.Error(),
 				}
 		}
 
-		// Only retry on 429 (rate limited) or 503 (service unavailable)
-		if statusCode != 429 && statusCode != 503 {
-			return CompletionResponse{}, err
+		// The server could not be reached. Retrying a timeout would
+		// take as long again, so leave it to a fallback provider.
+		if statusCode == 0 && isTransportError(ctx, err) {
+			return zero, &ServerError{Err: err}
+		}
+
+		// Only retry on 429 (rate limited) or 5xx (server error)
+		if statusCode != 429 && !isServerStatus(statusCode) {
+			return zero, err
 		}
 
 		if attempt < maxRetries {
-			backoff := time.Duration(math.Pow(2, float64(attempt))) * time.Second
+			backoff := time.Duration(math.Pow(2, float64(attempt))) * retryBaseDelay
 			select {
 			case <-ctx.Done():
-				return CompletionResponse{}, ctx.Err()
+				return zero, ctx.Err()
 			case <-time.After(backoff):
 			}
 		}
 	}
-	return CompletionResponse{}, lastErr
+	return zero, wrapServerError(lastStatus, lastErr)
+}
+
+// wrapServerError wraps err in a ServerError when statusCode is 5xx.
+func wrapServerError(statusCode int, err error) error {
+	if isServerStatus(statusCode) {
+		return &ServerError{StatusCode: statusCode, Err: err}
+	}
+	return err
+}
+
+// isServerStatus reports whether statusCode is a 5xx server error.
+func isServerStatus(statusCode int) bool {
+	return statusCode >= 500 && statusCode <= 599
+}
+
+// isTransportError reports whether err is a failure to exchange an
+// HTTP request with the server, rather than the caller giving up.
+func isTransportError(ctx context.Context, err error) bool {
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) && ctx.Err() == nil
 }
 
 // isQuotaError checks whether the HTTP status code
@@ -0,0 +1,159 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+// RoutableAgents lists the agent route keys that can be mapped to a
+// specific provider and model. Pipeline agents named "<key>-expert"
+// share the route of their key.
+var RoutableAgents = []string{"ttrpg", "canon", "graph", "enrichment", "revision", "memory", "assistant", "prep"}
+
+// AgentRouteKey returns the route key for an agent name, e.g. "canon"
+// for "canon-expert".
+func AgentRouteKey(agent string) string {
+	return strings.TrimSuffix(agent, "-expert")
+}
+
+// NewProviderWithModel is like NewProvider but overrides the model the
+// provider requests. An empty model selects the provider's default.
+// The replay service ignores the model.
+func NewProviderWithModel(service models.LLMService, apiKey, model string) (Provider, error) {
+	provider, err := NewProvider(service, apiKey)
+	if err != nil || model == "" {
+		return provider, err
+	}
+
+	switch p := provider.(type) {
+	case *AnthropicProvider:
+		p.model = model
+	case *OpenAIProvider:
+		p.model = model
+	case *GeminiProvider:
+		p.model = model
+	case *OllamaProvider:
+		p.model = model
+	}
+	return provider, nil
+}
+
+// FallbackProvider tries an ordered chain of providers, moving on to the
+// next one when a provider's quota is exhausted, it keeps failing with
+// 5xx errors after retries, or it cannot be reached. Other errors, such
+// as invalid requests, are returned immediately since another provider
+// would fail the same way.
+type FallbackProvider struct {
+	providers []Provider
+}
+
+// NewFallbackProvider creates a provider that tries providers in order.
+func NewFallbackProvider(providers ...Provider) (*FallbackProvider, error) {
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("at least one provider is required")
+	}
+	return &FallbackProvider{providers: providers}, nil
+}
+
+// shouldFallBack reports whether err warrants trying the next provider:
+// an exhausted quota, or a ServerError from repeated 5xx responses or a
+// transport failure.
+func shouldFallBack(err error) bool {
+	var quotaErr *QuotaExceededError
+	var serverErr *ServerError
+	return errors.As(err, &quotaErr) || errors.As(err, &serverErr)
+}
+
+// Complete sends the request to each provider in turn until one
+// succeeds or fails with an error that does not warrant a fallback.
+func (p *FallbackProvider) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+	var err error
+	for i, provider := range p.providers {
+		var resp CompletionResponse
+		resp, err = provider.Complete(ctx, req)
+		if err == nil || !shouldFallBack(err) {
+			return resp, err
+		}
+		if i < len(p.providers)-1 {
+			log.Printf("llm: provider %d of %d failed, falling back: %v", i+1, len(p.providers), err)
+		}
+	}
+	return CompletionResponse{}, err
+}
+
+// Stream opens a stream on each provider in turn. Only failures to
+// establish the stream fall back; once a stream is open its errors are
+// delivered to the caller.
+func (p *FallbackProvider) Stream(ctx context.Context, req CompletionRequest) (<-chan StreamEvent, error) {
+	var err error
+	for i, provider := range p.providers {
+		var events <-chan StreamEvent
+		if sp, ok := provider.(StreamingProvider); ok {
+			events, err = sp.Stream(ctx, req)
+		} else {
+			events, err = completeAsStream(ctx, provider, req)
+		}
+		if err == nil || !shouldFallBack(err) {
+			return events, err
+		}
+		if i < len(p.providers)-1 {
+			log.Printf("llm: provider %d of %d failed, falling back: %v", i+1, len(p.providers), err)
+		}
+	}
+	return nil, err
+}
+
+// RoutedProvider sends each request to a provider chosen by the agent
+// name in the request context (see WithAgentName), so agents can be
+// routed to different models without knowing about it. Requests from
+// agents without a route use the default provider.
+type RoutedProvider struct {
+	defaultProvider Provider
+	routes          map[string]Provider
+}
+
+// NewRoutedProvider creates a provider that routes requests by agent.
+// routes is keyed by AgentRouteKey.
+func NewRoutedProvider(defaultProvider Provider, routes map[string]Provider) *RoutedProvider {
+	return &RoutedProvider{
+		defaultProvider: defaultProvider,
+		routes:          routes,
+	}
+}
+
+// providerFor returns the provider for the agent in ctx.
+func (p *RoutedProvider) providerFor(ctx context.Context) Provider {
+	if provider, ok := p.routes[AgentRouteKey(AgentName(ctx))]; ok {
+		return provider
+	}
+	return p.defaultProvider
+}
+
+// Complete sends the request to the provider routed for the agent.
+func (p *RoutedProvider) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+	return p.providerFor(ctx).Complete(ctx, req)
+}
+
+// Stream streams the request from the provider routed for the agent.
+func (p *RoutedProvider) Stream(ctx context.Context, req CompletionRequest) (<-chan StreamEvent, error) {
+	provider := p.providerFor(ctx)
+	if sp, ok := provider.(StreamingProvider); ok {
+		return sp.Stream(ctx, req)
+	}
+	return completeAsStream(ctx, provider, req)
+}
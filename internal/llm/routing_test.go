@@ -0,0 +1,263 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+func TestFallbackProvider_FallsBackOnQuota(t *testing.T) {
+	backup := &countingProvider{}
+	provider, err := NewFallbackProvider(
+		failingProvider{err: &QuotaExceededError{Provider: "llm", Message: "quota"}},
+		backup,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, err := provider.Complete(context.Background(), CompletionRequest{UserPrompt: "Hi"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Content != "echo: Hi" || backup.calls != 1 {
+		t.Errorf("expected backup to serve the request, got %+v after %d calls", resp, backup.calls)
+	}
+}
+
+func TestFallbackProvider_FallsBackOnServerError(t *testing.T) {
+	backup := &countingProvider{}
+	provider, _ := NewFallbackProvider(
+		failingProvider{err: wrapServerError(503, fmt.Errorf("overloaded"))},
+		backup,
+	)
+
+	if _, err := provider.Complete(context.Background(), CompletionRequest{UserPrompt: "Hi"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if backup.calls != 1 {
+		t.Errorf("expected backup to be called once, got %d", backup.calls)
+	}
+}
+
+func TestFallbackProvider_NoFallbackOnClientError(t *testing.T) {
+	backup := &countingProvider{}
+	provider, _ := NewFallbackProvider(
+		failingProvider{err: wrapServerError(400, fmt.Errorf("bad request"))},
+		backup,
+	)
+
+	_, err := provider.Complete(context.Background(), CompletionRequest{UserPrompt: "Hi"})
+	if err == nil || err.Error() != "bad request" {
+		t.Fatalf("expected bad request error, got %v", err)
+	}
+	if backup.calls != 0 {
+		t.Errorf("expected backup not to be called, got %d calls", backup.calls)
+	}
+}
+
+func TestFallbackProvider_ReturnsLastError(t *testing.T) {
+	provider, _ := NewFallbackProvider(
+		failingProvider{err: &QuotaExceededError{Provider: "llm", Message: "quota"}},
+		failingProvider{err: wrapServerError(500, fmt.Errorf("down"))},
+	)
+
+	_, err := provider.Complete(context.Background(), CompletionRequest{UserPrompt: "Hi"})
+	var serverErr *ServerError
+	if !errors.As(err, &serverErr) || serverErr.StatusCode != 500 {
+		t.Fatalf("expected ServerError 500, got %T: %v", err, err)
+	}
+}
+
+// shortenRetryDelay makes retries immediate for the rest of the test.
+func shortenRetryDelay(t *testing.T) {
+	t.Helper()
+	delay := retryBaseDelay
+	retryBaseDelay = time.Millisecond
+	t.Cleanup(func() { retryBaseDelay = delay })
+}
+
+func TestFallbackProvider_FallsBackOnTransportError(t *testing.T) {
+	// A closed server refuses connections.
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	backup := &countingProvider{}
+	provider, _ := NewFallbackProvider(
+		&OllamaProvider{host: server.URL, model: "test", client: server.Client()},
+		backup,
+	)
+
+	resp, err := provider.Complete(context.Background(), CompletionRequest{UserPrompt: "Hi"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Content != "echo: Hi" || backup.calls != 1 {
+		t.Errorf("expected backup to serve the request, got %+v after %d calls", resp, backup.calls)
+	}
+}
+
+func TestFallbackProvider_RetriesServerErrorBeforeFallingBack(t *testing.T) {
+	shortenRetryDelay(t)
+
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"message":{"content":"recovered"},"eval_count":1,"prompt_eval_count":1}`))
+	}))
+	defer server.Close()
+
+	backup := &countingProvider{}
+	provider, _ := NewFallbackProvider(
+		&OllamaProvider{host: server.URL, model: "test", client: server.Client()},
+		backup,
+	)
+
+	resp, err := provider.Complete(context.Background(), CompletionRequest{UserPrompt: "Hi"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Content != "recovered" || attempts != 2 || backup.calls != 0 {
+		t.Errorf("expected a single 502 to be retried, got %+v after %d attempts and %d backup calls",
+			resp, attempts, backup.calls)
+	}
+}
+
+func TestFallbackProvider_FallsBackOnRepeatedServerErrors(t *testing.T) {
+	shortenRetryDelay(t)
+
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	backup := &countingProvider{}
+	provider, _ := NewFallbackProvider(
+		&OllamaProvider{host: server.URL, model: "test", client: server.Client()},
+		backup,
+	)
+
+	if _, err := provider.Complete(context.Background(), CompletionRequest{UserPrompt: "Hi"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempts != maxRetries+1 || backup.calls != 1 {
+		t.Errorf("expected %d attempts before falling back, got %d attempts and %d backup calls",
+			maxRetries+1, attempts, backup.calls)
+	}
+}
+
+func TestFallbackProvider_Stream(t *testing.T) {
+	provider, _ := NewFallbackProvider(
+		failingProvider{err: &QuotaExceededError{Provider: "llm", Message: "quota"}},
+		streamingStub{},
+	)
+
+	resp, err := StreamCompletion(context.Background(), provider, CompletionRequest{UserPrompt: "Hi"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Content != "ab" || resp.TokensUsed != 9 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestNewFallbackProvider_Empty(t *testing.T) {
+	if _, err := NewFallbackProvider(); err == nil {
+		t.Error("expected error for empty chain")
+	}
+}
+
+func TestRoutedProvider_RoutesByAgent(t *testing.T) {
+	fallback := &countingProvider{}
+	canon := &countingProvider{}
+	provider := NewRoutedProvider(fallback, map[string]Provider{"canon": canon})
+
+	ctx := WithAgentName(context.Background(), "canon-expert")
+	if _, err := provider.Complete(ctx, CompletionRequest{UserPrompt: "Hi"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx = WithAgentName(context.Background(), "graph-expert")
+	if _, err := provider.Complete(ctx, CompletionRequest{UserPrompt: "Hi"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := provider.Complete(context.Background(), CompletionRequest{UserPrompt: "Hi"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if canon.calls != 1 {
+		t.Errorf("expected 1 call to canon route, got %d", canon.calls)
+	}
+	if fallback.calls != 2 {
+		t.Errorf("expected 2 calls to default provider, got %d", fallback.calls)
+	}
+}
+
+func TestAgentRouteKey(t *testing.T) {
+	tests := map[string]string{
+		"ttrpg-expert": "ttrpg",
+		"canon-expert": "canon",
+		"enrichment":   "enrichment",
+		"":             "",
+	}
+	for agent, want := range tests {
+		if got := AgentRouteKey(agent); got != want {
+			t.Errorf("AgentRouteKey(%q) = %q, want %q", agent, got, want)
+		}
+	}
+}
+
+func TestNewProviderWithModel(t *testing.T) {
+	var gotModel string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req anthropicRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		gotModel = req.Model
+		fmt.Fprint(w, `{"content":[{"type":"text","text":"ok"}],"usage":{"input_tokens":1,"output_tokens":1}}`)
+	}))
+	defer server.Close()
+
+	provider, err := NewProviderWithModel(models.LLMServiceAnthropic, "test-key", "claude-haiku-4-5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	anthropic := provider.(*AnthropicProvider)
+	anthropic.baseURL = server.URL
+	anthropic.client = server.Client()
+
+	if _, err := provider.Complete(context.Background(), CompletionRequest{UserPrompt: "Hi"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotModel != "claude-haiku-4-5" {
+		t.Errorf("expected overridden model, got %q", gotModel)
+	}
+
+	provider, _ = NewProviderWithModel(models.LLMServiceAnthropic, "test-key", "")
+	if provider.(*AnthropicProvider).model != "" {
+		t.Error("expected empty model to keep the provider default")
+	}
+}
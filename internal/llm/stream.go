@@ -0,0 +1,232 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package llm
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxStreamLineBytes bounds a single line read from a streaming
+// response body. Provider events are small JSON objects, so this is
+// generous while still protecting against a runaway stream.
+const maxStreamLineBytes = 1 << 20 // 1 MB
+
+// StreamingProvider is implemented by providers that can deliver a
+// completion incrementally. Stream returns once the connection has been
+// established (retrying on 429/503 like Complete); the returned channel
+// then yields text deltas and is closed after a final event with Done
+// set or an event carrying Err.
+type StreamingProvider interface {
+	Provider
+	Stream(ctx context.Context, req CompletionRequest) (<-chan StreamEvent, error)
+}
+
+// StreamEvent is a single event emitted by a streaming completion.
+type StreamEvent struct {
+	// Delta holds the next fragment of generated text, if any.
+	Delta string
+
+	// TokensUsed reports total input plus output tokens. It is only
+	// populated on the final event.
+	TokensUsed int
+
+	// ToolCalls holds any tool invocations requested by the model. It
+	// is only populated on the final event.
+	ToolCalls []ToolCall
+
+	// Done marks the final event of a successful stream.
+	Done bool
+
+	// Err is set when the stream fails after the connection was
+	// established. No further events follow an error.
+	Err error
+}
+
+// StreamCompletion runs req against provider and invokes onDelta for
+// every text fragment as it arrives. Providers that do not implement
+// StreamingProvider fall back to Complete, delivering the whole
+// response as a single delta. The assembled response is returned once
+// the stream finishes.
+func StreamCompletion(
+	ctx context.Context,
+	provider Provider,
+	req CompletionRequest,
+	onDelta func(delta string),
+) (CompletionResponse, error) {
+	sp, ok := provider.(StreamingProvider)
+	if !ok {
+		resp, err := provider.Complete(ctx, req)
+		if err != nil {
+			return CompletionResponse{}, err
+		}
+		if onDelta != nil && resp.Content != "" {
+			onDelta(resp.Content)
+		}
+		return resp, nil
+	}
+
+	events, err := sp.Stream(ctx, req)
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+
+	var sb strings.Builder
+	var tokens int
+	var toolCalls []ToolCall
+	done := false
+	for ev := range events {
+		if ev.Err != nil {
+			return CompletionResponse{}, ev.Err
+		}
+		if ev.Delta != "" {
+			sb.WriteString(ev.Delta)
+			if onDelta != nil {
+				onDelta(ev.Delta)
+			}
+		}
+		if ev.Done {
+			tokens = ev.TokensUsed
+			toolCalls = ev.ToolCalls
+			done = true
+		}
+	}
+
+	if !done {
+		if err := ctx.Err(); err != nil {
+			return CompletionResponse{}, err
+		}
+		return CompletionResponse{}, fmt.Errorf("stream closed before completion")
+	}
+
+	return CompletionResponse{
+		Content:    sb.String(),
+		TokensUsed: tokens,
+		ToolCalls:  toolCalls,
+	}, nil
+}
+
+// completeAsStream serves req with provider.Complete and returns the
+// response as a stream of one delta followed by the final event, for
+// wrappers that must implement StreamingProvider over any Provider.
+func completeAsStream(ctx context.Context, provider Provider, req CompletionRequest) (<-chan StreamEvent, error) {
+	resp, err := provider.Complete(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan StreamEvent, 2)
+	if resp.Content != "" {
+		out <- StreamEvent{Delta: resp.Content}
+	}
+	out <- StreamEvent{Done: true, TokensUsed: resp.TokensUsed, ToolCalls: resp.ToolCalls}
+	close(out)
+	return out, nil
+}
+
+// openStream sends an HTTP request built by newReq and returns the
+// response once a 200 status has been received. Non-200 responses are
+// read in full and converted to an error via errFn so that retryRequest
+// can classify rate-limit and quota failures exactly as it does for
+// blocking completions.
+func openStream(
+	ctx context.Context,
+	client *http.Client,
+	newReq func(ctx context.Context) (*http.Request, error),
+	errFn func(status int, body []byte) error,
+) (*http.Response, error) {
+	return retryRequest(ctx, func(ctx context.Context) (*http.Response, int, error) {
+		httpReq, err := newReq(ctx)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		resp, err := client.Do(httpReq)
+		if err != nil {
+			return nil, 0, fmt.Errorf("request failed: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			return nil, resp.StatusCode, errFn(resp.StatusCode, body)
+		}
+
+		return resp, resp.StatusCode, nil
+	})
+}
+
+// sendEvent delivers ev on out unless ctx is cancelled first. It
+// reports whether the event was delivered.
+func sendEvent(ctx context.Context, out chan<- StreamEvent, ev StreamEvent) bool {
+	select {
+	case out <- ev:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// newLineScanner returns a bufio.Scanner over r that tolerates long
+// lines up to maxStreamLineBytes.
+func newLineScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLineBytes)
+	return scanner
+}
+
+// readSSE parses a Server-Sent Events stream and invokes fn with the
+// event name and data payload of every complete event. Parsing stops
+// when fn returns false, the reader is exhausted, or a read error
+// occurs (which is returned).
+func readSSE(r io.Reader, fn func(event, data string) bool) error {
+	scanner := newLineScanner(r)
+
+	var event string
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if line == "" {
+			if len(data) > 0 {
+				if !fn(event, strings.Join(data, "\n")) {
+					return nil
+				}
+			}
+			event = ""
+			data = data[:0]
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, ":"):
+			// Comment / keep-alive line.
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	// Flush a trailing event that was not followed by a blank line.
+	if len(data) > 0 {
+		fn(event, strings.Join(data, "\n"))
+	}
+	return nil
+}
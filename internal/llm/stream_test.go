@@ -0,0 +1,287 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// collectStream drains a stream, returning the concatenated deltas and
+// the final event.
+func collectStream(t *testing.T, events <-chan StreamEvent) (string, StreamEvent) {
+	t.Helper()
+	var sb strings.Builder
+	var last StreamEvent
+	for ev := range events {
+		sb.WriteString(ev.Delta)
+		last = ev
+	}
+	return sb.String(), last
+}
+
+func TestAnthropicProvider_Stream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req anthropicRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if !req.Stream {
+			t.Error("stream should be true")
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: message_start\n")
+		fmt.Fprint(w, `data: {"type":"message_start","message":{"usage":{"input_tokens":10}}}`+"\n\n")
+		fmt.Fprint(w, "event: ping\ndata: {\"type\":\"ping\"}\n\n")
+		fmt.Fprint(w, "event: content_block_delta\n")
+		fmt.Fprint(w, `data: {"type":"content_block_delta","delta":{"type":"text_delta","text":"Hello"}}`+"\n\n")
+		fmt.Fprint(w, "event: content_block_delta\n")
+		fmt.Fprint(w, `data: {"type":"content_block_delta","delta":{"type":"text_delta","text":", world"}}`+"\n\n")
+		fmt.Fprint(w, "event: message_delta\n")
+		fmt.Fprint(w, `data: {"type":"message_delta","usage":{"output_tokens":5}}`+"\n\n")
+		fmt.Fprint(w, "event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n")
+	}))
+	defer server.Close()
+
+	provider := &AnthropicProvider{
+		apiKey:  "test-key",
+		baseURL: server.URL,
+		client:  server.Client(),
+	}
+
+	events, err := provider.Stream(context.Background(), CompletionRequest{UserPrompt: "Hi"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	text, last := collectStream(t, events)
+	if text != "Hello, world" {
+		t.Errorf("unexpected text: %q", text)
+	}
+	if !last.Done {
+		t.Fatalf("expected final event to be done, got %+v", last)
+	}
+	if last.TokensUsed != 15 {
+		t.Errorf("unexpected tokens used: got %d, want 15", last.TokensUsed)
+	}
+}
+
+func TestAnthropicProvider_StreamErrorEvent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"type":"content_block_delta","delta":{"text":"partial"}}`+"\n\n")
+		fmt.Fprint(w, `data: {"type":"error","error":{"message":"Overloaded"}}`+"\n\n")
+	}))
+	defer server.Close()
+
+	provider := &AnthropicProvider{
+		apiKey:  "test-key",
+		baseURL: server.URL,
+		client:  server.Client(),
+	}
+
+	_, err := StreamCompletion(context.Background(), provider, CompletionRequest{UserPrompt: "Hi"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "Overloaded") {
+		t.Fatalf("expected overloaded error, got %v", err)
+	}
+}
+
+func TestOpenAIProvider_Stream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Error("missing or incorrect Authorization header")
+		}
+
+		var req openaiRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if !req.Stream || req.StreamOptions == nil || !req.StreamOptions.IncludeUsage {
+			t.Error("expected stream with include_usage")
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"choices":[{"delta":{"role":"assistant"}}]}`+"\n\n")
+		fmt.Fprint(w, `data: {"choices":[{"delta":{"content":"foo"}}]}`+"\n\n")
+		fmt.Fprint(w, `data: {"choices":[{"delta":{"content":"bar"}}]}`+"\n\n")
+		fmt.Fprint(w, `data: {"choices":[],"usage":{"total_tokens":42}}`+"\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	provider := &OpenAIProvider{
+		apiKey:  "test-key",
+		baseURL: server.URL,
+		client:  server.Client(),
+	}
+
+	var deltas []string
+	resp, err := StreamCompletion(context.Background(), provider, CompletionRequest{
+		SystemPrompt: "sys",
+		UserPrompt:   "Hi",
+	}, func(d string) { deltas = append(deltas, d) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Content != "foobar" {
+		t.Errorf("unexpected content: %q", resp.Content)
+	}
+	if resp.TokensUsed != 42 {
+		t.Errorf("unexpected tokens used: got %d, want 42", resp.TokensUsed)
+	}
+	if len(deltas) != 2 {
+		t.Errorf("expected 2 deltas, got %d", len(deltas))
+	}
+}
+
+func TestOllamaProvider_Stream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ollamaRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if !req.Stream {
+			t.Error("stream should be true")
+		}
+
+		fmt.Fprintln(w, `{"message":{"content":"local "},"done":false}`)
+		fmt.Fprintln(w, `{"message":{"content":"model"},"done":false}`)
+		fmt.Fprintln(w, `{"message":{"content":""},"done":true,"eval_count":7,"prompt_eval_count":3}`)
+	}))
+	defer server.Close()
+
+	provider := &OllamaProvider{
+		host:   server.URL,
+		model:  "llama3.2",
+		client: server.Client(),
+	}
+
+	events, err := provider.Stream(context.Background(), CompletionRequest{UserPrompt: "Hi"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	text, last := collectStream(t, events)
+	if text != "local model" {
+		t.Errorf("unexpected text: %q", text)
+	}
+	if !last.Done || last.TokensUsed != 10 {
+		t.Errorf("unexpected final event: %+v", last)
+	}
+}
+
+func TestStream_RetriesInitialConnection(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("busy"))
+			return
+		}
+		fmt.Fprintln(w, `{"message":{"content":"ok"},"done":true}`)
+	}))
+	defer server.Close()
+
+	provider := &OllamaProvider{
+		host:   server.URL,
+		model:  "test",
+		client: server.Client(),
+	}
+
+	resp, err := StreamCompletion(context.Background(), provider, CompletionRequest{UserPrompt: "Hi"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error after retry: %v", err)
+	}
+	if resp.Content != "ok" {
+		t.Errorf("unexpected content: %q", resp.Content)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestStream_QuotaErrorNoRetry(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusPaymentRequired)
+		_, _ = w.Write([]byte(`{"error":{"message":"credit balance too low"}}`))
+	}))
+	defer server.Close()
+
+	provider := &AnthropicProvider{
+		apiKey:  "test-key",
+		baseURL: server.URL,
+		client:  server.Client(),
+	}
+
+	_, err := provider.Stream(context.Background(), CompletionRequest{UserPrompt: "Hi"})
+
+	var quotaErr *QuotaExceededError
+	if !errors.As(err, &quotaErr) {
+		t.Fatalf("expected QuotaExceededError, got %T: %v", err, err)
+	}
+	if attempts != 1 {
+		t.Errorf("expected exactly 1 attempt, got %d", attempts)
+	}
+}
+
+// completeOnlyProvider implements Provider without streaming support.
+type completeOnlyProvider struct{}
+
+func (completeOnlyProvider) Complete(_ context.Context, _ CompletionRequest) (CompletionResponse, error) {
+	return CompletionResponse{Content: "whole response", TokensUsed: 3}, nil
+}
+
+func TestStreamCompletion_FallsBackToComplete(t *testing.T) {
+	var deltas []string
+	resp, err := StreamCompletion(context.Background(), completeOnlyProvider{},
+		CompletionRequest{UserPrompt: "Hi"},
+		func(d string) { deltas = append(deltas, d) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Content != "whole response" || resp.TokensUsed != 3 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if len(deltas) != 1 || deltas[0] != "whole response" {
+		t.Errorf("expected single delta with full content, got %v", deltas)
+	}
+}
+
+func TestReadSSE_MultiLineData(t *testing.T) {
+	input := "event: a\ndata: line1\ndata: line2\n\n: comment\ndata: tail"
+	var got []string
+	err := readSSE(strings.NewReader(input), func(event, data string) bool {
+		got = append(got, event+"|"+data)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"a|line1\nline2", "|tail"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
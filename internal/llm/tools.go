@@ -0,0 +1,45 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package llm
+
+import "encoding/json"
+
+// StructuredContent returns the JSON arguments of the first call to the
+// named tool in resp. When the model did not call the tool (for example
+// because the provider ignores tool definitions) it falls back to the
+// prose content, so callers can feed the result to the same JSON parser
+// either way.
+func StructuredContent(resp CompletionResponse, toolName string) string {
+	for _, call := range resp.ToolCalls {
+		if call.Name == toolName && len(call.Arguments) > 0 {
+			return string(call.Arguments)
+		}
+	}
+	return resp.Content
+}
+
+// normalizeArguments returns raw as a JSON value, substituting an empty
+// object when a provider sends no arguments for a tool call.
+func normalizeArguments(raw []byte) json.RawMessage {
+	if len(raw) == 0 || string(raw) == "null" {
+		return json.RawMessage("{}")
+	}
+	return json.RawMessage(raw)
+}
+
+// toolSchema returns the tool's input schema, defaulting to an empty
+// object schema so that providers always receive a valid definition.
+func toolSchema(t Tool) json.RawMessage {
+	if len(t.InputSchema) == 0 {
+		return json.RawMessage(`{"type":"object","properties":{}}`)
+	}
+	return t.InputSchema
+}
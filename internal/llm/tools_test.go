@@ -0,0 +1,322 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var testTool = Tool{
+	Name:        "report",
+	Description: "Report findings",
+	InputSchema: json.RawMessage(`{"type":"object","properties":{"findings":{"type":"array"}}}`),
+}
+
+func TestAnthropicProvider_CompleteWithTools(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Tools []struct {
+				Name        string          `json:"name"`
+				InputSchema json.RawMessage `json:"input_schema"`
+			} `json:"tools"`
+			ToolChoice struct {
+				Type string `json:"type"`
+				Name string `json:"name"`
+			} `json:"tool_choice"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if len(req.Tools) != 1 || req.Tools[0].Name != "report" || len(req.Tools[0].InputSchema) == 0 {
+			t.Errorf("unexpected tools: %+v", req.Tools)
+		}
+		if req.ToolChoice.Type != "tool" || req.ToolChoice.Name != "report" {
+			t.Errorf("unexpected tool_choice: %+v", req.ToolChoice)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"content": [
+				{"type": "text", "text": "Calling tool."},
+				{"type": "tool_use", "id": "toolu_1", "name": "report", "input": {"findings": [1, 2]}}
+			],
+			"usage": {"input_tokens": 4, "output_tokens": 6}
+		}`))
+	}))
+	defer server.Close()
+
+	provider := &AnthropicProvider{
+		apiKey:  "test-key",
+		baseURL: server.URL,
+		client:  server.Client(),
+	}
+
+	resp, err := provider.Complete(context.Background(), CompletionRequest{
+		UserPrompt: "Analyse",
+		Tools:      []Tool{testTool},
+		ToolChoice: "report",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Content != "Calling tool." {
+		t.Errorf("unexpected content: %q", resp.Content)
+	}
+	if len(resp.ToolCalls) != 1 {
+		t.Fatalf("expected 1 tool call, got %d", len(resp.ToolCalls))
+	}
+	call := resp.ToolCalls[0]
+	if call.ID != "toolu_1" || call.Name != "report" {
+		t.Errorf("unexpected tool call: %+v", call)
+	}
+	if string(call.Arguments) != `{"findings": [1, 2]}` {
+		t.Errorf("unexpected arguments: %s", call.Arguments)
+	}
+}
+
+func TestAnthropicProvider_StreamWithTools(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"type":"message_start","message":{"usage":{"input_tokens":3}}}`+"\n\n")
+		fmt.Fprint(w, `data: {"type":"content_block_start","index":0,"content_block":{"type":"tool_use","id":"toolu_9","name":"report"}}`+"\n\n")
+		fmt.Fprint(w, `data: {"type":"content_block_delta","index":0,"delta":{"type":"input_json_delta","partial_json":"{\"findings\":"}}`+"\n\n")
+		fmt.Fprint(w, `data: {"type":"content_block_delta","index":0,"delta":{"type":"input_json_delta","partial_json":"[]}"}}`+"\n\n")
+		fmt.Fprint(w, `data: {"type":"message_delta","usage":{"output_tokens":2}}`+"\n\n")
+		fmt.Fprint(w, `data: {"type":"message_stop"}`+"\n\n")
+	}))
+	defer server.Close()
+
+	provider := &AnthropicProvider{
+		apiKey:  "test-key",
+		baseURL: server.URL,
+		client:  server.Client(),
+	}
+
+	resp, err := StreamCompletion(context.Background(), provider, CompletionRequest{
+		UserPrompt: "Analyse",
+		Tools:      []Tool{testTool},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.ToolCalls) != 1 {
+		t.Fatalf("expected 1 tool call, got %d", len(resp.ToolCalls))
+	}
+	if resp.ToolCalls[0].ID != "toolu_9" || string(resp.ToolCalls[0].Arguments) != `{"findings":[]}` {
+		t.Errorf("unexpected tool call: %+v", resp.ToolCalls[0])
+	}
+	if resp.TokensUsed != 5 {
+		t.Errorf("unexpected tokens used: %d", resp.TokensUsed)
+	}
+}
+
+func TestOpenAIProvider_CompleteWithTools(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Tools []struct {
+				Type     string `json:"type"`
+				Function struct {
+					Name       string          `json:"name"`
+					Parameters json.RawMessage `json:"parameters"`
+				} `json:"function"`
+			} `json:"tools"`
+			ToolChoice struct {
+				Type     string `json:"type"`
+				Function struct {
+					Name string `json:"name"`
+				} `json:"function"`
+			} `json:"tool_choice"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if len(req.Tools) != 1 || req.Tools[0].Type != "function" || req.Tools[0].Function.Name != "report" {
+			t.Errorf("unexpected tools: %+v", req.Tools)
+		}
+		if req.ToolChoice.Function.Name != "report" {
+			t.Errorf("unexpected tool_choice: %+v", req.ToolChoice)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"choices": [{"message": {"content": null, "tool_calls": [
+				{"id": "call_1", "type": "function", "function": {"name": "report", "arguments": "{\"findings\":[]}"}}
+			]}}],
+			"usage": {"total_tokens": 12}
+		}`))
+	}))
+	defer server.Close()
+
+	provider := &OpenAIProvider{
+		apiKey:  "test-key",
+		baseURL: server.URL,
+		client:  server.Client(),
+	}
+
+	resp, err := provider.Complete(context.Background(), CompletionRequest{
+		UserPrompt: "Analyse",
+		Tools:      []Tool{testTool},
+		ToolChoice: "report",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.ToolCalls) != 1 {
+		t.Fatalf("expected 1 tool call, got %d", len(resp.ToolCalls))
+	}
+	if got := StructuredContent(resp, "report"); got != `{"findings":[]}` {
+		t.Errorf("unexpected structured content: %s", got)
+	}
+}
+
+func TestOpenAIProvider_StreamWithTools(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"choices":[{"delta":{"tool_calls":[{"index":0,"id":"call_7","type":"function","function":{"name":"report","arguments":""}}]}}]}`+"\n\n")
+		fmt.Fprint(w, `data: {"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"findings\""}}]}}]}`+"\n\n")
+		fmt.Fprint(w, `data: {"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":":[]}"}}]}}]}`+"\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	provider := &OpenAIProvider{
+		apiKey:  "test-key",
+		baseURL: server.URL,
+		client:  server.Client(),
+	}
+
+	resp, err := StreamCompletion(context.Background(), provider, CompletionRequest{
+		UserPrompt: "Analyse",
+		Tools:      []Tool{testTool},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.ToolCalls) != 1 {
+		t.Fatalf("expected 1 tool call, got %d", len(resp.ToolCalls))
+	}
+	call := resp.ToolCalls[0]
+	if call.ID != "call_7" || call.Name != "report" || string(call.Arguments) != `{"findings":[]}` {
+		t.Errorf("unexpected tool call: %+v", call)
+	}
+}
+
+func TestOllamaProvider_CompleteWithTools(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ollamaRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if len(req.Tools) != 1 || req.Tools[0].Function.Name != "report" {
+			t.Errorf("unexpected tools: %+v", req.Tools)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"message": {"content": "", "tool_calls": [
+				{"function": {"name": "report", "arguments": {"findings": []}}}
+			]},
+			"done": true
+		}`))
+	}))
+	defer server.Close()
+
+	provider := &OllamaProvider{
+		host:   server.URL,
+		model:  "llama3.2",
+		client: server.Client(),
+	}
+
+	resp, err := provider.Complete(context.Background(), CompletionRequest{
+		UserPrompt: "Analyse",
+		Tools:      []Tool{testTool},
+		ToolChoice: "report",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.ToolCalls) != 1 || resp.ToolCalls[0].Name != "report" {
+		t.Fatalf("unexpected tool calls: %+v", resp.ToolCalls)
+	}
+	if string(resp.ToolCalls[0].Arguments) != `{"findings": []}` {
+		t.Errorf("unexpected arguments: %s", resp.ToolCalls[0].Arguments)
+	}
+}
+
+func TestCompleteWithoutTools_OmitsToolFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var raw map[string]json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if _, ok := raw["tools"]; ok {
+			t.Error("tools should be omitted when none are requested")
+		}
+		if _, ok := raw["tool_choice"]; ok {
+			t.Error("tool_choice should be omitted when none are requested")
+		}
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"ok"}}]}`))
+	}))
+	defer server.Close()
+
+	provider := &OpenAIProvider{
+		apiKey:  "test-key",
+		baseURL: server.URL,
+		client:  server.Client(),
+	}
+
+	if _, err := provider.Complete(context.Background(), CompletionRequest{UserPrompt: "Hi"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStructuredContent(t *testing.T) {
+	tests := []struct {
+		name string
+		resp CompletionResponse
+		want string
+	}{
+		{
+			name: "matching tool call",
+			resp: CompletionResponse{
+				Content:   "prose",
+				ToolCalls: []ToolCall{{Name: "report", Arguments: json.RawMessage(`{"a":1}`)}},
+			},
+			want: `{"a":1}`,
+		},
+		{
+			name: "other tool only",
+			resp: CompletionResponse{
+				Content:   `{"b":2}`,
+				ToolCalls: []ToolCall{{Name: "other", Arguments: json.RawMessage(`{"a":1}`)}},
+			},
+			want: `{"b":2}`,
+		},
+		{
+			name: "no tool calls",
+			resp: CompletionResponse{Content: "```json\n{}\n```"},
+			want: "```json\n{}\n```",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StructuredContent(tt.resp, "report"); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package llm
+
+import (
+	"context"
+	"log"
+
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+// UsageRecorder persists the token usage of LLM completions.
+type UsageRecorder interface {
+	RecordLLMUsage(ctx context.Context, usage models.LLMUsage) error
+}
+
+type agentNameKey struct{}
+
+// WithAgentName returns a context that attributes completions made
+// with it to the named agent.
+func WithAgentName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, agentNameKey{}, name)
+}
+
+// AgentName returns the agent name stored in ctx by WithAgentName, or
+// an empty string.
+func AgentName(ctx context.Context) string {
+	name, _ := ctx.Value(agentNameKey{}).(string)
+	return name
+}
+
+// MeteredProvider wraps a Provider and records the token usage of every
+// successful completion. Failed completions are not recorded.
+type MeteredProvider struct {
+	provider Provider
+	recorder UsageRecorder
+	scope    models.LLMUsage
+}
+
+// NewMeteredProvider wraps provider so that each completion is recorded
+// with recorder. scope supplies the campaign, job, and provider name of
+// every record; the agent name is taken from the request context.
+func NewMeteredProvider(provider Provider, recorder UsageRecorder, scope models.LLMUsage) *MeteredProvider {
+	return &MeteredProvider{
+		provider: provider,
+		recorder: recorder,
+		scope:    scope,
+	}
+}
+
+// Complete forwards the request to the wrapped provider and records the
+// tokens it used.
+func (p *MeteredProvider) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+	resp, err := p.provider.Complete(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+	p.record(ctx, resp.TokensUsed)
+	return resp, nil
+}
+
+// Stream forwards the request to the wrapped provider and records the
+// tokens reported by the final event. Providers without streaming
+// support are served by Complete and delivered as a single delta.
+func (p *MeteredProvider) Stream(ctx context.Context, req CompletionRequest) (<-chan StreamEvent, error) {
+	sp, ok := p.provider.(StreamingProvider)
+	if !ok {
+		return completeAsStream(ctx, p, req)
+	}
+
+	events, err := sp.Stream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan StreamEvent)
+	go func() {
+		defer close(out)
+		for ev := range events {
+			if ev.Done {
+				p.record(ctx, ev.TokensUsed)
+			}
+			if !sendEvent(ctx, out, ev) {
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// record stores a usage row. The completion has already been paid for,
+// so the row is written even if ctx was cancelled in the meantime, and
+// a storage failure is logged rather than failing the completion.
+func (p *MeteredProvider) record(ctx context.Context, tokens int) {
+	usage := p.scope
+	usage.AgentName = AgentName(ctx)
+	usage.TokensUsed = tokens
+
+	if err := p.recorder.RecordLLMUsage(context.WithoutCancel(ctx), usage); err != nil {
+		log.Printf("llm: failed to record usage for campaign %d: %v", usage.CampaignID, err)
+	}
+}
@@ -0,0 +1,134 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+// memoryRecorder collects usage records in memory.
+type memoryRecorder struct {
+	records []models.LLMUsage
+}
+
+func (r *memoryRecorder) RecordLLMUsage(_ context.Context, usage models.LLMUsage) error {
+	r.records = append(r.records, usage)
+	return nil
+}
+
+// failingProvider always returns err.
+type failingProvider struct {
+	err error
+}
+
+func (p failingProvider) Complete(_ context.Context, _ CompletionRequest) (CompletionResponse, error) {
+	return CompletionResponse{}, p.err
+}
+
+// streamingStub streams a fixed response.
+type streamingStub struct{}
+
+func (streamingStub) Complete(_ context.Context, _ CompletionRequest) (CompletionResponse, error) {
+	return CompletionResponse{Content: "ab", TokensUsed: 9}, nil
+}
+
+func (streamingStub) Stream(_ context.Context, _ CompletionRequest) (<-chan StreamEvent, error) {
+	out := make(chan StreamEvent, 3)
+	out <- StreamEvent{Delta: "a"}
+	out <- StreamEvent{Delta: "b"}
+	out <- StreamEvent{Done: true, TokensUsed: 9}
+	close(out)
+	return out, nil
+}
+
+func TestMeteredProvider_Complete(t *testing.T) {
+	recorder := &memoryRecorder{}
+	jobID := int64(7)
+	provider := NewMeteredProvider(completeOnlyProvider{}, recorder, models.LLMUsage{
+		CampaignID: 3,
+		JobID:      &jobID,
+		Provider:   "anthropic",
+	})
+
+	ctx := WithAgentName(context.Background(), "canon-expert")
+	if _, err := provider.Complete(ctx, CompletionRequest{UserPrompt: "Hi"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(recorder.records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(recorder.records))
+	}
+	rec := recorder.records[0]
+	if rec.CampaignID != 3 || rec.JobID == nil || *rec.JobID != 7 {
+		t.Errorf("unexpected scope: %+v", rec)
+	}
+	if rec.AgentName != "canon-expert" || rec.Provider != "anthropic" || rec.TokensUsed != 3 {
+		t.Errorf("unexpected record: %+v", rec)
+	}
+}
+
+func TestMeteredProvider_ErrorNotRecorded(t *testing.T) {
+	recorder := &memoryRecorder{}
+	provider := NewMeteredProvider(
+		failingProvider{err: errors.New("boom")}, recorder, models.LLMUsage{CampaignID: 1},
+	)
+
+	if _, err := provider.Complete(context.Background(), CompletionRequest{}); err == nil {
+		t.Fatal("expected error")
+	}
+	if len(recorder.records) != 0 {
+		t.Errorf("expected no records, got %d", len(recorder.records))
+	}
+}
+
+func TestMeteredProvider_Stream(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider Provider
+		want     string
+		tokens   int
+	}{
+		{"streaming provider", streamingStub{}, "ab", 9},
+		{"complete-only provider", completeOnlyProvider{}, "whole response", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &memoryRecorder{}
+			provider := NewMeteredProvider(tt.provider, recorder, models.LLMUsage{CampaignID: 1})
+
+			ctx := WithAgentName(context.Background(), "enrichment")
+			resp, err := StreamCompletion(ctx, provider, CompletionRequest{}, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.Content != tt.want || resp.TokensUsed != tt.tokens {
+				t.Errorf("unexpected response: %+v", resp)
+			}
+			if len(recorder.records) != 1 || recorder.records[0].TokensUsed != tt.tokens {
+				t.Fatalf("unexpected records: %+v", recorder.records)
+			}
+			if recorder.records[0].AgentName != "enrichment" {
+				t.Errorf("unexpected agent: %q", recorder.records[0].AgentName)
+			}
+		})
+	}
+}
+
+func TestAgentName_Unset(t *testing.T) {
+	if name := AgentName(context.Background()); name != "" {
+		t.Errorf("expected empty agent name, got %q", name)
+	}
+}
@@ -0,0 +1,217 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+// Package memory maintains the campaign, chapter and session memory
+// tiers described in docs/memory-system-design.md, using an LLM to
+// distil play notes into memories.
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/antonypegg/imagineer/internal/agents"
+	"github.com/antonypegg/imagineer/internal/enrichment"
+	"github.com/antonypegg/imagineer/internal/llm"
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+// AgentName is the name under which memory LLM calls are metered and
+// routed.
+const AgentName = "memory"
+
+// maxSessionNotesChars is the maximum number of characters of each
+// session's notes included in the chapter prompt.
+const maxSessionNotesChars = 6000
+
+// chapterToolName is the tool the LLM is asked to call with the
+// regenerated chapter memory.
+const chapterToolName = "report_chapter_memory"
+
+// chapterTool describes ChapterResult as a JSON Schema.
+var chapterTool = llm.Tool{
+	Name:        chapterToolName,
+	Description: "Report the chapter summary and the plot threads that are still open.",
+	InputSchema: json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"summary": {"type": "string", "description": "Narrative summary of the chapter so far."},
+			"activeThreads": {
+				"type": "array",
+				"items": {"type": "string"},
+				"description": "One sentence per plot thread that is still unresolved."
+			}
+		},
+		"required": ["summary", "activeThreads"]
+	}`),
+}
+
+// ErrNoSessionNotes is returned when none of a chapter's sessions has
+// wrap-up notes to summarise.
+var ErrNoSessionNotes = errors.New("no session of the chapter has wrap-up notes")
+
+// ChapterSession is a session of a chapter as seen by the summarizer.
+type ChapterSession struct {
+	Title  string
+	Number *int
+	Notes  string // Wrap-up notes of the session
+}
+
+// ChapterInput contains everything needed to regenerate a chapter
+// memory.
+type ChapterInput struct {
+	ChapterTitle  string
+	Overview      string
+	Goals         []string
+	ActiveThreads []string // Threads recorded before this regeneration
+	Sessions      []ChapterSession
+	Memories      []models.CampaignMemory // Campaign memory for context
+}
+
+// ChapterResult is a regenerated chapter summary and thread list.
+type ChapterResult struct {
+	Summary       string   `json:"summary"`
+	ActiveThreads []string `json:"activeThreads"`
+}
+
+// ChapterSummarizer regenerates a chapter's summary and active plot
+// threads from the wrap-up notes of its sessions.
+type ChapterSummarizer struct{}
+
+// NewChapterSummarizer creates a new ChapterSummarizer.
+func NewChapterSummarizer() *ChapterSummarizer {
+	return &ChapterSummarizer{}
+}
+
+// Summarize asks the LLM for a chapter summary and the plot threads
+// that remain open. Sessions without notes are ignored; if none has
+// notes ErrNoSessionNotes is returned without an LLM call.
+func (s *ChapterSummarizer) Summarize(
+	ctx context.Context,
+	provider llm.Provider,
+	input ChapterInput,
+) (*ChapterResult, error) {
+	sessions := make([]ChapterSession, 0, len(input.Sessions))
+	for _, session := range input.Sessions {
+		if strings.TrimSpace(session.Notes) != "" {
+			sessions = append(sessions, session)
+		}
+	}
+	if len(sessions) == 0 {
+		return nil, ErrNoSessionNotes
+	}
+	input.Sessions = sessions
+
+	resp, err := provider.Complete(llm.WithAgentName(ctx, AgentName), llm.CompletionRequest{
+		SystemPrompt: buildChapterSystemPrompt(),
+		UserPrompt:   buildChapterUserPrompt(input),
+		MaxTokens:    2048,
+		Temperature:  0.3,
+		Tools:        []llm.Tool{chapterTool},
+		ToolChoice:   chapterToolName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("LLM completion failed: %w", err)
+	}
+
+	return parseChapterResponse(llm.StructuredContent(resp, chapterToolName))
+}
+
+// buildChapterSystemPrompt returns the system prompt for chapter
+// memory regeneration.
+func buildChapterSystemPrompt() string {
+	return `You are the campaign archivist for a tabletop RPG. From the game master's wrap-up notes of every session in a chapter, write the chapter's memory.
+
+Rules:
+- "summary": a narrative summary of the chapter so far, in past tense, in at most three paragraphs. Cover what the player characters did, what they learned and what changed in the world.
+- "activeThreads": the plot threads that are still unresolved at the end of the latest session, one sentence each. Keep a previously recorded thread only if the notes do not resolve it. Drop resolved threads.
+- Use only what the notes and chapter details state. Do not invent events.
+
+Respond with JSON only.`
+}
+
+// buildChapterUserPrompt constructs the user prompt containing the
+// chapter details, previously recorded threads and session notes.
+func buildChapterUserPrompt(input ChapterInput) string {
+	var b strings.Builder
+
+	if section := enrichment.FormatCampaignMemories(input.Memories); section != "" {
+		b.WriteString(section)
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "## Chapter: %s\n\n", input.ChapterTitle)
+	if input.Overview != "" {
+		fmt.Fprintf(&b, "%s\n\n", input.Overview)
+	}
+
+	if len(input.Goals) > 0 {
+		b.WriteString("## Chapter Goals\n\n")
+		for _, goal := range input.Goals {
+			fmt.Fprintf(&b, "- %s\n", goal)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(input.ActiveThreads) > 0 {
+		b.WriteString("## Previously Recorded Threads\n\n")
+		for _, thread := range input.ActiveThreads {
+			fmt.Fprintf(&b, "- %s\n", thread)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Session Wrap-Up Notes\n\n")
+	for i, session := range input.Sessions {
+		heading := fmt.Sprintf("Session %d", i+1)
+		if session.Number != nil {
+			heading = fmt.Sprintf("Session %d", *session.Number)
+		}
+		if session.Title != "" {
+			heading += ": " + session.Title
+		}
+		fmt.Fprintf(&b, "### %s\n\n%s\n\n", heading,
+			agents.TruncateString(strings.TrimSpace(session.Notes), maxSessionNotesChars))
+	}
+
+	return b.String()
+}
+
+// parseChapterResponse parses the LLM response into a ChapterResult,
+// dropping blank threads.
+func parseChapterResponse(raw string) (*ChapterResult, error) {
+	cleaned := strings.TrimSpace(agents.StripCodeFences(raw))
+	if cleaned == "" {
+		return nil, fmt.Errorf("empty response from LLM")
+	}
+
+	var result ChapterResult
+	if err := json.Unmarshal([]byte(cleaned), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	result.Summary = strings.TrimSpace(result.Summary)
+	if result.Summary == "" {
+		return nil, fmt.Errorf("LLM returned an empty summary")
+	}
+
+	threads := make([]string, 0, len(result.ActiveThreads))
+	for _, thread := range result.ActiveThreads {
+		if thread = strings.TrimSpace(thread); thread != "" {
+			threads = append(threads, thread)
+		}
+	}
+	result.ActiveThreads = threads
+
+	return &result, nil
+}
@@ -0,0 +1,117 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/llm"
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProvider returns a canned response and records the request it
+// was given.
+type fakeProvider struct {
+	resp    llm.CompletionResponse
+	err     error
+	calls   int
+	request llm.CompletionRequest
+	agent   string
+}
+
+func (p *fakeProvider) Complete(ctx context.Context, req llm.CompletionRequest) (llm.CompletionResponse, error) {
+	p.calls++
+	p.request = req
+	p.agent = llm.AgentName(ctx)
+	return p.resp, p.err
+}
+
+func intPtr(n int) *int { return &n }
+
+func TestChapterSummarizer_Summarize(t *testing.T) {
+	provider := &fakeProvider{resp: llm.CompletionResponse{
+		ToolCalls: []llm.ToolCall{{
+			Name: chapterToolName,
+			Arguments: []byte(`{
+				"summary": " The investigators traced the cult to the docks. ",
+				"activeThreads": ["Who funds the cult?", "  ", "Where is the heir?"]
+			}`),
+		}},
+	}}
+
+	result, err := NewChapterSummarizer().Summarize(context.Background(), provider, ChapterInput{
+		ChapterTitle:  "The Dockside Murders",
+		Goals:         []string{"Find the killer"},
+		ActiveThreads: []string{"Who funds the cult?"},
+		Sessions: []ChapterSession{
+			{Title: "Arrival", Number: intPtr(1), Notes: "The party arrived in Limehouse."},
+			{Title: "Unplayed", Number: intPtr(2)},
+		},
+		Memories: []models.CampaignMemory{
+			{MemoryType: models.MemoryTypePremise, Content: "A cult stirs in London."},
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "The investigators traced the cult to the docks.", result.Summary)
+	assert.Equal(t, []string{"Who funds the cult?", "Where is the heir?"}, result.ActiveThreads)
+
+	assert.Equal(t, AgentName, provider.agent)
+	assert.Equal(t, chapterToolName, provider.request.ToolChoice)
+	prompt := provider.request.UserPrompt
+	assert.Contains(t, prompt, "## Chapter: The Dockside Murders")
+	assert.Contains(t, prompt, "- Find the killer")
+	assert.Contains(t, prompt, "## Previously Recorded Threads\n\n- Who funds the cult?")
+	assert.Contains(t, prompt, "### Session 1: Arrival\n\nThe party arrived in Limehouse.")
+	assert.Contains(t, prompt, "A cult stirs in London.")
+	assert.NotContains(t, prompt, "Unplayed", "sessions without notes are left out")
+}
+
+func TestChapterSummarizer_NoSessionNotes(t *testing.T) {
+	provider := &fakeProvider{}
+
+	_, err := NewChapterSummarizer().Summarize(context.Background(), provider, ChapterInput{
+		ChapterTitle: "Empty",
+		Sessions:     []ChapterSession{{Title: "Planned", Notes: "  "}},
+	})
+
+	assert.ErrorIs(t, err, ErrNoSessionNotes)
+	assert.Equal(t, 0, provider.calls, "the LLM must not be called")
+}
+
+func TestChapterSummarizer_ProviderError(t *testing.T) {
+	provider := &fakeProvider{err: errors.New("rate limited")}
+
+	_, err := NewChapterSummarizer().Summarize(context.Background(), provider, ChapterInput{
+		Sessions: []ChapterSession{{Notes: "Something happened."}},
+	})
+
+	assert.ErrorContains(t, err, "rate limited")
+}
+
+func TestParseChapterResponse(t *testing.T) {
+	result, err := parseChapterResponse("```json\n{\"summary\": \"Done.\", \"activeThreads\": []}\n```")
+	require.NoError(t, err)
+	assert.Equal(t, "Done.", result.Summary)
+	assert.Empty(t, result.ActiveThreads)
+	assert.NotNil(t, result.ActiveThreads, "threads must be an empty list, not null")
+
+	_, err = parseChapterResponse("")
+	assert.Error(t, err)
+	_, err = parseChapterResponse("not json")
+	assert.Error(t, err)
+	_, err = parseChapterResponse(`{"summary": "", "activeThreads": ["x"]}`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,243 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/antonypegg/imagineer/internal/agents"
+	"github.com/antonypegg/imagineer/internal/llm"
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+const (
+	// maxEntityTextChars is the maximum number of characters of text
+	// scanned for entities in one extraction.
+	maxEntityTextChars = 4000
+
+	// maxEvidenceChars is the maximum length of the evidence kept for
+	// a proposal.
+	maxEvidenceChars = 300
+)
+
+// entityToolName is the tool the LLM is asked to call with the
+// entities it detected.
+const entityToolName = "report_entities"
+
+// entityTool describes entityResponse as a JSON Schema.
+var entityTool = llm.Tool{
+	Name:        entityToolName,
+	Description: "Report the entities introduced or changed by the text.",
+	InputSchema: json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"entities": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"properties": {
+						"name": {"type": "string"},
+						"entityType": {"type": "string", "enum": ["npc", "location", "item", "faction", "clue", "creature", "organization", "event", "document", "other"]},
+						"description": {"type": "string", "description": "What the text establishes about the entity."},
+						"evidence": {"type": "string", "description": "The passage of the text the entity appears in."},
+						"existingEntity": {"type": "string", "description": "Exact name of the known entity this updates, if any."}
+					},
+					"required": ["name", "entityType", "description"]
+				}
+			}
+		},
+		"required": ["entities"]
+	}`),
+}
+
+// ErrNoEntityText is returned when there is no text to extract
+// entities from.
+var ErrNoEntityText = errors.New("no text to extract entities from")
+
+// EntityInput contains a fragment of session text to extract entities
+// from and the campaign's known entities.
+type EntityInput struct {
+	Source   models.EntityExtractionSource
+	Text     string
+	Entities []models.Entity
+}
+
+// entityResponse is the structured output of the entity extraction.
+type entityResponse struct {
+	Entities []extractedEntity `json:"entities"`
+}
+
+// extractedEntity is a single entity as reported by the LLM.
+type extractedEntity struct {
+	Name           string            `json:"name"`
+	EntityType     models.EntityType `json:"entityType"`
+	Description    string            `json:"description"`
+	Evidence       string            `json:"evidence"`
+	ExistingEntity string            `json:"existingEntity"`
+}
+
+// EntityExtractor detects new entities, and new information about
+// known ones, in the chat and notes written during play. It is meant
+// to run on each fragment of text as it is saved, so its prompt is
+// kept small.
+type EntityExtractor struct{}
+
+// NewEntityExtractor creates a new EntityExtractor.
+func NewEntityExtractor() *EntityExtractor {
+	return &EntityExtractor{}
+}
+
+// Extract asks the LLM for the entities in input.Text and returns them
+// as proposals for the GM to review. A proposal that names a known
+// entity becomes an update of that entity, and is dropped if it adds
+// nothing to it. If the text is blank ErrNoEntityText is returned
+// without an LLM call.
+func (e *EntityExtractor) Extract(
+	ctx context.Context,
+	provider llm.Provider,
+	input EntityInput,
+) ([]models.EntityExtractionProposal, error) {
+	if strings.TrimSpace(input.Text) == "" {
+		return nil, ErrNoEntityText
+	}
+
+	resp, err := provider.Complete(llm.WithAgentName(ctx, AgentName), llm.CompletionRequest{
+		SystemPrompt: buildEntitySystemPrompt(),
+		UserPrompt:   buildEntityUserPrompt(input),
+		MaxTokens:    1024,
+		Temperature:  0.2,
+		Tools:        []llm.Tool{entityTool},
+		ToolChoice:   entityToolName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("LLM completion failed: %w", err)
+	}
+
+	return parseEntityResponse(llm.StructuredContent(resp, entityToolName), input.Entities)
+}
+
+// buildEntitySystemPrompt returns the system prompt for entity
+// extraction.
+func buildEntitySystemPrompt() string {
+	return `You track the people, places and things of a tabletop RPG campaign while it is being played. Read what the game master just wrote and report the entities it introduces or changes.
+
+Rules:
+- Report a new entity only when the text names it. Do not report unnamed characters, the player characters' generic actions or rules terms.
+- Report a known entity only when the text establishes something new about it. Set "existingEntity" to its exact known name and describe only what is new.
+- Describe each entity in one or two sentences using only what the text says.
+- Copy the sentence the entity appears in to "evidence".
+- Report nothing when the text introduces nothing.
+
+Respond with JSON only.`
+}
+
+// buildEntityUserPrompt constructs the user prompt containing the text
+// and the known entities.
+func buildEntityUserPrompt(input EntityInput) string {
+	var b strings.Builder
+
+	heading := "Text"
+	switch input.Source {
+	case models.EntityExtractionSourceChat:
+		heading = "GM Chat Message"
+	case models.EntityExtractionSourcePlayNotes:
+		heading = "New Play Notes"
+	}
+	fmt.Fprintf(&b, "## %s\n\n%s\n\n", heading,
+		agents.TruncateString(strings.TrimSpace(input.Text), maxEntityTextChars))
+
+	if len(input.Entities) > 0 {
+		b.WriteString("## Known Entities\n\n")
+		for i, entity := range input.Entities {
+			if i == maxKnownEntities {
+				break
+			}
+			fmt.Fprintf(&b, "- %s (%s)\n", entity.Name, entity.EntityType)
+		}
+	}
+
+	return b.String()
+}
+
+// parseEntityResponse parses the LLM response into entity proposals.
+// Entities without a name are dropped, unknown types become "other"
+// and names are de-duplicated case-insensitively. An entity whose
+// existing entity or name matches one of entities, case-insensitively,
+// is proposed as an update of it, keeping its name and type, and is
+// dropped if it has no description.
+func parseEntityResponse(
+	raw string,
+	entities []models.Entity,
+) ([]models.EntityExtractionProposal, error) {
+	cleaned := strings.TrimSpace(agents.StripCodeFences(raw))
+	if cleaned == "" {
+		return nil, fmt.Errorf("empty response from LLM")
+	}
+
+	var resp entityResponse
+	if err := json.Unmarshal([]byte(cleaned), &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	byName := make(map[string]models.Entity, len(entities))
+	for _, entity := range entities {
+		byName[strings.ToLower(strings.TrimSpace(entity.Name))] = entity
+	}
+
+	seen := make(map[string]bool)
+	proposals := make([]models.EntityExtractionProposal, 0, len(resp.Entities))
+	for _, x := range resp.Entities {
+		p := models.EntityExtractionProposal{
+			Name:        strings.TrimSpace(x.Name),
+			EntityType:  x.EntityType,
+			Description: strings.TrimSpace(x.Description),
+			Evidence:    agents.TruncateString(strings.TrimSpace(x.Evidence), maxEvidenceChars),
+		}
+
+		known, ok := byName[strings.ToLower(strings.TrimSpace(x.ExistingEntity))]
+		if !ok {
+			known, ok = byName[strings.ToLower(p.Name)]
+		}
+		if ok {
+			if p.Description == "" {
+				continue
+			}
+			id := known.ID
+			p.Name = known.Name
+			p.EntityType = known.EntityType
+			p.MatchedEntityID = &id
+		}
+
+		key := strings.ToLower(p.Name)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		switch p.EntityType {
+		case models.EntityTypeNPC, models.EntityTypeLocation,
+			models.EntityTypeItem, models.EntityTypeFaction,
+			models.EntityTypeClue, models.EntityTypeCreature,
+			models.EntityTypeOrganization, models.EntityTypeEvent,
+			models.EntityTypeDocument, models.EntityTypeOther:
+		default:
+			p.EntityType = models.EntityTypeOther
+		}
+
+		proposals = append(proposals, p)
+	}
+
+	return proposals, nil
+}
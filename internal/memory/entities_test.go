@@ -0,0 +1,79 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/llm"
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntityExtractor_Extract(t *testing.T) {
+	provider := &fakeProvider{resp: llm.CompletionResponse{
+		ToolCalls: []llm.ToolCall{{
+			Name: entityToolName,
+			Arguments: []byte(`{"entities": [
+				{"name": " Brother Aldric ", "entityType": "npc", "description": "A monk guarding the crypt.",
+				 "evidence": "Brother Aldric bars the crypt door."},
+				{"name": "brother aldric", "entityType": "npc", "description": "Duplicate."},
+				{"name": "The Vance woman", "entityType": "npc", "description": "Lost an eye in the fight.",
+				 "existingEntity": "mira vance"},
+				{"name": "Mira Vance", "entityType": "npc", "description": ""},
+				{"name": "The Black Ledger", "entityType": "book", "description": "A coded ledger."},
+				{"name": " ", "entityType": "npc", "description": "Nameless."}
+			]}`),
+		}},
+	}}
+
+	proposals, err := NewEntityExtractor().Extract(context.Background(), provider, EntityInput{
+		Source: models.EntityExtractionSourceChat,
+		Text:   "Brother Aldric bars the crypt door. Mira lost an eye.",
+		Entities: []models.Entity{
+			{ID: 7, Name: "Mira Vance", EntityType: models.EntityTypeNPC},
+		},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, proposals, 3)
+
+	assert.Equal(t, "Brother Aldric", proposals[0].Name)
+	assert.Equal(t, "Brother Aldric bars the crypt door.", proposals[0].Evidence)
+	assert.Nil(t, proposals[0].MatchedEntityID)
+
+	assert.Equal(t, "Mira Vance", proposals[1].Name, "updates keep the known name")
+	require.NotNil(t, proposals[1].MatchedEntityID)
+	assert.Equal(t, int64(7), *proposals[1].MatchedEntityID)
+	assert.Equal(t, "Lost an eye in the fight.", proposals[1].Description)
+
+	assert.Equal(t, models.EntityTypeOther, proposals[2].EntityType, "unknown types become other")
+
+	assert.Equal(t, AgentName, provider.agent)
+	assert.Equal(t, entityToolName, provider.request.ToolChoice)
+	assert.Contains(t, provider.request.UserPrompt,
+		"## GM Chat Message\n\nBrother Aldric bars the crypt door.")
+	assert.Contains(t, provider.request.UserPrompt, "- Mira Vance (npc)")
+}
+
+func TestEntityExtractor_NoText(t *testing.T) {
+	provider := &fakeProvider{}
+
+	_, err := NewEntityExtractor().Extract(context.Background(), provider, EntityInput{
+		Source: models.EntityExtractionSourcePlayNotes,
+		Text:   "  \n",
+	})
+
+	assert.ErrorIs(t, err, ErrNoEntityText)
+	assert.Zero(t, provider.calls)
+}
@@ -0,0 +1,222 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package memory
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/antonypegg/imagineer/internal/agents"
+	"github.com/antonypegg/imagineer/internal/llm"
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+const (
+	// maxRecapNotesChars is the maximum number of characters of the
+	// wrap-up notes included in the GM recap sources.
+	maxRecapNotesChars = 6000
+
+	// maxRecapEntityChars is the maximum number of characters of each
+	// entity description and GM notes included in the recap sources.
+	maxRecapEntityChars = 300
+
+	// maxRecapTokens caps the length of a recap.
+	maxRecapTokens = 1024
+)
+
+// ErrNoRecapSources is returned when a session has no wrap-up notes,
+// memories or entity log entries to recap.
+var ErrNoRecapSources = errors.New("session has nothing to recap")
+
+// RecapInput contains the material a session recap is written from.
+type RecapInput struct {
+	Session    models.Session
+	Memories   []models.SessionMemory
+	EntityLogs []models.EntityLog // Entity log entries recorded for the session
+	Entities   []models.Entity    // Campaign entities, to resolve log entries and memory mentions
+}
+
+// RecapSources renders the material for a recap of input for audience
+// as text. The player-safe version is written only from player-visible
+// memories and entity log entries. It leaves out the wrap-up notes,
+// which are the GM's own and may hold secrets, as well as hidden
+// memories and entities' GM notes. The result depends only on its
+// arguments.
+func RecapSources(input RecapInput, audience models.RecapAudience) string {
+	var b strings.Builder
+	playerSafe := audience == models.RecapAudiencePlayer
+
+	if notes := strings.TrimSpace(derefString(input.Session.ActualNotes)); notes != "" && !playerSafe {
+		fmt.Fprintf(&b, "## Wrap-Up Notes\n\n%s\n\n",
+			agents.TruncateString(notes, maxRecapNotesChars))
+	}
+
+	byID := make(map[int64]models.Entity, len(input.Entities))
+	for _, entity := range input.Entities {
+		byID[entity.ID] = entity
+	}
+	mentioned := make(map[int64]bool)
+	var order []int64
+	mention := func(id int64) {
+		if _, ok := byID[id]; ok && !mentioned[id] {
+			mentioned[id] = true
+			order = append(order, id)
+		}
+	}
+
+	var memories strings.Builder
+	for _, m := range input.Memories {
+		if playerSafe && !m.IsPlayerVisible {
+			continue
+		}
+		content := strings.TrimSpace(m.Content)
+		if content == "" {
+			continue
+		}
+		fmt.Fprintf(&memories, "- [%s] ", m.MemoryType)
+		if m.Title != nil && strings.TrimSpace(*m.Title) != "" {
+			fmt.Fprintf(&memories, "%s: ", strings.TrimSpace(*m.Title))
+		}
+		memories.WriteString(content)
+		memories.WriteString("\n")
+		for _, id := range m.EntitiesMentioned {
+			mention(id)
+		}
+	}
+	if memories.Len() > 0 {
+		fmt.Fprintf(&b, "## Session Memories\n\n%s\n", memories.String())
+	}
+
+	var logs strings.Builder
+	for _, l := range input.EntityLogs {
+		entity, ok := byID[l.EntityID]
+		content := strings.TrimSpace(l.Content)
+		if !ok || content == "" {
+			continue
+		}
+		fmt.Fprintf(&logs, "- %s: %s\n", entity.Name, content)
+		mention(l.EntityID)
+	}
+	if logs.Len() > 0 {
+		fmt.Fprintf(&b, "## Entity Events\n\n%s\n", logs.String())
+	}
+
+	if len(order) > 0 {
+		b.WriteString("## Entities\n\n")
+		for _, id := range order {
+			entity := byID[id]
+			fmt.Fprintf(&b, "- %s (%s)", entity.Name, entity.EntityType)
+			if description := strings.TrimSpace(derefString(entity.Description)); description != "" {
+				fmt.Fprintf(&b, ": %s", agents.TruncateString(description, maxRecapEntityChars))
+			}
+			if gmNotes := strings.TrimSpace(derefString(entity.GMNotes)); gmNotes != "" && !playerSafe {
+				fmt.Fprintf(&b, " GM notes: %s", agents.TruncateString(gmNotes, maxRecapEntityChars))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
+
+// RecapSourceHash returns a hash of the prompt a recap of input for
+// audience is written from. A cached recap with the same hash is still
+// current.
+func RecapSourceHash(input RecapInput, audience models.RecapAudience) string {
+	prompt := buildRecapUserPrompt(input.Session, RecapSources(input, audience))
+	sum := sha256.Sum256([]byte(string(audience) + "\n" + prompt))
+	return hex.EncodeToString(sum[:])
+}
+
+// Recapper writes recaps of a played session for the prep of the
+// session after it.
+type Recapper struct{}
+
+// NewRecapper creates a new Recapper.
+func NewRecapper() *Recapper {
+	return &Recapper{}
+}
+
+// Recap asks provider for a recap of input for audience. If there is
+// nothing to recap ErrNoRecapSources is returned without an LLM call.
+func (r *Recapper) Recap(
+	ctx context.Context,
+	provider llm.Provider,
+	input RecapInput,
+	audience models.RecapAudience,
+) (string, error) {
+	sources := RecapSources(input, audience)
+	if strings.TrimSpace(sources) == "" {
+		return "", ErrNoRecapSources
+	}
+
+	resp, err := provider.Complete(llm.WithAgentName(ctx, AgentName), llm.CompletionRequest{
+		SystemPrompt: buildRecapSystemPrompt(audience),
+		UserPrompt:   buildRecapUserPrompt(input.Session, sources),
+		MaxTokens:    maxRecapTokens,
+		Temperature:  0.4,
+	})
+	if err != nil {
+		return "", fmt.Errorf("LLM completion failed: %w", err)
+	}
+
+	recap := strings.TrimSpace(resp.Content)
+	if recap == "" {
+		return "", fmt.Errorf("LLM returned an empty recap")
+	}
+	return recap, nil
+}
+
+// buildRecapSystemPrompt returns the system prompt for a recap for
+// audience.
+func buildRecapSystemPrompt(audience models.RecapAudience) string {
+	if audience == models.RecapAudiencePlayer {
+		return `You write the "previously on" recap read to the players at the start of a tabletop RPG session. Recap the previous session from the material provided.
+
+Rules:
+- Write two to four short paragraphs in past tense, addressed to the players.
+- Cover what the characters did, learned and decided, and where they left off.
+- Only include what the characters witnessed or learned. Never reveal plans, secrets or motives they have not discovered.
+- Do not invent events.`
+	}
+	return `You write a recap of the previous tabletop RPG session to help the game master prepare the next one. Recap it from the material provided.
+
+Rules:
+- Start with two or three short paragraphs in past tense covering what happened and where the session left off.
+- Follow with a short list of open threads, unresolved decisions and consequences to prepare for.
+- Include GM-only information such as secrets and NPC plans where relevant.
+- Do not invent events.`
+}
+
+// buildRecapUserPrompt constructs the user prompt containing the
+// session's heading and its recap sources.
+func buildRecapUserPrompt(session models.Session, sources string) string {
+	heading := "Previous Session"
+	if session.SessionNumber != nil {
+		heading = fmt.Sprintf("Previous Session: Session %d", *session.SessionNumber)
+	}
+	if title := strings.TrimSpace(derefString(session.Title)); title != "" {
+		heading += " - " + title
+	}
+	return fmt.Sprintf("# %s\n\n%s", heading, sources)
+}
+
+// derefString returns the value of s, or "" if s is nil.
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
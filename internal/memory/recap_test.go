@@ -0,0 +1,114 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/llm"
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recapInput() RecapInput {
+	return RecapInput{
+		Session: models.Session{
+			SessionNumber: intPtr(4),
+			Title:         strPtr("The Warehouse"),
+			ActualNotes:   strPtr("The party raided the warehouse."),
+		},
+		Memories: []models.SessionMemory{
+			{MemoryType: models.SessionMemoryTypeDiscovery, Content: "They found the ledger.",
+				EntitiesMentioned: []int64{1}, IsPlayerVisible: true},
+			{MemoryType: models.SessionMemoryTypeDecision, Content: "Mick sold them out to the cult.",
+				EntitiesMentioned: []int64{2}, IsPlayerVisible: false},
+		},
+		EntityLogs: []models.EntityLog{
+			{EntityID: 1, Content: "Taken by the party."},
+			{EntityID: 99, Content: "Unknown entity."},
+		},
+		Entities: []models.Entity{
+			{ID: 1, Name: "Black Ledger", EntityType: models.EntityTypeItem,
+				Description: strPtr("A coded ledger."), GMNotes: strPtr("Names the cult's patron.")},
+			{ID: 2, Name: "Mick", EntityType: models.EntityTypeNPC},
+		},
+	}
+}
+
+func TestRecapSources(t *testing.T) {
+	gm := RecapSources(recapInput(), models.RecapAudienceGM)
+	assert.Contains(t, gm, "## Wrap-Up Notes\n\nThe party raided the warehouse.")
+	assert.Contains(t, gm, "- [decision] Mick sold them out to the cult.")
+	assert.Contains(t, gm, "## Entity Events\n\n- Black Ledger: Taken by the party.\n")
+	assert.Contains(t, gm, "- Black Ledger (item): A coded ledger. GM notes: Names the cult's patron.")
+	assert.Contains(t, gm, "- Mick (npc)")
+	assert.NotContains(t, gm, "Unknown entity.", "log entries of unknown entities are dropped")
+
+	player := RecapSources(recapInput(), models.RecapAudiencePlayer)
+	assert.Contains(t, player, "- [discovery] They found the ledger.")
+	assert.NotContains(t, player, "sold them out", "spoiler memories are left out")
+	assert.NotContains(t, player, "GM notes", "GM notes are left out")
+	assert.NotContains(t, player, "Mick", "entities only mentioned by spoilers are left out")
+}
+
+func TestRecapSources_PlayerOmitsWrapUpNotes(t *testing.T) {
+	input := recapInput()
+	input.Session.ActualNotes = strPtr("The party raided the warehouse. Secretly, Mick is the cult's informant.")
+
+	player := RecapSources(input, models.RecapAudiencePlayer)
+
+	assert.NotContains(t, player, "Wrap-Up Notes")
+	assert.NotContains(t, player, "raided the warehouse")
+	assert.NotContains(t, player, "informant")
+	assert.Contains(t, player, "- [discovery] They found the ledger.")
+	assert.Contains(t, player, "- Black Ledger: Taken by the party.")
+}
+
+func TestRecapSourceHash(t *testing.T) {
+	input := recapInput()
+	hash := RecapSourceHash(input, models.RecapAudienceGM)
+
+	assert.Equal(t, hash, RecapSourceHash(recapInput(), models.RecapAudienceGM))
+	assert.NotEqual(t, hash, RecapSourceHash(input, models.RecapAudiencePlayer))
+
+	input.Session.ActualNotes = strPtr("The party burned the warehouse.")
+	assert.NotEqual(t, hash, RecapSourceHash(input, models.RecapAudienceGM),
+		"a change to the session invalidates the recap")
+}
+
+func TestRecapper_Recap(t *testing.T) {
+	provider := &fakeProvider{resp: llm.CompletionResponse{Content: " Last time, the party raided the warehouse. "}}
+
+	recap, err := NewRecapper().Recap(context.Background(), provider, recapInput(), models.RecapAudiencePlayer)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Last time, the party raided the warehouse.", recap)
+	assert.Equal(t, AgentName, provider.agent)
+	assert.Contains(t, provider.request.UserPrompt, "# Previous Session: Session 4 - The Warehouse")
+	assert.Contains(t, provider.request.SystemPrompt, "Never reveal")
+}
+
+func TestRecapper_NoSources(t *testing.T) {
+	provider := &fakeProvider{}
+	input := RecapInput{
+		Session: models.Session{ActualNotes: strPtr(" ")},
+		Memories: []models.SessionMemory{
+			{MemoryType: models.SessionMemoryTypeMoment, Content: "A secret.", IsPlayerVisible: false},
+		},
+	}
+
+	_, err := NewRecapper().Recap(context.Background(), provider, input, models.RecapAudiencePlayer)
+
+	assert.ErrorIs(t, err, ErrNoRecapSources)
+	assert.Zero(t, provider.calls)
+}
@@ -0,0 +1,349 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/antonypegg/imagineer/internal/agents"
+	"github.com/antonypegg/imagineer/internal/enrichment"
+	"github.com/antonypegg/imagineer/internal/llm"
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+const (
+	// maxSceneFieldChars is the maximum number of characters of each
+	// scene field included in the session prompt.
+	maxSceneFieldChars = 500
+
+	// maxChatChars is the maximum number of characters of chat
+	// transcript included in the session prompt. The most recent
+	// messages are kept.
+	maxChatChars = 8000
+
+	// maxKnownEntities is the maximum number of campaign entities
+	// listed for the LLM to link memories to.
+	maxKnownEntities = 200
+
+	// defaultSessionMemoryImportance is used when the LLM gives no
+	// usable importance.
+	defaultSessionMemoryImportance = 5
+)
+
+// sessionToolName is the tool the LLM is asked to call with the
+// extracted session memories.
+const sessionToolName = "report_session_memories"
+
+// sessionTool describes sessionResponse as a JSON Schema.
+var sessionTool = llm.Tool{
+	Name:        sessionToolName,
+	Description: "Report the memories extracted from the session.",
+	InputSchema: json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"memories": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"properties": {
+						"memoryType": {"type": "string", "enum": ["summary", "scene", "decision", "discovery", "moment"]},
+						"title": {"type": "string", "description": "A short title."},
+						"content": {"type": "string", "description": "The memory itself, in past tense."},
+						"sceneIndex": {"type": "integer", "description": "Number of the scene the memory belongs to, if any."},
+						"entities": {
+							"type": "array",
+							"items": {"type": "string"},
+							"description": "Exact names of the known entities the memory mentions."
+						},
+						"importance": {"type": "integer", "minimum": 1, "maximum": 10},
+						"playerVisible": {"type": "boolean", "description": "True if the players know everything in the memory."}
+					},
+					"required": ["memoryType", "content", "importance", "playerVisible"]
+				}
+			}
+		},
+		"required": ["memories"]
+	}`),
+}
+
+// ErrNoSessionContent is returned when a session has no notes, scenes
+// or chat messages to extract memories from.
+var ErrNoSessionContent = errors.New("session has no notes, scenes or chat messages")
+
+// SessionInput contains everything needed to extract the memories of
+// a session.
+type SessionInput struct {
+	Title        string
+	Number       *int
+	PrepNotes    string
+	PlayNotes    string
+	ActualNotes  string // Wrap-up notes
+	Scenes       []models.Scene
+	ChatMessages []models.SessionChatMessage
+	Entities     []models.Entity         // Campaign entities memories may link to
+	Memories     []models.CampaignMemory // Campaign memory for context
+}
+
+// hasContent reports whether the session has anything to extract
+// memories from.
+func (in SessionInput) hasContent() bool {
+	for _, notes := range []string{in.PrepNotes, in.PlayNotes, in.ActualNotes} {
+		if strings.TrimSpace(notes) != "" {
+			return true
+		}
+	}
+	return len(in.Scenes) > 0 || len(in.ChatMessages) > 0
+}
+
+// sessionResponse is the structured output of the session extraction.
+type sessionResponse struct {
+	Memories []extractedMemory `json:"memories"`
+}
+
+// extractedMemory is a single memory as reported by the LLM, with
+// entities referenced by name.
+type extractedMemory struct {
+	MemoryType    models.SessionMemoryType `json:"memoryType"`
+	Title         string                   `json:"title"`
+	Content       string                   `json:"content"`
+	SceneIndex    *int                     `json:"sceneIndex"`
+	Entities      []string                 `json:"entities"`
+	Importance    int                      `json:"importance"`
+	PlayerVisible bool                     `json:"playerVisible"`
+}
+
+// SessionExtractor turns a session's notes, scenes and chat into typed
+// session memories linked to the campaign's entities.
+type SessionExtractor struct{}
+
+// NewSessionExtractor creates a new SessionExtractor.
+func NewSessionExtractor() *SessionExtractor {
+	return &SessionExtractor{}
+}
+
+// Extract asks the LLM for the memories of a session and returns them
+// as suggestions for the GM to review. Entity names the LLM reports are
+// resolved against input.Entities; names that match no entity are
+// dropped. If the session has no content ErrNoSessionContent is
+// returned without an LLM call.
+func (e *SessionExtractor) Extract(
+	ctx context.Context,
+	provider llm.Provider,
+	input SessionInput,
+) ([]models.SessionMemorySuggestion, error) {
+	if !input.hasContent() {
+		return nil, ErrNoSessionContent
+	}
+
+	resp, err := provider.Complete(llm.WithAgentName(ctx, AgentName), llm.CompletionRequest{
+		SystemPrompt: buildSessionSystemPrompt(),
+		UserPrompt:   buildSessionUserPrompt(input),
+		MaxTokens:    4096,
+		Temperature:  0.3,
+		Tools:        []llm.Tool{sessionTool},
+		ToolChoice:   sessionToolName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("LLM completion failed: %w", err)
+	}
+
+	return parseSessionResponse(llm.StructuredContent(resp, sessionToolName),
+		input.Entities, len(input.Scenes))
+}
+
+// buildSessionSystemPrompt returns the system prompt for session
+// memory extraction.
+func buildSessionSystemPrompt() string {
+	return `You are the campaign archivist for a tabletop RPG. From the game master's notes, scenes and assistant chat of one session, extract the memories worth keeping.
+
+Memory types:
+- "summary": exactly one brief recap of the whole session.
+- "scene": what happened in one scene. Set "sceneIndex" to the scene's number.
+- "decision": a meaningful choice the players made and its consequence.
+- "discovery": new information the characters learned.
+- "moment": a memorable highlight of play.
+
+Rules:
+- Write every memory in past tense, in one to three sentences.
+- Prefer the wrap-up notes over prep notes: prep notes describe what was planned, not what happened. Do not record planned events that the other sources do not confirm.
+- List in "entities" the exact names of the known entities each memory mentions. Do not list names that are not known entities.
+- Rate "importance" from 1 (trivia) to 10 (changes the campaign).
+- Set "playerVisible" to false when a memory contains anything the players have not learned, such as GM secrets or plans.
+- Do not invent events.
+
+Respond with JSON only.`
+}
+
+// buildSessionUserPrompt constructs the user prompt containing the
+// session's notes, scenes, chat transcript and known entities.
+func buildSessionUserPrompt(input SessionInput) string {
+	var b strings.Builder
+
+	if section := enrichment.FormatCampaignMemories(input.Memories); section != "" {
+		b.WriteString(section)
+		b.WriteString("\n")
+	}
+
+	heading := "Session"
+	if input.Number != nil {
+		heading = fmt.Sprintf("Session %d", *input.Number)
+	}
+	if input.Title != "" {
+		heading += ": " + input.Title
+	}
+	fmt.Fprintf(&b, "## %s\n\n", heading)
+
+	writeNotes := func(title, notes string) {
+		if notes = strings.TrimSpace(notes); notes != "" {
+			fmt.Fprintf(&b, "### %s\n\n%s\n\n", title,
+				agents.TruncateString(notes, maxSessionNotesChars))
+		}
+	}
+	writeNotes("Wrap-Up Notes", input.ActualNotes)
+	writeNotes("Play Notes", input.PlayNotes)
+	writeNotes("Prep Notes", input.PrepNotes)
+
+	if len(input.Scenes) > 0 {
+		b.WriteString("### Scenes\n\n")
+		for i, scene := range input.Scenes {
+			fmt.Fprintf(&b, "%d. %s (%s, %s)\n", i+1, scene.Title, scene.SceneType, scene.Status)
+			writeSceneField := func(label string, value *string) {
+				if value != nil && strings.TrimSpace(*value) != "" {
+					fmt.Fprintf(&b, "   %s: %s\n", label,
+						agents.TruncateString(strings.TrimSpace(*value), maxSceneFieldChars))
+				}
+			}
+			writeSceneField("Description", scene.Description)
+			writeSceneField("Objective", scene.Objective)
+			writeSceneField("GM notes", scene.GMNotes)
+		}
+		b.WriteString("\n")
+	}
+
+	if transcript := formatChatTranscript(input.ChatMessages); transcript != "" {
+		fmt.Fprintf(&b, "### Assistant Chat\n\n%s\n", transcript)
+	}
+
+	if len(input.Entities) > 0 {
+		b.WriteString("## Known Entities\n\n")
+		for i, entity := range input.Entities {
+			if i == maxKnownEntities {
+				break
+			}
+			fmt.Fprintf(&b, "- %s (%s)\n", entity.Name, entity.EntityType)
+		}
+	}
+
+	return b.String()
+}
+
+// formatChatTranscript renders chat messages one per line, keeping the
+// most recent messages that fit in maxChatChars.
+func formatChatTranscript(messages []models.SessionChatMessage) string {
+	lines := make([]string, 0, len(messages))
+	remaining := maxChatChars
+	for i := len(messages) - 1; i >= 0; i-- {
+		content := strings.TrimSpace(messages[i].Content)
+		if content == "" {
+			continue
+		}
+		line := fmt.Sprintf("%s: %s", messages[i].Role, content)
+		if len(line) > remaining {
+			break
+		}
+		remaining -= len(line)
+		lines = append(lines, line)
+	}
+
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseSessionResponse parses the LLM response into session memory
+// suggestions. Memories of an unknown type or without content are
+// dropped, importance is clamped to 1-10, scene indexes outside the
+// session's sceneCount scenes are cleared and entity names are
+// resolved case-insensitively against entities.
+func parseSessionResponse(
+	raw string,
+	entities []models.Entity,
+	sceneCount int,
+) ([]models.SessionMemorySuggestion, error) {
+	cleaned := strings.TrimSpace(agents.StripCodeFences(raw))
+	if cleaned == "" {
+		return nil, fmt.Errorf("empty response from LLM")
+	}
+
+	var resp sessionResponse
+	if err := json.Unmarshal([]byte(cleaned), &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	byName := make(map[string]models.Entity, len(entities))
+	for _, entity := range entities {
+		byName[strings.ToLower(strings.TrimSpace(entity.Name))] = entity
+	}
+
+	suggestions := make([]models.SessionMemorySuggestion, 0, len(resp.Memories))
+	for _, m := range resp.Memories {
+		switch m.MemoryType {
+		case models.SessionMemoryTypeSummary, models.SessionMemoryTypeScene,
+			models.SessionMemoryTypeDecision, models.SessionMemoryTypeDiscovery,
+			models.SessionMemoryTypeMoment:
+		default:
+			continue
+		}
+		content := strings.TrimSpace(m.Content)
+		if content == "" {
+			continue
+		}
+
+		s := models.SessionMemorySuggestion{
+			MemoryType:      m.MemoryType,
+			Title:           strings.TrimSpace(m.Title),
+			Content:         content,
+			Importance:      m.Importance,
+			IsPlayerVisible: m.PlayerVisible,
+		}
+		switch {
+		case s.Importance == 0:
+			s.Importance = defaultSessionMemoryImportance
+		case s.Importance < 1:
+			s.Importance = 1
+		case s.Importance > 10:
+			s.Importance = 10
+		}
+		if m.SceneIndex != nil && *m.SceneIndex >= 1 && *m.SceneIndex <= sceneCount {
+			s.SceneIndex = m.SceneIndex
+		}
+
+		seen := make(map[int64]bool)
+		for _, name := range m.Entities {
+			entity, ok := byName[strings.ToLower(strings.TrimSpace(name))]
+			if !ok || seen[entity.ID] {
+				continue
+			}
+			seen[entity.ID] = true
+			s.EntityIDs = append(s.EntityIDs, entity.ID)
+			s.EntityNames = append(s.EntityNames, entity.Name)
+		}
+
+		suggestions = append(suggestions, s)
+	}
+
+	return suggestions, nil
+}
@@ -0,0 +1,116 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package memory
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/llm"
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestSessionExtractor_Extract(t *testing.T) {
+	provider := &fakeProvider{resp: llm.CompletionResponse{
+		ToolCalls: []llm.ToolCall{{
+			Name: sessionToolName,
+			Arguments: []byte(`{"memories": [
+				{"memoryType": "summary", "content": " The party reached the docks. ", "importance": 6, "playerVisible": true},
+				{"memoryType": "scene", "title": "Ambush", "content": "Cultists ambushed the party.",
+				 "sceneIndex": 1, "entities": ["mira vance", "Nobody", "Mira Vance"], "importance": 7, "playerVisible": true}
+			]}`),
+		}},
+	}}
+
+	suggestions, err := NewSessionExtractor().Extract(context.Background(), provider, SessionInput{
+		Title:       "Arrival",
+		Number:      intPtr(3),
+		ActualNotes: "The party fought cultists at the docks.",
+		Scenes: []models.Scene{
+			{Title: "Ambush", SceneType: "combat", Status: "completed", Objective: strPtr("Survive")},
+		},
+		ChatMessages: []models.SessionChatMessage{
+			{Role: "user", Content: "Who leads the cult?"},
+			{Role: "assistant", Content: "Mira Vance."},
+		},
+		Entities: []models.Entity{
+			{ID: 7, Name: "Mira Vance", EntityType: models.EntityTypeNPC},
+		},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, suggestions, 2)
+	assert.Equal(t, models.SessionMemoryTypeSummary, suggestions[0].MemoryType)
+	assert.Equal(t, "The party reached the docks.", suggestions[0].Content)
+	assert.Equal(t, []int64{7}, suggestions[1].EntityIDs)
+	assert.Equal(t, []string{"Mira Vance"}, suggestions[1].EntityNames)
+	assert.Equal(t, intPtr(1), suggestions[1].SceneIndex)
+
+	assert.Equal(t, AgentName, provider.agent)
+	assert.Equal(t, sessionToolName, provider.request.ToolChoice)
+	prompt := provider.request.UserPrompt
+	assert.Contains(t, prompt, "## Session 3: Arrival")
+	assert.Contains(t, prompt, "### Wrap-Up Notes\n\nThe party fought cultists at the docks.")
+	assert.Contains(t, prompt, "1. Ambush (combat, completed)\n   Objective: Survive")
+	assert.Contains(t, prompt, "user: Who leads the cult?\nassistant: Mira Vance.")
+	assert.Contains(t, prompt, "- Mira Vance (npc)")
+	assert.NotContains(t, prompt, "Prep Notes", "empty notes are left out")
+}
+
+func TestSessionExtractor_NoContent(t *testing.T) {
+	provider := &fakeProvider{}
+
+	_, err := NewSessionExtractor().Extract(context.Background(), provider, SessionInput{
+		Title:     "Planned",
+		PlayNotes: " ",
+	})
+
+	assert.ErrorIs(t, err, ErrNoSessionContent)
+	assert.Equal(t, 0, provider.calls, "the LLM must not be called")
+}
+
+func TestParseSessionResponse(t *testing.T) {
+	suggestions, err := parseSessionResponse(`{"memories": [
+		{"memoryType": "rumour", "content": "Unknown type."},
+		{"memoryType": "moment", "content": "  "},
+		{"memoryType": "moment", "content": "No importance."},
+		{"memoryType": "decision", "content": "Too important.", "importance": 42, "sceneIndex": 2},
+		{"memoryType": "discovery", "content": "Too trivial.", "importance": -3}
+	]}`, nil, 1)
+
+	require.NoError(t, err)
+	require.Len(t, suggestions, 3)
+	assert.Equal(t, defaultSessionMemoryImportance, suggestions[0].Importance)
+	assert.Equal(t, 10, suggestions[1].Importance)
+	assert.Nil(t, suggestions[1].SceneIndex, "scene index beyond the session's scenes is cleared")
+	assert.Equal(t, 1, suggestions[2].Importance)
+
+	_, err = parseSessionResponse("", nil, 0)
+	assert.Error(t, err)
+	_, err = parseSessionResponse("not json", nil, 0)
+	assert.Error(t, err)
+}
+
+func TestFormatChatTranscript_KeepsMostRecent(t *testing.T) {
+	old := strings.Repeat("a", maxChatChars)
+	transcript := formatChatTranscript([]models.SessionChatMessage{
+		{Role: "user", Content: old},
+		{Role: "user", Content: "Latest question"},
+		{Role: "assistant", Content: "  "},
+	})
+
+	assert.Equal(t, "user: Latest question", transcript)
+}
@@ -140,6 +140,157 @@ type UpdateEntityRequest struct {
 	SourceConfidence  *SourceConfidence `json:"sourceConfidence,omitempty"`
 }
 
+// EntityRevisionAuthorType identifies who made a change to an entity.
+type EntityRevisionAuthorType string
+
+const (
+	EntityRevisionAuthorUser   EntityRevisionAuthorType = "user"
+	EntityRevisionAuthorAgent  EntityRevisionAuthorType = "agent"
+	EntityRevisionAuthorImport EntityRevisionAuthorType = "import"
+)
+
+// EntityRevisionAuthor describes who made a change to an entity. An
+// agent's suggestion accepted by a GM has both the agent and the user,
+// and the analysis item that carried the suggestion.
+type EntityRevisionAuthor struct {
+	Type           EntityRevisionAuthorType
+	UserID         *int64
+	Agent          string
+	AnalysisItemID *int64
+}
+
+// EntityRevision is a snapshot of an entity at one of its versions.
+type EntityRevision struct {
+	ID                  int64                     `json:"id"`
+	EntityID            int64                     `json:"entityId"`
+	CampaignID          int64                     `json:"campaignId"`
+	Version             int                       `json:"version"`
+	EntityType          EntityType                `json:"entityType"`
+	Name                string                    `json:"name"`
+	Description         *string                   `json:"description,omitempty"`
+	Attributes          json.RawMessage           `json:"attributes,omitempty"`
+	Tags                []string                  `json:"tags,omitempty"`
+	GMNotes             *string                   `json:"gmNotes,omitempty"`
+	DiscoveredSession   *int64                    `json:"discoveredSession,omitempty"`
+	SourceDocument      *string                   `json:"sourceDocument,omitempty"`
+	SourceConfidence    SourceConfidence          `json:"sourceConfidence"`
+	AuthorType          *EntityRevisionAuthorType `json:"authorType,omitempty"`
+	AuthorUserID        *int64                    `json:"authorUserId,omitempty"`
+	AuthorAgent         *string                   `json:"authorAgent,omitempty"`
+	AnalysisItemID      *int64                    `json:"analysisItemId,omitempty"`
+	RestoredFromVersion *int                      `json:"restoredFromVersion,omitempty"`
+	CreatedAt           time.Time                 `json:"createdAt"`
+}
+
+// EntityFieldChange is a field that differs between two revisions of
+// an entity. Attributes are compared key by key, as "attributes.<key>".
+type EntityFieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// EntityRevisionDiff lists the fields changed from one revision of an
+// entity to another.
+type EntityRevisionDiff struct {
+	EntityID    int64               `json:"entityId"`
+	FromVersion int                 `json:"fromVersion"`
+	ToVersion   int                 `json:"toVersion"`
+	Changes     []EntityFieldChange `json:"changes"`
+}
+
+// MergeEntityRequest represents the request body for merging a
+// duplicate entity into another.
+type MergeEntityRequest struct {
+	SourceEntityID int64 `json:"sourceEntityId"`
+}
+
+// EntityMergeResult reports what merging a source entity into a target
+// entity moved. Relationships that would duplicate one the target
+// already has, directly or as its inverse, are dropped.
+type EntityMergeResult struct {
+	Entity               *Entity `json:"entity"`
+	MergedEntityID       int64   `json:"mergedEntityId"`
+	RelationshipsMoved   int     `json:"relationshipsMoved"`
+	RelationshipsDropped int     `json:"relationshipsDropped"`
+	LinksMoved           int64   `json:"linksMoved"`
+	ContentUpdated       int64   `json:"contentUpdated"`
+	AliasRecorded        bool    `json:"aliasRecorded"`
+}
+
+// EntityAliasSource records how an entity alias was added.
+type EntityAliasSource string
+
+const (
+	EntityAliasSourceManual   EntityAliasSource = "manual"
+	EntityAliasSourceMerge    EntityAliasSource = "merge"
+	EntityAliasSourceAnalysis EntityAliasSource = "analysis"
+)
+
+// EntityAlias is another name a campaign entity is known by. Aliases
+// are unique within a campaign, ignoring case.
+type EntityAlias struct {
+	ID         int64             `json:"id"`
+	EntityID   int64             `json:"entityId"`
+	CampaignID int64             `json:"campaignId"`
+	Alias      string            `json:"alias"`
+	Source     EntityAliasSource `json:"source"`
+	CreatedAt  time.Time         `json:"createdAt"`
+	UpdatedAt  time.Time         `json:"updatedAt"`
+}
+
+// CreateEntityAliasRequest represents the request body for adding an
+// alias to an entity.
+type CreateEntityAliasRequest struct {
+	Alias string `json:"alias"`
+}
+
+// UpdateEntityAliasRequest represents the request body for renaming an
+// alias or reassigning it to another entity of the campaign.
+type UpdateEntityAliasRequest struct {
+	Alias    *string `json:"alias,omitempty"`
+	EntityID *int64  `json:"entityId,omitempty"`
+}
+
+// AttributeValidationMode is how a campaign treats entity attributes
+// that do not match its game system's schema. It is read from the
+// attributeValidation campaign setting.
+type AttributeValidationMode string
+
+const (
+	// AttributeValidationWarn saves the entity and reports the
+	// problems alongside it. This is the default.
+	AttributeValidationWarn AttributeValidationMode = "warn"
+	// AttributeValidationStrict rejects the entity.
+	AttributeValidationStrict AttributeValidationMode = "strict"
+)
+
+// Attribute field error codes.
+const (
+	AttributeErrorUnknownAttribute = "unknown_attribute"
+	AttributeErrorRequired         = "required"
+	AttributeErrorInvalidType      = "invalid_type"
+	AttributeErrorOutOfRange       = "out_of_range"
+	AttributeErrorUnknownSkill     = "unknown_skill"
+)
+
+// AttributeFieldError describes an entity attribute that does not match
+// the game system's schema. Field is the path of the attribute, e.g.
+// "attributes.characteristics.STR".
+type AttributeFieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// AttributeValidationError is the error response for an entity whose
+// attributes are rejected by a campaign in strict mode.
+type AttributeValidationError struct {
+	Code        int                   `json:"code"`
+	Message     string                `json:"message"`
+	FieldErrors []AttributeFieldError `json:"fieldErrors"`
+}
+
 // RelationshipTone represents the emotional quality of a relationship.
 type RelationshipTone string
 
@@ -352,6 +503,25 @@ type CreateSceneRequest struct {
 	Connections      json.RawMessage   `json:"connections,omitempty"`
 }
 
+// SceneSourceAI is the source of scenes drafted by the session prep
+// generator.
+const SceneSourceAI = "ai"
+
+// SceneConnection links a scene to another scene of the session or to
+// an entity. Scene connections are stored as a JSON array of these.
+type SceneConnection struct {
+	SceneID     *int64 `json:"sceneId,omitempty"`
+	EntityID    *int64 `json:"entityId,omitempty"`
+	Description string `json:"description"`
+}
+
+// DraftScenesRequest represents the request body for drafting scenes
+// from the session's chapter.
+type DraftScenesRequest struct {
+	Count    *int    `json:"count,omitempty"`
+	Guidance *string `json:"guidance,omitempty"`
+}
+
 // UpdateSceneRequest represents the request body for updating a scene.
 type UpdateSceneRequest struct {
 	Title            *string           `json:"title,omitempty"`
@@ -379,6 +549,18 @@ type SessionChatMessage struct {
 	CreatedAt  time.Time `json:"createdAt"`
 }
 
+// Session chat message roles.
+const (
+	ChatRoleUser      = "user"
+	ChatRoleAssistant = "assistant"
+)
+
+// SendSessionChatMessageRequest is the request body for sending a
+// message to the play assistant of a session.
+type SendSessionChatMessageRequest struct {
+	Content string `json:"content"`
+}
+
 // DatePrecision represents the precision level of a date.
 type DatePrecision string
 
@@ -441,15 +623,56 @@ const (
 
 // CanonConflict represents a contradiction between different sources.
 type CanonConflict struct {
-	ID                int64           `json:"id"`
-	CampaignID        int64           `json:"campaignId"`
-	EntityID          *int64          `json:"entityId,omitempty"`
-	FieldName         *string         `json:"fieldName,omitempty"`
-	ConflictingValues json.RawMessage `json:"conflictingValues"`
-	Status            ConflictStatus  `json:"status"`
-	Resolution        *string         `json:"resolution,omitempty"`
-	ResolvedAt        *time.Time      `json:"resolvedAt,omitempty"`
-	CreatedAt         time.Time       `json:"createdAt"`
+	ID                 int64           `json:"id"`
+	CampaignID         int64           `json:"campaignId"`
+	EntityID           *int64          `json:"entityId,omitempty"`
+	FieldName          *string         `json:"fieldName,omitempty"`
+	ConflictingValues  json.RawMessage `json:"conflictingValues"`
+	ConflictType       *string         `json:"conflictType,omitempty"`
+	Severity           *string         `json:"severity,omitempty"`
+	Description        *string         `json:"description,omitempty"`
+	AnalysisItemID     *int64          `json:"analysisItemId,omitempty"`
+	Status             ConflictStatus  `json:"status"`
+	Resolution         *string         `json:"resolution,omitempty"`
+	SupersededEntityID *int64          `json:"supersededEntityId,omitempty"`
+	SupersededSceneID  *int64          `json:"supersededSceneId,omitempty"`
+	ResolvedAt         *time.Time      `json:"resolvedAt,omitempty"`
+	CreatedAt          time.Time       `json:"createdAt"`
+	UpdatedAt          time.Time       `json:"updatedAt"`
+}
+
+// ConflictingValue is one side of a canon conflict: a value and where
+// it comes from. Source is a human-readable description of the
+// source; SourceTable, SourceID and SourceField locate it when it is
+// campaign content.
+type ConflictingValue struct {
+	Value       string     `json:"value"`
+	Source      string     `json:"source"`
+	SourceTable *string    `json:"sourceTable,omitempty"`
+	SourceID    *int64     `json:"sourceId,omitempty"`
+	SourceField *string    `json:"sourceField,omitempty"`
+	Date        *time.Time `json:"date,omitempty"`
+}
+
+// CreateCanonConflictRequest contains the fields of a new canon
+// conflict.
+type CreateCanonConflictRequest struct {
+	EntityID          *int64             `json:"entityId,omitempty"`
+	FieldName         *string            `json:"fieldName,omitempty"`
+	ConflictingValues []ConflictingValue `json:"conflictingValues"`
+	ConflictType      *string            `json:"conflictType,omitempty"`
+	Severity          *string            `json:"severity,omitempty"`
+	Description       *string            `json:"description,omitempty"`
+	AnalysisItemID    *int64             `json:"analysisItemId,omitempty"`
+}
+
+// ResolveCanonConflictRequest is the request body for resolving a
+// canon conflict. The entity or scene holding the losing value can be
+// marked SUPERSEDED.
+type ResolveCanonConflictRequest struct {
+	Resolution         string `json:"resolution"`
+	SupersededEntityID *int64 `json:"supersededEntityId,omitempty"`
+	SupersededSceneID  *int64 `json:"supersededSceneId,omitempty"`
 }
 
 // DashboardStats represents statistics for the dashboard.
@@ -509,6 +732,11 @@ const (
 	LLMServiceVoyage    LLMService = "voyage"
 	LLMServiceStability LLMService = "stability"
 	LLMServiceOllama    LLMService = "ollama"
+
+	// LLMServiceReplay replays recorded completions from disk instead
+	// of calling a live service. It is intended for tests and offline
+	// demos.
+	LLMServiceReplay LLMService = "replay"
 )
 
 // UserSettings stores a user's API keys and service preferences.
@@ -522,6 +750,7 @@ type UserSettings struct {
 	EmbeddingAPIKey   *string     `json:"-"`
 	ImageGenService   *LLMService `json:"imageGenService,omitempty"`
 	ImageGenAPIKey    *string     `json:"-"`
+	LLMRouting        *LLMRouting `json:"-"`
 	CreatedAt         time.Time   `json:"createdAt"`
 	UpdatedAt         time.Time   `json:"updatedAt"`
 }
@@ -535,6 +764,7 @@ type UserSettingsResponse struct {
 	EmbeddingAPIKey   *string     `json:"embeddingApiKey,omitempty"`
 	ImageGenService   *LLMService `json:"imageGenService,omitempty"`
 	ImageGenAPIKey    *string     `json:"imageGenApiKey,omitempty"`
+	LLMRouting        *LLMRouting `json:"llmRouting,omitempty"`
 	CreatedAt         time.Time   `json:"createdAt"`
 	UpdatedAt         time.Time   `json:"updatedAt"`
 }
@@ -547,6 +777,26 @@ type UpdateUserSettingsRequest struct {
 	EmbeddingAPIKey   *string     `json:"embeddingApiKey,omitempty"`
 	ImageGenService   *LLMService `json:"imageGenService,omitempty"`
 	ImageGenAPIKey    *string     `json:"imageGenApiKey,omitempty"`
+	LLMRouting        *LLMRouting `json:"llmRouting,omitempty"`
+}
+
+// LLMRoute selects a content generation service and, optionally, a
+// model other than the service's default. APIKey may be omitted when
+// the service needs no key or shares the key of the primary service.
+type LLMRoute struct {
+	Service LLMService `json:"service"`
+	Model   string     `json:"model,omitempty"`
+	APIKey  *string    `json:"apiKey,omitempty"`
+}
+
+// LLMRouting configures how content generation requests are served
+// beyond the primary ContentGenService. Fallbacks are tried in order
+// when the primary service's quota is exhausted or it keeps failing;
+// AgentRoutes sends specific agents (ttrpg, canon, graph, enrichment,
+// revision) to a specific service and model.
+type LLMRouting struct {
+	Fallbacks   []LLMRoute          `json:"fallbacks,omitempty"`
+	AgentRoutes map[string]LLMRoute `json:"agentRoutes,omitempty"`
 }
 
 // CampaignGenre represents campaign genre types.
@@ -607,6 +857,54 @@ type UpdateChapterEntityRequest struct {
 	MentionType *ChapterEntityMentionType `json:"mentionType,omitempty"`
 }
 
+// SessionEntityMentionType represents how an entity is associated with a session.
+type SessionEntityMentionType string
+
+const (
+	SessionEntityMentionLinked    SessionEntityMentionType = "linked"    // Explicitly linked by user
+	SessionEntityMentionMentioned SessionEntityMentionType = "mentioned" // Detected in session notes or scenes
+	SessionEntityMentionFeatured  SessionEntityMentionType = "featured"  // Primary entity for session
+)
+
+// SessionEntity represents a link between a session and an entity.
+type SessionEntity struct {
+	ID          int64                    `json:"id"`
+	SessionID   int64                    `json:"sessionId"`
+	EntityID    int64                    `json:"entityId"`
+	MentionType SessionEntityMentionType `json:"mentionType"`
+	Role        string                   `json:"role"`
+	Notes       *string                  `json:"notes,omitempty"`
+	CreatedAt   time.Time                `json:"createdAt"`
+
+	// Joined fields (not in database)
+	Entity *Entity `json:"entity,omitempty"`
+}
+
+// CreateSessionEntityRequest represents the request body for linking an entity to a session.
+type CreateSessionEntityRequest struct {
+	EntityID    int64                     `json:"entityId"`
+	MentionType *SessionEntityMentionType `json:"mentionType,omitempty"`
+	Role        *string                   `json:"role,omitempty"`
+	Notes       *string                   `json:"notes,omitempty"`
+}
+
+// UpdateSessionEntityRequest represents the request body for updating a session-entity link.
+type UpdateSessionEntityRequest struct {
+	MentionType *SessionEntityMentionType `json:"mentionType,omitempty"`
+	Role        *string                   `json:"role,omitempty"`
+	Notes       *string                   `json:"notes,omitempty"`
+}
+
+// EntitySessionAppearance is a session an entity is linked to, with
+// how it is linked.
+type EntitySessionAppearance struct {
+	Session     Session                  `json:"session"`
+	LinkID      int64                    `json:"linkId"`
+	MentionType SessionEntityMentionType `json:"mentionType"`
+	Role        string                   `json:"role"`
+	Notes       *string                  `json:"notes,omitempty"`
+}
+
 // PlayerCharacter represents a player character in a campaign.
 type PlayerCharacter struct {
 	ID            int64     `json:"id"`
@@ -639,12 +937,15 @@ type UpdatePlayerCharacterRequest struct {
 }
 
 // EntityResolveResult represents a fuzzy-matched entity returned by the
-// entity resolve endpoint for wiki-link autocomplete.
+// entity resolve endpoint for wiki-link autocomplete. MatchedAlias is
+// set when the entity matched by one of its aliases rather than its
+// name.
 type EntityResolveResult struct {
-	ID         int64      `json:"id"`
-	Name       string     `json:"name"`
-	EntityType EntityType `json:"entityType"`
-	Similarity float64    `json:"similarity"`
+	ID           int64      `json:"id"`
+	Name         string     `json:"name"`
+	EntityType   EntityType `json:"entityType"`
+	Similarity   float64    `json:"similarity"`
+	MatchedAlias *string    `json:"matchedAlias,omitempty"`
 }
 
 // SearchResult represents a content chunk returned by hybrid search.
@@ -703,7 +1004,11 @@ type ContentAnalysisItem struct {
 
 // Item type constants for content analysis items.
 const (
-	ItemTypeNewEntitySuggestion = "new_entity_suggestion"
+	ItemTypeNewEntitySuggestion    = "new_entity_suggestion"
+	ItemTypeSessionMemory          = "session_memory"
+	ItemTypeCanonContradiction     = "canon_contradiction"
+	ItemTypeTemporalInconsistency  = "temporal_inconsistency"
+	ItemTypeCharacterInconsistency = "character_inconsistency"
 )
 
 // ResolveAnalysisItemRequest is the request body for resolving an analysis item.
@@ -768,6 +1073,18 @@ type LogEntrySuggestion struct {
 	OccurredAt *string `json:"occurredAt,omitempty"`
 }
 
+// CanonContradictionSuggestion is a contradiction reported by the
+// canon expert between new content and established campaign facts.
+type CanonContradictionSuggestion struct {
+	ContradictionType string `json:"contradiction_type"`
+	Severity          string `json:"severity"`
+	EstablishedFact   string `json:"established_fact"`
+	Source            string `json:"source"`
+	ConflictingText   string `json:"conflicting_text"`
+	Description       string `json:"description"`
+	Suggestion        string `json:"suggestion"`
+}
+
 // RelationshipSuggestion is an enrichment suggestion for creating a
 // new relationship between entities.
 type RelationshipSuggestion struct {
@@ -779,6 +1096,20 @@ type RelationshipSuggestion struct {
 	Description      string `json:"description"`
 }
 
+// SessionMemorySuggestion is a session memory extracted from a
+// session at wrap-up, awaiting review by the GM. EntityNames holds the
+// names of the EntityIDs for display.
+type SessionMemorySuggestion struct {
+	MemoryType      SessionMemoryType `json:"memoryType"`
+	Title           string            `json:"title,omitempty"`
+	Content         string            `json:"content"`
+	SceneIndex      *int              `json:"sceneIndex,omitempty"`
+	EntityIDs       []int64           `json:"entityIds,omitempty"`
+	EntityNames     []string          `json:"entityNames,omitempty"`
+	Importance      int               `json:"importance"`
+	IsPlayerVisible bool              `json:"isPlayerVisible"`
+}
+
 // DraftStatus represents the lifecycle status of a draft.
 type DraftStatus string
 
@@ -881,3 +1212,351 @@ type CreateConstraintOverrideRequest struct {
 	ConstraintType string `json:"constraintType"`
 	OverrideKey    string `json:"overrideKey"`
 }
+
+// LLMUsage records the tokens consumed by a single LLM completion.
+type LLMUsage struct {
+	ID         int64     `json:"id"`
+	CampaignID int64     `json:"campaignId"`
+	JobID      *int64    `json:"jobId,omitempty"`
+	AgentName  string    `json:"agentName"`
+	Provider   string    `json:"provider"`
+	TokensUsed int       `json:"tokensUsed"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// LLMUsageBreakdown aggregates usage for one agent or provider.
+type LLMUsageBreakdown struct {
+	Name        string `json:"name"`
+	Completions int64  `json:"completions"`
+	TokensUsed  int64  `json:"tokensUsed"`
+}
+
+// CampaignUsageSummary aggregates a campaign's LLM usage since a point
+// in time.
+type CampaignUsageSummary struct {
+	CampaignID  int64               `json:"campaignId"`
+	Since       time.Time           `json:"since"`
+	Completions int64               `json:"completions"`
+	TokensUsed  int64               `json:"tokensUsed"`
+	ByAgent     []LLMUsageBreakdown `json:"byAgent"`
+	ByProvider  []LLMUsageBreakdown `json:"byProvider"`
+}
+
+// CampaignBudgetStatus reports a campaign's monthly token budget and
+// its usage in the current calendar month (UTC). A nil limit means the
+// campaign has no cap.
+type CampaignBudgetStatus struct {
+	CampaignID        int64     `json:"campaignId"`
+	MonthlyTokenLimit *int64    `json:"monthlyTokenLimit,omitempty"`
+	PeriodStart       time.Time `json:"periodStart"`
+	TokensUsed        int64     `json:"tokensUsed"`
+	Exceeded          bool      `json:"exceeded"`
+}
+
+// UpdateCampaignBudgetRequest is the request body for setting a
+// campaign's monthly token budget. A nil limit removes the cap.
+type UpdateCampaignBudgetRequest struct {
+	MonthlyTokenLimit *int64 `json:"monthlyTokenLimit"`
+}
+
+// Background job statuses.
+const (
+	BackgroundJobQueued    = "queued"
+	BackgroundJobRunning   = "running"
+	BackgroundJobCompleted = "completed"
+	BackgroundJobFailed    = "failed"
+	BackgroundJobCancelled = "cancelled"
+)
+
+// BackgroundJob is a unit of work in the durable background job queue.
+// Payload and Result are interpreted by the handler registered for
+// Kind.
+type BackgroundJob struct {
+	ID             int64           `json:"id"`
+	Kind           string          `json:"kind"`
+	CampaignID     *int64          `json:"campaignId,omitempty"`
+	AnalysisJobID  *int64          `json:"analysisJobId,omitempty"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	MaxAttempts    int             `json:"maxAttempts"`
+	RunAfter       time.Time       `json:"runAfter"`
+	LockedBy       *string         `json:"-"`
+	LeaseExpiresAt *time.Time      `json:"-"`
+	LastError      *string         `json:"lastError,omitempty"`
+	Result         json.RawMessage `json:"result,omitempty"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	UpdatedAt      time.Time       `json:"updatedAt"`
+}
+
+// Analysis job event types, published while a job is enriched.
+const (
+	AnalysisEventItemCreated  = "item_created"
+	AnalysisEventItemsRemoved = "items_removed"
+	AnalysisEventPhaseChanged = "phase_changed"
+	AnalysisEventJobComplete  = "job_complete"
+)
+
+// AnalysisJobEvent is a progress event of a content analysis job.
+// Events are stored in order and announced via LISTEN/NOTIFY, so that
+// clients can be pushed new events and resume after reconnecting.
+type AnalysisJobEvent struct {
+	ID        int64           `json:"id"`
+	JobID     int64           `json:"jobId"`
+	EventType string          `json:"eventType"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// PhaseChangedEvent is the payload of a phase_changed event.
+type PhaseChangedEvent struct {
+	Stage           string `json:"stage"`
+	Phase           string `json:"phase"`
+	EnrichmentTotal int    `json:"enrichmentTotal"`
+}
+
+// ItemsRemovedEvent is the payload of an items_removed event, sent when
+// items from an interrupted enrichment attempt are discarded before it
+// is retried.
+type ItemsRemovedEvent struct {
+	ItemIDs []int64 `json:"itemIds"`
+}
+
+// JobCompleteEvent is the payload of a job_complete event.
+type JobCompleteEvent struct {
+	Status        string  `json:"status"`
+	Cancelled     bool    `json:"cancelled,omitempty"`
+	FailureReason *string `json:"failureReason,omitempty"`
+}
+
+// MemoryType represents the kind of a campaign memory.
+type MemoryType string
+
+const (
+	MemoryTypePremise        MemoryType = "premise"
+	MemoryTypeTheme          MemoryType = "theme"
+	MemoryTypeFactionSummary MemoryType = "faction_summary"
+	MemoryTypePlotThread     MemoryType = "plot_thread"
+	MemoryTypeGMNote         MemoryType = "gm_note"
+)
+
+// CampaignMemory is a long-lived fact about a campaign, such as its
+// premise, a recurring theme or an active plot thread. Campaign memory
+// is always included in the context given to the AI.
+type CampaignMemory struct {
+	ID              int64      `json:"id"`
+	CampaignID      int64      `json:"campaignId"`
+	MemoryType      MemoryType `json:"memoryType"`
+	Title           *string    `json:"title,omitempty"`
+	Content         string     `json:"content"`
+	SourceSessionID *int64     `json:"sourceSessionId,omitempty"`
+	Importance      int        `json:"importance"`
+	IsSpoiler       bool       `json:"isSpoiler"`
+	GMCreated       bool       `json:"gmCreated"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+}
+
+// CreateCampaignMemoryRequest is the request body for creating a
+// campaign memory. Importance defaults to 5 when omitted.
+type CreateCampaignMemoryRequest struct {
+	MemoryType      MemoryType `json:"memoryType"`
+	Title           *string    `json:"title,omitempty"`
+	Content         string     `json:"content"`
+	SourceSessionID *int64     `json:"sourceSessionId,omitempty"`
+	Importance      *int       `json:"importance,omitempty"`
+	IsSpoiler       bool       `json:"isSpoiler"`
+}
+
+// UpdateCampaignMemoryRequest is the request body for updating a
+// campaign memory.
+type UpdateCampaignMemoryRequest struct {
+	MemoryType      *MemoryType `json:"memoryType,omitempty"`
+	Title           *string     `json:"title,omitempty"`
+	Content         *string     `json:"content,omitempty"`
+	SourceSessionID *int64      `json:"sourceSessionId,omitempty"`
+	Importance      *int        `json:"importance,omitempty"`
+	IsSpoiler       *bool       `json:"isSpoiler,omitempty"`
+}
+
+// CampaignMemoryFilter narrows a campaign memory listing. Zero values
+// do not filter.
+type CampaignMemoryFilter struct {
+	MemoryType    MemoryType
+	MinImportance int
+	IsSpoiler     *bool
+}
+
+// ChapterMemory is the arc-level memory of a chapter: its goals, the
+// plot threads still open and a summary of what has happened so far.
+// At most one chapter memory per campaign is current.
+type ChapterMemory struct {
+	ID            int64     `json:"id"`
+	CampaignID    int64     `json:"campaignId"`
+	ChapterID     int64     `json:"chapterId"`
+	Goals         []string  `json:"goals"`
+	ActiveThreads []string  `json:"activeThreads"`
+	Summary       *string   `json:"summary,omitempty"`
+	IsCurrent     bool      `json:"isCurrent"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// SaveChapterMemoryRequest is the request body for creating or updating
+// a chapter memory. Omitted fields keep their current value; an empty
+// list clears goals or threads.
+type SaveChapterMemoryRequest struct {
+	Goals         []string `json:"goals,omitempty"`
+	ActiveThreads []string `json:"activeThreads,omitempty"`
+	Summary       *string  `json:"summary,omitempty"`
+}
+
+// SessionMemoryType represents the kind of a session memory.
+type SessionMemoryType string
+
+const (
+	SessionMemoryTypeSummary   SessionMemoryType = "summary"
+	SessionMemoryTypeScene     SessionMemoryType = "scene"
+	SessionMemoryTypeDecision  SessionMemoryType = "decision"
+	SessionMemoryTypeDiscovery SessionMemoryType = "discovery"
+	SessionMemoryTypeMoment    SessionMemoryType = "moment"
+)
+
+// SessionMemory is a short-term memory of a single session: its
+// summary, a scene, a decision the players made, something the
+// characters discovered or a memorable moment.
+type SessionMemory struct {
+	ID                int64             `json:"id"`
+	SessionID         int64             `json:"sessionId"`
+	MemoryType        SessionMemoryType `json:"memoryType"`
+	Title             *string           `json:"title,omitempty"`
+	Content           string            `json:"content"`
+	SceneIndex        *int              `json:"sceneIndex,omitempty"`
+	EntitiesMentioned []int64           `json:"entitiesMentioned"`
+	Importance        int               `json:"importance"`
+	IsPlayerVisible   bool              `json:"isPlayerVisible"`
+	GMEdited          bool              `json:"gmEdited"`
+	CreatedAt         time.Time         `json:"createdAt"`
+	UpdatedAt         time.Time         `json:"updatedAt"`
+}
+
+// CreateSessionMemoryRequest contains the fields of a new session
+// memory. Importance defaults to 5 when omitted.
+type CreateSessionMemoryRequest struct {
+	MemoryType        SessionMemoryType `json:"memoryType"`
+	Title             *string           `json:"title,omitempty"`
+	Content           string            `json:"content"`
+	SceneIndex        *int              `json:"sceneIndex,omitempty"`
+	EntitiesMentioned []int64           `json:"entitiesMentioned,omitempty"`
+	Importance        *int              `json:"importance,omitempty"`
+	IsPlayerVisible   bool              `json:"isPlayerVisible"`
+}
+
+// SessionSummary is the recap of a played session used as context for
+// AI calls: the session's summary memory, or its wrap-up notes when it
+// has no summary memory yet.
+type SessionSummary struct {
+	SessionID     int64   `json:"sessionId"`
+	SessionNumber *int    `json:"sessionNumber,omitempty"`
+	Title         *string `json:"title,omitempty"`
+	Summary       string  `json:"summary"`
+}
+
+// EntityExtractionSource identifies the session text an entity
+// extraction was detected in.
+type EntityExtractionSource string
+
+const (
+	EntityExtractionSourceChat          EntityExtractionSource = "chat"
+	EntityExtractionSourcePlayNotes     EntityExtractionSource = "play_notes"
+	EntityExtractionSourceSessionMemory EntityExtractionSource = "session_memory"
+)
+
+// EntityExtractionStatus represents the GM's review of an entity
+// extraction. A deferred extraction is kept for review at wrap-up.
+type EntityExtractionStatus string
+
+const (
+	EntityExtractionStatusPending  EntityExtractionStatus = "pending"
+	EntityExtractionStatusAccepted EntityExtractionStatus = "accepted"
+	EntityExtractionStatusDeferred EntityExtractionStatus = "deferred"
+	EntityExtractionStatusRejected EntityExtractionStatus = "rejected"
+)
+
+// EntityExtractionProposal is a new entity, or new information about
+// an existing entity, detected in session text.
+type EntityExtractionProposal struct {
+	Name            string     `json:"name"`
+	EntityType      EntityType `json:"entityType"`
+	Description     string     `json:"description"`
+	Evidence        string     `json:"evidence"`
+	MatchedEntityID *int64     `json:"matchedEntityId,omitempty"`
+}
+
+// MemoryEntityExtraction is an entity detected in a session, queued
+// for the GM to accept, defer or reject. When MatchedEntityID is set
+// the extraction proposes an update to that entity instead of a new
+// one.
+type MemoryEntityExtraction struct {
+	ID                int64                  `json:"id"`
+	SessionID         int64                  `json:"sessionId"`
+	SessionMemoryID   *int64                 `json:"sessionMemoryId,omitempty"`
+	Source            EntityExtractionSource `json:"source"`
+	Name              string                 `json:"name"`
+	EntityType        EntityType             `json:"entityType"`
+	Description       *string                `json:"description,omitempty"`
+	EvidenceText      *string                `json:"evidenceText,omitempty"`
+	MatchedEntityID   *int64                 `json:"matchedEntityId,omitempty"`
+	MatchedEntityName *string                `json:"matchedEntityName,omitempty"`
+	Status            EntityExtractionStatus `json:"status"`
+	EntityID          *int64                 `json:"entityId,omitempty"` // Created when accepted or deferred
+	CreatedAt         time.Time              `json:"createdAt"`
+	UpdatedAt         time.Time              `json:"updatedAt"`
+}
+
+// ResolveEntityExtractionRequest is the request body for accepting,
+// deferring or rejecting an entity extraction. Description replaces
+// the proposed description, or the update to the matched entity; Name
+// and EntityType replace those of a new entity.
+type ResolveEntityExtractionRequest struct {
+	Status      EntityExtractionStatus `json:"status"`
+	Name        *string                `json:"name,omitempty"`
+	EntityType  *EntityType            `json:"entityType,omitempty"`
+	Description *string                `json:"description,omitempty"`
+}
+
+// SessionChatSummary is a rolling summary of the older messages of a
+// session's play assistant chat. Each summary folds the previous one
+// and the messages after it, up to the message with ThroughSortOrder.
+type SessionChatSummary struct {
+	ID               int64     `json:"id"`
+	SessionID        int64     `json:"sessionId"`
+	Summary          string    `json:"summary"`
+	ThroughSortOrder int       `json:"throughSortOrder"`
+	MessageCount     int       `json:"messageCount"`
+	CreatedAt        time.Time `json:"createdAt"`
+}
+
+// RecapAudience identifies who a session recap is written for.
+type RecapAudience string
+
+const (
+	// RecapAudienceGM recaps everything the GM knows about a session.
+	RecapAudienceGM RecapAudience = "gm"
+
+	// RecapAudiencePlayer is a player-safe recap, without GM notes or
+	// memories the players have not learned.
+	RecapAudiencePlayer RecapAudience = "player"
+)
+
+// SessionRecap is a recap of a session, cached until the material it
+// was written from changes.
+type SessionRecap struct {
+	SessionID     int64         `json:"sessionId"` // The recapped session
+	SessionNumber *int          `json:"sessionNumber,omitempty"`
+	Title         *string       `json:"title,omitempty"`
+	Audience      RecapAudience `json:"audience"`
+	Recap         string        `json:"recap"`
+	SourceHash    string        `json:"-"`
+	UpdatedAt     time.Time     `json:"updatedAt"`
+}
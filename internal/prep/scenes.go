@@ -0,0 +1,425 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+// Package prep drafts session prep material for the GM to review.
+package prep
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/antonypegg/imagineer/internal/agents"
+	"github.com/antonypegg/imagineer/internal/llm"
+	"github.com/antonypegg/imagineer/internal/models"
+)
+
+// AgentName is the name under which session prep LLM calls are
+// metered and routed.
+const AgentName = "prep"
+
+const (
+	// DefaultSceneCount is the number of scenes drafted when the
+	// request does not ask for a number.
+	DefaultSceneCount = 4
+
+	// MaxSceneCount is the largest number of scenes drafted at once.
+	MaxSceneCount = 8
+
+	// maxOverviewChars is the maximum number of characters of the
+	// chapter overview and summary included in the prompt.
+	maxOverviewChars = 4000
+
+	// maxSceneEntityChars is the maximum number of characters of each
+	// featured entity's description and GM notes included in the
+	// prompt.
+	maxSceneEntityChars = 400
+
+	// maxGuidanceChars is the maximum number of characters of the GM's
+	// guidance included in the prompt.
+	maxGuidanceChars = 1000
+)
+
+// sceneToolName is the tool the LLM is asked to call with the scenes
+// it drafted.
+const sceneToolName = "report_scenes"
+
+// sceneTool describes sceneResponse as a JSON Schema.
+var sceneTool = llm.Tool{
+	Name:        sceneToolName,
+	Description: "Report the drafted scenes in play order.",
+	InputSchema: json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"scenes": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"properties": {
+						"title": {"type": "string"},
+						"sceneType": {"type": "string", "enum": ["exploration", "combat", "social", "puzzle", "chase", "travel", "downtime", "other"]},
+						"description": {"type": "string", "description": "What the scene is and how it plays out."},
+						"objective": {"type": "string", "description": "What the characters are trying to achieve in the scene."},
+						"entities": {"type": "array", "items": {"type": "string"}, "description": "Exact names of the featured entities in the scene."},
+						"connections": {
+							"type": "array",
+							"items": {
+								"type": "object",
+								"properties": {
+									"scene": {"type": "string", "description": "Exact title of the connected scene."},
+									"entity": {"type": "string", "description": "Exact name of the connected featured entity."},
+									"description": {"type": "string", "description": "How the scenes or the scene and entity connect."}
+								},
+								"required": ["description"]
+							}
+						}
+					},
+					"required": ["title", "sceneType", "description", "objective"]
+				}
+			}
+		},
+		"required": ["scenes"]
+	}`),
+}
+
+// ErrNoChapterContext is returned when the chapter has no overview,
+// memory or featured entities to draft scenes from.
+var ErrNoChapterContext = errors.New("chapter has nothing to draft scenes from")
+
+// SceneInput contains the chapter context scenes are drafted from.
+type SceneInput struct {
+	Session  models.Session
+	Chapter  models.Chapter
+	Memory   *models.ChapterMemory // nil if the chapter has no memory
+	Entities []models.Entity       // The chapter's featured entities
+	Scenes   []models.Scene        // Scenes already planned for the session
+	Count    int                   // Number of scenes to draft
+	Guidance string                // Optional direction from the GM
+}
+
+// SceneDraft is a proposed scene.
+type SceneDraft struct {
+	Title       string
+	SceneType   string
+	Description string
+	Objective   string
+	EntityIDs   []int64
+	Connections []DraftConnection
+}
+
+// DraftConnection links a drafted scene to another drafted scene, to a
+// scene already planned for the session or to a featured entity.
+// Exactly one of SceneIndex, SceneID and EntityID is set.
+type DraftConnection struct {
+	SceneIndex  *int   // Index of the connected draft
+	SceneID     *int64 // ID of the connected existing scene
+	EntityID    *int64
+	Description string
+}
+
+// sceneResponse is the structured output of the scene drafting.
+type sceneResponse struct {
+	Scenes []draftedScene `json:"scenes"`
+}
+
+// draftedScene is a single scene as reported by the LLM.
+type draftedScene struct {
+	Title       string              `json:"title"`
+	SceneType   string              `json:"sceneType"`
+	Description string              `json:"description"`
+	Objective   string              `json:"objective"`
+	Entities    []string            `json:"entities"`
+	Connections []draftedConnection `json:"connections"`
+}
+
+// draftedConnection is a single scene connection as reported by the
+// LLM.
+type draftedConnection struct {
+	Scene       string `json:"scene"`
+	Entity      string `json:"entity"`
+	Description string `json:"description"`
+}
+
+// SceneDrafter drafts the scenes of a session from its chapter's
+// overview, memory and featured entities.
+type SceneDrafter struct{}
+
+// NewSceneDrafter creates a new SceneDrafter.
+func NewSceneDrafter() *SceneDrafter {
+	return &SceneDrafter{}
+}
+
+// Draft asks provider for up to input.Count scenes. Entities and
+// connections that do not name a featured entity or a scene are
+// dropped. If the chapter has no overview, memory or featured
+// entities ErrNoChapterContext is returned without an LLM call.
+func (d *SceneDrafter) Draft(
+	ctx context.Context,
+	provider llm.Provider,
+	input SceneInput,
+) ([]SceneDraft, error) {
+	if !hasChapterContext(input) {
+		return nil, ErrNoChapterContext
+	}
+
+	count := input.Count
+	if count <= 0 {
+		count = DefaultSceneCount
+	}
+	count = min(count, MaxSceneCount)
+
+	resp, err := provider.Complete(llm.WithAgentName(ctx, AgentName), llm.CompletionRequest{
+		SystemPrompt: buildSceneSystemPrompt(count),
+		UserPrompt:   buildSceneUserPrompt(input),
+		MaxTokens:    4096,
+		Temperature:  0.7,
+		Tools:        []llm.Tool{sceneTool},
+		ToolChoice:   sceneToolName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("LLM completion failed: %w", err)
+	}
+
+	drafts, err := parseSceneResponse(llm.StructuredContent(resp, sceneToolName), input)
+	if err != nil {
+		return nil, err
+	}
+	if len(drafts) > count {
+		drafts = truncateDrafts(drafts, count)
+	}
+	return drafts, nil
+}
+
+// hasChapterContext reports whether input has any chapter material to
+// draft scenes from.
+func hasChapterContext(input SceneInput) bool {
+	if strings.TrimSpace(derefString(input.Chapter.Overview)) != "" || len(input.Entities) > 0 {
+		return true
+	}
+	if m := input.Memory; m != nil {
+		return len(m.Goals) > 0 || len(m.ActiveThreads) > 0 ||
+			strings.TrimSpace(derefString(m.Summary)) != ""
+	}
+	return false
+}
+
+// buildSceneSystemPrompt returns the system prompt for drafting count
+// scenes.
+func buildSceneSystemPrompt(count int) string {
+	return fmt.Sprintf(`You help a tabletop RPG game master prepare the next session. Draft up to %d scenes for it from the chapter material provided.
+
+Rules:
+- Drive the chapter's goals and unresolved threads forward. Each thread should be picked up by at least one scene where it fits.
+- Build the scenes around the featured entities, and list the exact names of those that appear in each scene in "entities". Do not invent named entities.
+- Mix scene types, and give every scene an objective the characters can achieve, fail or bypass.
+- Connect scenes that lead into each other, or that share a clue or consequence, using the exact title of the other scene. Connect a scene to a featured entity when it reveals or changes something about it.
+- Do not repeat scenes already planned for the session.
+- Report the scenes in the order they are likely to be played.
+
+Respond with JSON only.`, count)
+}
+
+// buildSceneUserPrompt constructs the user prompt containing the
+// chapter material, the session's planned scenes and prep notes, and
+// the GM's guidance.
+func buildSceneUserPrompt(input SceneInput) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## Chapter: %s\n\n", input.Chapter.Title)
+	if overview := strings.TrimSpace(derefString(input.Chapter.Overview)); overview != "" {
+		fmt.Fprintf(&b, "%s\n\n", agents.TruncateString(overview, maxOverviewChars))
+	}
+
+	if m := input.Memory; m != nil {
+		if summary := strings.TrimSpace(derefString(m.Summary)); summary != "" {
+			fmt.Fprintf(&b, "## Story So Far\n\n%s\n\n", agents.TruncateString(summary, maxOverviewChars))
+		}
+		writeList(&b, "Chapter Goals", m.Goals)
+		writeList(&b, "Unresolved Threads", m.ActiveThreads)
+	}
+
+	if len(input.Entities) > 0 {
+		b.WriteString("## Featured Entities\n\n")
+		for _, entity := range input.Entities {
+			fmt.Fprintf(&b, "- %s (%s)", entity.Name, entity.EntityType)
+			if description := strings.TrimSpace(derefString(entity.Description)); description != "" {
+				fmt.Fprintf(&b, ": %s", agents.TruncateString(description, maxSceneEntityChars))
+			}
+			if gmNotes := strings.TrimSpace(derefString(entity.GMNotes)); gmNotes != "" {
+				fmt.Fprintf(&b, " GM notes: %s", agents.TruncateString(gmNotes, maxSceneEntityChars))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if len(input.Scenes) > 0 {
+		b.WriteString("## Scenes Already Planned\n\n")
+		for _, scene := range input.Scenes {
+			fmt.Fprintf(&b, "- %s (%s)", scene.Title, scene.SceneType)
+			if objective := strings.TrimSpace(derefString(scene.Objective)); objective != "" {
+				fmt.Fprintf(&b, ": %s", objective)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if notes := strings.TrimSpace(derefString(input.Session.PrepNotes)); notes != "" {
+		fmt.Fprintf(&b, "## Session Prep Notes\n\n%s\n\n", agents.TruncateString(notes, maxOverviewChars))
+	}
+
+	if guidance := strings.TrimSpace(input.Guidance); guidance != "" {
+		fmt.Fprintf(&b, "## GM Guidance\n\n%s\n\n", agents.TruncateString(guidance, maxGuidanceChars))
+	}
+
+	return b.String()
+}
+
+// writeList writes items under heading as a bulleted list, skipping
+// blank items. Nothing is written if all items are blank.
+func writeList(b *strings.Builder, heading string, items []string) {
+	var list strings.Builder
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			fmt.Fprintf(&list, "- %s\n", item)
+		}
+	}
+	if list.Len() > 0 {
+		fmt.Fprintf(b, "## %s\n\n%s\n", heading, list.String())
+	}
+}
+
+// parseSceneResponse parses the LLM response into scene drafts.
+// Scenes without a title, or with the title of another draft or a
+// planned scene, are dropped and unknown scene types become "other".
+// Entities and connections are resolved case-insensitively against
+// input's featured entities and scenes; unresolved ones, and
+// connections of a scene to itself, are dropped.
+func parseSceneResponse(raw string, input SceneInput) ([]SceneDraft, error) {
+	cleaned := strings.TrimSpace(agents.StripCodeFences(raw))
+	if cleaned == "" {
+		return nil, fmt.Errorf("empty response from LLM")
+	}
+
+	var resp sceneResponse
+	if err := json.Unmarshal([]byte(cleaned), &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	entityIDs := make(map[string]int64, len(input.Entities))
+	for _, entity := range input.Entities {
+		entityIDs[strings.ToLower(strings.TrimSpace(entity.Name))] = entity.ID
+	}
+	sceneIDs := make(map[string]int64, len(input.Scenes))
+	for _, scene := range input.Scenes {
+		sceneIDs[strings.ToLower(strings.TrimSpace(scene.Title))] = scene.ID
+	}
+
+	// Collect the drafts first so connections can refer to drafts
+	// reported after them.
+	draftIndex := make(map[string]int)
+	kept := make([]draftedScene, 0, len(resp.Scenes))
+	for _, s := range resp.Scenes {
+		key := strings.ToLower(strings.TrimSpace(s.Title))
+		if key == "" {
+			continue
+		}
+		if _, ok := draftIndex[key]; ok {
+			continue
+		}
+		if _, ok := sceneIDs[key]; ok {
+			continue
+		}
+		draftIndex[key] = len(kept)
+		kept = append(kept, s)
+	}
+
+	drafts := make([]SceneDraft, 0, len(kept))
+	for i, s := range kept {
+		draft := SceneDraft{
+			Title:       strings.TrimSpace(s.Title),
+			SceneType:   s.SceneType,
+			Description: strings.TrimSpace(s.Description),
+			Objective:   strings.TrimSpace(s.Objective),
+			EntityIDs:   []int64{},
+		}
+		if !isSceneType(draft.SceneType) {
+			draft.SceneType = "other"
+		}
+
+		seen := make(map[int64]bool)
+		for _, name := range s.Entities {
+			id, ok := entityIDs[strings.ToLower(strings.TrimSpace(name))]
+			if ok && !seen[id] {
+				seen[id] = true
+				draft.EntityIDs = append(draft.EntityIDs, id)
+			}
+		}
+
+		for _, c := range s.Connections {
+			conn := DraftConnection{Description: strings.TrimSpace(c.Description)}
+			scene := strings.ToLower(strings.TrimSpace(c.Scene))
+			if index, ok := draftIndex[scene]; ok && scene != "" {
+				if index == i {
+					continue
+				}
+				conn.SceneIndex = &index
+			} else if id, ok := sceneIDs[scene]; ok && scene != "" {
+				conn.SceneID = &id
+			} else if id, ok := entityIDs[strings.ToLower(strings.TrimSpace(c.Entity))]; ok {
+				conn.EntityID = &id
+			} else {
+				continue
+			}
+			draft.Connections = append(draft.Connections, conn)
+		}
+
+		drafts = append(drafts, draft)
+	}
+
+	return drafts, nil
+}
+
+// truncateDrafts returns the first n drafts, dropping connections to
+// the drafts that were cut.
+func truncateDrafts(drafts []SceneDraft, n int) []SceneDraft {
+	drafts = drafts[:n]
+	for i := range drafts {
+		kept := drafts[i].Connections[:0]
+		for _, c := range drafts[i].Connections {
+			if c.SceneIndex == nil || *c.SceneIndex < n {
+				kept = append(kept, c)
+			}
+		}
+		drafts[i].Connections = kept
+	}
+	return drafts
+}
+
+// isSceneType reports whether t is one of the scene types.
+func isSceneType(t string) bool {
+	switch t {
+	case "exploration", "combat", "social", "puzzle",
+		"chase", "travel", "downtime", "other":
+		return true
+	}
+	return false
+}
+
+// derefString returns the value of s, or "" if s is nil.
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
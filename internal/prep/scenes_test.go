@@ -0,0 +1,206 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+package prep
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/antonypegg/imagineer/internal/llm"
+	"github.com/antonypegg/imagineer/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProvider returns a canned response and records the request it
+// was given.
+type fakeProvider struct {
+	resp    llm.CompletionResponse
+	err     error
+	calls   int
+	request llm.CompletionRequest
+	agent   string
+}
+
+func (p *fakeProvider) Complete(ctx context.Context, req llm.CompletionRequest) (llm.CompletionResponse, error) {
+	p.calls++
+	p.request = req
+	p.agent = llm.AgentName(ctx)
+	return p.resp, p.err
+}
+
+func strPtr(s string) *string { return &s }
+
+func sceneToolCall(arguments string) llm.CompletionResponse {
+	return llm.CompletionResponse{
+		ToolCalls: []llm.ToolCall{{Name: sceneToolName, Arguments: []byte(arguments)}},
+	}
+}
+
+func dockInput() SceneInput {
+	return SceneInput{
+		Session: models.Session{PrepNotes: strPtr("The party owes the harbourmaster a favour.")},
+		Chapter: models.Chapter{
+			Title:    "The Dockside Murders",
+			Overview: strPtr("A cult hunts sailors in Limehouse."),
+		},
+		Memory: &models.ChapterMemory{
+			Goals:         []string{"Find the killer"},
+			ActiveThreads: []string{"Who funds the cult?", " "},
+		},
+		Entities: []models.Entity{
+			{ID: 10, Name: "Silas Marsh", EntityType: models.EntityTypeNPC, GMNotes: strPtr("Secretly the cult's banker.")},
+			{ID: 11, Name: "The Red Warehouse", EntityType: models.EntityTypeLocation},
+		},
+		Scenes: []models.Scene{
+			{ID: 7, Title: "Arrival at the Docks", SceneType: "travel"},
+		},
+		Guidance: "End on a cliffhanger.",
+	}
+}
+
+func TestSceneDrafter_Draft(t *testing.T) {
+	provider := &fakeProvider{resp: sceneToolCall(`{
+		"scenes": [
+			{
+				"title": " Marsh's Offer ",
+				"sceneType": "social",
+				"description": "Silas offers the party money to look away.",
+				"objective": "Learn who pays Silas.",
+				"entities": ["silas marsh", "Nobody Known", "Silas Marsh"],
+				"connections": [
+					{"scene": "Raid on the Warehouse", "description": "Silas lets slip the warehouse."},
+					{"scene": "Arrival at the Docks", "description": "Silas meets them off the boat."},
+					{"scene": "Marsh's Offer", "description": "Itself."},
+					{"entity": "The Red Warehouse", "description": "Silas owns it."},
+					{"entity": "Unknown", "description": "Dropped."}
+				]
+			},
+			{
+				"title": "Raid on the Warehouse",
+				"sceneType": "heist",
+				"description": "The party storms the warehouse.",
+				"objective": "Rescue the captive sailors.",
+				"entities": ["The Red Warehouse"]
+			},
+			{"title": "marsh's offer", "sceneType": "social", "description": "Duplicate.", "objective": "x"},
+			{"title": "arrival at the docks", "sceneType": "travel", "description": "Already planned.", "objective": "x"},
+			{"title": "  ", "sceneType": "combat", "description": "Untitled.", "objective": "x"}
+		]
+	}`)}
+
+	drafts, err := NewSceneDrafter().Draft(context.Background(), provider, dockInput())
+
+	require.NoError(t, err)
+	require.Len(t, drafts, 2)
+
+	offer := drafts[0]
+	assert.Equal(t, "Marsh's Offer", offer.Title)
+	assert.Equal(t, "social", offer.SceneType)
+	assert.Equal(t, "Learn who pays Silas.", offer.Objective)
+	assert.Equal(t, []int64{10}, offer.EntityIDs)
+	require.Len(t, offer.Connections, 3)
+	require.NotNil(t, offer.Connections[0].SceneIndex)
+	assert.Equal(t, 1, *offer.Connections[0].SceneIndex)
+	require.NotNil(t, offer.Connections[1].SceneID)
+	assert.Equal(t, int64(7), *offer.Connections[1].SceneID)
+	require.NotNil(t, offer.Connections[2].EntityID)
+	assert.Equal(t, int64(11), *offer.Connections[2].EntityID)
+	assert.Equal(t, "Silas owns it.", offer.Connections[2].Description)
+
+	raid := drafts[1]
+	assert.Equal(t, "other", raid.SceneType, "unknown scene types become other")
+	assert.Equal(t, []int64{11}, raid.EntityIDs)
+	assert.Empty(t, raid.Connections)
+
+	assert.Equal(t, AgentName, provider.agent)
+	assert.Equal(t, sceneToolName, provider.request.ToolChoice)
+	assert.Contains(t, provider.request.SystemPrompt, "up to 4 scenes")
+	prompt := provider.request.UserPrompt
+	assert.Contains(t, prompt, "## Chapter: The Dockside Murders\n\nA cult hunts sailors in Limehouse.")
+	assert.Contains(t, prompt, "## Chapter Goals\n\n- Find the killer\n")
+	assert.Contains(t, prompt, "## Unresolved Threads\n\n- Who funds the cult?\n\n")
+	assert.Contains(t, prompt, "- Silas Marsh (npc) GM notes: Secretly the cult's banker.")
+	assert.Contains(t, prompt, "## Scenes Already Planned\n\n- Arrival at the Docks (travel)")
+	assert.Contains(t, prompt, "The party owes the harbourmaster a favour.")
+	assert.Contains(t, prompt, "## GM Guidance\n\nEnd on a cliffhanger.")
+}
+
+func TestSceneDrafter_Draft_Count(t *testing.T) {
+	provider := &fakeProvider{resp: sceneToolCall(`{
+		"scenes": [
+			{"title": "One", "sceneType": "combat", "description": "d", "objective": "o",
+				"connections": [{"scene": "Two", "description": "leads on"}]},
+			{"title": "Two", "sceneType": "combat", "description": "d", "objective": "o",
+				"connections": [{"scene": "One", "description": "follows"}]}
+		]
+	}`)}
+
+	input := dockInput()
+	input.Count = 1
+	drafts, err := NewSceneDrafter().Draft(context.Background(), provider, input)
+
+	require.NoError(t, err)
+	require.Len(t, drafts, 1)
+	assert.Equal(t, "One", drafts[0].Title)
+	assert.Empty(t, drafts[0].Connections, "connections to cut drafts are dropped")
+	assert.Contains(t, provider.request.SystemPrompt, "up to 1 scenes")
+
+	input.Count = 50
+	_, err = NewSceneDrafter().Draft(context.Background(), provider, input)
+	require.NoError(t, err)
+	assert.Contains(t, provider.request.SystemPrompt, "up to 8 scenes")
+}
+
+func TestSceneDrafter_Draft_NoChapterContext(t *testing.T) {
+	provider := &fakeProvider{}
+
+	_, err := NewSceneDrafter().Draft(context.Background(), provider, SceneInput{
+		Chapter: models.Chapter{Title: "Empty", Overview: strPtr("  ")},
+		Memory:  &models.ChapterMemory{},
+	})
+
+	assert.ErrorIs(t, err, ErrNoChapterContext)
+	assert.Equal(t, 0, provider.calls)
+}
+
+func TestSceneDrafter_Draft_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider *fakeProvider
+		wantErr  string
+	}{
+		{
+			name:     "provider error",
+			provider: &fakeProvider{err: errors.New("boom")},
+			wantErr:  "LLM completion failed",
+		},
+		{
+			name:     "empty response",
+			provider: &fakeProvider{},
+			wantErr:  "empty response",
+		},
+		{
+			name:     "invalid JSON",
+			provider: &fakeProvider{resp: llm.CompletionResponse{Content: "not json"}},
+			wantErr:  "failed to parse JSON",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSceneDrafter().Draft(context.Background(), tt.provider, dockInput())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+-- ============================================
+-- Migration 008: Content Embeddings
+-- Go-side embedding index for semantic search
+-- on stock PostgreSQL with pgvector, used when
+-- the pgedge_vectorizer extension is absent.
+-- ============================================
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_available_extensions WHERE name = 'vector'
+    ) THEN
+        RAISE NOTICE 'pgvector extension not available. Skipping content_embeddings setup.';
+        RETURN;
+    END IF;
+
+    CREATE EXTENSION IF NOT EXISTS vector;
+
+    CREATE TABLE IF NOT EXISTS content_embeddings (
+        id              BIGSERIAL PRIMARY KEY,
+        campaign_id     BIGINT NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+        source_table    TEXT NOT NULL,
+        source_id       BIGINT NOT NULL,
+        source_column   TEXT NOT NULL,
+        source_name     TEXT,
+        chunk_index     INT NOT NULL,
+        content         TEXT NOT NULL,
+        content_hash    TEXT NOT NULL,
+        embedding_model TEXT NOT NULL,
+        embedding       vector(1024) NOT NULL,
+        created_at      TIMESTAMPTZ DEFAULT NOW(),
+        UNIQUE (source_table, source_id, source_column, chunk_index)
+    );
+
+    CREATE INDEX IF NOT EXISTS idx_content_embeddings_campaign
+        ON content_embeddings(campaign_id);
+    CREATE INDEX IF NOT EXISTS idx_content_embeddings_source
+        ON content_embeddings(source_table, source_id);
+    CREATE INDEX IF NOT EXISTS idx_content_embeddings_embedding
+        ON content_embeddings USING hnsw (embedding vector_cosine_ops);
+
+    COMMENT ON TABLE content_embeddings IS
+        'Chunk embeddings written by the Go indexer when pgedge_vectorizer is not installed';
+    COMMENT ON COLUMN content_embeddings.source_table IS
+        'Table the chunk was taken from (campaigns, entities, chapters, sessions, ...)';
+    COMMENT ON COLUMN content_embeddings.source_column IS
+        'Column the chunk was taken from (description, overview, prep_notes, ...)';
+    COMMENT ON COLUMN content_embeddings.source_name IS
+        'Display name of the source row at indexing time';
+    COMMENT ON COLUMN content_embeddings.content_hash IS
+        'SHA-256 of the source text and name; unchanged sources are not re-embedded';
+    COMMENT ON COLUMN content_embeddings.embedding_model IS
+        'Embedding model that produced the vector; vectors from other models are re-indexed';
+
+    RAISE NOTICE 'content_embeddings table created';
+END $$;
+
+INSERT INTO schema_migrations (version) VALUES ('008_content_embeddings');
@@ -0,0 +1,27 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+-- ============================================
+-- Migration 009: Replay LLM Service
+-- Allow the "replay" content generation
+-- service, which serves recorded completions
+-- for tests and offline demos.
+-- ============================================
+
+ALTER TABLE user_settings
+    DROP CONSTRAINT IF EXISTS user_settings_content_gen_service_check;
+
+ALTER TABLE user_settings
+    ADD CONSTRAINT user_settings_content_gen_service_check
+    CHECK (content_gen_service IN ('anthropic', 'openai', 'gemini', 'replay'));
+
+COMMENT ON COLUMN user_settings.content_gen_service IS 'Selected service for content generation: anthropic (Claude), openai (GPT), gemini, or replay (recorded responses)';
+
+INSERT INTO schema_migrations (version) VALUES ('009_replay_llm_service');
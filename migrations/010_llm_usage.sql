@@ -0,0 +1,47 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+-- ============================================
+-- Migration 010: LLM Usage Accounting
+-- Per-completion token usage and monthly
+-- per-campaign token budgets.
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS llm_usage (
+    id          BIGSERIAL PRIMARY KEY,
+    campaign_id BIGINT NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    job_id      BIGINT REFERENCES content_analysis_jobs(id) ON DELETE SET NULL,
+    agent_name  TEXT NOT NULL DEFAULT '',
+    provider    TEXT NOT NULL,
+    tokens_used INTEGER NOT NULL DEFAULT 0 CHECK (tokens_used >= 0),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_llm_usage_campaign_created
+    ON llm_usage(campaign_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_llm_usage_job
+    ON llm_usage(job_id) WHERE job_id IS NOT NULL;
+
+COMMENT ON TABLE llm_usage IS 'Token usage of every LLM completion, attributed to a campaign, job, and agent';
+COMMENT ON COLUMN llm_usage.job_id IS 'Content analysis job that made the completion, if any';
+COMMENT ON COLUMN llm_usage.agent_name IS 'Pipeline agent that made the completion (e.g. canon-expert, enrichment, revision)';
+COMMENT ON COLUMN llm_usage.provider IS 'LLM service that served the completion (anthropic, openai, gemini, ollama, replay)';
+COMMENT ON COLUMN llm_usage.tokens_used IS 'Input plus output tokens reported by the provider';
+
+CREATE TABLE IF NOT EXISTS campaign_llm_budgets (
+    campaign_id         BIGINT PRIMARY KEY REFERENCES campaigns(id) ON DELETE CASCADE,
+    monthly_token_limit BIGINT NOT NULL CHECK (monthly_token_limit > 0),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE campaign_llm_budgets IS 'Optional monthly token cap per campaign; enrichment is refused once exceeded';
+COMMENT ON COLUMN campaign_llm_budgets.monthly_token_limit IS 'Maximum tokens per calendar month (UTC)';
+
+INSERT INTO schema_migrations (version) VALUES ('010_llm_usage');
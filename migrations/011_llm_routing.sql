@@ -0,0 +1,23 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+-- ============================================
+-- Migration 011: LLM Routing
+-- Per-user provider fallback chain and
+-- per-agent model routing for content
+-- generation.
+-- ============================================
+
+ALTER TABLE user_settings
+    ADD COLUMN IF NOT EXISTS llm_routing JSONB;
+
+COMMENT ON COLUMN user_settings.llm_routing IS 'Fallback chain and per-agent service/model routes for content generation; API keys inside are encrypted at application layer (AES-256-GCM)';
+
+INSERT INTO schema_migrations (version) VALUES ('011_llm_routing');
@@ -0,0 +1,60 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+-- ============================================
+-- Migration 012: Background Job Queue
+-- Durable queue for enrichment and revision
+-- work, claimed by workers with
+-- FOR UPDATE SKIP LOCKED and held under a
+-- renewable lease.
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS background_jobs (
+    id               BIGSERIAL PRIMARY KEY,
+    kind             TEXT NOT NULL,
+    campaign_id      BIGINT REFERENCES campaigns(id) ON DELETE CASCADE,
+    analysis_job_id  BIGINT REFERENCES content_analysis_jobs(id) ON DELETE CASCADE,
+    payload          JSONB NOT NULL DEFAULT '{}',
+    status           TEXT NOT NULL DEFAULT 'queued'
+                     CHECK (status IN ('queued', 'running', 'completed', 'failed', 'cancelled')),
+    attempts         INTEGER NOT NULL DEFAULT 0 CHECK (attempts >= 0),
+    max_attempts     INTEGER NOT NULL DEFAULT 3 CHECK (max_attempts > 0),
+    run_after        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_by        TEXT,
+    lease_expires_at TIMESTAMPTZ,
+    last_error       TEXT,
+    result           JSONB,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_background_jobs_ready
+    ON background_jobs(run_after, id) WHERE status = 'queued';
+CREATE INDEX IF NOT EXISTS idx_background_jobs_lease
+    ON background_jobs(lease_expires_at) WHERE status = 'running';
+CREATE INDEX IF NOT EXISTS idx_background_jobs_analysis_job
+    ON background_jobs(analysis_job_id) WHERE analysis_job_id IS NOT NULL;
+
+CREATE TRIGGER update_background_jobs_updated_at
+    BEFORE UPDATE ON background_jobs
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE background_jobs IS 'Durable queue of background work (enrichment, revision) that survives server restarts';
+COMMENT ON COLUMN background_jobs.kind IS 'Job type, which selects the handler that runs it (e.g. enrichment, revision)';
+COMMENT ON COLUMN background_jobs.analysis_job_id IS 'Content analysis job this work belongs to, if any; used to cancel it';
+COMMENT ON COLUMN background_jobs.payload IS 'Handler-specific JSON input';
+COMMENT ON COLUMN background_jobs.status IS 'Job status: queued, running, completed, failed, cancelled';
+COMMENT ON COLUMN background_jobs.attempts IS 'Number of times a worker has claimed the job';
+COMMENT ON COLUMN background_jobs.run_after IS 'Earliest time the job may be claimed; pushed back between retries';
+COMMENT ON COLUMN background_jobs.locked_by IS 'Worker that holds the lease on a running job';
+COMMENT ON COLUMN background_jobs.lease_expires_at IS 'When a running job may be reclaimed by another worker unless its lease is renewed';
+COMMENT ON COLUMN background_jobs.result IS 'Handler-specific JSON output of a completed job';
+
+INSERT INTO schema_migrations (version) VALUES ('012_background_jobs');
@@ -0,0 +1,63 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+-- ============================================
+-- Migration 013: Analysis Job Events
+-- Append-only log of enrichment progress
+-- events. Each insert is announced with
+-- pg_notify so that servers can push events
+-- to SSE clients, and the log lets clients
+-- resume from Last-Event-ID.
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS analysis_job_events (
+    id          BIGSERIAL PRIMARY KEY,
+    job_id      BIGINT NOT NULL
+                REFERENCES content_analysis_jobs(id) ON DELETE CASCADE,
+    event_type  TEXT NOT NULL
+                CHECK (event_type IN ('item_created', 'items_removed',
+                                      'phase_changed', 'job_complete')),
+    payload     JSONB NOT NULL DEFAULT '{}',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_analysis_job_events_job
+    ON analysis_job_events(job_id, id);
+
+COMMENT ON TABLE analysis_job_events IS 'Enrichment progress events per analysis job, streamed to clients via LISTEN/NOTIFY and replayed on reconnect';
+COMMENT ON COLUMN analysis_job_events.id IS 'Monotonic event ID, sent to SSE clients as the event id for Last-Event-ID';
+COMMENT ON COLUMN analysis_job_events.event_type IS 'Event type: item_created, items_removed, phase_changed, job_complete';
+COMMENT ON COLUMN analysis_job_events.payload IS 'Event-specific JSON body sent to clients';
+
+-- ============================================
+-- Notify listeners of new events
+-- The notification carries only the event and
+-- job IDs; payloads can exceed the 8000 byte
+-- NOTIFY limit, so listeners read them from
+-- the table.
+-- ============================================
+CREATE OR REPLACE FUNCTION notify_analysis_job_event()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify(
+        'analysis_job_events',
+        json_build_object('id', NEW.id, 'jobId', NEW.job_id)::TEXT
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER analysis_job_events_notify
+    AFTER INSERT ON analysis_job_events
+    FOR EACH ROW EXECUTE FUNCTION notify_analysis_job_event();
+
+COMMENT ON FUNCTION notify_analysis_job_event IS 'Announces a new analysis job event on the analysis_job_events channel';
+
+INSERT INTO schema_migrations (version) VALUES ('013_analysis_job_events');
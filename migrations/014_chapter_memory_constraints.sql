@@ -0,0 +1,51 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+-- ============================================
+-- Migration 014: Chapter Memory Constraints
+-- A chapter has at most one memory, and at
+-- most one chapter memory per campaign is
+-- marked current.
+-- ============================================
+
+-- Keep the most recently updated memory of any chapter that has
+-- several.
+DELETE FROM chapter_memories cm
+USING chapter_memories newer
+WHERE newer.chapter_id = cm.chapter_id
+  AND (newer.updated_at, newer.id) > (cm.updated_at, cm.id);
+
+-- Keep the most recently updated current chapter of any campaign that
+-- has several.
+UPDATE chapter_memories cm
+SET is_current = false
+FROM chapter_memories newer
+WHERE newer.campaign_id = cm.campaign_id
+  AND newer.is_current
+  AND cm.is_current
+  AND (newer.updated_at, newer.id) > (cm.updated_at, cm.id);
+
+DROP INDEX IF EXISTS idx_chapter_memories_chapter_id;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_chapter_memories_chapter_id
+    ON chapter_memories(chapter_id);
+
+DROP INDEX IF EXISTS idx_chapter_memories_is_current;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_chapter_memories_is_current
+    ON chapter_memories(campaign_id) WHERE is_current = true;
+
+UPDATE chapter_memories SET is_current = false WHERE is_current IS NULL;
+ALTER TABLE chapter_memories ALTER COLUMN is_current SET NOT NULL;
+
+COMMENT ON INDEX idx_chapter_memories_chapter_id IS
+    'A chapter has at most one memory';
+COMMENT ON INDEX idx_chapter_memories_is_current IS
+    'At most one current chapter per campaign';
+
+INSERT INTO schema_migrations (version) VALUES ('014_chapter_memory_constraints');
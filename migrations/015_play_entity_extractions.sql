@@ -0,0 +1,73 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+-- ============================================
+-- Migration 015: Play Entity Extractions
+-- Entities detected in a session's chat and
+-- play notes are queued for the GM to accept,
+-- defer or reject. A deferred entity is
+-- created as a DRAFT to review at wrap-up.
+-- ============================================
+
+ALTER TABLE memory_entity_extractions
+    ADD COLUMN IF NOT EXISTS session_id BIGINT REFERENCES sessions(id) ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT 'session_memory',
+    ADD COLUMN IF NOT EXISTS description TEXT,
+    ADD COLUMN IF NOT EXISTS entity_id BIGINT REFERENCES entities(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+UPDATE memory_entity_extractions x
+SET session_id = sm.session_id
+FROM session_memories sm
+WHERE sm.id = x.session_memory_id
+  AND x.session_id IS NULL;
+
+ALTER TABLE memory_entity_extractions
+    ALTER COLUMN session_id SET NOT NULL,
+    ALTER COLUMN session_memory_id DROP NOT NULL;
+
+UPDATE memory_entity_extractions SET created_at = NOW() WHERE created_at IS NULL;
+ALTER TABLE memory_entity_extractions ALTER COLUMN created_at SET NOT NULL;
+
+-- Approved and created extractions are both accepted.
+ALTER TABLE memory_entity_extractions
+    DROP CONSTRAINT IF EXISTS memory_entity_extractions_status_check;
+UPDATE memory_entity_extractions
+SET status = 'accepted'
+WHERE status IN ('approved', 'created');
+UPDATE memory_entity_extractions SET status = 'pending' WHERE status IS NULL;
+ALTER TABLE memory_entity_extractions
+    ALTER COLUMN status SET NOT NULL,
+    ADD CONSTRAINT memory_entity_extractions_status_check CHECK (status IN (
+        'pending', 'accepted', 'deferred', 'rejected'
+    )),
+    ADD CONSTRAINT memory_entity_extractions_source_check CHECK (source IN (
+        'chat', 'play_notes', 'session_memory'
+    ));
+
+CREATE INDEX IF NOT EXISTS idx_memory_entity_extractions_session_id
+    ON memory_entity_extractions(session_id);
+
+-- A name is proposed at most once per session from play, whatever the
+-- GM decided about it.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_memory_entity_extractions_session_name
+    ON memory_entity_extractions(session_id, lower(extracted_name))
+    WHERE session_memory_id IS NULL;
+
+COMMENT ON COLUMN memory_entity_extractions.session_id IS 'Session where the entity was detected';
+COMMENT ON COLUMN memory_entity_extractions.session_memory_id IS 'Session memory where the entity was detected, if any';
+COMMENT ON COLUMN memory_entity_extractions.source IS 'Where the entity was detected: chat, play_notes, or session_memory';
+COMMENT ON COLUMN memory_entity_extractions.description IS 'Proposed description, or new information about the matched entity';
+COMMENT ON COLUMN memory_entity_extractions.entity_id IS 'Entity created when the extraction was accepted or deferred';
+COMMENT ON COLUMN memory_entity_extractions.status IS 'Review status: pending, accepted, deferred, or rejected';
+COMMENT ON INDEX idx_memory_entity_extractions_session_name IS
+    'An entity name is proposed at most once per session from play';
+
+INSERT INTO schema_migrations (version) VALUES ('015_play_entity_extractions');
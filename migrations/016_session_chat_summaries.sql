@@ -0,0 +1,41 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+-- ============================================
+-- Migration 016: Session Chat Summaries
+-- Older turns of a long play assistant chat are
+-- folded into rolling summaries. Every message
+-- is also kept in session_chat_logs.
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS session_chat_summaries (
+    id                 BIGSERIAL PRIMARY KEY,
+    session_id         BIGINT NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    summary            TEXT NOT NULL,
+    through_sort_order INT NOT NULL,
+    message_count      INT NOT NULL,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT unique_session_chat_summary UNIQUE (session_id, through_sort_order)
+);
+
+COMMENT ON TABLE session_chat_summaries IS 'Rolling summaries of the older messages of a session''s play assistant chat';
+COMMENT ON COLUMN session_chat_summaries.summary IS 'Summary of every message up to through_sort_order, folding the previous summary';
+COMMENT ON COLUMN session_chat_summaries.through_sort_order IS 'Sort order of the last session_chat_messages row folded into the summary';
+COMMENT ON COLUMN session_chat_summaries.message_count IS 'Number of messages the summary covers in total';
+
+-- Copy the chat so far into the full log.
+INSERT INTO session_chat_logs (session_id, message_index, role, content, timestamp)
+SELECT session_id, sort_order, role, content, created_at
+FROM session_chat_messages
+WHERE role IN ('user', 'assistant', 'system')
+ON CONFLICT (session_id, message_index) DO NOTHING;
+
+INSERT INTO schema_migrations (version) VALUES ('016_session_chat_summaries');
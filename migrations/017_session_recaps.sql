@@ -0,0 +1,36 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+-- ============================================
+-- Migration 017: Session Recaps
+-- Cached AI recaps of a session for the prep
+-- screen of the session after it, in a GM and
+-- a player-safe version.
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS session_recaps (
+    id          BIGSERIAL PRIMARY KEY,
+    session_id  BIGINT NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    audience    TEXT NOT NULL CHECK (audience IN ('gm', 'player')),
+    recap       TEXT NOT NULL,
+    source_hash TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT unique_session_recap UNIQUE (session_id, audience)
+);
+
+COMMENT ON TABLE session_recaps IS 'Cached AI recaps of sessions, one per audience';
+COMMENT ON COLUMN session_recaps.session_id IS 'Session the recap summarizes';
+COMMENT ON COLUMN session_recaps.audience IS 'Who the recap is for: gm, or player (player-safe)';
+COMMENT ON COLUMN session_recaps.recap IS 'The generated recap';
+COMMENT ON COLUMN session_recaps.source_hash IS 'Hash of the session material the recap was written from; the recap is regenerated when it changes';
+
+INSERT INTO schema_migrations (version) VALUES ('017_session_recaps');
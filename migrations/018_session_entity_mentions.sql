@@ -0,0 +1,36 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+-- ============================================
+-- Migration 018: Session Entity Mentions
+-- Gives session entity links the linked,
+-- mentioned and featured mention types of
+-- chapter entity links, and backfills mentions
+-- from the entities of existing scenes.
+-- ============================================
+
+ALTER TABLE session_entities
+    ADD COLUMN IF NOT EXISTS mention_type TEXT NOT NULL DEFAULT 'linked'
+        CHECK (mention_type IN ('linked', 'mentioned', 'featured'));
+
+COMMENT ON COLUMN session_entities.mention_type IS 'How the entity is associated: linked (manual), mentioned (detected in session notes or scenes), featured (primary)';
+
+CREATE INDEX IF NOT EXISTS idx_session_entities_mention_type
+    ON session_entities(mention_type);
+
+INSERT INTO session_entities (session_id, entity_id, mention_type)
+SELECT DISTINCT s.session_id, e.id, 'mentioned'
+FROM scenes s
+CROSS JOIN LATERAL unnest(s.entity_ids) AS scene_entity(entity_id)
+JOIN entities e ON e.id = scene_entity.entity_id
+               AND e.campaign_id = s.campaign_id
+ON CONFLICT (session_id, entity_id) DO NOTHING;
+
+INSERT INTO schema_migrations (version) VALUES ('018_session_entity_mentions');
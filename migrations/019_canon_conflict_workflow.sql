@@ -0,0 +1,54 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+-- ============================================
+-- Migration 019: Canon Conflict Workflow
+-- Records contradictions found by the canon
+-- expert and accepted by the GM as canon
+-- conflicts, and what resolving them
+-- superseded.
+-- ============================================
+
+UPDATE canon_conflicts SET status = 'DETECTED' WHERE status IS NULL;
+UPDATE canon_conflicts SET created_at = NOW() WHERE created_at IS NULL;
+
+ALTER TABLE canon_conflicts
+    ALTER COLUMN status SET NOT NULL,
+    ALTER COLUMN created_at SET NOT NULL,
+    ADD COLUMN IF NOT EXISTS conflict_type TEXT
+        CHECK (conflict_type IN ('factual', 'temporal', 'character')),
+    ADD COLUMN IF NOT EXISTS severity TEXT
+        CHECK (severity IN ('info', 'warning', 'error')),
+    ADD COLUMN IF NOT EXISTS description TEXT,
+    ADD COLUMN IF NOT EXISTS analysis_item_id BIGINT
+        REFERENCES content_analysis_items(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS superseded_entity_id BIGINT
+        REFERENCES entities(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS superseded_scene_id BIGINT
+        REFERENCES scenes(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+COMMENT ON COLUMN canon_conflicts.conflicting_values IS 'Array of {value, source, sourceTable, sourceId, sourceField, date} objects: the established fact first, then the content contradicting it';
+COMMENT ON COLUMN canon_conflicts.conflict_type IS 'Kind of contradiction: factual, temporal or character';
+COMMENT ON COLUMN canon_conflicts.severity IS 'Severity reported by the canon expert: info, warning or error';
+COMMENT ON COLUMN canon_conflicts.description IS 'What contradicts what';
+COMMENT ON COLUMN canon_conflicts.analysis_item_id IS 'Canon expert analysis item the conflict was accepted from';
+COMMENT ON COLUMN canon_conflicts.superseded_entity_id IS 'Entity marked SUPERSEDED when the conflict was resolved';
+COMMENT ON COLUMN canon_conflicts.superseded_scene_id IS 'Scene marked SUPERSEDED when the conflict was resolved';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_canon_conflicts_analysis_item
+    ON canon_conflicts(analysis_item_id)
+    WHERE analysis_item_id IS NOT NULL;
+
+CREATE TRIGGER update_canon_conflicts_updated_at
+    BEFORE UPDATE ON canon_conflicts
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+INSERT INTO schema_migrations (version) VALUES ('019_canon_conflict_workflow');
@@ -0,0 +1,63 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+-- ============================================
+-- Migration 020: Entity Revisions
+-- A snapshot of every version of an entity,
+-- with who made the change, so that changes
+-- can be compared and undone.
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS entity_revisions (
+    id                    BIGSERIAL PRIMARY KEY,
+    entity_id             BIGINT NOT NULL REFERENCES entities(id) ON DELETE CASCADE,
+    campaign_id           BIGINT NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    version               INT NOT NULL,
+    entity_type           TEXT NOT NULL,
+    name                  TEXT NOT NULL,
+    description           TEXT,
+    attributes            JSONB NOT NULL DEFAULT '{}',
+    tags                  TEXT[] NOT NULL DEFAULT '{}',
+    gm_notes              TEXT,
+    discovered_session    BIGINT,
+    source_document       TEXT,
+    source_confidence     TEXT NOT NULL,
+    author_type           TEXT CHECK (author_type IN ('user', 'agent', 'import')),
+    author_user_id        BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    author_agent          TEXT,
+    analysis_item_id      BIGINT REFERENCES content_analysis_items(id) ON DELETE SET NULL,
+    restored_from_version INT,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT unique_entity_revision UNIQUE (entity_id, version)
+);
+
+COMMENT ON TABLE entity_revisions IS 'Snapshot of each version of an entity';
+COMMENT ON COLUMN entity_revisions.version IS 'Entity version the snapshot is of';
+COMMENT ON COLUMN entity_revisions.author_type IS 'Who made the change: user, agent or import; NULL when unknown';
+COMMENT ON COLUMN entity_revisions.author_user_id IS 'User who made or accepted the change';
+COMMENT ON COLUMN entity_revisions.author_agent IS 'Agent whose suggestion the change applied (e.g. enrichment)';
+COMMENT ON COLUMN entity_revisions.analysis_item_id IS 'Content analysis item whose acceptance made the change';
+COMMENT ON COLUMN entity_revisions.restored_from_version IS 'Older version this revision restored';
+
+-- Record the current state of existing entities as their first
+-- known revision.
+INSERT INTO entity_revisions
+    (entity_id, campaign_id, version, entity_type, name, description,
+     attributes, tags, gm_notes, discovered_session, source_document,
+     source_confidence, created_at)
+SELECT id, campaign_id, COALESCE(version, 1), entity_type, name, description,
+       COALESCE(attributes, '{}'), COALESCE(tags, '{}'), gm_notes,
+       discovered_session, source_document,
+       COALESCE(source_confidence, 'DRAFT'), COALESCE(updated_at, NOW())
+FROM entities
+ON CONFLICT (entity_id, version) DO NOTHING;
+
+INSERT INTO schema_migrations (version) VALUES ('020_entity_revisions');
@@ -0,0 +1,42 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+-- ============================================
+-- Migration 021: Entity Aliases
+-- Other names an entity is known by. Merging a
+-- duplicate entity into another keeps the
+-- duplicate's name as an alias.
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS entity_aliases (
+    id          BIGSERIAL PRIMARY KEY,
+    entity_id   BIGINT NOT NULL REFERENCES entities(id) ON DELETE CASCADE,
+    campaign_id BIGINT NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    alias       TEXT NOT NULL CHECK (btrim(alias) <> ''),
+    source      TEXT NOT NULL DEFAULT 'manual'
+                CHECK (source IN ('manual', 'merge', 'analysis')),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE entity_aliases IS 'Other names campaign entities are known by';
+COMMENT ON COLUMN entity_aliases.alias IS 'Alternative name; unique per campaign, ignoring case';
+COMMENT ON COLUMN entity_aliases.source IS 'How the alias was added: manual, merge (name of a merged duplicate) or analysis (accepted potential alias)';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_entity_aliases_campaign_alias
+    ON entity_aliases(campaign_id, lower(alias));
+CREATE INDEX IF NOT EXISTS idx_entity_aliases_entity
+    ON entity_aliases(entity_id);
+
+CREATE TRIGGER update_entity_aliases_updated_at
+    BEFORE UPDATE ON entity_aliases
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+INSERT INTO schema_migrations (version) VALUES ('021_entity_aliases');
@@ -0,0 +1,21 @@
+/*-------------------------------------------------------------------------
+ *
+ * Imagineer - TTRPG Campaign Intelligence Platform
+ *
+ * Copyright (c) 2025 - 2026
+ * This software is released under The MIT License
+ *
+ *-------------------------------------------------------------------------
+ */
+
+-- ============================================
+-- Migration 022: Entity Alias Search
+-- Entity name resolution and search match
+-- aliases with the pg_trgm similarity operator,
+-- as they do entity names.
+-- ============================================
+
+CREATE INDEX IF NOT EXISTS idx_entity_aliases_alias_trgm
+    ON entity_aliases USING GIN(alias gin_trgm_ops);
+
+INSERT INTO schema_migrations (version) VALUES ('022_entity_alias_search');